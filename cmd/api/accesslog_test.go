@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/felixge/httpsnoop"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+func TestIPAnonymizerTruncate(t *testing.T) {
+	a, err := newIPAnonymizer(anonymizeIPTruncate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"203.0.113.42", "203.0.113.0"},
+		{"2001:db8:85a3:8d3:1319:8a2e:370:7348", "2001:db8:85a3::"},
+		{"::ffff:203.0.113.42", "203.0.113.0"},
+		{"not an ip", ""},
+	}
+
+	for _, tt := range tests {
+		if got := a.anonymize(tt.ip); got != tt.want {
+			t.Errorf("anonymize(%q) = %q, want %q", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestIPAnonymizerHash(t *testing.T) {
+	a, err := newIPAnonymizer(anonymizeIPHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newIPAnonymizer(anonymizeIPHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := a.anonymize("203.0.113.42")
+	if len(hash) != 16 || hash == "203.0.113.42" {
+		t.Errorf("got hash %q, want 16 hex digits", hash)
+	}
+
+	// Requests from one client can be correlated, but not across processes, which have keys of
+	// their own.
+	if got := a.anonymize("203.0.113.42"); got != hash {
+		t.Errorf("got hash %q the second time, want %q", got, hash)
+	}
+	if got := a.anonymize("203.0.113.43"); got == hash {
+		t.Errorf("got the same hash %q for another address", got)
+	}
+	if got := b.anonymize("203.0.113.42"); got == hash {
+		t.Errorf("got the same hash %q with another key", got)
+	}
+}
+
+func TestNewIPAnonymizerInvalid(t *testing.T) {
+	if _, err := newIPAnonymizer("scramble"); err == nil {
+		t.Error("got no error for an invalid mode")
+	}
+}
+
+func TestLogRequestAnonymizesIP(t *testing.T) {
+	for _, mode := range []string{anonymizeIPOff, anonymizeIPHash} {
+		var buf bytes.Buffer
+
+		anonymizer, err := newIPAnonymizer(mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		app := &application{logger: jsonlog.NewLogger(&buf, jsonlog.LevelInfo), ipAnonymizer: anonymizer}
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/champions", nil)
+		r.RemoteAddr = "203.0.113.42:51234"
+		app.logRequest(r, httpsnoop.Metrics{Code: http.StatusOK})
+
+		var entry struct {
+			Properties map[string]string `json:"properties"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+
+		got := entry.Properties["remote_addr"]
+		switch mode {
+		case anonymizeIPOff:
+			if got != "203.0.113.42" {
+				t.Errorf("anonymization off: got remote_addr %q, want the raw address", got)
+			}
+		case anonymizeIPHash:
+			if got != anonymizer.anonymize("203.0.113.42") {
+				t.Errorf("anonymization on: got remote_addr %q, want the address's hash", got)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	app := &application{
+		logger: jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models: data.NewModels(newTestDB(t), nil),
+	}
+
+	insertKey := func(name string, expiry *time.Time) *data.APIKey {
+		key := &data.APIKey{Name: name, Permissions: data.Permissions{"matches:write"}, Expiry: expiry}
+		if err := data.GenerateAPIKey(key); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.models.APIKeys.Insert(key); err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+
+	valid := insertKey("ingestion", nil)
+	revoked := insertKey("old ingestion", nil)
+	if err := app.models.APIKeys.Revoke(revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	yesterday := time.Now().Add(-24 * time.Hour)
+	expired := insertKey("trial", &yesterday)
+
+	var got *data.APIKey
+	handler := app.authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = app.contextGetAPIKey(r)
+	}))
+
+	tests := []struct {
+		name     string
+		key      string
+		wantCode int
+	}{
+		{"valid", valid.Plaintext, http.StatusOK},
+		{"revoked", revoked.Plaintext, http.StatusUnauthorized},
+		{"expired", expired.Plaintext, http.StatusUnauthorized},
+		{"unknown", strings.Repeat("A", 52), http.StatusUnauthorized},
+		{"malformed", "guess", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		got = nil
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/champions", nil)
+		r.Header.Set("X-API-Key", tt.key)
+		// An invalid key is rejected even with a bearer token to fall back to.
+		r.Header.Set("Authorization", "Bearer "+strings.Repeat("A", 26))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s key: got status %d, want %d", tt.name, rr.Code, tt.wantCode)
+		}
+		if tt.wantCode == http.StatusOK && (got == nil || got.ID != valid.ID) {
+			t.Errorf("%s key: got API key %+v in the context, want key %d", tt.name, got, valid.ID)
+		}
+		if strings.Contains(rr.Body.String(), tt.key) {
+			t.Errorf("%s key: the response repeated the key", tt.name)
+		}
+	}
+}
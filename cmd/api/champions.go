@@ -2,8 +2,11 @@ package main
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"league_of_graphs.satellite.net/internal/data"
 	"league_of_graphs.satellite.net/internal/validator"
@@ -13,8 +16,12 @@ import (
 // return a plain-text placeholder response.
 func (app *application) createChampionHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Name     string `json:"name"`
-		MainRole string `json:"main_role"`
+		Name          string     `json:"name"`
+		MainRole      string     `json:"main_role"`
+		ImageURL      string     `json:"image_url"`
+		DamageProfile string     `json:"damage_profile"`
+		Classes       []string   `json:"classes"`
+		ReleaseDate   *time.Time `json:"release_date"`
 	}
 
 	err := app.readJSON(w, r, &input)
@@ -24,10 +31,16 @@ func (app *application) createChampionHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	champion := &data.Champion{
-		Name:     input.Name,
-		MainRole: input.MainRole,
+		Name:          input.Name,
+		MainRole:      input.MainRole,
+		ImageURL:      input.ImageURL,
+		DamageProfile: input.DamageProfile,
+		Classes:       input.Classes,
+		ReleaseDate:   input.ReleaseDate,
 	}
 
+	data.NormalizeChampion(champion)
+
 	v := validator.New()
 
 	if data.ValidateChampion(v, champion); !v.Valid() {
@@ -41,10 +54,131 @@ func (app *application) createChampionHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	app.metaThresholds().Apply(champion)
+
 	headers := make(http.Header)
-	headers.Set("Location", fmt.Sprintf("/v1/champions/%d", champion.ID))
+	headers.Set("Location", app.url("/v1/champions/%d", champion.ID))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"champion": champion, "_links": app.championLinks(champion.ID)}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// bulkItemError describes why a single entry in a bulk request wasn't created.
+type bulkItemError struct {
+	Index  int               `json:"index"`
+	Errors map[string]string `json:"errors"`
+}
+
+// championInsertErrors describes why a champion in a bulk request couldn't be inserted, naming the
+// database constraint it broke if that was the reason.
+func championInsertErrors(err error) map[string]string {
+	if constraint, ok := data.ConstraintViolation(err); ok {
+		return map[string]string{"champion": "violates the " + constraint + " constraint"}
+	}
+	return map[string]string{"champion": "could not be created"}
+}
+
+// createChampionsBulkHandler creates a batch of champions. In the default "atomic" mode either
+// every champion is created or none are. With ?mode=best_effort the valid champions are created
+// and the response is a 207 Multi-Status listing what was created and which entries failed.
+func (app *application) createChampionsBulkHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	mode := app.readString(r.URL.Query(), "mode", "atomic")
+	v.Check(validator.In(mode, "atomic", "best_effort"), "mode", "must be atomic or best_effort")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	var input []struct {
+		Name     string `json:"name"`
+		MainRole string `json:"main_role"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v.Check(len(input) > 0, "champions", "must contain at least one champion")
+	v.Check(len(input) <= 100, "champions", "must not contain more than 100 champions")
 
-	err = app.writeJSON(w, http.StatusCreated, envelope{"champion": champion}, headers)
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	// Validate every entry up front, keeping track of where each valid champion came from in the
+	// request so that failures can be reported against the client's indexes.
+	var champions []*data.Champion
+	var indexes []int
+	itemErrors := []bulkItemError{}
+
+	for i, item := range input {
+		champion := &data.Champion{Name: item.Name, MainRole: item.MainRole}
+		data.NormalizeChampion(champion)
+
+		itemValidator := validator.New()
+		if data.ValidateChampion(itemValidator, champion); !itemValidator.Valid() {
+			itemErrors = append(itemErrors, bulkItemError{Index: i, Errors: itemValidator.Errors})
+			continue
+		}
+
+		champions = append(champions, champion)
+		indexes = append(indexes, i)
+	}
+
+	bestEffort := mode == "best_effort"
+
+	if !bestEffort && len(itemErrors) > 0 {
+		app.errorResponse(w, r, http.StatusUnprocessableEntity, itemErrors)
+		return
+	}
+
+	created := []*data.Champion{}
+
+	if len(champions) > 0 {
+		failed, err := app.models.Champions.InsertMany(champions, bestEffort)
+		if err != nil {
+			// A champion which passed validation can still be rejected by a database constraint,
+			// which in atomic mode fails the batch like an invalid entry would.
+			var insertErr *data.BulkInsertError
+			if errors.As(err, &insertErr) {
+				if _, ok := data.ConstraintViolation(insertErr.Err); ok {
+					itemErrors = append(itemErrors, bulkItemError{Index: indexes[insertErr.Index], Errors: championInsertErrors(insertErr.Err)})
+					app.errorResponse(w, r, http.StatusUnprocessableEntity, itemErrors)
+					return
+				}
+			}
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		for i, champion := range champions {
+			if err, ok := failed[i]; ok {
+				app.logError(r, err)
+				itemErrors = append(itemErrors, bulkItemError{Index: indexes[i], Errors: championInsertErrors(err)})
+				continue
+			}
+			created = append(created, champion)
+		}
+	}
+
+	sort.Slice(itemErrors, func(i, j int) bool { return itemErrors[i].Index < itemErrors[j].Index })
+
+	app.metaThresholds().Apply(created...)
+
+	status := http.StatusCreated
+	if bestEffort {
+		status = http.StatusMultiStatus
+	}
+
+	err = app.writeJSON(w, status, envelope{"created": created, "errors": itemErrors}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -60,20 +194,77 @@ func (app *application) showChampionHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// With ?period= the response also compares the champion's win rate over that period with the
+	// period before it, for trending badges. ?match_type= and ?rank= scope the win rate, popularity
+	// and trend to those matches.
+	v := validator.New()
+
+	qs := r.URL.Query()
+	period := app.readPeriod(qs, "period", v)
+	scope := app.readStatsScope(qs, v)
+	fields := app.readFields(qs, championFields, v)
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
 	// Create a new instance of the Champion struct with dummy data.
-	champion, err := app.models.Champions.Get(id)
+	champion, err := app.models.Champions.GetInScope(id, scope)
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.championNotFoundResponse(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
 		return
 	}
 
+	app.metaThresholds().Apply(champion)
+
+	champion.RoleDrift, err = app.models.Champions.GetRoleDrift(champion.ID, app.config.roleDrift.days, app.config.roleDrift.minGames)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	champion.Aliases, err = app.models.Champions.GetAliases(champion.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	champion.Notes, err = app.models.Champions.GetLatestNotes(champion.ID, app.config.championNotesLimit)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if period > 0 {
+		champion.WinRateTrend, err = app.models.Champions.GetWinRateTrend(champion.ID, period, scope)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		champion.WinRateTrend.Period = qs.Get("period")
+	}
+
+	selected, err := fields.apply(champion)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	env := envelope{"champion": selected, "_links": app.championLinks(champion.ID)}
+	if v.HasWarnings() {
+		env["warnings"] = v.Warnings
+	}
+
 	// Encode the struct to JSON and send it as the HTTP response.
-	err = app.writeJSON(w, http.StatusOK, envelope{"champion": champion}, nil)
+	err = app.writeJSON(w, http.StatusOK, env, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -90,7 +281,7 @@ func (app *application) updateChampionHandler(w http.ResponseWriter, r *http.Req
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.championNotFoundResponse(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -98,8 +289,12 @@ func (app *application) updateChampionHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	var input struct {
-		Name     string `json:"name"`
-		MainRole string `json:"main_role"`
+		Name          string     `json:"name"`
+		MainRole      string     `json:"main_role"`
+		ImageURL      string     `json:"image_url"`
+		DamageProfile string     `json:"damage_profile"`
+		Classes       []string   `json:"classes"`
+		ReleaseDate   *time.Time `json:"release_date"`
 	}
 
 	err = app.readJSON(w, r, &input)
@@ -110,6 +305,12 @@ func (app *application) updateChampionHandler(w http.ResponseWriter, r *http.Req
 
 	champion.Name = input.Name
 	champion.MainRole = input.MainRole
+	champion.ImageURL = input.ImageURL
+	champion.DamageProfile = input.DamageProfile
+	champion.Classes = input.Classes
+	champion.ReleaseDate = input.ReleaseDate
+
+	data.NormalizeChampion(champion)
 
 	v := validator.New()
 
@@ -119,12 +320,127 @@ func (app *application) updateChampionHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	err = app.models.Champions.Update(champion)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	app.metaThresholds().Apply(champion)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"champion": champion, "_links": app.championLinks(champion.ID)}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// patchChampionHandler updates only the fields present in the request body. The client must send
+// the version of the champion it based its edit on; the edit is rejected with a 409 Conflict only
+// if one of the fields it changes has been written by someone else since that version.
+//
+// A body sent as application/merge-patch+json follows RFC 7386, where an explicit null clears a
+// field (only image_url can be cleared) rather than leaving it unchanged.
+func (app *application) patchChampionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	champion, err := app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	var input struct {
+		Name     *string `json:"name"`
+		MainRole *string `json:"main_role"`
+		ImageURL *string `json:"image_url"`
+		Version  *int32  `json:"version"`
+	}
+
+	var nulls map[string]bool
+
+	if isMergePatch(r) {
+		nulls, err = app.readMergePatch(w, r, map[string]interface{}{
+			"name":      &input.Name,
+			"main_role": &input.MainRole,
+			"image_url": &input.ImageURL,
+			"version":   &input.Version,
+		})
+	} else {
+		err = app.readJSON(w, r, &input)
+	}
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if nulls["image_url"] {
+		cleared := ""
+		input.ImageURL = &cleared
+	}
+
+	// Apply the patch to the champion we just read so that the result can be normalized and
+	// validated as a whole, then send only the patched fields to the database.
+	if input.Name != nil {
+		champion.Name = *input.Name
+	}
+	if input.MainRole != nil {
+		champion.MainRole = *input.MainRole
+	}
+	if input.ImageURL != nil {
+		champion.ImageURL = *input.ImageURL
+	}
+
+	data.NormalizeChampion(champion)
+
+	v := validator.New()
+
+	v.Check(input.Version != nil, "version", "must be provided")
+	v.Check(!nulls["name"], "name", "cannot be cleared")
+	v.Check(!nulls["main_role"], "main_role", "cannot be cleared")
+
+	if data.ValidateChampion(v, champion); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	var patch data.ChampionPatch
+	if input.Name != nil {
+		patch.Name = &champion.Name
+	}
+	if input.MainRole != nil {
+		patch.MainRole = &champion.MainRole
+	}
+	if input.ImageURL != nil {
+		patch.ImageURL = &champion.ImageURL
+	}
+
+	champion, err = app.models.Champions.Patch(id, *input.Version, patch)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"champion": champion}, nil)
+	app.metaThresholds().Apply(champion)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"champion": champion, "_links": app.championLinks(champion.ID)}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -142,7 +458,7 @@ func (app *application) deleteChampionHandler(w http.ResponseWriter, r *http.Req
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.championNotFoundResponse(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -155,38 +471,875 @@ func (app *application) deleteChampionHandler(w http.ResponseWriter, r *http.Req
 	}
 }
 
-func (app *application) listChampionsHandler(w http.ResponseWriter, r *http.Request) {
+// recalculateChampionRoleHandler derives the champion's main_role from its play data, and returns
+// the (possibly updated) champion.
+func (app *application) recalculateChampionRoleHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	changed, err := app.models.Champions.RecalculateMainRole(id, app.config.mainRoleMargin)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	champion, err := app.models.Champions.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.metaThresholds().Apply(champion)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"champion": champion, "changed": changed, "_links": app.championLinks(champion.ID)}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// createChampionNoteHandler adds an editorial note to a champion on behalf of the authenticated
+// user.
+func (app *application) createChampionNoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
 	var input struct {
-		Name     string
-		MainRole string
-		data.Filters
+		Body string `json:"body"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	// The user of an API key has no ID, so a note written with one is stored without an author.
+	note := &data.ChampionNote{
+		ChampionID: id,
+		UserID:     app.contextGetUser(r).ID,
+		Body:       strings.TrimSpace(input.Body),
 	}
 
 	v := validator.New()
 
-	qs := r.URL.Query()
+	if data.ValidateChampionNote(v, note); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
 
-	input.Name = app.readString(qs, "name", "")
-	input.MainRole = app.readString(qs, "main_role", "")
+	err = app.models.Champions.InsertNote(note)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"note": note}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
 
-	input.Filters.Page = app.readInt(qs, "page", 1, v)
-	input.Filters.PageSize = app.readInt(qs, "page_size", 20, v)
+// showChampionItemsHandler returns the items most often bought on a champion, with the win rate
+// of the games they were bought in. ?role= limits the results to games in that role.
+func (app *application) showChampionItemsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
 
-	input.Filters.Sort = app.readString(qs, "sort", "id")
-	input.Filters.SortSafelist = []string{"id", "name", "main_role", "-id", "-name", "-main_role"}
+	v := validator.New()
 
-	if data.ValidateFilters(v, input.Filters); !v.Valid() {
+	qs := r.URL.Query()
+
+	role := data.NormalizeRole(app.readString(qs, "role", ""))
+	scope := app.readStatsScope(qs, v)
+
+	if !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
-	champions, err := app.models.Champions.GetAll(input.Name, input.MainRole, input.Filters)
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	items, err := app.models.Champions.GetPopularItems(id, role, app.config.itemsMinGames, scope)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"champions": champions}, nil)
+	app.setCacheControl(w, app.config.cache.champions)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"role": role, "items": items}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showChampionDraftTimingHandler reports how early in the draft the champion is typically picked
+// and banned, as a measure of its draft priority rather than its performance.
+func (app *application) showChampionDraftTimingHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+	scope := app.readStatsScope(r.URL.Query(), v)
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	timing, err := app.models.Champions.GetDraftTiming(id, scope)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"draftTiming": timing}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showSimilarChampionsHandler returns the champions most often played by the summoners who play
+// this champion, for "players who play X also play Y" recommendations.
+func (app *application) showSimilarChampionsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	limit := app.readInt(r.URL.Query(), "limit", 10, v)
+
+	v.Check(limit > 0, "limit", "must be greater than zero")
+	v.Check(limit <= 50, "limit", "must be a maximum of 50")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	similar, err := app.models.Champions.GetSimilar(id, limit)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"similar": similar}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showChampionVsRoleHandler breaks down the champion's win rate by the roles of the champions on
+// the enemy team, to show which kinds of opponents it struggles against.
+func (app *application) showChampionVsRoleHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	scope := app.readStatsScope(r.URL.Query(), v)
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	matchups, err := app.models.Champions.GetRoleMatchups(id, app.config.matchupMinGames, scope)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"vsRole": matchups}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showChampionLaneMatchupHandler returns the champion's record against the ?opponent= champion
+// split into the lane phase (whether its team led in gold at 15 minutes) and the full game, with
+// the number of games behind each.
+func (app *application) showChampionLaneMatchupHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	opponentID := int64(app.readInt(qs, "opponent", 0, v))
+	scope := app.readStatsScope(qs, v)
+
+	v.Check(opponentID > 0, "opponent", "must be provided")
+	v.Check(opponentID != id, "opponent", "must be different from the champion")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	for _, championID := range []int64{id, opponentID} {
+		_, err = app.models.Champions.Get(championID)
+		if err != nil {
+			switch {
+			case errors.Is(err, data.ErrRecordNotFound):
+				app.championNotFoundResponse(w, r, championID)
+			default:
+				app.serverErrorResponse(w, r, err)
+			}
+			return
+		}
+	}
+
+	matchup, err := app.models.Champions.GetLaneMatchup(id, opponentID, scope)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"laneMatchup": matchup}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showChampionGlobalStatsHandler returns the champion's win rate in each region along with a
+// global win rate combining them by the -region-weights weights, so that the global figure isn't
+// dominated by the regions with the most games. ?weights= overrides the configured weights, in
+// the same format.
+func (app *application) showChampionGlobalStatsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	scope := app.readStatsScope(qs, v)
+
+	weights := app.config.regionWeights.weights
+	if spec := qs.Get("weights"); spec != "" {
+		weights, err = data.ParseRegionWeights(spec)
+		if err != nil {
+			v.AddError("weights", "must be a comma-separated list of region weights such as EUW:1,NA:0.5, with at least one above zero")
+		}
+	}
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	regions, err := app.models.Champions.GetRegionStats(id, scope)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"global": data.NewGlobalStats(id, regions, weights)}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// compareChampionsHandler returns two champions side by side, along with the first champion's
+// head-to-head record against the second and whether that record has enough games to be reliable.
+func (app *application) compareChampionsHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	championID := int64(app.readInt(qs, "champion", 0, v))
+	opponentID := int64(app.readInt(qs, "opponent", 0, v))
+	scope := app.readStatsScope(qs, v)
+
+	v.Check(championID > 0, "champion", "must be provided")
+	v.Check(opponentID > 0, "opponent", "must be provided")
+	v.Check(championID != opponentID, "opponent", "must be different from the champion")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	champions := make([]*data.Champion, 0, 2)
+	for _, id := range []int64{championID, opponentID} {
+		champion, err := app.models.Champions.GetInScope(id, scope)
+		if err != nil {
+			switch {
+			case errors.Is(err, data.ErrRecordNotFound):
+				app.championNotFoundResponse(w, r, id)
+			default:
+				app.serverErrorResponse(w, r, err)
+			}
+			return
+		}
+		champions = append(champions, champion)
+	}
+
+	app.metaThresholds().Apply(champions...)
+
+	matchup, err := app.models.Champions.GetMatchup(championID, opponentID, app.config.matchupMinGames, scope)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"champions": champions, "matchup": matchup}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// championSortSafelist holds the values accepted by the ?sort= parameter of the champion list.
+var championSortSafelist = []string{"id", "name", "main_role", "popularity", "win_rate", "ban_rate", "release_date",
+	"-id", "-name", "-main_role", "-popularity", "-win_rate", "-ban_rate", "-release_date"}
+
+// championFields holds the fields a champion response can be restricted to with ?fields=.
+var championFields = jsonFields(data.Champion{})
+
+func (app *application) listChampionsHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name          string
+		MainRole      string
+		MetaOnly      bool
+		PowerSpikes   []string
+		Classes       []string
+		ReleasedAfter time.Time
+		Stream        bool
+		Scope         data.StatsScope
+		data.Filters
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	input.Name = app.readString(qs, "name", "")
+	input.MainRole = app.readString(qs, "main_role", "")
+	input.MetaOnly = app.readBool(qs, "meta_only", false, v)
+	input.PowerSpikes = app.readPowerSpikeFilter(qs, v)
+	input.Classes = app.readClassFilter(qs, v)
+	input.ReleasedAfter = app.readDate(qs, "released_after", v)
+	input.Stream = app.readBool(qs, "stream", false, v)
+	input.Scope = app.readStatsScope(qs, v)
+
+	input.Filters = app.readFilters(qs, "id", championSortSafelist, v)
+	fields := app.readFields(qs, championFields, v)
+
+	if data.ValidateFilters(v, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	// In streaming mode every matching champion is written out as it's read from the database,
+	// rather than a single page being assembled in memory first.
+	if input.Stream {
+		input.Filters.PageSize = 0
+
+		err := app.streamJSON(w, "champions", func(emit func(interface{}) error) error {
+			return app.models.Champions.Stream(r.Context(), input.Name, input.MainRole, input.MetaOnly, app.metaThresholds(), input.PowerSpikes, input.Classes, input.ReleasedAfter, input.Scope, input.Filters, func(champion *data.Champion) error {
+				selected, err := fields.apply(champion)
+				if err != nil {
+					return err
+				}
+				return emit(selected)
+			})
+		})
+		if err != nil {
+			app.logError(r, err)
+		}
+		return
+	}
+
+	champions, metadata, err := app.models.Champions.GetAll(input.Name, input.MainRole, input.MetaOnly, app.metaThresholds(), input.PowerSpikes, input.Classes, input.ReleasedAfter, input.Scope, input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if metadata.OutOfRange && app.config.pagination.outOfRange == pageOutOfRangeNotFound {
+		app.pageOutOfRangeResponse(w, r, metadata)
+		return
+	}
+
+	selected, err := fields.apply(champions)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env := envelope{"champions": selected, "metadata": metadata}
+	if v.HasWarnings() {
+		env["warnings"] = v.Warnings
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// readPowerSpikeFilter reads the ?power_spike= filter, which is either a phase of the game (early,
+// mid or late) or a single power spike tag such as "level 6", and returns the tags it matches.
+func (app *application) readPowerSpikeFilter(qs url.Values, v *validator.Validator) []string {
+	spike := app.readString(qs, "power_spike", "")
+	if spike == "" {
+		return nil
+	}
+
+	tags := data.PowerSpikeTags(spike)
+	v.Check(len(tags) > 0, "power_spike", "must be early, mid, late or a known power spike tag")
+
+	return tags
+}
+
+// readClassFilter reads the ?class= filter, a comma-separated list of classes which champions must
+// all have (e.g. "Mage,Support").
+func (app *application) readClassFilter(qs url.Values, v *validator.Validator) []string {
+	class := app.readString(qs, "class", "")
+	if class == "" {
+		return nil
+	}
+
+	classes := data.NormalizeClasses(strings.Split(class, ","))
+	for _, class := range classes {
+		v.Check(validator.In(class, data.ChampionClassList...), "class", "must only contain "+strings.Join(data.ChampionClassList, ", "))
+	}
+
+	return classes
+}
+
+// metaThresholds builds the data.MetaThresholds from the application config.
+func (app *application) metaThresholds() data.MetaThresholds {
+	return data.MetaThresholds{
+		MinPopularity: app.config.meta.minPopularity,
+		MinWinRate:    app.config.meta.minWinRate,
+	}
+}
+
+// addChampionAliasesHandler adds search aliases (e.g. "mundo" for Dr. Mundo) to a champion and
+// returns all of the champion's aliases.
+func (app *application) addChampionAliasesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	var input struct {
+		Aliases []string `json:"aliases"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	aliases := data.NormalizeAliases(input.Aliases)
+
+	v := validator.New()
+
+	if data.ValidateAliases(v, aliases); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Champions.AddAliases(id, aliases)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateAlias):
+			v.AddError("aliases", "must not already be used by another champion")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	aliases, err = app.models.Champions.GetAliases(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"aliases": aliases}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// roleDriftReportHandler lists the champions whose recently most played role has drifted from
+// their stored main role by more than the configured threshold, largest drift first, so editors
+// can keep main roles up to date with the meta.
+func (app *application) roleDriftReportHandler(w http.ResponseWriter, r *http.Request) {
+	drifts, err := app.models.Champions.GetRoleDrifts(app.config.roleDrift.days, app.config.roleDrift.minGames)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	drifted := []*data.RoleDrift{}
+	for _, drift := range drifts {
+		if drift.Drift > app.config.roleDrift.threshold {
+			drifted = append(drifted, drift)
+		}
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"days": app.config.roleDrift.days, "threshold": app.config.roleDrift.threshold, "champions": drifted}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showChampionBestSummonersHandler lists the summoners with the best record on the champion.
+// ?min_games= sets how many games a summoner must have played on it (5 by default).
+func (app *application) showChampionBestSummonersHandler(w http.ResponseWriter, r *http.Request) {
+	app.writeBestSummoners(w, r, false, "summoners")
+}
+
+// showChampionProsHandler lists the professional players with the best record on the champion.
+// ?min_games= sets how many games a pro must have played on it (5 by default).
+func (app *application) showChampionProsHandler(w http.ResponseWriter, r *http.Request) {
+	app.writeBestSummoners(w, r, true, "pros")
+}
+
+// writeBestSummoners responds with the summoners with the best record on the champion, under the
+// given envelope key, optionally only the professional players among them. ?limit= caps how many
+// are listed (10 by default, at most 50).
+func (app *application) writeBestSummoners(w http.ResponseWriter, r *http.Request, prosOnly bool, key string) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	minGames := app.readInt(qs, "min_games", 5, v)
+	limit := app.readInt(qs, "limit", 10, v)
+
+	v.Check(minGames >= 0, "min_games", "must not be negative")
+	v.Check(limit > 0, "limit", "must be greater than zero")
+	v.Check(limit <= 50, "limit", "must be a maximum of 50")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	summoners, err := app.models.Champions.GetBestSummoners(id, minGames, prosOnly, limit)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{key: summoners}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showChampionWinRateHistoryHandler returns the champion's win rate over time, grouped by the
+// ?bucket= parameter: day, week (the default), iso_week or season. ?period= limits the history to
+// a recent period such as 90d; without it the whole history is returned.
+func (app *application) showChampionWinRateHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	bucket := app.readString(qs, "bucket", data.BucketWeek)
+	period := app.readPeriod(qs, "period", v)
+	scope := app.readStatsScope(qs, v)
+
+	v.Check(validator.In(bucket, data.Buckets...), "bucket", "must be one of "+strings.Join(data.Buckets, ", "))
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	var since time.Time
+	if period > 0 {
+		since = time.Now().Add(-period)
+	}
+
+	history, err := app.models.Champions.GetWinRateHistory(id, bucket, since, scope)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.champions)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"bucket": bucket, "history": history}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateChampionPowerSpikesHandler replaces the champion's power spike tags.
+func (app *application) updateChampionPowerSpikesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		PowerSpikes data.PowerSpikes `json:"power_spikes"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	champion, err := app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	champion.PowerSpikes = data.NormalizePowerSpikes(input.PowerSpikes)
+
+	v := validator.New()
+
+	if data.ValidatePowerSpikes(v, champion.PowerSpikes); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Champions.SetPowerSpikes(champion)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	app.metaThresholds().Apply(champion)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"champion": champion, "_links": app.championLinks(champion.ID)}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showChampionMatchesHandler returns a page of the matches the champion was picked in, most
+// recent first, with the summoner who played it and the outcome for their team. ?outcome=win or
+// ?outcome=loss and ?role= narrow the matches down.
+func (app *application) showChampionMatchesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	role := data.NormalizeRole(app.readString(qs, "role", ""))
+	outcome := app.readString(qs, "outcome", "")
+	filters := app.readFilters(qs, "-played_date", matchHistorySortSafelist, v)
+
+	v.Check(outcome == "" || validator.In(outcome, data.OutcomeWin, data.OutcomeLoss), "outcome", "must be win or loss")
+
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	_, err = app.models.Champions.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.championNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	history, metadata, err := app.models.Champions.GetMatchHistory(id, role, outcome, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env := envelope{"matches": history, "metadata": metadata}
+	if v.HasWarnings() {
+		env["warnings"] = v.Warnings
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+func TestCreateChampionsBulk(t *testing.T) {
+	db := newTestDB(t)
+	app := &application{
+		logger: jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models: data.NewModels(db, nil),
+	}
+
+	// A constraint the validator knows nothing about, so Teemo only fails once he reaches the
+	// database.
+	if _, err := db.Exec(`ALTER TABLE champions ADD CONSTRAINT champions_no_teemo_check CHECK (name <> 'Teemo')`); err != nil {
+		t.Fatal(err)
+	}
+
+	type response struct {
+		Created []data.Champion `json:"created"`
+		Errors  []bulkItemError `json:"errors"`
+		Error   []bulkItemError `json:"error"`
+	}
+
+	post := func(mode, body string) (int, response) {
+		t.Helper()
+
+		r := httptest.NewRequest(http.MethodPost, "/v1/champions/bulk?mode="+mode, strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		app.createChampionsBulkHandler(rr, r)
+
+		var resp response
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, rr.Body)
+		}
+		return rr.Code, resp
+	}
+
+	countChampions := func() int {
+		var count int
+		if err := db.QueryRow(`SELECT count(*) FROM champions`).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	teemoErrors := map[string]string{"champion": "violates the champions_no_teemo_check constraint"}
+
+	// Best effort: the valid champions are created around an invalid entry and a rejected one.
+	status, resp := post("best_effort", `[
+        {"name": "Ahri", "main_role": "Mid"},
+        {"name": "", "main_role": "Mid"},
+        {"name": "Teemo", "main_role": "Top"},
+        {"name": "Syndra", "main_role": "Mid"}
+    ]`)
+
+	if status != http.StatusMultiStatus {
+		t.Fatalf("best effort: got status %d, want %d", status, http.StatusMultiStatus)
+	}
+	var created []string
+	for _, champion := range resp.Created {
+		created = append(created, champion.Name)
+	}
+	if !reflect.DeepEqual(created, []string{"Ahri", "Syndra"}) {
+		t.Errorf("best effort: got %q created, want Ahri and Syndra", created)
+	}
+	wantErrors := []bulkItemError{
+		{Index: 1, Errors: map[string]string{"name": "must be provided"}},
+		{Index: 2, Errors: teemoErrors},
+	}
+	if !reflect.DeepEqual(resp.Errors, wantErrors) {
+		t.Errorf("best effort: got errors %+v, want %+v", resp.Errors, wantErrors)
+	}
+	if got := countChampions(); got != 2 {
+		t.Errorf("best effort: got %d champions stored, want 2", got)
+	}
+
+	// Atomic: a constraint violation is reported against its entry, and nothing is created.
+	status, resp = post("atomic", `[{"name": "Lux", "main_role": "Mid"}, {"name": "Teemo", "main_role": "Top"}]`)
+
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("atomic: got status %d, want %d", status, http.StatusUnprocessableEntity)
+	}
+	wantErrors = []bulkItemError{{Index: 1, Errors: teemoErrors}}
+	if !reflect.DeepEqual(resp.Error, wantErrors) {
+		t.Errorf("atomic: got errors %+v, want %+v", resp.Error, wantErrors)
+	}
+	if got := countChampions(); got != 2 {
+		t.Errorf("atomic: got %d champions stored, want 2", got)
+	}
+}
+
+// maxAgeRx extracts the max-age directive of a Cache-Control header.
+var maxAgeRx = regexp.MustCompile(`max-age=(\d+)`)
+
+// cacheMaxAge returns the number of seconds a response may be cached for, which is zero for a
+// response with no max-age (such as one marked no-store).
+func cacheMaxAge(t *testing.T, header http.Header) int {
+	t.Helper()
+
+	m := maxAgeRx.FindStringSubmatch(header.Get("Cache-Control"))
+	if m == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(m[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return seconds
+}
+
+func TestCacheControlChampionOutlivesSummoner(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+
+	ahri := &data.Champion{Name: "Ahri", MainRole: "Mid", Classes: data.ChampionClasses{}}
+	if err := app.models.Champions.Insert(ahri); err != nil {
+		t.Fatal(err)
+	}
+	faker := &data.Summoner{Username: "Faker", Region: "KR"}
+	if err := app.models.Summoners.Insert(faker); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(path string) http.Header {
+		rr := httptest.NewRecorder()
+		app.router().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET %s: got status %d: %s", path, rr.Code, rr.Body)
+		}
+		return rr.Header()
+	}
+
+	// The flag defaults, and a summoner max-age which allows some caching.
+	for _, summoners := range []time.Duration{0, 30 * time.Second} {
+		app.config.cache.champions = time.Hour
+		app.config.cache.summoners = summoners
+
+		champion := cacheMaxAge(t, get("/v1/champions/"+strconv.FormatInt(ahri.ID, 10)))
+		summoner := cacheMaxAge(t, get("/v1/summoners/"+strconv.FormatInt(faker.ID, 10)))
+
+		if champion != 3600 || champion <= summoner {
+			t.Errorf("summoners cached for %v: got a champion max-age of %d and a summoner max-age of %d, want 3600 and less",
+				summoners, champion, summoner)
+		}
+	}
+}
+
+func TestPatchChampionConcurrentEdits(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+
+	ahri := &data.Champion{Name: "Ahri", MainRole: "Mid", Classes: data.ChampionClasses{}}
+	if err := app.models.Champions.Insert(ahri); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/v1/champions/" + strconv.FormatInt(ahri.ID, 10)
+	version := strconv.Itoa(int(ahri.Version))
+
+	// Every edit is based on the champion as first read, so each one after the first is
+	// concurrent with those before it.
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"first edit", `{"main_role": "Top", "version": ` + version + `}`, http.StatusOK},
+		{"different field", `{"image_url": "https://example.com/ahri.png", "version": ` + version + `}`, http.StatusOK},
+		{"same field", `{"main_role": "Support", "version": ` + version + `}`, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		app.router().ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, path, strings.NewReader(tt.body)))
+		if rr.Code != tt.want {
+			t.Errorf("%s: got status %d, want %d: %s", tt.name, rr.Code, tt.want, rr.Body)
+		}
+	}
+
+	got, err := app.models.Champions.Get(ahri.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MainRole != "Top" || got.ImageURL != "https://example.com/ahri.png" {
+		t.Errorf("got main role %q and image URL %q, want both successful edits kept", got.MainRole, got.ImageURL)
+	}
+}
+
+func TestCreateChampionNoteWithAPIKey(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+
+	ahri := &data.Champion{Name: "Ahri", MainRole: "Mid", Classes: data.ChampionClasses{}}
+	if err := app.models.Champions.Insert(ahri); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/v1/champions/" + strconv.FormatInt(ahri.ID, 10) + "/notes"
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"body": "14.1: base AD increased"}`))
+	rr := httptest.NewRecorder()
+	app.router().ServeHTTP(rr, withAPIKey(app, r, "champions:write"))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+
+	var body struct {
+		Note data.ChampionNote `json:"note"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Note.ID == 0 || body.Note.UserID != 0 {
+		t.Errorf("got note %d by user %d, want a stored note without a user", body.Note.ID, body.Note.UserID)
+	}
+}
@@ -13,6 +13,10 @@ type contextKey string
 // context.
 const userContextKey = contextKey("user")
 
+// apiKeyContextKey is used as a key for getting and setting the API key a request was
+// authenticated with.
+const apiKeyContextKey = contextKey("apiKey")
+
 // contextSetUser returns a new copy of the request with the provided User struct added to the
 // context.
 func (app *application) contextSetUser(r *http.Request, user *data.User) *http.Request {
@@ -31,3 +35,17 @@ func (app *application) contextGetUser(r *http.Request) *data.User {
 
 	return user
 }
+
+// contextSetAPIKey returns a new copy of the request with the API key it was authenticated with
+// added to the context.
+func (app *application) contextSetAPIKey(r *http.Request, key *data.APIKey) *http.Request {
+	ctx := context.WithValue(r.Context(), apiKeyContextKey, key)
+	return r.WithContext(ctx)
+}
+
+// contextGetAPIKey retrieves the API key the request was authenticated with, or nil if it wasn't
+// authenticated with one.
+func (app *application) contextGetAPIKey(r *http.Request) *data.APIKey {
+	key, _ := r.Context().Value(apiKeyContextKey).(*data.APIKey)
+	return key
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// suggestDraftHandler recommends champions to fill a role in a live draft. Candidates are scored
+// on their win rate in the role, against the enemy picks and alongside the allied picks; champions
+// which have already been picked or banned are excluded.
+func (app *application) suggestDraftHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Role    string  `json:"role"`
+		Allies  []int64 `json:"allies"`
+		Enemies []int64 `json:"enemies"`
+		Bans    []int64 `json:"bans"`
+		Limit   *int    `json:"limit"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	draft := &data.Draft{
+		Role:    data.NormalizeRole(input.Role),
+		Allies:  input.Allies,
+		Enemies: input.Enemies,
+		Bans:    input.Bans,
+	}
+
+	limit := 5
+	if input.Limit != nil {
+		limit = *input.Limit
+	}
+
+	v := validator.New()
+
+	v.Check(limit > 0, "limit", "must be greater than zero")
+	v.Check(limit <= 50, "limit", "must be a maximum of 50")
+
+	if data.ValidateDraft(v, draft); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	stats, err := app.models.Champions.GetRoleStats(draft.Role, data.StatsScope{})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	counters, err := app.models.Champions.GetPairRecords(draft.Role, draft.Enemies, false)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	synergies, err := app.models.Champions.GetPairRecords(draft.Role, draft.Allies, true)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	weights := data.DraftWeights{
+		CounterWeight: app.config.draft.counterWeight,
+		SynergyWeight: app.config.draft.synergyWeight,
+	}
+
+	suggestions := data.RankDraft(draft, stats, counters, synergies, weights, app.config.draft.minGames, limit)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"role": draft.Role, "suggestions": suggestions}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"runtime/debug"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/errreport"
+	"league_of_graphs.satellite.net/internal/i18n"
 )
 
 // logError method is a generic helper for logging an error message in *application, as well
@@ -14,17 +20,57 @@ func (app *application) logError(r *http.Request, err error) {
 	})
 }
 
+// reportError sends the error, along with the current stack and the request details, to the
+// configured error reporter. When called while recovering from a panic the stack still includes
+// the frames which panicked. If no reporter has been configured the error is only logged.
+func (app *application) reportError(r *http.Request, err error) {
+	reporter := app.errorReporter
+	if reporter == nil {
+		reporter = errreport.Nop{}
+	}
+
+	reporter.Report(r.Context(), err, debug.Stack(), map[string]string{
+		"request_method": r.Method,
+		"request_url":    r.URL.String(),
+	})
+}
+
 // errorResponse method is a generic helper for sending JSON-formatted error messages to the
 // client with a given status code. Note that we're using an interface{} type for the message
 // parameter, rather than just a string type, as this gives us more flexibility over the values
 // that we can include in the response.
+//
+// Messages (and the values of a map of messages) are translated into the language preferred by
+// the client's Accept-Language header where we have a translation, falling back to English.
 func (app *application) errorResponse(w http.ResponseWriter, r *http.Request, status int, message interface{}) {
+	app.errorResponseWithFields(w, r, status, message, nil)
+}
+
+// errorResponseWithFields is errorResponse with extra fields added to the envelope alongside the
+// error message. The extra fields are not translated.
+func (app *application) errorResponseWithFields(w http.ResponseWriter, r *http.Request, status int, message interface{}, fields envelope) {
+	language := i18n.Negotiate(r.Header.Get("Accept-Language"))
+
+	switch m := message.(type) {
+	case string:
+		message = i18n.Translate(language, m)
+	case map[string]string:
+		message = i18n.TranslateAll(language, m)
+	}
+
 	env := envelope{"error": message}
+	for key, value := range fields {
+		env[key] = value
+	}
+
+	headers := make(http.Header)
+	headers.Set("Content-Language", language)
+	headers.Add("Vary", "Accept-Language")
 
 	// Write the response using the writeJSON() helper. If this happens to return an error
 	// then log it, and fall back to sending the client an empty response with a 500 Internal
 	// Server Error status code
-	err := app.writeJSON(w, status, env, nil)
+	err := app.writeJSON(w, status, env, headers)
 	if err != nil {
 		app.logError(r, err)
 		w.WriteHeader(500)
@@ -37,8 +83,12 @@ func (app *application) errorResponse(w http.ResponseWriter, r *http.Request, st
 // to the client
 func (app *application) serverErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	app.logError(r, err)
+	app.reportError(r, err)
 
 	message := "the server encountered a problem and could not process your request"
+	if errors.Is(err, errResponseTooLarge) {
+		message = "the response is too large to send, please request fewer results"
+	}
 	app.errorResponse(w, r, 500, message)
 }
 
@@ -49,10 +99,34 @@ func (app *application) notFoundResponse(w http.ResponseWriter, r *http.Request)
 	app.errorResponse(w, r, http.StatusNotFound, message)
 }
 
+// entityNotFoundResponse sends a 404 Not Found status code and a JSON response naming the type of
+// entity which could not be found and the ID it was requested by, so that clients of endpoints
+// which read several entities can tell which one was missing.
+func (app *application) entityNotFoundResponse(w http.ResponseWriter, r *http.Request, entity string, id int64) {
+	message := fmt.Sprintf("the requested %s could not be found", entity)
+	app.errorResponseWithFields(w, r, http.StatusNotFound, message, envelope{"entity": entity, "id": id})
+}
+
+func (app *application) championNotFoundResponse(w http.ResponseWriter, r *http.Request, id int64) {
+	app.entityNotFoundResponse(w, r, "champion", id)
+}
+
+func (app *application) summonerNotFoundResponse(w http.ResponseWriter, r *http.Request, id int64) {
+	app.entityNotFoundResponse(w, r, "summoner", id)
+}
+
+func (app *application) matchNotFoundResponse(w http.ResponseWriter, r *http.Request, id int64) {
+	app.entityNotFoundResponse(w, r, "match", id)
+}
+
 // methodNotAllowedResponse method is used to send a 405 Method Not Allowed status code and
-// JSON response to the client.
+// JSON response to the client. The router sets the Allow header listing the methods the resource
+// does support before calling it, and the same list is repeated in the message.
 func (app *application) methodNotAllowedResponse(w http.ResponseWriter, r *http.Request) {
 	message := fmt.Sprintf("the %s method is not supported this resource", r.Method)
+	if allow := w.Header().Get("Allow"); allow != "" {
+		message += fmt.Sprintf(" (allowed methods: %s)", allow)
+	}
 	app.errorResponse(w, r, http.StatusMethodNotAllowed, message)
 }
 
@@ -85,6 +159,11 @@ func (app *application) invalidCredentialsResponse(w http.ResponseWriter, r *htt
 
 // invalidAuthenticationTokenResponse sends a JSON-formatted error with a 401 Unauthorized status
 // code and "WWW-Authenticate: Bearer" header to the client.
+func (app *application) invalidAPIKeyResponse(w http.ResponseWriter, r *http.Request) {
+	message := "invalid, expired or revoked API key"
+	app.errorResponse(w, r, http.StatusUnauthorized, message)
+}
+
 func (app *application) invalidAuthenticationTokenResponse(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("WWWW-Authenticate", "Bearer")
 
@@ -111,6 +190,13 @@ func (app *application) notPermittedResponse(w http.ResponseWriter, r *http.Requ
 	app.errorResponse(w, r, http.StatusForbidden, message)
 }
 
+// pageOutOfRangeResponse is sent instead of an empty list when the requested page is beyond the
+// last page of results and -page-out-of-range is "404".
+func (app *application) pageOutOfRangeResponse(w http.ResponseWriter, r *http.Request, metadata data.Metadata) {
+	message := fmt.Sprintf("page %d is beyond the last page of results (%d)", metadata.CurrentPage, metadata.LastPage)
+	app.errorResponse(w, r, http.StatusNotFound, message)
+}
+
 func (app *application) rateLimitExceededResponse(w http.ResponseWriter, r *http.Request) {
 	message := "rate limited exceeded"
 	app.errorResponse(w, r, http.StatusTooManyRequests, message)
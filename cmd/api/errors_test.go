@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+// notFoundBody is the body of an entity-aware 404 response.
+type notFoundBody struct {
+	Error  string `json:"error"`
+	Entity string `json:"entity"`
+	ID     int64  `json:"id"`
+}
+
+func TestEntityNotFoundResponse(t *testing.T) {
+	app := &application{logger: jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo)}
+
+	tests := []struct {
+		respond  func(http.ResponseWriter, *http.Request, int64)
+		language string
+		want     notFoundBody
+	}{
+		{app.championNotFoundResponse, "", notFoundBody{"the requested champion could not be found", "champion", 42}},
+		{app.summonerNotFoundResponse, "", notFoundBody{"the requested summoner could not be found", "summoner", 42}},
+		{app.matchNotFoundResponse, "", notFoundBody{"the requested match could not be found", "match", 42}},
+		// The message is translated, but the entity and ID are left for clients to match on.
+		{app.summonerNotFoundResponse, "ko", notFoundBody{"요청한 소환사를 찾을 수 없습니다", "summoner", 42}},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Language", tt.language)
+		rr := httptest.NewRecorder()
+		tt.respond(rr, r, 42)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s: got status %d, want %d", tt.want.Entity, rr.Code, http.StatusNotFound)
+		}
+
+		var got notFoundBody
+		if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("got body %+v, want %+v", got, tt.want)
+		}
+	}
+}
+
+func TestShowChampionNotFound(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+
+	rr := httptest.NewRecorder()
+	app.router().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/champions/999", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, want %d: %s", rr.Code, http.StatusNotFound, rr.Body)
+	}
+
+	var got notFoundBody
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Entity != "champion" || got.ID != 999 {
+		t.Errorf("got body %+v, want it to name champion 999", got)
+	}
+}
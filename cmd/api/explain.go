@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// explainQueries lists the queries which explainHandler can explain, by name.
+var explainQueries = []string{"list_champions", "list_summoners", "list_matches"}
+
+// explainHandler returns the EXPLAIN ANALYZE plan of the query a list endpoint would run with the
+// filters in the query string, to diagnose slow filters without shell access to PostgreSQL. The
+// ?query= parameter names the endpoint; every other parameter is read and validated exactly as
+// the endpoint itself would, so only the endpoint's own (parameterized) query is ever run.
+func (app *application) explainHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	query := app.readString(qs, "query", "")
+
+	v.Check(query != "", "query", "must be provided")
+	v.Check(query == "" || validator.In(query, explainQueries...), "query", "must be one of "+strings.Join(explainQueries, ", "))
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	var plan []string
+	var err error
+
+	switch query {
+	case "list_champions":
+		name := app.readString(qs, "name", "")
+		mainRole := app.readString(qs, "main_role", "")
+		metaOnly := app.readBool(qs, "meta_only", false, v)
+		powerSpikes := app.readPowerSpikeFilter(qs, v)
+		classes := app.readClassFilter(qs, v)
+		releasedAfter := app.readDate(qs, "released_after", v)
+		scope := app.readStatsScope(qs, v)
+		filters := app.readFilters(qs, "id", championSortSafelist, v)
+
+		if data.ValidateFilters(v, filters); !v.Valid() {
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		plan, err = app.models.Champions.ExplainGetAll(name, mainRole, metaOnly, app.metaThresholds(), powerSpikes, classes, releasedAfter, scope, filters)
+
+	case "list_summoners":
+		filter := app.readSummonerFilter(qs, v)
+		rank := app.readString(qs, "rank", "")
+		filters := app.readFilters(qs, "id", summonerSortSafelist, v)
+
+		v.Check(validator.In(rank, "", "relevance"), "rank", "invalid rank value")
+		v.Check(rank == "" || filter.Search != "", "q", "must be provided when ranking by relevance")
+
+		if data.ValidateFilters(v, filters); !v.Valid() {
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		plan, err = app.models.Summoners.ExplainGetAll(filter, rank == "relevance", filters)
+
+	case "list_matches":
+		filter := app.readMatchFilter(qs, v)
+		filters := app.readFilters(qs, "id", matchSortSafelist, v)
+
+		if data.ValidateFilters(v, filters); !v.Valid() {
+			app.failedValidationResponse(w, r, v.Errors)
+			return
+		}
+
+		plan, err = app.models.Matches.ExplainGetAll(filter, filters)
+	}
+
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"query": query, "plan": plan}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
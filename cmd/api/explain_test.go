@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+func TestExplainReturnsPlan(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+
+	for _, query := range explainQueries {
+		r := withAPIKey(app, httptest.NewRequest(http.MethodGet, "/v1/admin/explain?query="+query+"&page_size=5", nil), "system:write")
+		rr := httptest.NewRecorder()
+		app.router().ServeHTTP(rr, r)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: got status %d, want %d: %s", query, rr.Code, http.StatusOK, rr.Body)
+			continue
+		}
+
+		var body struct {
+			Query string   `json:"query"`
+			Plan  []string `json:"plan"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+
+		// EXPLAIN ANALYZE ends its plan with the time the query took to run.
+		if body.Query != query || len(body.Plan) == 0 || !strings.HasPrefix(body.Plan[len(body.Plan)-1], "Execution Time") {
+			t.Errorf("%s: got query %q and plan %q, want an analyzed plan", query, body.Query, body.Plan)
+		}
+	}
+}
+
+func TestExplainRejectsRequests(t *testing.T) {
+	app := &application{logger: jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo), features: newFeatureFlags()}
+
+	tests := []struct {
+		name        string
+		target      string
+		permissions []string
+		want        int
+	}{
+		{"without permission", "/v1/admin/explain?query=list_matches", []string{"matches:read"}, http.StatusForbidden},
+		{"without a query", "/v1/admin/explain", []string{"system:write"}, http.StatusUnprocessableEntity},
+		{"unknown query", "/v1/admin/explain?query=list_users", []string{"system:write"}, http.StatusUnprocessableEntity},
+		{"unsafe sort", "/v1/admin/explain?query=list_summoners&sort=password", []string{"system:write"}, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		r := withAPIKey(app, httptest.NewRequest(http.MethodGet, tt.target, nil), tt.permissions...)
+		rr := httptest.NewRecorder()
+		app.router().ServeHTTP(rr, r)
+
+		if rr.Code != tt.want {
+			t.Errorf("%s: got status %d, want %d: %s", tt.name, rr.Code, tt.want, rr.Body)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/julienschmidt/httprouter"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// Features which can be switched off, so that new endpoints can be rolled out (and pulled) without
+// a deploy. Every feature is enabled unless it's turned off with -features or the admin API.
+const (
+	featureMatchImport      = "match_import"
+	featureRoleDrift        = "role_drift"
+	featureSimilarChampions = "similar_champions"
+	featureWebhooks         = "webhooks"
+)
+
+var knownFeatures = []string{featureMatchImport, featureRoleDrift, featureSimilarChampions, featureWebhooks}
+
+// featureFlags holds whether each known feature is enabled. It's safe for concurrent use, since
+// flags can be toggled while requests are being served.
+type featureFlags struct {
+	mu      sync.RWMutex
+	enabled map[string]bool
+}
+
+func newFeatureFlags() *featureFlags {
+	enabled := make(map[string]bool, len(knownFeatures))
+	for _, name := range knownFeatures {
+		enabled[name] = true
+	}
+	return &featureFlags{enabled: enabled}
+}
+
+// parse applies a comma-separated list of feature states, such as
+// "match_import=false,webhooks=true", as given to the -features flag.
+func (f *featureFlags) parse(spec string) error {
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("invalid feature %q: must be name=true or name=false", part)
+		}
+
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid feature %q: must be name=true or name=false", part)
+		}
+
+		if !validator.In(name, knownFeatures...) {
+			return fmt.Errorf("unknown feature %q", name)
+		}
+
+		f.set(name, enabled)
+	}
+
+	return nil
+}
+
+// isEnabled reports whether a feature is enabled. Unknown features are disabled.
+func (f *featureFlags) isEnabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.enabled[name]
+}
+
+func (f *featureFlags) set(name string, enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.enabled[name] = enabled
+}
+
+// all returns a copy of the state of every feature.
+func (f *featureFlags) all() map[string]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	all := make(map[string]bool, len(f.enabled))
+	for name, enabled := range f.enabled {
+		all[name] = enabled
+	}
+	return all
+}
+
+// loadFeatureFlags applies the feature states stored through the admin API, which take precedence
+// over the -features flag so that a runtime toggle survives a restart. Stored states for features
+// which no longer exist are ignored.
+func (app *application) loadFeatureFlags() error {
+	stored, err := app.models.Features.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for name, enabled := range stored {
+		if validator.In(name, knownFeatures...) {
+			app.features.set(name, enabled)
+		}
+	}
+
+	return nil
+}
+
+// listFeaturesHandler returns whether each feature is enabled.
+func (app *application) listFeaturesHandler(w http.ResponseWriter, r *http.Request) {
+	err := app.writeJSON(w, http.StatusOK, envelope{"features": app.features.all()}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateFeatureHandler switches a feature on or off. The change takes effect immediately on this
+// instance and is stored so other instances pick it up when they next start.
+func (app *application) updateFeatureHandler(w http.ResponseWriter, r *http.Request) {
+	name := httprouter.ParamsFromContext(r.Context()).ByName("name")
+	if !validator.In(name, knownFeatures...) {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		Enabled *bool `json:"enabled"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(input.Enabled != nil, "enabled", "must be provided")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Features.Set(name, *input.Enabled)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.features.set(name, *input.Enabled)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"feature": envelope{"name": name, "enabled": *input.Enabled}}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
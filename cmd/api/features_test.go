@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+func TestFeatureFlagsParse(t *testing.T) {
+	f := newFeatureFlags()
+
+	if err := f.parse(" match_import=false, webhooks=true,,"); err != nil {
+		t.Fatal(err)
+	}
+	if f.isEnabled(featureMatchImport) || !f.isEnabled(featureWebhooks) || !f.isEnabled(featureRoleDrift) {
+		t.Errorf("got features %v, want only match_import disabled", f.all())
+	}
+
+	for _, spec := range []string{"match_import", "match_import=maybe", "teleport=true"} {
+		if err := newFeatureFlags().parse(spec); err == nil {
+			t.Errorf("parse(%q): got no error", spec)
+		}
+	}
+
+	if f.isEnabled("teleport") {
+		t.Error("got an unknown feature enabled")
+	}
+}
+
+func TestRequireFeature(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		features: newFeatureFlags(),
+	}
+
+	handler := app.requireFeature(featureSimilarChampions, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func() int {
+		r := httptest.NewRequest(http.MethodGet, "/v1/champions/1/similar", nil)
+		rr := httptest.NewRecorder()
+		handler(rr, app.contextSetUser(r, data.AnonymousUser))
+		return rr.Code
+	}
+
+	if got := request(); got != http.StatusOK {
+		t.Errorf("got status %d with the feature enabled, want %d", got, http.StatusOK)
+	}
+
+	// A disabled feature's endpoints act as if they didn't exist.
+	app.features.set(featureSimilarChampions, false)
+	if got := request(); got != http.StatusNotFound {
+		t.Errorf("got status %d with the feature disabled, want %d", got, http.StatusNotFound)
+	}
+
+	app.features.set(featureSimilarChampions, true)
+	if got := request(); got != http.StatusOK {
+		t.Errorf("got status %d with the feature re-enabled, want %d", got, http.StatusOK)
+	}
+}
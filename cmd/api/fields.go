@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// fieldSet is the set of JSON fields a client asked for with ?fields=. A nil fieldSet selects
+// every field.
+type fieldSet []string
+
+// jsonFields returns the JSON names of the fields of a struct (or pointer to struct) value, as
+// they would be encoded by encoding/json.
+func jsonFields(value interface{}) []string {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// The readFields() helper reads a comma-separated list of field names from the ?fields=
+// parameter. Names which aren't in known are dropped with a warning rather than rejected, so a
+// client built against a newer API version still gets the fields we do have.
+func (app *application) readFields(qs url.Values, known []string, v *validator.Validator) fieldSet {
+	s := qs.Get("fields")
+	if s == "" {
+		return nil
+	}
+
+	fields := fieldSet{}
+	var unknown []string
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case validator.In(name, known...):
+			fields = append(fields, name)
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		v.AddWarning("fields", "unknown fields ignored: "+strings.Join(unknown, ", "))
+	}
+
+	return fields
+}
+
+// apply restricts a value to the selected fields. The value is encoded to JSON and decoded into
+// generic maps, so it may be a struct or a slice of structs; for a slice, each element is
+// restricted. Without a selection the value is returned unchanged.
+func (f fieldSet) apply(value interface{}) (interface{}, error) {
+	if f == nil {
+		return value, nil
+	}
+
+	js, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use json.Number so numbers are written back exactly as they were encoded.
+	var generic interface{}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	switch v := generic.(type) {
+	case map[string]interface{}:
+		return f.filter(v), nil
+	case []interface{}:
+		for i, element := range v {
+			if object, ok := element.(map[string]interface{}); ok {
+				v[i] = f.filter(object)
+			}
+		}
+	}
+
+	return generic, nil
+}
+
+// filter returns a copy of object holding only the selected fields.
+func (f fieldSet) filter(object map[string]interface{}) map[string]interface{} {
+	filtered := make(map[string]interface{}, len(f))
+	for _, name := range f {
+		if value, ok := object[name]; ok {
+			filtered[name] = value
+		}
+	}
+	return filtered
+}
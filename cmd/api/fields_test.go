@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+type fieldsTestValue struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	WinRate  float64 `json:"winRate,omitempty"`
+	Password string  `json:"-"`
+	Untagged string
+	internal string
+}
+
+func TestJSONFields(t *testing.T) {
+	want := []string{"id", "name", "winRate", "Untagged"}
+
+	if got := jsonFields(&fieldsTestValue{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestReadFields(t *testing.T) {
+	known := []string{"id", "name", "winRate"}
+
+	tests := []struct {
+		value string
+		want  fieldSet
+		warn  bool
+	}{
+		{"", nil, false},
+		{"id,name", fieldSet{"id", "name"}, false},
+		{" id , winRate ,", fieldSet{"id", "winRate"}, false},
+		{"id,password", fieldSet{"id"}, true},
+		// Only unknown fields still select nothing, rather than everything.
+		{"password", fieldSet{}, true},
+	}
+
+	app := &application{}
+
+	for _, tt := range tests {
+		v := validator.New()
+		got := app.readFields(url.Values{"fields": {tt.value}}, known, v)
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("readFields(%q) = %#v, want %#v", tt.value, got, tt.want)
+		}
+		if _, warned := v.Warnings["fields"]; warned != tt.warn {
+			t.Errorf("readFields(%q): got warnings %v, want warning=%t", tt.value, v.Warnings, tt.warn)
+		}
+	}
+}
+
+func TestFieldSetApply(t *testing.T) {
+	value := &fieldsTestValue{ID: 9007199254740993, Name: "Ahri", WinRate: 0.52}
+
+	tests := []struct {
+		fields fieldSet
+		value  interface{}
+		want   string
+	}{
+		{nil, value, `{"id":9007199254740993,"name":"Ahri","winRate":0.52,"Untagged":""}`},
+		{fieldSet{"id", "winRate"}, value, `{"id":9007199254740993,"winRate":0.52}`},
+		{fieldSet{}, value, `{}`},
+		{fieldSet{"name"}, []*fieldsTestValue{value, {Name: "Syndra"}}, `[{"name":"Ahri"},{"name":"Syndra"}]`},
+		// Fields left out by omitempty stay out.
+		{fieldSet{"name", "winRate"}, &fieldsTestValue{Name: "Syndra"}, `{"name":"Syndra"}`},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.fields.apply(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		js, err := json.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(js) != tt.want {
+			t.Errorf("fields %q: got %s, want %s", tt.fields, js, tt.want)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"league_of_graphs.satellite.net/internal/data"
+	leaguev1 "league_of_graphs.satellite.net/internal/rpc/leaguev1"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// readServer implements the gRPC ReadService. Each method is a thin layer over the model method
+// used by the matching HTTP handler, with its parameters read through the same helpers as the
+// query string, so both APIs apply the same defaults and validation and return the same data.
+// Callers are treated like anonymous HTTP clients, so private summoners only show their public
+// fields.
+type readServer struct {
+	leaguev1.UnimplementedReadServiceServer
+	app *application
+}
+
+// newGRPCServer returns a gRPC server with the ReadService registered.
+func (app *application) newGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	srv := grpc.NewServer(append(opts, grpc.UnaryInterceptor(app.grpcRecoverPanic))...)
+	leaguev1.RegisterReadServiceServer(srv, &readServer{app: app})
+	return srv
+}
+
+// grpcRecoverPanic is the gRPC counterpart of the recoverPanic middleware: a panicking method
+// returns an Internal error rather than taking down the server.
+func (app *application) grpcRecoverPanic(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			app.logger.PrintError(fmt.Errorf("%s", p), map[string]string{"grpc_method": info.FullMethod})
+			err = status.Error(codes.Internal, "the server encountered a problem and could not process your request")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func (s *readServer) GetChampion(ctx context.Context, req *leaguev1.GetChampionRequest) (*leaguev1.Champion, error) {
+	champion, err := s.app.models.Champions.Get(req.GetId())
+	if err != nil {
+		return nil, s.error("GetChampion", err)
+	}
+
+	s.app.metaThresholds().Apply(champion)
+
+	return championMessage(champion), nil
+}
+
+func (s *readServer) ListChampions(ctx context.Context, req *leaguev1.ListChampionsRequest) (*leaguev1.ListChampionsResponse, error) {
+	v := validator.New()
+
+	qs := filterValues(req.GetFilters())
+	filters := s.app.readFilters(qs, "id", championSortSafelist, v)
+
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		return nil, validationError(v.Errors)
+	}
+
+	champions, metadata, err := s.app.models.Champions.GetAll(req.GetName(), req.GetMainRole(), req.GetMetaOnly(), s.app.metaThresholds(), nil, nil, time.Time{}, data.StatsScope{}, filters)
+	if err != nil {
+		return nil, s.error("ListChampions", err)
+	}
+
+	if err := s.checkPageInRange(metadata); err != nil {
+		return nil, err
+	}
+
+	resp := &leaguev1.ListChampionsResponse{Metadata: metadataMessage(metadata)}
+	for _, champion := range champions {
+		resp.Champions = append(resp.Champions, championMessage(champion))
+	}
+
+	return resp, nil
+}
+
+func (s *readServer) GetSummoner(ctx context.Context, req *leaguev1.GetSummonerRequest) (*leaguev1.Summoner, error) {
+	summoner, err := s.app.models.Summoners.Get(req.GetId())
+	if err != nil {
+		return nil, s.error("GetSummoner", err)
+	}
+
+	return summonerMessage(summoner), nil
+}
+
+func (s *readServer) ListSummoners(ctx context.Context, req *leaguev1.ListSummonersRequest) (*leaguev1.ListSummonersResponse, error) {
+	v := validator.New()
+
+	qs := filterValues(req.GetFilters())
+	qs.Set("username", req.GetUsername())
+	qs.Set("region", req.GetRegion())
+	qs.Set("q", req.GetSearch())
+	qs.Set("min_rating", strconv.Itoa(int(req.GetMinRating())))
+
+	filter := s.app.readSummonerFilter(qs, v)
+	filters := s.app.readFilters(qs, "id", summonerSortSafelist, v)
+
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		return nil, validationError(v.Errors)
+	}
+
+	summoners, metadata, err := s.app.models.Summoners.GetAll(filter, false, filters)
+	if err != nil {
+		return nil, s.error("ListSummoners", err)
+	}
+
+	if err := s.checkPageInRange(metadata); err != nil {
+		return nil, err
+	}
+
+	resp := &leaguev1.ListSummonersResponse{Metadata: metadataMessage(metadata)}
+	for _, summoner := range summoners {
+		resp.Summoners = append(resp.Summoners, summonerMessage(summoner))
+	}
+
+	return resp, nil
+}
+
+func (s *readServer) GetMatch(ctx context.Context, req *leaguev1.GetMatchRequest) (*leaguev1.Match, error) {
+	match, err := s.app.models.Matches.Get(req.GetId())
+	if err != nil {
+		return nil, s.error("GetMatch", err)
+	}
+
+	return matchMessage(match), nil
+}
+
+func (s *readServer) ListMatches(ctx context.Context, req *leaguev1.ListMatchesRequest) (*leaguev1.ListMatchesResponse, error) {
+	v := validator.New()
+
+	qs := filterValues(req.GetFilters())
+	qs.Set("tag", req.GetTag())
+
+	filter := s.app.readMatchFilter(qs, v)
+	filters := s.app.readFilters(qs, "id", matchSortSafelist, v)
+
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		return nil, validationError(v.Errors)
+	}
+
+	matches, metadata, err := s.app.models.Matches.GetAll(filter, filters)
+	if err != nil {
+		return nil, s.error("ListMatches", err)
+	}
+
+	if err := s.checkPageInRange(metadata); err != nil {
+		return nil, err
+	}
+
+	resp := &leaguev1.ListMatchesResponse{Metadata: metadataMessage(metadata)}
+	for _, match := range matches {
+		resp.Matches = append(resp.Matches, matchMessage(match))
+	}
+
+	return resp, nil
+}
+
+// error converts a model error into a gRPC status. A missing record is NotFound; anything else
+// is logged and returned as Internal, without its details, like serverErrorResponse.
+func (s *readServer) error(method string, err error) error {
+	if errors.Is(err, data.ErrRecordNotFound) {
+		return status.Error(codes.NotFound, "the requested resource could not be found")
+	}
+
+	s.app.logger.PrintError(err, map[string]string{"grpc_method": method})
+	return status.Error(codes.Internal, "the server encountered a problem and could not process your request")
+}
+
+// checkPageInRange returns NotFound for a page past the last one, when the server is configured to
+// reject those over HTTP too.
+func (s *readServer) checkPageInRange(metadata data.Metadata) error {
+	if metadata.OutOfRange && s.app.config.pagination.outOfRange == pageOutOfRangeNotFound {
+		return status.Errorf(codes.NotFound, "page %d is out of range; the last page is %d", metadata.CurrentPage, metadata.LastPage)
+	}
+	return nil
+}
+
+// validationError returns an InvalidArgument status listing the validation errors, ordered by
+// field so the message is stable.
+func validationError(errs map[string]string) error {
+	fields := make([]string, 0, len(errs))
+	for field := range errs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field + ": " + errs[field]
+	}
+
+	return status.Error(codes.InvalidArgument, strings.Join(messages, "; "))
+}
+
+// filterValues returns the paging and sorting filters as the query string parameters read by
+// readFilters. Unset fields are left out, so they take the same defaults.
+func filterValues(f *leaguev1.Filters) url.Values {
+	qs := url.Values{}
+
+	if f.GetPage() != 0 {
+		qs.Set("page", strconv.Itoa(int(f.GetPage())))
+	}
+	if f.GetPageSize() != 0 {
+		qs.Set("page_size", strconv.Itoa(int(f.GetPageSize())))
+	}
+	if f.GetSort() != "" {
+		qs.Set("sort", f.GetSort())
+	}
+
+	return qs
+}
+
+func metadataMessage(m data.Metadata) *leaguev1.Metadata {
+	return &leaguev1.Metadata{
+		CurrentPage:  int32(m.CurrentPage),
+		PageSize:     int32(m.PageSize),
+		FirstPage:    int32(m.FirstPage),
+		LastPage:     int32(m.LastPage),
+		TotalRecords: int32(m.TotalRecords),
+		OutOfRange:   m.OutOfRange,
+	}
+}
+
+func kdaMessage(k data.KDA) *leaguev1.KDA {
+	return &leaguev1.KDA{Kills: int32(k.Kills), Deaths: int32(k.Deaths), Assists: int32(k.Assists)}
+}
+
+func championMessage(c *data.Champion) *leaguev1.Champion {
+	return &leaguev1.Champion{
+		Id:         c.ID,
+		Name:       c.Name,
+		MainRole:   c.MainRole,
+		ImageUrl:   c.ImageURL,
+		Popularity: c.Popularity,
+		WinRate:    c.WinRate,
+		BanRate:    c.BanRate,
+		IsMeta:     c.IsMeta,
+		Version:    c.Version,
+	}
+}
+
+// summonerMessage converts the summoner as an anonymous viewer may see it: private summoners only
+// keep their public fields.
+func summonerMessage(s *data.Summoner) *leaguev1.Summoner {
+	msg := &leaguev1.Summoner{
+		Id:        s.ID,
+		Username:  s.Username,
+		Region:    s.Region,
+		IsPrivate: s.IsPrivate,
+	}
+
+	if (summonerViewer{}).canView(s) {
+		msg.Rating = int32(s.Rating)
+		msg.CountOfPlayedGames = int32(s.CountOfPlayedGames)
+		msg.WinRate = s.WinRate
+		msg.AverageKda = kdaMessage(s.AverageKDA)
+	}
+
+	return msg
+}
+
+func teamMessage(t *data.Team) *leaguev1.Team {
+	if t == nil {
+		return nil
+	}
+
+	return &leaguev1.Team{
+		TeamKda:             kdaMessage(t.TeamKDA),
+		TurretsDestroyed:    int32(t.TurretsDestroyed),
+		InhibitorsDestroyed: int32(t.InhibitorsDestroyed),
+		RiftHeraldsKilled:   int32(t.RiftHeraldsKilled),
+		DragonsKilled:       int32(t.DragonsKilled),
+		BaronNashorsKilled:  int32(t.BaronNashorsKilled),
+		TotalNetWorth:       int32(t.TotalNetWorth),
+		TotalGold:           int32(t.TotalGold),
+		FirstBlood:          t.FirstBlood,
+		FirstTower:          t.FirstTower,
+		FirstDragon:         t.FirstDragon,
+	}
+}
+
+func matchMessage(m *data.Match) *leaguev1.Match {
+	return &leaguev1.Match{
+		Id:         m.ID,
+		PlayedDate: timestamppb.New(m.PlayedDate),
+		Duration:   int32(m.Duration),
+		Result:     m.Result,
+		MatchType:  m.MatchType,
+		BlueTeam:   teamMessage(m.BlueTeam),
+		RedTeam:    teamMessage(m.RedTeam),
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+	leaguev1 "league_of_graphs.satellite.net/internal/rpc/leaguev1"
+)
+
+// newGRPCTestClient serves the app's gRPC API over an in-memory connection and returns a client
+// for it.
+func newGRPCTestClient(t *testing.T, app *application) leaguev1.ReadServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := app.newGRPCServer()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return leaguev1.NewReadServiceClient(conn)
+}
+
+// newTestDB connects to the Postgres database named by TEST_DATABASE_DSN and rebuilds its schema
+// from the migrations. Tests which call it are skipped when TEST_DATABASE_DSN isn't set. The
+// database is wiped, so never point it at one holding data you want to keep.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// Drop leaves the migrate instance unusable for Up, so migrate up with a fresh one.
+	for _, step := range []func(*migrate.Migrate) error{(*migrate.Migrate).Drop, (*migrate.Migrate).Up} {
+		driver, err := postgres.WithInstance(db, &postgres.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := migrate.NewWithDatabaseInstance("file://../../migrations", "postgres", driver)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := step(m); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			t.Fatal(err)
+		}
+	}
+
+	return db
+}
+
+func TestGRPCErrors(t *testing.T) {
+	app := &application{logger: jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo)}
+	app.config.pagination.maxPageSize = data.DefaultMaxPageSize
+	client := newGRPCTestClient(t, app)
+
+	// IDs below one are never looked up, so no database is needed.
+	_, err := client.GetChampion(context.Background(), &leaguev1.GetChampionRequest{Id: 0})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("GetChampion: got %v, want NotFound", err)
+	}
+
+	_, err = client.ListChampions(context.Background(), &leaguev1.ListChampionsRequest{
+		Filters: &leaguev1.Filters{Sort: "password"},
+	})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("ListChampions: got %v, want InvalidArgument", err)
+	}
+
+	_, err = client.ListMatches(context.Background(), &leaguev1.ListMatchesRequest{
+		Filters: &leaguev1.Filters{PageSize: data.DefaultMaxPageSize + 1},
+	})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("ListMatches: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestSummonerMessagePrivacy(t *testing.T) {
+	summoner := &data.Summoner{ID: 1, Username: "Faker", Region: "KR", Rating: 3000, CountOfPlayedGames: 10, WinRate: 0.6}
+
+	msg := summonerMessage(summoner)
+	if msg.Rating != 3000 || msg.CountOfPlayedGames != 10 || msg.WinRate != 0.6 {
+		t.Errorf("public summoner: got %v, want its stats", msg)
+	}
+
+	summoner.IsPrivate = true
+
+	msg = summonerMessage(summoner)
+	if msg.Rating != 0 || msg.CountOfPlayedGames != 0 || msg.WinRate != 0 || msg.AverageKda != nil {
+		t.Errorf("private summoner: got %v, want only its public fields", msg)
+	}
+	if msg.Username != "Faker" || msg.Region != "KR" || !msg.IsPrivate {
+		t.Errorf("private summoner: got %v, want its public fields", msg)
+	}
+}
+
+func TestGRPCGetChampionMatchesHTTP(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+	app.config.envelopeStyle = envelopeStyleTyped
+
+	champion := &data.Champion{Name: "Ahri", MainRole: "Mid", ImageURL: "https://example.com/ahri.png", Classes: data.ChampionClasses{}}
+	if err := app.models.Champions.Insert(champion); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := newGRPCTestClient(t, app).GetChampion(context.Background(), &leaguev1.GetChampionRequest{Id: champion.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	app.router().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/champions/"+strconv.FormatInt(champion.ID, 10), nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got HTTP status %d: %s", rr.Code, rr.Body)
+	}
+
+	var body struct {
+		Champion struct {
+			ID         int64   `json:"id"`
+			Name       string  `json:"name"`
+			MainRole   string  `json:"mainRole"`
+			ImageURL   string  `json:"imageUrl"`
+			Popularity float64 `json:"popularity"`
+			WinRate    float64 `json:"winRate"`
+			BanRate    float64 `json:"banRate"`
+			IsMeta     bool    `json:"isMeta"`
+			Version    int32   `json:"version"`
+		} `json:"champion"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	want := body.Champion
+	if got.Id != want.ID || got.Name != want.Name || got.MainRole != want.MainRole || got.ImageUrl != want.ImageURL ||
+		got.Popularity != want.Popularity || got.WinRate != want.WinRate || got.BanRate != want.BanRate ||
+		got.IsMeta != want.IsMeta || got.Version != want.Version {
+		t.Errorf("gRPC returned %v, HTTP returned %+v", got, want)
+	}
+}
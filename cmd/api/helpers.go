@@ -1,16 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"league_of_graphs.satellite.net/internal/data"
 	"league_of_graphs.satellite.net/internal/validator"
 )
 
@@ -27,13 +32,34 @@ func (app *application) readIDParam(r *http.Request) (int64, error) {
 
 type envelope map[string]interface{}
 
+// errResponseTooLarge is returned by writeJSON when the encoded response is larger than
+// -max-response-bytes.
+var errResponseTooLarge = errors.New("response too large")
+
 // Change the data parameter to have the type envelope instead of interface{}.
+//
+// Responses larger than -max-response-bytes aren't written; an error wrapping errResponseTooLarge
+// is returned instead, so that the handler's usual serverErrorResponse call sends a 500. This
+// guards against a large page of matches (each with a full roster) turning into a multi-megabyte
+// body. Streamed responses (see streamJSON) don't go through writeJSON and so are exempt.
+//
+// List values in the envelope are always encoded as arrays, never null: see emptySlices.
 func (app *application) writeJSON(w http.ResponseWriter, status int, data envelope, headers http.Header) error {
-	js, err := json.MarshalIndent(data, "", "\t")
+	value, err := app.transformJSON(app.styleEnvelope(emptySlices(data)))
+	if err != nil {
+		return err
+	}
+
+	js, err := json.MarshalIndent(value, "", "\t")
 	if err != nil {
 		return err
 	}
 	js = append(js, '\n')
+
+	if max := app.config.maxResponseBytes; max > 0 && len(js) > max {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d", errResponseTooLarge, len(js), max)
+	}
+
 	for key, value := range headers {
 		w.Header()[key] = value
 	}
@@ -43,7 +69,31 @@ func (app *application) writeJSON(w http.ResponseWriter, status int, data envelo
 	return nil
 }
 
+// emptySlices replaces each nil slice in the envelope with an empty slice of the same type, so
+// that an empty list is encoded as [] rather than null and clients can rely on every list value
+// being an array. Handlers therefore don't have to remember to initialize their slices; only the
+// envelope's own values are replaced, not slices nested inside them.
+func emptySlices(env envelope) envelope {
+	for key, value := range env {
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			env[key] = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+		}
+	}
+	return env
+}
+
 func (app *application) readJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	return app.decodeJSON(w, r, dst, true)
+}
+
+// readExternalJSON is like readJSON, but ignores any fields which can't be mapped to dst. It's
+// for documents in formats we don't control, where we only read the fields we need.
+func (app *application) readExternalJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	return app.decodeJSON(w, r, dst, false)
+}
+
+func (app *application) decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}, strict bool) error {
 	// Use http.MaxBytesReader() to limit the size of the request body to 1MB.
 	maxBytes := 1_048_576
 	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
@@ -52,7 +102,9 @@ func (app *application) readJSON(w http.ResponseWriter, r *http.Request, dst int
 	// field which cannot be mapped to the target destination, the decoder will return
 	// an error instead of just ignoring the field.
 	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
+	if strict {
+		dec.DisallowUnknownFields()
+	}
 	// Decode the request body to the destination.
 	err := dec.Decode(dst)
 	if err != nil {
@@ -102,6 +154,48 @@ func (app *application) readJSON(w http.ResponseWriter, r *http.Request, dst int
 	return nil
 }
 
+// isMergePatch reports whether the request body is a JSON merge patch (RFC 7386).
+func isMergePatch(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/merge-patch+json"
+}
+
+// readMergePatch reads a JSON merge patch (RFC 7386) from the request body. Each member of the
+// patch is decoded into the destination registered for its key in fields; a member which is
+// explicitly null is left undecoded and its key is returned in nulls instead, so that callers
+// can tell a field being cleared apart from one which was simply absent.
+func (app *application) readMergePatch(w http.ResponseWriter, r *http.Request, fields map[string]interface{}) (nulls map[string]bool, err error) {
+	var patch map[string]json.RawMessage
+
+	err = app.readJSON(w, r, &patch)
+	if err != nil {
+		return nil, err
+	}
+	if patch == nil {
+		return nil, errors.New("body must be a JSON object")
+	}
+
+	nulls = make(map[string]bool)
+
+	for key, raw := range patch {
+		dst, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("body contains unknown key %q", key)
+		}
+
+		if string(bytes.TrimSpace(raw)) == "null" {
+			nulls[key] = true
+			continue
+		}
+
+		if err := json.Unmarshal(raw, dst); err != nil {
+			return nil, fmt.Errorf("body contains incorrect JSON type for field %q", key)
+		}
+	}
+
+	return nulls, nil
+}
+
 func (app *application) readString(qs url.Values, key string, defaultValue string) string {
 	// Extract the value for a given key from the query string. If no key exists this
 	// will return the empty string "".
@@ -135,3 +229,351 @@ func (app *application) readInt(qs url.Values, key string, defaultValue int, v *
 	// Otherwise, return the converted integer value.
 	return i
 }
+
+// The readBool() helper reads a string value from the query string and converts it to a
+// boolean before returning. If no matching key could be found it returns the provided
+// default value. If the value couldn't be converted to a boolean, then we record an
+// error message in the provided Validator instance.
+func (app *application) readBool(qs url.Values, key string, defaultValue bool, v *validator.Validator) bool {
+	s := qs.Get(key)
+	if s == "" {
+		return defaultValue
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		v.AddError(key, "must be a boolean value")
+		return defaultValue
+	}
+
+	return b
+}
+
+// streamJSON writes a JSON object of the form {"<key>": [...]}, encoding each element of the array
+// as soon as produce emits it, so the full result set never has to be held in memory. Because the
+// status code and the start of the array have already been sent by the time produce runs, an
+// error from produce can't be turned into an error response; instead the array is closed and an
+// "error" key is appended to the object, and the error is returned so the caller can log it.
+func (app *application) streamJSON(w http.ResponseWriter, key string, produce func(emit func(interface{}) error) error) error {
+	flusher, _ := w.(http.Flusher)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if app.config.envelopeStyle == envelopeStyleData {
+		key = "data"
+	}
+
+	prefix, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "{%s:[", prefix)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	count := 0
+
+	err = produce(func(value interface{}) error {
+		if count > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		value, err := app.transformJSON(value)
+		if err != nil {
+			return err
+		}
+
+		if err := enc.Encode(value); err != nil {
+			return err
+		}
+
+		count++
+
+		// Flush periodically so the client starts receiving data straight away, without paying
+		// the cost of a flush for every element.
+		if flusher != nil && count%100 == 0 {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(w, "],%q:%q}\n", "error", "the server encountered a problem and could not finish the response")
+		return err
+	}
+
+	_, err = io.WriteString(w, "]}\n")
+	return err
+}
+
+// Envelope styles, chosen with -envelope-style. In the typed style (the default) each response
+// names its payload after what it contains, e.g. {"champion": {...}}; in the data style every
+// payload is under {"data": ...} instead.
+const (
+	envelopeStyleTyped = "typed"
+	envelopeStyleData  = "data"
+)
+
+// Ways of answering a request for a page beyond the last page of a list, chosen with
+// -page-out-of-range. By default the (empty) page is returned with outOfRange set in its
+// metadata; alternatively the request can fail with a 404.
+const (
+	pageOutOfRangeFlag     = "flag"
+	pageOutOfRangeNotFound = "404"
+)
+
+// Ways of handling a page_size above -max-page-size, chosen with -page-size-mode.
+const (
+	pageSizeModeStrict = "strict"
+	pageSizeModeClamp  = "clamp"
+)
+
+// envelopeMetaKeys are the envelope keys which hold information about a response rather than its
+// payload. They stay at the top level in every envelope style.
+var envelopeMetaKeys = map[string]bool{
+	"_links":   true,
+	"metadata": true,
+	"warnings": true,
+	"error":    true,
+}
+
+// styleEnvelope rewrites an envelope into the configured style. In the data style, a response
+// with a single payload key has that value moved to "data" ({"champion": c} becomes
+// {"data": c}); a response with several payload keys has them all moved into an object under
+// "data". Meta keys such as "_links" and "error" are left where they are.
+func (app *application) styleEnvelope(env envelope) envelope {
+	if app.config.envelopeStyle != envelopeStyleData {
+		return env
+	}
+
+	styled := envelope{}
+	payload := map[string]interface{}{}
+
+	for key, value := range env {
+		if envelopeMetaKeys[key] {
+			styled[key] = value
+			continue
+		}
+		payload[key] = value
+	}
+
+	switch len(payload) {
+	case 0:
+	case 1:
+		for _, value := range payload {
+			styled["data"] = value
+		}
+	default:
+		styled["data"] = payload
+	}
+
+	return styled
+}
+
+// transformJSON applies any configured response transformations to a value before it's encoded.
+// Currently the only transformation is -json-string-ids, which re-encodes every ID field as a
+// string so that JavaScript clients don't lose precision on IDs above 2^53. When no
+// transformation is enabled the value is returned unchanged.
+func (app *application) transformJSON(value interface{}) (interface{}, error) {
+	if !app.config.jsonStringIDs {
+		return value, nil
+	}
+
+	js, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode into generic values, using json.Number so the IDs are kept exactly as they were
+	// encoded rather than passing through a float64.
+	var generic interface{}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return stringifyIDs(generic), nil
+}
+
+// stringifyIDs walks a decoded JSON value, replacing numeric values of ID fields ("id", and any
+// key ending in "Id", "ID" or "_id") with their string form. Arrays of IDs (under "ids", or a key
+// ending in "Ids", "IDs" or "_ids") have their numeric elements replaced in the same way.
+func stringifyIDs(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if number, ok := child.(json.Number); ok && isIDKey(key) {
+				v[key] = number.String()
+				continue
+			}
+			if elements, ok := child.([]interface{}); ok && isIDsKey(key) {
+				for i, element := range elements {
+					if number, ok := element.(json.Number); ok {
+						elements[i] = number.String()
+					}
+				}
+				continue
+			}
+			v[key] = stringifyIDs(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = stringifyIDs(child)
+		}
+	}
+
+	return value
+}
+
+// isIDKey reports whether a JSON key names an ID field.
+func isIDKey(key string) bool {
+	return key == "id" || strings.HasSuffix(key, "Id") || strings.HasSuffix(key, "ID") || strings.HasSuffix(key, "_id")
+}
+
+// isIDsKey reports whether a JSON key names an array of IDs.
+func isIDsKey(key string) bool {
+	return key == "ids" || strings.HasSuffix(key, "Ids") || strings.HasSuffix(key, "IDs") || strings.HasSuffix(key, "_ids")
+}
+
+// The readDate() helper reads a date in YYYY-MM-DD (or full RFC 3339) format from the query
+// string. If no matching key could be found it returns the zero time. If the value couldn't be
+// parsed, then we record an error message in the provided Validator instance.
+func (app *application) readDate(qs url.Values, key string, v *validator.Validator) time.Time {
+	s := qs.Get(key)
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t
+	}
+
+	t, err = time.Parse("2006-01-02", s)
+	if err != nil {
+		v.AddError(key, "must be a date in YYYY-MM-DD format")
+		return time.Time{}
+	}
+
+	return t
+}
+
+// The readPeriod() helper reads a length of time given as a whole number of days or weeks, e.g.
+// "7d" or "2w", from the query string. If no matching key could be found it returns zero. If the
+// value couldn't be parsed or is out of range, then we record an error message in the provided
+// Validator instance.
+func (app *application) readPeriod(qs url.Values, key string, v *validator.Validator) time.Duration {
+	s := qs.Get(key)
+	if s == "" {
+		return 0
+	}
+
+	day := 24 * time.Hour
+	unit := map[byte]time.Duration{'d': day, 'w': 7 * day}[s[len(s)-1]]
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if unit == 0 || err != nil {
+		v.AddError(key, "must be a number of days or weeks, such as 7d or 2w")
+		return 0
+	}
+
+	period := time.Duration(n) * unit
+	if period < day || period > 365*day {
+		v.AddError(key, "must be between 1 day and 365 days")
+		return 0
+	}
+
+	return period
+}
+
+// descendingSorts lists the sort columns whose natural order is descending, so that a bare
+// ?sort=win_rate puts the highest win rates first. A "+" prefix still sorts them ascending.
+var descendingSorts = []string{"popularity", "win_rate", "ban_rate"}
+
+// The readFilters() helper reads the ?page=, ?page_size= and ?sort= parameters of a list endpoint,
+// sorting by defaultSort if no sort is given. A page size above -max-page-size is either left for
+// ValidateFilters to reject (the strict mode) or reduced to the maximum with a warning (the clamp
+// mode), depending on -page-size-mode.
+func (app *application) readFilters(qs url.Values, defaultSort string, sortSafelist []string, v *validator.Validator) data.Filters {
+	filters := data.Filters{
+		Page:            app.readInt(qs, "page", 1, v),
+		PageSize:        app.readInt(qs, "page_size", 20, v),
+		MaxPageSize:     app.config.pagination.maxPageSize,
+		Sort:            app.readString(qs, "sort", defaultSort),
+		SortSafelist:    sortSafelist,
+		DescendingSorts: descendingSorts,
+	}
+
+	// An unescaped "+" prefix on the sort decodes as a space. Treat a leading space as the "+"
+	// the client meant.
+	if trimmed := strings.TrimLeft(filters.Sort, " "); trimmed != filters.Sort {
+		filters.Sort = "+" + trimmed
+	}
+
+	if app.config.pagination.pageSizeMode == pageSizeModeClamp {
+		filters.ClampPageSize(v)
+	}
+
+	return filters
+}
+
+// The readStatsScope() helper reads the optional ?match_type= and ?rank= parameters used to scope
+// champion statistics. A missing parameter leaves that part of the scope unrestricted; invalid
+// values are recorded in the Validator.
+func (app *application) readStatsScope(qs url.Values, v *validator.Validator) data.StatsScope {
+	var scope data.StatsScope
+
+	scope.MatchType = strings.ToLower(strings.TrimSpace(qs.Get("match_type")))
+	if scope.MatchType != "" && !validator.In(scope.MatchType, data.MatchTypes...) {
+		v.AddError("match_type", "must be one of solo_queue, pro or tournament")
+	}
+
+	// An unescaped "+" in a query string decodes as a space, so "?rank=Diamond+" arrives as
+	// "Diamond ". Treat a trailing space as the "+" the client meant.
+	if rank := qs.Get("rank"); rank != "" {
+		if strings.HasSuffix(rank, " ") {
+			rank = strings.TrimRight(rank, " ") + "+"
+		}
+
+		tiers, err := data.ParseRankFilter(rank)
+		if err != nil {
+			v.AddError("rank", "must be a rank tier such as Gold, optionally followed by + for that tier and above")
+		}
+		scope.Tiers = tiers
+	}
+
+	return scope
+}
+
+// setCacheControl sets the Cache-Control header for a successful read. Data which rarely changes
+// can be cached by clients and CDNs for maxAge; a maxAge of zero marks the response as not
+// cacheable at all.
+func (app *application) setCacheControl(w http.ResponseWriter, maxAge time.Duration) {
+	if maxAge <= 0 {
+		w.Header().Set("Cache-Control", "no-store")
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+}
+
+// background runs fn in a new goroutine. Any panic in fn is recovered and logged, rather than
+// terminating the application.
+func (app *application) background(fn func()) {
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				app.logger.PrintError(fmt.Errorf("%s", err), nil)
+			}
+		}()
+
+		fn()
+	}()
+}
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/errreport"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func TestReadPeriod(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		valid bool
+	}{
+		{"", 0, true},
+		{"7d", 7 * day, true},
+		{"2w", 14 * day, true},
+		{"1d", day, true},
+		{"365d", 365 * day, true},
+		{"0d", 0, false},
+		{"366d", 0, false},
+		{"53w", 0, false},
+		{"7", 0, false},
+		{"7h", 0, false},
+		{"d", 0, false},
+		{"-1w", 0, false},
+	}
+
+	app := &application{}
+
+	for _, tt := range tests {
+		v := validator.New()
+		got := app.readPeriod(url.Values{"period": {tt.value}}, "period", v)
+
+		if got != tt.want || v.Valid() != tt.valid {
+			t.Errorf("readPeriod(%q) = %v with errors %v, want %v and valid=%t", tt.value, got, v.Errors, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestReadFiltersSortPrefix(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "id"},
+		{"sort=-win_rate", "-win_rate"},
+		{"sort=%2Bwin_rate", "+win_rate"},
+		// An unescaped "+" decodes as a space, which is read as the "+" the client meant.
+		{"sort=+win_rate", "+win_rate"},
+	}
+
+	app := &application{}
+
+	for _, tt := range tests {
+		qs, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v := validator.New()
+		if got := app.readFilters(qs, "id", championSortSafelist, v); got.Sort != tt.want {
+			t.Errorf("readFilters(%q): got sort %q, want %q", tt.query, got.Sort, tt.want)
+		}
+	}
+}
+
+func TestWriteJSONMaxResponseBytes(t *testing.T) {
+	app := &application{
+		logger:        jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		errorReporter: errreport.Nop{},
+	}
+	app.config.maxResponseBytes = 100
+
+	small := envelope{"champion": "Ahri"}
+	large := envelope{"matches": strings.Repeat("x", 200)}
+
+	rr := httptest.NewRecorder()
+	if err := app.writeJSON(rr, http.StatusOK, small, nil); err != nil {
+		t.Fatalf("small response: %v", err)
+	}
+
+	rr = httptest.NewRecorder()
+	err := app.writeJSON(rr, http.StatusOK, large, nil)
+	if !errors.Is(err, errResponseTooLarge) {
+		t.Fatalf("got error %v, want errResponseTooLarge", err)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("got %d bytes written for an oversized response, want none", rr.Body.Len())
+	}
+
+	// The handler's serverErrorResponse then sends a 500 saying why.
+	r := httptest.NewRequest(http.MethodGet, "/v1/matches", nil)
+	app.serverErrorResponse(rr, r, err)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want %d", rr.Code, http.StatusInternalServerError)
+	}
+	if !strings.Contains(rr.Body.String(), "too large") {
+		t.Errorf("got body %s, want it to say the response is too large", rr.Body)
+	}
+
+	// A zero limit turns the guard off.
+	app.config.maxResponseBytes = 0
+	if err := app.writeJSON(httptest.NewRecorder(), http.StatusOK, large, nil); err != nil {
+		t.Errorf("without a limit: %v", err)
+	}
+}
+
+func TestEmptySlices(t *testing.T) {
+	var champions []*data.Champion
+	var names []string
+
+	env := emptySlices(envelope{
+		"champions": champions,
+		"names":     names,
+		"full":      []string{"Ahri"},
+		"count":     0,
+		"missing":   nil,
+	})
+
+	js, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"champions":[],"count":0,"full":["Ahri"],"missing":null,"names":[]}`
+	if string(js) != want {
+		t.Errorf("got %s, want %s", js, want)
+	}
+
+	// The empty slice keeps the nil slice's type.
+	if _, ok := env["champions"].([]*data.Champion); !ok {
+		t.Errorf("got %T, want []*data.Champion", env["champions"])
+	}
+}
+
+func TestListEndpointsEmpty(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+	app.config.pagination.maxPageSize = data.DefaultMaxPageSize
+
+	champion := &data.Champion{Name: "Ahri", MainRole: "Mid", Classes: data.ChampionClasses{}}
+	if err := app.models.Champions.Insert(champion); err != nil {
+		t.Fatal(err)
+	}
+	summoner := &data.Summoner{Username: "Faker", Region: "KR"}
+	if err := app.models.Summoners.Insert(summoner); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"/v1/champions?name=Zed", "champions"},
+		{"/v1/champions?name=Zed&stream=true", "champions"},
+		{"/v1/champions?name=Zed&fields=name", "champions"},
+		{"/v1/summoners?username=Caps", "summoners"},
+		{"/v1/summoners?username=Caps&stream=true", "summoners"},
+		{"/v1/matches?tag=none", "matches"},
+		{"/v1/matches?tag=none&stream=true", "matches"},
+		{fmt.Sprintf("/v1/summoners/%d/matches", summoner.ID), "matches"},
+		{fmt.Sprintf("/v1/summoners/%d/ban-suggestions", summoner.ID), "bans"},
+		{fmt.Sprintf("/v1/champions/%d/pros", champion.ID), "pros"},
+	}
+
+	for _, tt := range tests {
+		r := app.contextSetUser(httptest.NewRequest(http.MethodGet, tt.path, nil), data.AnonymousUser)
+		rr := httptest.NewRecorder()
+		app.router().ServeHTTP(rr, r)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: got status %d: %s", tt.path, rr.Code, rr.Body)
+			continue
+		}
+
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if got := string(body[tt.key]); got != "[]" {
+			t.Errorf("%s: got %s = %s, want []", tt.path, tt.key, got)
+		}
+	}
+}
+
+// flushCounter is a ResponseRecorder which counts how often it's flushed.
+type flushCounter struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (f *flushCounter) Flush() {
+	f.flushes++
+	f.ResponseRecorder.Flush()
+}
+
+// produceChampions returns a streamJSON producer emitting n champions, then failing with err if
+// it isn't nil.
+func produceChampions(n int, err error) func(emit func(interface{}) error) error {
+	return func(emit func(interface{}) error) error {
+		for i := 1; i <= n; i++ {
+			if err := emit(&data.Champion{ID: int64(i), Name: fmt.Sprintf("Champion %d", i)}); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func TestStreamJSON(t *testing.T) {
+	app := &application{}
+
+	w := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+	if err := app.streamJSON(w, "champions", produceChampions(250, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Flushed after the 100th and 200th champions.
+	if w.flushes != 2 {
+		t.Errorf("got %d flushes, want 2", w.flushes)
+	}
+
+	var got struct {
+		Champions []data.Champion `json:"champions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, w.Body)
+	}
+	if len(got.Champions) != 250 {
+		t.Fatalf("got %d champions, want 250", len(got.Champions))
+	}
+	for i, champion := range got.Champions {
+		if champion.ID != int64(i+1) {
+			t.Errorf("champion %d: got ID %d, want %d", i, champion.ID, i+1)
+		}
+	}
+}
+
+func TestStreamJSONErrorTrailer(t *testing.T) {
+	app := &application{}
+	failure := errors.New("connection reset")
+
+	rr := httptest.NewRecorder()
+	if err := app.streamJSON(rr, "champions", produceChampions(3, failure)); !errors.Is(err, failure) {
+		t.Errorf("got error %v, want %v", err, failure)
+	}
+
+	// The status had already been sent, so the error is only in the body, after the champions
+	// that made it out.
+	if rr.Code != http.StatusOK {
+		t.Errorf("got status %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	var got struct {
+		Champions []data.Champion `json:"champions"`
+		Error     string          `json:"error"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rr.Body)
+	}
+	if len(got.Champions) != 3 || got.Error == "" {
+		t.Errorf("got %d champions and error %q, want 3 and an error", len(got.Champions), got.Error)
+	}
+}
+
+func TestStreamJSONDataEnvelope(t *testing.T) {
+	app := &application{}
+	app.config.envelopeStyle = envelopeStyleData
+
+	rr := httptest.NewRecorder()
+	if err := app.streamJSON(rr, "champions", produceChampions(2, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string][]data.Champion
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rr.Body)
+	}
+	if len(got) != 1 || len(got["data"]) != 2 {
+		t.Errorf("got %v, want two champions under data alone", got)
+	}
+}
+
+func TestIsIDKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		id, ids bool
+	}{
+		{"id", true, false},
+		{"championId", true, false},
+		{"summonerID", true, false},
+		{"source_id", true, false},
+		{"ids", false, true},
+		{"championIds", false, true},
+		{"summonerIDs", false, true},
+		{"match_ids", false, true},
+		// Keys merely containing "id" aren't IDs.
+		{"kills", false, false},
+		{"width", false, false},
+		{"valid", false, false},
+		{"paid", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := isIDKey(tt.key); got != tt.id {
+			t.Errorf("isIDKey(%q) = %t, want %t", tt.key, got, tt.id)
+		}
+		if got := isIDsKey(tt.key); got != tt.ids {
+			t.Errorf("isIDsKey(%q) = %t, want %t", tt.key, got, tt.ids)
+		}
+	}
+}
+
+func TestStringifyIDs(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{`{"id":1,"name":"Ahri","kills":5}`, `{"id":"1","kills":5,"name":"Ahri"}`},
+		{`{"champion":{"id":7,"summonerId":9007199254740993}}`, `{"champion":{"id":"7","summonerId":"9007199254740993"}}`},
+		{`{"matches":[{"id":1},{"id":2}]}`, `{"matches":[{"id":"1"},{"id":"2"}]}`},
+		// Every number in an array of IDs is stringified, anything else is left alone.
+		{`{"championIds":[1,2],"ids":[3,null]}`, `{"championIds":["1","2"],"ids":["3",null]}`},
+		{`{"scores":[1,2]}`, `{"scores":[1,2]}`},
+		// IDs which are already strings are unchanged.
+		{`{"platformId":"EUW1"}`, `{"platformId":"EUW1"}`},
+	}
+
+	for _, tt := range tests {
+		dec := json.NewDecoder(strings.NewReader(tt.input))
+		dec.UseNumber()
+
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := json.Marshal(stringifyIDs(value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("stringifyIDs(%s) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSetCacheControl(t *testing.T) {
+	tests := []struct {
+		maxAge time.Duration
+		want   string
+	}{
+		{time.Hour, "public, max-age=3600"},
+		{90 * time.Second, "public, max-age=90"},
+		{0, "no-store"},
+	}
+
+	app := &application{}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		app.setCacheControl(rr, tt.maxAge)
+		if got := rr.Header().Get("Cache-Control"); got != tt.want {
+			t.Errorf("setCacheControl(%v) = %q, want %q", tt.maxAge, got, tt.want)
+		}
+	}
+}
+
+func TestStyleEnvelope(t *testing.T) {
+	tests := []struct {
+		style string
+		env   envelope
+		want  string
+	}{
+		{envelopeStyleTyped, envelope{"champion": "Ahri"}, `{"champion":"Ahri"}`},
+		{envelopeStyleTyped, envelope{"champions": []string{"Ahri"}, "metadata": 1}, `{"champions":["Ahri"],"metadata":1}`},
+		{envelopeStyleData, envelope{"champion": "Ahri"}, `{"data":"Ahri"}`},
+		// Meta keys stay at the top level.
+		{envelopeStyleData, envelope{"champions": []string{"Ahri"}, "metadata": 1, "_links": 2}, `{"_links":2,"data":["Ahri"],"metadata":1}`},
+		{envelopeStyleData, envelope{"wins": 3, "losses": 2}, `{"data":{"losses":2,"wins":3}}`},
+		{envelopeStyleData, envelope{"error": "not found"}, `{"error":"not found"}`},
+	}
+
+	for _, tt := range tests {
+		app := &application{}
+		app.config.envelopeStyle = tt.style
+
+		js, err := json.Marshal(app.styleEnvelope(tt.env))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(js) != tt.want {
+			t.Errorf("%s style: got %s, want %s", tt.style, js, tt.want)
+		}
+	}
+}
+
+func TestReadMergePatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantURL   string // Empty if image_url should be left nil
+		wantNulls map[string]bool
+		wantErr   bool
+	}{
+		{"set", `{"image_url": "https://example.com/ahri.png"}`, "https://example.com/ahri.png", map[string]bool{}, false},
+		{"clear with null", `{"image_url": null}`, "", map[string]bool{"image_url": true}, false},
+		// Absent isn't the same as null: the field is neither set nor cleared.
+		{"omit", `{"name": "Ahri"}`, "", map[string]bool{}, false},
+		{"unknown key", `{"title": "the Nine-Tailed Fox"}`, "", nil, true},
+		{"wrong type", `{"image_url": 3}`, "", nil, true},
+		{"not an object", `null`, "", nil, true},
+	}
+
+	app := &application{}
+
+	for _, tt := range tests {
+		var name, imageURL *string
+
+		r := httptest.NewRequest(http.MethodPatch, "/v1/champions/1", strings.NewReader(tt.body))
+		nulls, err := app.readMergePatch(httptest.NewRecorder(), r, map[string]interface{}{
+			"name":      &name,
+			"image_url": &imageURL,
+		})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+
+		switch {
+		case tt.wantURL == "" && imageURL != nil:
+			t.Errorf("%s: got image_url %q, want it left nil", tt.name, *imageURL)
+		case tt.wantURL != "" && (imageURL == nil || *imageURL != tt.wantURL):
+			t.Errorf("%s: got image_url %v, want %q", tt.name, imageURL, tt.wantURL)
+		}
+		if !reflect.DeepEqual(nulls, tt.wantNulls) {
+			t.Errorf("%s: got nulls %v, want %v", tt.name, nulls, tt.wantNulls)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyBuckets holds the upper bound (in milliseconds) of each histogram bucket. Using a fixed
+// set of buckets keeps the memory used per route constant no matter how many requests we record,
+// at the cost of percentiles only being accurate to the nearest bucket boundary.
+var latencyBuckets = [...]float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000}
+
+// latencyHistogram counts request durations into the fixed latencyBuckets. The final element of
+// counts holds any durations above the largest bucket.
+type latencyHistogram struct {
+	counts [len(latencyBuckets) + 1]int64
+	total  int64
+}
+
+// record adds a single duration to the histogram.
+func (h *latencyHistogram) record(d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	i := 0
+	for i < len(latencyBuckets) && ms > latencyBuckets[i] {
+		i++
+	}
+
+	h.counts[i]++
+	h.total++
+}
+
+// percentile returns the upper bound of the bucket containing the p-th percentile (0 < p <= 1)
+// of the recorded durations, in milliseconds. Durations above the largest bucket are reported
+// as the largest bucket bound.
+func (h *latencyHistogram) percentile(p float64) float64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	rank := int64(p*float64(h.total) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+
+	var cumulative int64
+	for i, count := range h.counts {
+		cumulative += count
+		if cumulative >= rank {
+			if i >= len(latencyBuckets) {
+				break
+			}
+			return latencyBuckets[i]
+		}
+	}
+
+	return latencyBuckets[len(latencyBuckets)-1]
+}
+
+// routeLatencies keeps a latencyHistogram for each route template (e.g. "GET /v1/champions/:id").
+type routeLatencies struct {
+	mu     sync.Mutex
+	routes map[string]*latencyHistogram
+}
+
+func newRouteLatencies() *routeLatencies {
+	return &routeLatencies{routes: make(map[string]*latencyHistogram)}
+}
+
+// record adds a request duration to the histogram for the given route.
+func (rl *routeLatencies) record(route string, d time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	h, ok := rl.routes[route]
+	if !ok {
+		h = &latencyHistogram{}
+		rl.routes[route] = h
+	}
+
+	h.record(d)
+}
+
+// snapshot returns the request count and p50/p90/p99 latencies (in milliseconds) for every route
+// that has been recorded. It is published through expvar, so the result must be JSON-encodable.
+func (rl *routeLatencies) snapshot() interface{} {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	out := make(map[string]map[string]float64, len(rl.routes))
+	for route, h := range rl.routes {
+		out[route] = map[string]float64{
+			"count": float64(h.total),
+			"p50":   h.percentile(0.50),
+			"p90":   h.percentile(0.90),
+			"p99":   h.percentile(0.99),
+		}
+	}
+
+	return out
+}
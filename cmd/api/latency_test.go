@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogramPercentile(t *testing.T) {
+	var h latencyHistogram
+
+	if got := h.percentile(0.5); got != 0 {
+		t.Errorf("empty histogram: got p50 %v, want 0", got)
+	}
+
+	record := func(n int, d time.Duration) {
+		for i := 0; i < n; i++ {
+			h.record(d)
+		}
+	}
+
+	record(50, 3*time.Millisecond)
+	record(40, 40*time.Millisecond)
+	record(9, 150*time.Millisecond)
+	record(1, 20*time.Second)
+
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0.01, 5},
+		{0.50, 5},
+		{0.51, 50},
+		{0.90, 50},
+		{0.99, 200},
+		// The slowest request is beyond the largest bucket, so it's reported as that bucket.
+		{1, 10000},
+	}
+
+	for _, tt := range tests {
+		if got := h.percentile(tt.p); got != tt.want {
+			t.Errorf("p%v: got %vms, want %vms", tt.p*100, got, tt.want)
+		}
+	}
+}
+
+func TestLatencyHistogramBucketBounds(t *testing.T) {
+	var h latencyHistogram
+
+	// A duration on a bucket's upper bound falls into that bucket.
+	h.record(5 * time.Millisecond)
+	if got := h.percentile(1); got != 5 {
+		t.Errorf("got %vms, want 5ms", got)
+	}
+
+	h.record(5*time.Millisecond + time.Microsecond)
+	if got := h.percentile(1); got != 10 {
+		t.Errorf("got %vms, want 10ms", got)
+	}
+}
+
+func TestRouteLatenciesSnapshot(t *testing.T) {
+	rl := newRouteLatencies()
+	rl.record("GET /v1/champions/:id", 3*time.Millisecond)
+	rl.record("GET /v1/champions/:id", 3*time.Millisecond)
+	rl.record("GET /v1/healthcheck", time.Millisecond)
+
+	snapshot := rl.snapshot().(map[string]map[string]float64)
+
+	if len(snapshot) != 2 {
+		t.Fatalf("got %d routes, want 2", len(snapshot))
+	}
+	if got := snapshot["GET /v1/champions/:id"]; got["count"] != 2 || got["p50"] != 5 || got["p99"] != 5 {
+		t.Errorf("got %v for the champion route", got)
+	}
+	if got := snapshot["GET /v1/healthcheck"]; got["count"] != 1 || got["p90"] != 1 {
+		t.Errorf("got %v for the healthcheck", got)
+	}
+}
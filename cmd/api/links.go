@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// links holds the hypermedia links included under the "_links" key of single-resource
+// responses, so clients don't need to construct URLs themselves.
+type links map[string]string
+
+// url builds an absolute (or root-relative, if no -base-url was given) URL for the provided
+// path, which is formatted with the given arguments.
+func (app *application) url(format string, args ...interface{}) string {
+	return strings.TrimSuffix(app.config.baseURL, "/") + fmt.Sprintf(format, args...)
+}
+
+// championLinks returns the links for a single champion, along with its related resources. The
+// similar champions are only linked while that feature is enabled.
+func (app *application) championLinks(id int64) links {
+	l := links{
+		"self":           app.url("/v1/champions/%d", id),
+		"collection":     app.url("/v1/champions"),
+		"matches":        app.url("/v1/champions/%d/matches", id),
+		"items":          app.url("/v1/champions/%d/items", id),
+		"draftTiming":    app.url("/v1/champions/%d/draft-timing", id),
+		"winRateHistory": app.url("/v1/champions/%d/win-rate-history", id),
+		"bestSummoners":  app.url("/v1/champions/%d/best-summoners", id),
+		"bestPros":       app.url("/v1/champions/%d/pros", id),
+		"vsRole":         app.url("/v1/champions/%d/vs-role", id),
+		"regions":        app.url("/v1/champions/%d/global", id),
+	}
+
+	if app.features.isEnabled(featureSimilarChampions) {
+		l["similar"] = app.url("/v1/champions/%d/similar", id)
+	}
+
+	return l
+}
+
+// summonerLinks returns the links for a single summoner, along with its related resources.
+func (app *application) summonerLinks(id int64) links {
+	return links{
+		"self":            app.url("/v1/summoners/%d", id),
+		"collection":      app.url("/v1/summoners"),
+		"champions":       app.url("/v1/summoners/%d/champions", id),
+		"matches":         app.url("/v1/summoners/%d/matches", id),
+		"streak":          app.url("/v1/summoners/%d/streak", id),
+		"activity":        app.url("/v1/summoners/%d/activity", id),
+		"kdaDistribution": app.url("/v1/summoners/%d/kda-distribution", id),
+		"banSuggestions":  app.url("/v1/summoners/%d/ban-suggestions", id),
+		"decayForecast":   app.url("/v1/summoners/%d/decay-forecast", id),
+	}
+}
+
+// matchLinks returns the links for a single match, along with its related resources.
+func (app *application) matchLinks(id int64) links {
+	return links{
+		"self":          app.url("/v1/matches/%d", id),
+		"collection":    app.url("/v1/matches"),
+		"summoners":     app.url("/v1/matches/%d/summoners", id),
+		"damageProfile": app.url("/v1/matches/%d/damage-profile", id),
+		"timeline":      app.url("/v1/matches/%d/timeline", id),
+		"goldDiff":      app.url("/v1/matches/%d/gold-diff", id),
+	}
+}
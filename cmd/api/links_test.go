@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// checkLinks asserts that every link is an absolute URL under the base URL which the router has a
+// GET route for.
+func checkLinks(t *testing.T, router *appRouter, l links, want []string) {
+	t.Helper()
+
+	for _, name := range want {
+		if _, ok := l[name]; !ok {
+			t.Errorf("missing %q link; got %v", name, l)
+		}
+	}
+
+	for name, link := range l {
+		u, err := url.Parse(link)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if u.Scheme != "https" || u.Host != "api.example.com" {
+			t.Errorf("%s: got %q, want a URL under https://api.example.com", name, link)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, u.Path, nil)
+		if router.template(r) == "unmatched" {
+			t.Errorf("%s: no GET route for %q", name, u.Path)
+		}
+	}
+}
+
+func TestChampionLinks(t *testing.T) {
+	app := &application{features: newFeatureFlags()}
+	app.config.baseURL = "https://api.example.com/"
+	router := app.router()
+
+	l := app.championLinks(42)
+
+	if got, want := l["self"], "https://api.example.com/v1/champions/42"; got != want {
+		t.Errorf("got self link %q, want %q", got, want)
+	}
+
+	checkLinks(t, router, l, []string{"self", "collection", "matches", "items", "draftTiming",
+		"winRateHistory", "bestSummoners", "bestPros", "vsRole", "regions", "similar"})
+
+	// A disabled feature's endpoint isn't linked.
+	app.features.set(featureSimilarChampions, false)
+	if link, ok := app.championLinks(42)["similar"]; ok {
+		t.Errorf("got similar link %q with the feature disabled", link)
+	}
+}
+
+func TestSummonerLinks(t *testing.T) {
+	app := &application{features: newFeatureFlags()}
+	app.config.baseURL = "https://api.example.com"
+
+	checkLinks(t, app.router(), app.summonerLinks(7), []string{"self", "collection", "champions",
+		"matches", "streak", "activity", "kdaDistribution", "banSuggestions", "decayForecast"})
+}
+
+func TestMatchLinks(t *testing.T) {
+	app := &application{features: newFeatureFlags()}
+	app.config.baseURL = "https://api.example.com"
+
+	checkLinks(t, app.router(), app.matchLinks(3), []string{"self", "collection", "summoners",
+		"damageProfile", "timeline", "goldDiff"})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewLoggerJSON(t *testing.T) {
+	var cfg config
+	cfg.log.format = "json"
+	cfg.log.level = "info"
+
+	var buf bytes.Buffer
+	logger, err := newLogger(cfg, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger.PrintInfo("starting server", map[string]string{"addr": ":4000"})
+
+	var entry struct {
+		Level      string            `json:"level"`
+		Time       string            `json:"time"`
+		Message    string            `json:"message"`
+		Properties map[string]string `json:"properties"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.Bytes())
+	}
+
+	if entry.Level != "INFO" || entry.Message != "starting server" || entry.Time == "" || entry.Properties["addr"] != ":4000" {
+		t.Errorf("got %s, want the level, time, message and properties", buf.Bytes())
+	}
+}
+
+func TestNewLoggerTextAndLevel(t *testing.T) {
+	var cfg config
+	cfg.log.format = "text"
+	cfg.log.level = "error"
+
+	var buf bytes.Buffer
+	logger, err := newLogger(cfg, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Below the minimum level, so not written at all.
+	logger.PrintInfo("starting server", nil)
+	if buf.Len() != 0 {
+		t.Errorf("got %q logged below the minimum level", buf.String())
+	}
+
+	cfg.log.level = "info"
+	logger, err = newLogger(cfg, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.PrintInfo("starting server", map[string]string{"addr": ":4000"})
+
+	line := buf.String()
+	if json.Valid(buf.Bytes()) || !strings.Contains(line, ` INFO starting server addr=":4000"`) {
+		t.Errorf("got %q, want a text line", line)
+	}
+}
+
+func TestNewLoggerInvalidConfig(t *testing.T) {
+	tests := []struct{ format, level string }{
+		{"yaml", "info"},
+		{"json", "verbose"},
+	}
+
+	for _, tt := range tests {
+		var cfg config
+		cfg.log.format = tt.format
+		cfg.log.level = tt.level
+
+		if _, err := newLogger(cfg, &bytes.Buffer{}); err == nil {
+			t.Errorf("newLogger(format %q, level %q): got no error", tt.format, tt.level)
+		}
+	}
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"league_of_graphs.satellite.net/internal/data"
@@ -16,9 +18,13 @@ func (app *application) createMatchHandler(w http.ResponseWriter, r *http.Reques
 	var input struct {
 		Duration   int       `json:"duration"`
 		Result     string    `json:"result"`
+		MatchType  string    `json:"match_type"`
 		PlayedDate time.Time `json:"played_date"`
 		BlueTeam   data.Team `json:"blue_team"`
 		RedTeam    data.Team `json:"red_team"`
+		ReplayURL  string    `json:"replay_url"`
+		VODURL     string    `json:"vod_url"`
+		Region     string    `json:"region"`
 	}
 
 	err := app.readJSON(w, r, &input)
@@ -27,31 +33,125 @@ func (app *application) createMatchHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Matches are assumed to come from solo queue unless the client says otherwise.
+	if input.MatchType == "" {
+		input.MatchType = data.MatchTypeSoloQueue
+	}
+
 	match := &data.Match{
 		PlayedDate: input.PlayedDate,
 		Duration:   input.Duration,
 		Result:     input.Result,
+		MatchType:  input.MatchType,
 		BlueTeam:   &input.BlueTeam,
 		RedTeam:    &input.RedTeam,
+		ReplayURL:  strings.TrimSpace(input.ReplayURL),
+		VODURL:     strings.TrimSpace(input.VODURL),
 	}
 
 	v := validator.New()
 
+	// The region is only used to tell apart summoners with the same username, so it can be left
+	// out when the usernames are unambiguous.
+	region := data.NormalizeRegion(input.Region)
+	v.Check(region == "" || validator.In(region, data.ValidRegions...), "region", "must be a valid region")
+
+	if data.ValidateMatch(v, match); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	// Flag any implausible combinations of values. These don't stop the match from being
+	// stored, but are logged and returned to the client so ingestion problems can be spotted.
+	data.CheckMatchPlausibility(v, match, app.matchPlausibilityLimits())
+
+	// Store the match and its performances together, so the match is never left out of the
+	// statistics derived from performance history.
+	err = app.models.Transact(r.Context(), func(tx data.Models) error {
+		err := tx.Matches.Insert(match)
+		if err != nil {
+			return err
+		}
+
+		return tx.Matches.InsertPerformances(v, match, region)
+	})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.updateMatchStatistics(match)
+
+	headers := make(http.Header)
+	headers.Set("Location", app.url("/v1/matches/%d", match.ID))
+
+	env := envelope{"match": match, "_links": app.matchLinks(match.ID)}
+	if v.HasWarnings() {
+		app.logMatchWarnings(match, v.Warnings)
+		env["warnings"] = v.Warnings
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, env, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// importMatchHandler stores a match given as a Riot match-v5 document, such as those exported by
+// third-party tools. Champions and summoners which we haven't seen before are created, and a
+// performance is recorded for every participant. The match type can't be read from the document,
+// so it's taken from the optional ?match_type= parameter.
+func (app *application) importMatchHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	matchType := app.readString(r.URL.Query(), "match_type", data.MatchTypeSoloQueue)
+	v.Check(validator.In(matchType, data.MatchTypes...), "match_type", "must be one of solo_queue, pro or tournament")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	var doc data.MatchV5
+
+	err := app.readExternalJSON(w, r, &doc)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if data.ValidateMatchV5(v, &doc); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	match := doc.ToMatch(matchType)
+
 	if data.ValidateMatch(v, match); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
-	err = app.models.Matches.Insert(match)
+	data.CheckMatchPlausibility(v, match, app.matchPlausibilityLimits())
+
+	created, err := app.models.Matches.Import(match, doc.Region())
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
+	app.updateMatchStatistics(match)
+
 	headers := make(http.Header)
-	headers.Set("Location", fmt.Sprintf("/v1/matches/%d", match.ID))
+	headers.Set("Location", app.url("/v1/matches/%d", match.ID))
 
-	err = app.writeJSON(w, http.StatusCreated, envelope{"match": match}, headers)
+	env := envelope{"match": match, "created": created, "_links": app.matchLinks(match.ID)}
+	if v.HasWarnings() {
+		app.logMatchWarnings(match, v.Warnings)
+		env["warnings"] = v.Warnings
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, env, headers)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -67,25 +167,50 @@ func (app *application) showMatchHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	v := validator.New()
+
+	fields := app.readFields(r.URL.Query(), matchFields, v)
+
 	// Create a new instance of the Match struct with dummy data.
 	match, err := app.models.Matches.Get(id)
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.matchNotFoundResponse(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
 		return
 	}
 
+	selected, err := fields.apply(match)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.matches)
+
+	env := envelope{
+		"match":        selected,
+		"goldDiff":     match.GoldDiff(),
+		"netWorthDiff": match.NetWorthDiff(),
+		"_links":       app.matchLinks(match.ID),
+	}
+	if v.HasWarnings() {
+		env["warnings"] = v.Warnings
+	}
+
 	// Encode the struct to JSON and send it as the HTTP response.
-	err = app.writeJSON(w, http.StatusOK, envelope{"match": match}, nil)
+	err = app.writeJSON(w, http.StatusOK, env, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
 }
 
+// updateMatchHandler replaces a match's details. A body sent as application/merge-patch+json
+// (RFC 7386) only changes the fields it includes, and an explicit null removes the replay or VOD
+// link; the other fields can't be cleared.
 func (app *application) updateMatchHandler(w http.ResponseWriter, r *http.Request) {
 
 	id, err := app.readIDParam(r)
@@ -98,7 +223,7 @@ func (app *application) updateMatchHandler(w http.ResponseWriter, r *http.Reques
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.matchNotFoundResponse(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -109,35 +234,88 @@ func (app *application) updateMatchHandler(w http.ResponseWriter, r *http.Reques
 	var input struct {
 		Duration   int       `json:"duration"`
 		Result     string    `json:"result"`
+		MatchType  string    `json:"match_type"`
 		PlayedDate time.Time `json:"played_date"`
 		BlueTeam   data.Team `json:"blue_team"`
 		RedTeam    data.Team `json:"red_team"`
+		ReplayURL  *string   `json:"replay_url"`
+		VODURL     *string   `json:"vod_url"`
 	}
 
-	err = app.readJSON(w, r, &input)
+	var nulls map[string]bool
+
+	if isMergePatch(r) {
+		// Members left out of a merge patch keep their current values.
+		input.Duration = match.Duration
+		input.Result = match.Result
+		input.MatchType = match.MatchType
+		input.PlayedDate = match.PlayedDate
+		nulls, err = app.readMergePatch(w, r, map[string]interface{}{
+			"duration":    &input.Duration,
+			"result":      &input.Result,
+			"match_type":  &input.MatchType,
+			"played_date": &input.PlayedDate,
+			"blue_team":   &input.BlueTeam,
+			"red_team":    &input.RedTeam,
+			"replay_url":  &input.ReplayURL,
+			"vod_url":     &input.VODURL,
+		})
+	} else {
+		err = app.readJSON(w, r, &input)
+	}
 	if err != nil {
 		app.badRequestResponse(w, r, err)
 		return
 	}
 
+	cleared := ""
+	if nulls["replay_url"] {
+		input.ReplayURL = &cleared
+	}
+	if nulls["vod_url"] {
+		input.VODURL = &cleared
+	}
+
 	match.Duration = input.Duration
 	match.Result = input.Result
 	match.PlayedDate = input.PlayedDate
+	if input.MatchType != "" {
+		match.MatchType = input.MatchType
+	}
+	// The links are kept unless given; an empty string removes one.
+	if input.ReplayURL != nil {
+		match.ReplayURL = strings.TrimSpace(*input.ReplayURL)
+	}
+	if input.VODURL != nil {
+		match.VODURL = strings.TrimSpace(*input.VODURL)
+	}
 
 	v := validator.New()
 
+	for _, key := range []string{"duration", "result", "match_type", "played_date", "blue_team", "red_team"} {
+		v.Check(!nulls[key], key, "cannot be cleared")
+	}
+
 	if data.ValidateMatch(v, match); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
+	data.CheckMatchPlausibility(v, match, app.matchPlausibilityLimits())
+
 	err = app.models.Matches.Update(match)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"match": match}, nil)
+	env := envelope{"match": match, "_links": app.matchLinks(match.ID)}
+	if v.HasWarnings() {
+		app.logMatchWarnings(match, v.Warnings)
+		env["warnings"] = v.Warnings
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -155,7 +333,7 @@ func (app *application) deleteMatchHandler(w http.ResponseWriter, r *http.Reques
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.matchNotFoundResponse(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -168,9 +346,46 @@ func (app *application) deleteMatchHandler(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// deleteOldMatchesHandler deletes every match played before the "before" date. With
+// ?dry_run=true nothing is deleted, but the response still reports how many matches would be.
+func (app *application) deleteOldMatchesHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	before := app.readDate(qs, "before", v)
+	dryRun := app.readBool(qs, "dry_run", false, v)
+
+	v.Check(!before.IsZero(), "before", "must be provided")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	deleted, err := app.models.Matches.DeleteOlderThan(before, dryRun)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"deleted": deleted, "dry_run": dryRun}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// matchSortSafelist holds the values accepted by the ?sort= parameter of the match list.
+// matchFields holds the fields a match response can be restricted to with ?fields=.
+var matchFields = jsonFields(data.Match{})
+
+var matchSortSafelist = []string{"id", "duration", "result", "played_date", "blue_team", "red_team"}
+
 func (app *application) listMatchesHandler(w http.ResponseWriter, r *http.Request) {
 
 	var input struct {
+		data.MatchFilter
+		Stream bool
 		data.Filters
 	}
 
@@ -178,24 +393,367 @@ func (app *application) listMatchesHandler(w http.ResponseWriter, r *http.Reques
 
 	qs := r.URL.Query()
 
-	input.Filters.Page = app.readInt(qs, "page", 1, v)
-	input.Filters.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.MatchFilter = app.readMatchFilter(qs, v)
+	input.Stream = app.readBool(qs, "stream", false, v)
 
-	input.Filters.Sort = app.readString(qs, "sort", "id")
-	input.Filters.SortSafelist = []string{"id", "duration", "result", "played_date", "blue_team", "red_team"}
+	input.Filters = app.readFilters(qs, "id", matchSortSafelist, v)
+	fields := app.readFields(qs, matchFields, v)
 
 	if data.ValidateFilters(v, input.Filters); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
-	matches, err := app.models.Matches.GetAll(input.Filters)
+	app.setCacheControl(w, app.config.cache.matches)
+
+	// In streaming mode every match is written out as it's read from the database, rather than a
+	// single page being assembled in memory first.
+	if input.Stream {
+		input.Filters.PageSize = 0
+
+		err := app.streamJSON(w, "matches", func(emit func(interface{}) error) error {
+			return app.models.Matches.Stream(r.Context(), input.MatchFilter, input.Filters, func(match *data.Match) error {
+				selected, err := fields.apply(match)
+				if err != nil {
+					return err
+				}
+				return emit(selected)
+			})
+		})
+		if err != nil {
+			app.logError(r, err)
+		}
+		return
+	}
+
+	matches, metadata, err := app.models.Matches.GetAll(input.MatchFilter, input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if metadata.OutOfRange && app.config.pagination.outOfRange == pageOutOfRangeNotFound {
+		app.pageOutOfRangeResponse(w, r, metadata)
+		return
+	}
+
+	selected, err := fields.apply(matches)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"matches": matches}, nil)
+	env := envelope{"matches": selected, "metadata": metadata}
+	if v.HasWarnings() {
+		env["warnings"] = v.Warnings
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// readMatchFilter reads the match list filters from the query string: a tag, minimum counts of
+// each objective taken by both teams combined (e.g. ?min_dragons=6), and ?has_vod=true.
+func (app *application) readMatchFilter(qs url.Values, v *validator.Validator) data.MatchFilter {
+	filter := data.MatchFilter{
+		Tag:           strings.ToLower(strings.TrimSpace(app.readString(qs, "tag", ""))),
+		MinDragons:    app.readInt(qs, "min_dragons", 0, v),
+		MinBarons:     app.readInt(qs, "min_barons", 0, v),
+		MinTurrets:    app.readInt(qs, "min_turrets", 0, v),
+		MinHeralds:    app.readInt(qs, "min_heralds", 0, v),
+		MinInhibitors: app.readInt(qs, "min_inhibitors", 0, v),
+		HasVOD:        app.readBool(qs, "has_vod", false, v),
+	}
+
+	v.Check(filter.MinDragons >= 0, "min_dragons", "must not be negative")
+	v.Check(filter.MinBarons >= 0, "min_barons", "must not be negative")
+	v.Check(filter.MinTurrets >= 0, "min_turrets", "must not be negative")
+	v.Check(filter.MinHeralds >= 0, "min_heralds", "must not be negative")
+	v.Check(filter.MinInhibitors >= 0, "min_inhibitors", "must not be negative")
+
+	return filter
+}
+
+// addMatchTagsHandler tags a match, e.g. as a "pentakill" or "comeback" for highlight reels, and
+// returns all of the match's tags.
+func (app *application) addMatchTagsHandler(w http.ResponseWriter, r *http.Request) {
+	app.changeMatchTags(w, r, app.models.Matches.AddTags)
+}
+
+// removeMatchTagsHandler removes tags from a match and returns the match's remaining tags.
+func (app *application) removeMatchTagsHandler(w http.ResponseWriter, r *http.Request) {
+	app.changeMatchTags(w, r, app.models.Matches.RemoveTags)
+}
+
+// changeMatchTags reads and validates a list of tags from the request body, applies change to
+// the match and writes out the match's tags.
+func (app *application) changeMatchTags(w http.ResponseWriter, r *http.Request, change func(int64, []string) error) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Matches.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.matchNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	var input struct {
+		Tags []string `json:"tags"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	tags := data.NormalizeTags(input.Tags)
+
+	v := validator.New()
+
+	if data.ValidateTags(v, tags); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = change(id, tags)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	tags, err = app.models.Matches.GetTags(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"tags": tags}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// matchPlausibilityLimits builds the thresholds for data.CheckMatchPlausibility from the
+// application config.
+func (app *application) matchPlausibilityLimits() data.MatchPlausibilityLimits {
+	return data.MatchPlausibilityLimits{
+		MaxNetWorthPerMinute: app.config.match.maxNetWorthPerMinute,
+	}
+}
+
+// updateMatchStatistics adds a newly stored match to the summoner and champion statistics. The
+// match itself is already stored by then, so a failure is logged rather than failing the request;
+// the statistics can be rebuilt from performance history with a recompute.
+func (app *application) updateMatchStatistics(match *data.Match) {
+	err := app.models.Matches.UpdateMatchStatistics(match)
+	if err != nil {
+		app.logger.PrintError(err, map[string]string{"match_id": strconv.FormatInt(match.ID, 10)})
+	}
+}
+
+// logMatchWarnings logs the plausibility warnings raised for a match at the INFO level.
+func (app *application) logMatchWarnings(match *data.Match, warnings map[string]string) {
+	properties := map[string]string{"match_id": strconv.FormatInt(match.ID, 10)}
+	for key, message := range warnings {
+		properties[key] = message
+	}
+
+	app.logger.PrintInfo("implausible match data", properties)
+}
+
+// showMatchDamageProfileHandler summarizes the damage composition of each team in a match from
+// the damage profiles of the champions its summoners played.
+func (app *application) showMatchDamageProfileHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	match, err := app.models.Matches.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.matchNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	teams := map[string]*data.Team{data.ResultBlue: match.BlueTeam, data.ResultRed: match.RedTeam}
+
+	var names []string
+	for _, team := range teams {
+		if team == nil {
+			continue
+		}
+		for _, summoner := range team.Summoners {
+			names = append(names, summoner.Champion.Name)
+		}
+	}
+
+	profiles, err := app.models.Champions.GetDamageProfiles(names)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	summaries := make(map[string]*data.TeamDamageProfile, len(teams))
+	for side, team := range teams {
+		champions := []data.ChampionDamage{}
+		if team != nil {
+			for _, summoner := range team.Summoners {
+				champions = append(champions, data.ChampionDamage{
+					Name:          summoner.Champion.Name,
+					DamageProfile: profiles[strings.ToLower(summoner.Champion.Name)],
+				})
+			}
+		}
+		summaries[side] = data.SummarizeDamageProfile(champions)
+	}
+
+	app.setCacheControl(w, app.config.cache.matches)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"damageProfile": summaries}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showMatchTimelineHandler returns a match's events (kills, objectives and so on) in the order
+// they happened, so that the flow of the match can be replayed.
+func (app *application) showMatchTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Matches.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.matchNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	events, err := app.models.Matches.GetEvents(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"events": events}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// addMatchEventsHandler adds events to a match's timeline and returns the whole timeline. The
+// events may be given in any order, but must all fall within the match's duration.
+func (app *application) addMatchEventsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	match, err := app.models.Matches.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.matchNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	var input struct {
+		Events []*data.MatchEvent `json:"events"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	data.NormalizeMatchEvents(input.Events)
+
+	if data.ValidateMatchEvents(v, input.Events, match.Duration); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Matches.InsertEvents(id, input.Events)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	events, err := app.models.Matches.GetEvents(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"events": events}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showMatchGoldDiffHandler returns the blue team's gold and XP lead at each minute of a match,
+// from the team_stats snapshots in its timeline, for graphing momentum swings.
+func (app *application) showMatchGoldDiffHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	match, err := app.models.Matches.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.matchNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	events, err := app.models.Matches.GetEvents(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	diffs, ok := data.DiffCurve(events, match.Duration)
+	if !ok {
+		app.errorResponse(w, r, http.StatusNotFound, "the match has no gold or XP timeline data")
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"diffs": diffs}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
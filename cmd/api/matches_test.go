@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+func TestCreateMatchUpdatesStatistics(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+
+	faker := &data.Summoner{Username: "Faker", Region: "KR"}
+	chovy := &data.Summoner{Username: "Chovy", Region: "KR"}
+	for _, summoner := range []*data.Summoner{faker, chovy} {
+		if err := app.models.Summoners.Insert(summoner); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ahri := &data.Champion{Name: "Ahri", MainRole: "Mid", Classes: data.ChampionClasses{}}
+	if err := app.models.Champions.Insert(ahri); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{
+        "duration": 1800,
+        "result": "blue",
+        "played_date": "2024-03-01T12:00:00Z",
+        "blue_team": {"summoners": [{"username": "Faker", "champion": {"name": "Ahri"}, "role": "Mid", "net_worth": 12000, "kda": {"kills": 6, "deaths": 2, "assists": 8}}]},
+        "red_team": {"summoners": [{"username": "Chovy", "champion": {"name": "Ahri"}, "role": "Mid", "net_worth": 11000, "kda": {"kills": 2, "deaths": 6, "assists": 4}}]}
+    }`
+
+	rr := httptest.NewRecorder()
+	app.router().ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/matches", strings.NewReader(body)))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+
+	// Faker's blue team won and Chovy's red team lost.
+	for _, tt := range []struct {
+		summoner *data.Summoner
+		winRate  float64
+		kills    int
+	}{{faker, 1, 6}, {chovy, 0, 2}} {
+		got, err := app.models.Summoners.Get(tt.summoner.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.CountOfPlayedGames != 1 || got.WinRate != tt.winRate || got.AverageKDA.Kills != tt.kills {
+			t.Errorf("%s: got %d games, a win rate of %v and %d kills, want 1, %v and %d",
+				got.Username, got.CountOfPlayedGames, got.WinRate, got.AverageKDA.Kills, tt.winRate, tt.kills)
+		}
+	}
+
+	champion, err := app.models.Champions.Get(ahri.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One win and one loss, by two different summoners.
+	if math.Abs(champion.WinRate-0.5) > 1e-9 || champion.Popularity != 2 {
+		t.Errorf("got a champion win rate of %v and popularity %v, want 0.5 and 2", champion.WinRate, champion.Popularity)
+	}
+}
@@ -4,15 +4,13 @@ import (
 	"errors"
 	"expvar"
 	"fmt"
+	"math/rand"
 	"net/http"
 	"strconv"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/felixge/httpsnoop"
 	"github.com/tomasen/realip"
-	"golang.org/x/time/rate"
 	"league_of_graphs.satellite.net/internal/data"
 	"league_of_graphs.satellite.net/internal/validator"
 )
@@ -22,6 +20,36 @@ func (app *application) authenticate(next http.Handler) http.Handler {
 		// Add the "Vary: Authorization" header to the response. This indicates to any caches
 		// that the response may vary based on the value of the Authorization header in the request.
 		w.Header().Set("Vary", "Authorization")
+		w.Header().Add("Vary", "X-API-Key")
+
+		// Service callers authenticate with an API key in the X-API-Key header instead of a bearer
+		// token. The key is checked first, and a request with an invalid key is rejected rather
+		// than falling back to its Authorization header.
+		if plaintext := r.Header.Get("X-API-Key"); plaintext != "" {
+			v := validator.New()
+
+			if data.ValidateAPIKeyPlaintext(v, plaintext); !v.Valid() {
+				app.invalidAPIKeyResponse(w, r)
+				return
+			}
+
+			key, err := app.models.APIKeys.GetForKey(plaintext)
+			if err != nil {
+				switch {
+				case errors.Is(err, data.ErrRecordNotFound):
+					app.invalidAPIKeyResponse(w, r)
+				default:
+					app.serverErrorResponse(w, r, err)
+				}
+				return
+			}
+
+			r = app.contextSetUser(r, key.User())
+			r = app.contextSetAPIKey(r, key)
+
+			next.ServeHTTP(w, r)
+			return
+		}
 
 		// Retrieve the value of the Authorization header from teh request. This will return the
 		// empty string "" if there is no such header found.
@@ -118,11 +146,8 @@ func (app *application) requireActivatedUser(next http.HandlerFunc) http.Handler
 
 func (app *application) requirePermissions(code string, next http.HandlerFunc) http.HandlerFunc {
 	fn := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Retrieve the user from the request context.
-		user := app.contextGetUser(r)
-
 		// Get the slice of permission for the user
-		permissions, err := app.models.Permissions.GetAllForUser(user.ID)
+		permissions, err := app.permissionsFor(r)
 		if err != nil {
 			app.serverErrorResponse(w, r, err)
 			return
@@ -143,13 +168,30 @@ func (app *application) requirePermissions(code string, next http.HandlerFunc) h
 	return app.requireActivatedUser(fn)
 }
 
-func (app *application) metrics(next http.Handler) http.Handler {
+// requireFeature responds as though the route doesn't exist while the named feature is disabled.
+func (app *application) requireFeature(name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !app.features.isEnabled(name) {
+			app.notFoundResponse(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+func (app *application) metrics(router *appRouter, next http.Handler) http.Handler {
 	// Initialize the new expvar variables when middleware chain is first build.
 	totalRequestsReceived := expvar.NewInt("total_requests_received")
 	totalResponsesSent := expvar.NewInt("total_responses_sent")
 	totalProcessingTimeMicroseconds := expvar.NewInt("total_processing_time_µs")
 	totalResponsesSentbyStatus := expvar.NewMap("total_responses_sent_by_status")
 
+	// Keep a latency histogram per route template, and publish the p50/p90/p99 values
+	// alongside the other metrics.
+	latencies := newRouteLatencies()
+	expvar.Publish("route_latency_ms", expvar.Func(latencies.snapshot))
+
 	// Below runs for every request.
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// use the Add method to increment the number of requests received by 1.
@@ -171,6 +213,16 @@ func (app *application) metrics(next http.Handler) http.Handler {
 		// Note, the expvar map is string-keyed, so we need to use the strconv.Itoa
 		// function to convert the status (an integer) to a string.
 		totalResponsesSentbyStatus.Add(strconv.Itoa(metrics.Code), 1)
+
+		// Only record a sample of the requests in the latency histograms, as configured by the
+		// -metrics-sample-rate flag.
+		if rand.Float64() < app.config.metrics.sampleRate {
+			latencies.record(router.template(r), metrics.Duration)
+		}
+
+		if app.config.log.requests {
+			app.logRequest(r, metrics)
+		}
 	})
 }
 
@@ -188,9 +240,9 @@ func (app *application) recoverPanic(next http.Handler) http.Handler {
 				// The value returned by recover() has the type interface{}, so we use
 				// fmt.Errorf() to normalize it into an error and call our
 				// serverErrorResponse() helper. In turn, this will log the error using our
-				// custom Logger type at the ERROR level and send the client a
-				// 500 Internal Server Error response.
-				app.serverErrorResponse(w, r, fmt.Errorf("%s", err))
+				// custom Logger type at the ERROR level, report it (with the panicking stack)
+				// to the error reporter, and send the client a 500 Internal Server Error response.
+				app.serverErrorResponse(w, r, fmt.Errorf("panic: %s", err))
 			}
 		}()
 		next.ServeHTTP(w, r)
@@ -225,10 +277,11 @@ func (app *application) enableCORS(next http.Handler) http.Handler {
 					if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
 						// Set the necessary preflight response headers.
 						w.Header().Set("Access-Control-Allow-Methods", "OPTIONS, PUT, PATCH, DELETE")
-						w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+						w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-API-Key")
 
-						// Set max cached times for headers for 60 seconds.
-						w.Header().Set("Access-Control-Max-Age", "60")
+						// Let the browser cache the preflight result for the configured number
+						// of seconds, rather than sending a preflight before every request.
+						w.Header().Set("Access-Control-Max-Age", strconv.Itoa(app.config.cors.maxAge))
 
 						// Write the headers along with a 200 OK status and return from the
 						// middleware with no further action.
@@ -245,76 +298,98 @@ func (app *application) enableCORS(next http.Handler) http.Handler {
 	})
 }
 
-func (app *application) rateLimit(next http.Handler) http.Handler {
-	// Define a client struct to hold the rate limiter and last seen time for reach client
-	type client struct {
-		limiter  *rate.Limiter
-		lastSeen time.Time
-	}
-
-	// Declare a mutex and a map to hold pointers to a client struct.
-	var (
-		mu      sync.Mutex
-		clients = make(map[string]*client)
-	)
-
-	// Launch a background goroutine which removes old entries from the clients map once every
-	// minute.
-	go func() {
-		for {
-			time.Sleep(time.Minute)
-
-			// Lock the mutex to prevent any rate limiter checks from happening while the cleanup
-			// is taking place.
-			mu.Lock()
-
-			// Loop through all clients. if they haven't been seen within the last three minutes,
-			// then delete the corresponding entry from the clients map.
-			for ip, client := range clients {
-				if time.Since(client.lastSeen) > 3*time.Minute {
-					delete(clients, ip)
-				}
+// rateLimitIP limits each client IP address to the configured -limiter-ip-rps, using the
+// limiter backend chosen at startup (see newLimiter). It runs before authenticate, so that
+// requests carrying bad tokens or API keys are throttled before each costs a database lookup,
+// and it applies to every request, including those of users exempt from rateLimit. It should be
+// set well above the regular limit, to bound abuse rather than shape normal traffic.
+func (app *application) rateLimitIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if app.config.limiter.enabled {
+			// The buckets are kept apart from rateLimit's, which share the backend and are
+			// also keyed by IP address.
+			allowed, err := app.ipLimiter.Allow(r.Context(), "ip "+realip.FromRequest(r))
+			if err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+			if !allowed {
+				app.rateLimitExceededResponse(w, r)
+				return
 			}
-
-			// Importantly, unlock the mutex when the cleanup is complete.
-			mu.Unlock()
 		}
-	}()
+		next.ServeHTTP(w, r)
+	})
+}
 
+// rateLimit limits each client IP address to the configured requests per second, using the
+// limiter backend chosen at startup (see newLimiter), or to the route's own limit where one is
+// configured with -limiter-routes. It runs after authenticate so that activated users holding
+// the "ratelimit:exempt" permission (used by our internal admin tooling) can skip it entirely.
+//
+// Authentication itself is guarded by rateLimitIP, which runs first and also applies to exempt
+// users, so bad token and API key guesses are still throttled. Anyone holding an exempt user's
+// token can put load on the API up to that per-IP ceiling, so the permission should only be
+// granted to trusted service accounts whose tokens are kept secret.
+func (app *application) rateLimit(router *appRouter, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Only carry out the check if rate limited is enabled.
 		if app.config.limiter.enabled {
-			// Use the realip.FromRequest function to get the client's real IP address.
-			ip := realip.FromRequest(r)
-
-			// Lock the mutex to prevent this code from being executed concurrently.
-			mu.Lock()
-
-			// Check to see if the IP address already exists in the map. If it doesn't,
-			// then initialize a new rate limiter and add the IP address and limiter to the map.
-			if _, found := clients[ip]; !found {
-				// Use the requests-per-second and burst values from the app.config struct.
-				clients[ip] = &client{
-					limiter: rate.NewLimiter(rate.Limit(app.config.limiter.rps), app.config.limiter.burst)}
+			exempt, err := app.isRateLimitExempt(r)
+			if err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+			if exempt {
+				next.ServeHTTP(w, r)
+				return
 			}
 
-			// Update the last seen time for the client.
-			clients[ip].lastSeen = time.Now()
+			// Use the realip.FromRequest function to get the client's real IP address, and take a
+			// token from that IP address's bucket. If the bucket is empty, send a 429 Too Many
+			// Requests response. Routes with their own limit take the token from a separate
+			// bucket for the route instead of the global one.
+			limiter, key := app.limiter, realip.FromRequest(r)
+			if template := router.template(r); app.routeLimiters[template] != nil {
+				limiter, key = app.routeLimiters[template], template+" "+key
+			}
 
-			// Call the limiter.Allow() method on the rate limiter for the current IP address.
-			// If the request isn't allowed, unlock the mutex and send a 429 Too Many Requests
-			// response.
-			if !clients[ip].limiter.Allow() {
-				mu.Unlock()
+			allowed, err := limiter.Allow(r.Context(), key)
+			if err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+			if !allowed {
 				app.rateLimitExceededResponse(w, r)
 				return
 			}
-
-			// Very importantly, unlock the mutex before calling the next handler in the chain.
-			// Notice that we DON'T use defer to unlock the mutex, as that would mean that the mutex
-			// isn't unlocked until all handlers downstream of this middleware have also returned.
-			mu.Unlock()
 		}
 		next.ServeHTTP(w, r)
 	})
 }
+
+// permissionsFor returns the permissions of the request: those of the API key it was authenticated
+// with, if any, or otherwise those of its user.
+func (app *application) permissionsFor(r *http.Request) (data.Permissions, error) {
+	if key := app.contextGetAPIKey(r); key != nil {
+		return key.Permissions, nil
+	}
+
+	return app.models.Permissions.GetAllForUser(app.contextGetUser(r).ID)
+}
+
+// isRateLimitExempt reports whether the request was made by an activated user holding the
+// "ratelimit:exempt" permission. Anonymous requests are never exempt and don't touch the database.
+func (app *application) isRateLimitExempt(r *http.Request) (bool, error) {
+	user := app.contextGetUser(r)
+	if user.IsAnonymous() || !user.Activated {
+		return false, nil
+	}
+
+	permissions, err := app.permissionsFor(r)
+	if err != nil {
+		return false, err
+	}
+
+	return permissions.Include("ratelimit:exempt"), nil
+}
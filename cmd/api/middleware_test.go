@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+	"league_of_graphs.satellite.net/internal/ratelimit"
+)
+
+// newLimitedApp returns an application with rate limiting enabled, whose regular limiter allows a
+// burst of burst requests per client and then (practically) nothing more.
+func newLimitedApp(burst int) *application {
+	app := &application{
+		limiter:   ratelimit.NewMemory(0.001, burst),
+		ipLimiter: ratelimit.NewMemory(0.001, 1000),
+	}
+	app.config.limiter.enabled = true
+	return app
+}
+
+// withAPIKey returns a request authenticated with an API key holding permissions, as authenticate
+// would leave it.
+func withAPIKey(app *application, r *http.Request, permissions ...string) *http.Request {
+	key := &data.APIKey{Name: "tooling", Permissions: permissions}
+	r = app.contextSetUser(r, key.User())
+	return app.contextSetAPIKey(r, key)
+}
+
+func TestRateLimitExemption(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name        string
+		permissions []string
+		wantLimited bool
+	}{
+		{"normal user", []string{"champions:read"}, true},
+		{"exempt user", []string{"champions:read", "ratelimit:exempt"}, false},
+	}
+
+	for _, tt := range tests {
+		app := newLimitedApp(3)
+		handler := app.rateLimit(newAppRouter(), ok)
+
+		limited := false
+		for i := 0; i < 20; i++ {
+			r := withAPIKey(app, httptest.NewRequest(http.MethodGet, "/v1/champions", nil), tt.permissions...)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, r)
+
+			if rr.Code == http.StatusTooManyRequests {
+				limited = true
+				if i < 3 {
+					t.Errorf("%s: throttled after %d requests, within the burst", tt.name, i)
+				}
+				break
+			}
+		}
+
+		if limited != tt.wantLimited {
+			t.Errorf("%s: got throttled=%t, want %t", tt.name, limited, tt.wantLimited)
+		}
+	}
+}
+
+func TestRateLimitIPRunsBeforeAuthentication(t *testing.T) {
+	app := newLimitedApp(1000)
+	app.ipLimiter = ratelimit.NewMemory(0.001, 2)
+
+	handler := app.rateLimitIP(app.authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request with a malformed token was authenticated")
+	})))
+
+	want := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}
+	for i, code := range want {
+		r := httptest.NewRequest(http.MethodGet, "/v1/champions", nil)
+		r.Header.Set("Authorization", "Bearer guess")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+
+		if rr.Code != code {
+			t.Errorf("request %d: got status %d, want %d", i+1, rr.Code, code)
+		}
+	}
+}
+
+func TestEnableCORSMaxAge(t *testing.T) {
+	app := &application{}
+	app.config.cors.trustedOrigins = []string{"https://example.com"}
+	app.config.cors.maxAge = 600
+
+	handler := app.enableCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodOptions, "/v1/champions/1", nil)
+		r.Header.Set("Origin", origin)
+		r.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+		return rr
+	}
+
+	if got := preflight("https://example.com").Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("got Access-Control-Max-Age %q, want %q", got, "600")
+	}
+
+	// Untrusted origins get no preflight response at all.
+	if got := preflight("https://evil.example.com").Header().Get("Access-Control-Max-Age"); got != "" {
+		t.Errorf("got Access-Control-Max-Age %q for an untrusted origin, want none", got)
+	}
+}
+
+// fakeReporter records the errors reported to it.
+type fakeReporter struct {
+	errs   []error
+	stacks [][]byte
+	tags   []map[string]string
+}
+
+func (f *fakeReporter) Report(ctx context.Context, err error, stack []byte, tags map[string]string) {
+	f.errs = append(f.errs, err)
+	f.stacks = append(f.stacks, stack)
+	f.tags = append(f.tags, tags)
+}
+
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	panic("champion not ready")
+}
+
+func TestRecoverPanicReportsPanic(t *testing.T) {
+	reporter := &fakeReporter{}
+	app := &application{
+		logger:        jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		errorReporter: reporter,
+	}
+
+	rr := httptest.NewRecorder()
+	app.recoverPanic(http.HandlerFunc(panickingHandler)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/champions/1", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want %d", rr.Code, http.StatusInternalServerError)
+	}
+	if len(reporter.errs) != 1 {
+		t.Fatalf("got %d reports, want 1", len(reporter.errs))
+	}
+	if got := reporter.errs[0].Error(); got != "panic: champion not ready" {
+		t.Errorf("got error %q, want %q", got, "panic: champion not ready")
+	}
+	// The stack is taken while recovering, so still shows where the handler panicked.
+	if !bytes.Contains(reporter.stacks[0], []byte("panickingHandler")) {
+		t.Errorf("got a stack without the panicking handler:\n%s", reporter.stacks[0])
+	}
+	if got := reporter.tags[0]["request_url"]; got != "/v1/champions/1" {
+		t.Errorf("got request_url tag %q, want %q", got, "/v1/champions/1")
+	}
+}
+
+func TestRecoverPanicWithoutReporter(t *testing.T) {
+	app := &application{logger: jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo)}
+
+	rr := httptest.NewRecorder()
+	app.recoverPanic(http.HandlerFunc(panickingHandler)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/champions/1", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want %d", rr.Code, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+)
+
+// Summoner percentile scopes.
+const (
+	percentileScopeGlobal = "global"
+	percentileScopeRegion = "region"
+)
+
+// summonerDistributions caches the summoner stat distributions percentiles are read from, by
+// region ("" for every region). Building one scans the whole summoners table, so each is reused
+// until it is older than the TTL; a percentile may therefore lag a rating change by up to the TTL.
+type summonerDistributions struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*summonerDistribution
+}
+
+type summonerDistribution struct {
+	distribution *data.SummonerDistribution
+	loadedAt     time.Time
+}
+
+func newSummonerDistributions(ttl time.Duration) *summonerDistributions {
+	return &summonerDistributions{ttl: ttl, entries: make(map[string]*summonerDistribution)}
+}
+
+// get returns the cached distribution of the region, loading it with load if there is none or it
+// has expired. The lock is held while loading so concurrent requests don't all scan the table.
+func (d *summonerDistributions) get(region string, load func(string) (*data.SummonerDistribution, error)) (*data.SummonerDistribution, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if entry, ok := d.entries[region]; ok && time.Since(entry.loadedAt) < d.ttl {
+		return entry.distribution, nil
+	}
+
+	distribution, err := load(region)
+	if err != nil {
+		return nil, err
+	}
+
+	d.entries[region] = &summonerDistribution{distribution: distribution, loadedAt: time.Now()}
+
+	return distribution, nil
+}
+
+// setSummonerPercentiles fills in the summoner's rating and win rate percentiles (from 0 to 100)
+// among all summoners, or only those of the summoner's region if scope is percentileScopeRegion.
+func (app *application) setSummonerPercentiles(summoner *data.Summoner, scope string) error {
+	region := ""
+	if scope == percentileScopeRegion {
+		region = summoner.Region
+	}
+
+	distribution, err := app.summonerDistributions.get(region, app.models.Summoners.GetDistribution)
+	if err != nil {
+		return err
+	}
+
+	rating := percent(distribution.Rating.Of(float64(summoner.Rating)))
+	winRate := percent(distribution.WinRate.Of(summoner.WinRate))
+
+	summoner.RatingPercentile = &rating
+	summoner.WinRatePercentile = &winRate
+
+	return nil
+}
+
+// percent converts a share from 0 to 1 into a percentage rounded to one decimal place.
+func percent(share float64) float64 {
+	return math.Round(share*1000) / 10
+}
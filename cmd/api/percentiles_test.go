@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+)
+
+func TestSummonerDistributionsCache(t *testing.T) {
+	cache := newSummonerDistributions(time.Hour)
+
+	loads := map[string]int{}
+	load := func(region string) (*data.SummonerDistribution, error) {
+		loads[region]++
+		return &data.SummonerDistribution{}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		for _, region := range []string{"", "EUW"} {
+			if _, err := cache.get(region, load); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Each region is loaded once, then served from the cache until it expires.
+	if loads[""] != 1 || loads["EUW"] != 1 {
+		t.Errorf("got loads %v, want one per region", loads)
+	}
+
+	cache.entries["EUW"].loadedAt = time.Now().Add(-2 * time.Hour)
+	if _, err := cache.get("EUW", load); err != nil {
+		t.Fatal(err)
+	}
+	if loads["EUW"] != 2 {
+		t.Errorf("got %d loads of an expired distribution, want 2", loads["EUW"])
+	}
+
+	// A failed load isn't cached.
+	failing := func(string) (*data.SummonerDistribution, error) { return nil, errors.New("boom") }
+	if _, err := cache.get("KR", failing); err == nil {
+		t.Error("got no error from a failed load")
+	}
+	if _, ok := cache.entries["KR"]; ok {
+		t.Error("a failed load was cached")
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		share float64
+		want  float64
+	}{
+		{0, 0},
+		{1, 100},
+		{0.25, 25},
+		{0.95123, 95.1},
+		{0.99999, 100},
+	}
+
+	for _, tt := range tests {
+		if got := percent(tt.share); got != tt.want {
+			t.Errorf("percent(%v) = %v, want %v", tt.share, got, tt.want)
+		}
+	}
+}
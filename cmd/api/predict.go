@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// predictMatchHandler predicts the blue team's chance of winning a match between two team
+// compositions, from the champions' win rates, how they fare alongside and against each other
+// and, when summoners are given, the teams' ratings. The response breaks the prediction down by
+// factor.
+func (app *application) predictMatchHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Blue data.TeamComp `json:"blue"`
+		Red  data.TeamComp `json:"red"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	if data.ValidatePrediction(v, &input.Blue, &input.Red); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	champions := append(append([]int64{}, input.Blue.Champions...), input.Red.Champions...)
+	summoners := append(append([]int64{}, input.Blue.Summoners...), input.Red.Summoners...)
+
+	records, err := app.models.Champions.GetPredictionRecords(champions, summoners)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	for _, id := range champions {
+		if _, ok := records.Champions[id]; !ok {
+			v.AddError("champions", "must only contain existing champions")
+			break
+		}
+	}
+	for _, id := range summoners {
+		if _, ok := records.Ratings[id]; !ok {
+			v.AddError("summoners", "must only contain existing summoners")
+			break
+		}
+	}
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	weights := data.PredictionWeights{
+		ChampionWeight: app.config.predict.championWeight,
+		SynergyWeight:  app.config.predict.synergyWeight,
+		CounterWeight:  app.config.predict.counterWeight,
+		SkillWeight:    app.config.predict.skillWeight,
+	}
+
+	prediction := data.PredictOutcome(&input.Blue, &input.Red, records, weights)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"prediction": prediction}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
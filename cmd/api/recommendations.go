@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// banRecommendationsHandler returns the champions most worth banning in a role: those which are
+// both picked often and win more than they lose.
+func (app *application) banRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	role := data.NormalizeRole(app.readString(qs, "role", ""))
+	limit := app.readInt(qs, "limit", 5, v)
+	scope := app.readStatsScope(qs, v)
+
+	v.Check(role != "", "role", "must be provided")
+	v.Check(limit > 0, "limit", "must be greater than zero")
+	v.Check(limit <= 50, "limit", "must be a maximum of 50")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	stats, err := app.models.Champions.GetRoleStats(role, scope)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	weights := data.BanWeights{
+		PickRateWeight: app.config.bans.pickRateWeight,
+		WinRateWeight:  app.config.bans.winRateWeight,
+	}
+
+	bans := data.RankBans(stats, weights, app.config.bans.minGames, limit)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"role": role, "matchType": scope.MatchType, "rankTiers": scope.Tiers, "bans": bans}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// summonerBanSuggestionsHandler suggests bans for a summoner: the enemy champions they have the
+// worst record against in their own match history, among those faced in at least ?min_games=
+// matches (5 by default).
+func (app *application) summonerBanSuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	minGames := app.readInt(qs, "min_games", 5, v)
+	limit := app.readInt(qs, "limit", 5, v)
+
+	v.Check(minGames > 0, "min_games", "must be greater than zero")
+	v.Check(limit > 0, "limit", "must be greater than zero")
+	v.Check(limit <= 50, "limit", "must be a maximum of 50")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if !app.showSummonerStats(w, r, summoner) {
+		return
+	}
+
+	bans, err := app.models.Summoners.GetBanSuggestions(id, minGames, limit)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"bans": bans}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// Recompute job statuses.
+const (
+	jobRunning = "running"
+	jobDone    = "done"
+	jobError   = "error"
+)
+
+// errRecomputeRunning is returned by recomputeJobs.start when a recompute is already in progress.
+var errRecomputeRunning = errors.New("a recompute is already running")
+
+// recomputeJob records the progress of one stats recompute started through the admin API.
+type recomputeJob struct {
+	ID           int64      `json:"id"`
+	Scope        string     `json:"scope"`
+	DryRun       bool       `json:"dryRun"`
+	Status       string     `json:"status"`
+	RecordsFixed int64      `json:"recordsFixed"`
+	Error        string     `json:"error,omitempty"`
+	StartedAt    time.Time  `json:"startedAt"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+}
+
+// recomputeJobs keeps track of recompute jobs in memory, and ensures only one runs at a time.
+// Job history is lost when the server restarts.
+type recomputeJobs struct {
+	mu     sync.Mutex
+	nextID int64
+	jobs   map[int64]*recomputeJob
+	active bool
+}
+
+func newRecomputeJobs() *recomputeJobs {
+	return &recomputeJobs{nextID: 1, jobs: make(map[int64]*recomputeJob)}
+}
+
+// start registers a new running job, or returns errRecomputeRunning if one is already running.
+func (j *recomputeJobs) start(scope string, dryRun bool) (recomputeJob, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.active {
+		return recomputeJob{}, errRecomputeRunning
+	}
+
+	job := &recomputeJob{ID: j.nextID, Scope: scope, DryRun: dryRun, Status: jobRunning, StartedAt: time.Now()}
+	j.jobs[job.ID] = job
+	j.nextID++
+	j.active = true
+
+	return *job, nil
+}
+
+// finish records the outcome of a job and allows the next one to start.
+func (j *recomputeJobs) finish(id int64, recordsFixed int64, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job := j.jobs[id]
+	now := time.Now()
+	job.FinishedAt = &now
+	job.RecordsFixed = recordsFixed
+	job.Status = jobDone
+	if err != nil {
+		job.Status = jobError
+		job.Error = err.Error()
+	}
+	j.active = false
+}
+
+// get returns a copy of a job, so it can be safely encoded while the job is still running.
+func (j *recomputeJobs) get(id int64) (recomputeJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job, ok := j.jobs[id]
+	if !ok {
+		return recomputeJob{}, false
+	}
+	return *job, true
+}
+
+// startRecomputeHandler starts recomputing stored stats from match data in the background and
+// returns the job, which can be polled with showRecomputeHandler. ?scope= chooses what to
+// recompute: champions, summoners or all (the default). With ?dry_run=true nothing is changed, but
+// the job still reports how many records would be fixed. Only one recompute may run at a time.
+func (app *application) startRecomputeHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+	qs := r.URL.Query()
+
+	scope := app.readString(qs, "scope", "all")
+	v.Check(validator.In(scope, "champions", "summoners", "all"), "scope", "must be champions, summoners or all")
+
+	dryRun := app.readBool(qs, "dry_run", false, v)
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	job, err := app.recomputeJobs.start(scope, dryRun)
+	if err != nil {
+		app.errorResponse(w, r, http.StatusConflict, err.Error())
+		return
+	}
+
+	app.background(func() {
+		var fixed int64
+		var err error
+
+		// Finish the job even if the recompute panics, turning the panic into the job's error, or
+		// the job would be left running and no other recompute could start.
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("panic: %v", p)
+			}
+			app.recomputeJobs.finish(job.ID, fixed, err)
+			if err != nil {
+				app.logger.PrintError(err, map[string]string{"job": strconv.FormatInt(job.ID, 10), "scope": scope})
+			}
+		}()
+
+		fixed, err = app.recompute(scope, dryRun)
+	})
+
+	headers := make(http.Header)
+	headers.Set("Location", app.url("/v1/admin/recompute/%d", job.ID))
+
+	err = app.writeJSON(w, http.StatusAccepted, envelope{"job": job}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showRecomputeHandler returns the status of a recompute job.
+func (app *application) showRecomputeHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	job, ok := app.recomputeJobs.get(id)
+	if !ok {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"job": job}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// recompute recalculates the stored stats in the given scope and returns the number of records
+// which were changed. A dry run only counts the stats which would change; main roles, which are
+// recalculated champion by champion, aren't included in it.
+func (app *application) recompute(scope string, dryRun bool) (int64, error) {
+	var fixed int64
+
+	if scope == "champions" || scope == "all" {
+		changes, err := app.models.Champions.RecomputeStats(dryRun)
+		if err != nil {
+			return fixed, fmt.Errorf("recompute champions: %w", err)
+		}
+		fixed += int64(len(changes))
+
+		if !dryRun {
+			app.notifyChampionStatsChanged(changes)
+
+			roles, err := app.models.Champions.RecalculateAllMainRoles(app.config.mainRoleMargin)
+			if err != nil {
+				return fixed, fmt.Errorf("recalculate main roles: %w", err)
+			}
+			fixed += int64(roles)
+		}
+	}
+
+	if scope == "summoners" || scope == "all" {
+		n, err := app.models.Summoners.RecomputeStats(dryRun)
+		if err != nil {
+			return fixed, fmt.Errorf("recompute summoners: %w", err)
+		}
+		fixed += n
+	}
+
+	return fixed, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+func TestRecomputeJobs(t *testing.T) {
+	jobs := newRecomputeJobs()
+
+	job, err := jobs.start("champions", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != jobRunning || !job.DryRun || job.Scope != "champions" {
+		t.Errorf("got job %+v, want a running champions dry run", job)
+	}
+
+	_, err = jobs.start("all", false)
+	if !errors.Is(err, errRecomputeRunning) {
+		t.Errorf("got error %v starting a second job, want %v", err, errRecomputeRunning)
+	}
+
+	jobs.finish(job.ID, 3, nil)
+
+	got, ok := jobs.get(job.ID)
+	if !ok {
+		t.Fatalf("job %d not found", job.ID)
+	}
+	if got.Status != jobDone || got.RecordsFixed != 3 || got.FinishedAt == nil {
+		t.Errorf("got job %+v, want done with 3 records fixed", got)
+	}
+
+	next, err := jobs.start("summoners", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs.finish(next.ID, 0, errors.New("boom"))
+
+	got, _ = jobs.get(next.ID)
+	if got.Status != jobError || got.Error != "boom" {
+		t.Errorf("got job %+v, want an error status", got)
+	}
+}
+
+// runRecompute starts a recompute through the admin endpoint and polls the job until it's no
+// longer running.
+func runRecompute(t *testing.T, app *application, query string) recomputeJob {
+	t.Helper()
+
+	handler := app.router()
+
+	r := withAPIKey(app, httptest.NewRequest(http.MethodPost, "/v1/admin/recompute"+query, nil), "system:write")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, r)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d: %s", rr.Code, http.StatusAccepted, rr.Body)
+	}
+
+	var started struct {
+		Job recomputeJob `json:"job"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&started); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		r := withAPIKey(app, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/admin/recompute/%d", started.Job.ID), nil), "system:write")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", rr.Code, http.StatusOK, rr.Body)
+		}
+
+		var polled struct {
+			Job recomputeJob `json:"job"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&polled); err != nil {
+			t.Fatal(err)
+		}
+		if polled.Job.Status != jobRunning {
+			return polled.Job
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("job %d still running", started.Job.ID)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRecomputeCompletes(t *testing.T) {
+	db := newTestDB(t)
+	app := &application{
+		logger:        jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:        data.NewModels(db, nil),
+		features:      newFeatureFlags(),
+		recomputeJobs: newRecomputeJobs(),
+	}
+
+	ahri := &data.Champion{Name: "Ahri", MainRole: "Mid", Classes: data.ChampionClasses{}}
+	if err := app.models.Champions.Insert(ahri); err != nil {
+		t.Fatal(err)
+	}
+	faker := &data.Summoner{Username: "Faker", Region: "KR"}
+	if err := app.models.Summoners.Insert(faker); err != nil {
+		t.Fatal(err)
+	}
+
+	// A won match stored without updating the champion's stats, so they're out of date.
+	var matchID int64
+	err := db.QueryRow(`
+        INSERT INTO matches (duration, result, match_type, blue_team, red_team)
+        VALUES (1800, 'blue', 'solo_queue', '{}', '{}')
+        RETURNING id`).Scan(&matchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`
+        INSERT INTO match_performance (match_id, summoner_id, champion_id, team, role)
+        VALUES ($1, $2, $3, 'blue', 'Mid')`, matchID, faker.ID, ahri.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := runRecompute(t, app, "?scope=champions")
+	if job.Status != jobDone || job.RecordsFixed != 1 || job.FinishedAt == nil {
+		t.Fatalf("got job %+v, want done with 1 record fixed", job)
+	}
+
+	got, err := app.models.Champions.Get(ahri.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.WinRate != 1 || got.Popularity != 1 {
+		t.Errorf("got a win rate of %v and popularity %v, want 1 and 1", got.WinRate, got.Popularity)
+	}
+}
+
+func TestRecomputePanicFinishesJob(t *testing.T) {
+	// With no database, the recompute panics on a nil pointer.
+	app := &application{
+		logger:        jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		features:      newFeatureFlags(),
+		recomputeJobs: newRecomputeJobs(),
+	}
+
+	job := runRecompute(t, app, "?scope=summoners")
+	if job.Status != jobError || !strings.HasPrefix(job.Error, "panic: ") || job.FinishedAt == nil {
+		t.Fatalf("got job %+v, want an error from the panic", job)
+	}
+
+	// The failed job no longer blocks the next one.
+	if _, err := app.recomputeJobs.start("all", true); err != nil {
+		t.Errorf("got error %v starting another job, want nil", err)
+	}
+}
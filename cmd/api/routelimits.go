@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"league_of_graphs.satellite.net/internal/jsonlog"
+	"league_of_graphs.satellite.net/internal/ratelimit"
+)
+
+// routeLimit is a rate limit which overrides the global one for a single route.
+type routeLimit struct {
+	rps   float64
+	burst int
+}
+
+// parseRouteLimits parses the -limiter-routes flag: a comma-separated list of overrides in the
+// form "METHOD /route/template=rps:burst", where the template is written as the router matches it,
+// e.g. "GET /v1/champions/:id=5:10".
+func parseRouteLimits(s string) (map[string]routeLimit, error) {
+	limits := make(map[string]routeLimit)
+
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		template, limit, ok := strings.Cut(entry, "=")
+		rps, burst, ok2 := strings.Cut(limit, ":")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("invalid route limit %q: must be in the form \"METHOD /path=rps:burst\"", entry)
+		}
+
+		method, path, ok := strings.Cut(strings.TrimSpace(template), " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid route limit %q: route must be a method and path", entry)
+		}
+
+		var l routeLimit
+		var err error
+
+		l.rps, err = strconv.ParseFloat(rps, 64)
+		if err != nil || l.rps <= 0 {
+			return nil, fmt.Errorf("invalid route limit %q: rps must be a number greater than zero", entry)
+		}
+
+		l.burst, err = strconv.Atoi(burst)
+		if err != nil || l.burst < 1 {
+			return nil, fmt.Errorf("invalid route limit %q: burst must be an integer greater than zero", entry)
+		}
+
+		limits[strings.ToUpper(method)+" "+path] = l
+	}
+
+	return limits, nil
+}
+
+// newRouteLimiters creates a limiter, on the configured backend, for each route limit override.
+func newRouteLimiters(cfg config, logger *jsonlog.Logger) (map[string]ratelimit.Limiter, error) {
+	limiters := make(map[string]ratelimit.Limiter, len(cfg.limiter.routes))
+
+	for template, limit := range cfg.limiter.routes {
+		limiter, err := newLimiter(cfg, limit.rps, limit.burst, logger)
+		if err != nil {
+			return nil, err
+		}
+		limiters[template] = limiter
+	}
+
+	return limiters, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/ratelimit"
+)
+
+func TestParseRouteLimits(t *testing.T) {
+	tests := []struct {
+		flag string
+		want map[string]routeLimit
+	}{
+		{"", map[string]routeLimit{}},
+		{"GET /v1/champions/:id=5:10", map[string]routeLimit{"GET /v1/champions/:id": {5, 10}}},
+		{
+			" get /v1/summoners=0.5:1 , POST /v1/matches=2:4,",
+			map[string]routeLimit{"GET /v1/summoners": {0.5, 1}, "POST /v1/matches": {2, 4}},
+		},
+		// A later override of the same route wins.
+		{"GET /v1/healthcheck=1:1,GET /v1/healthcheck=2:2", map[string]routeLimit{"GET /v1/healthcheck": {2, 2}}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRouteLimits(tt.flag)
+		if err != nil {
+			t.Errorf("parseRouteLimits(%q): %v", tt.flag, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseRouteLimits(%q) = %v, want %v", tt.flag, got, tt.want)
+		}
+	}
+}
+
+func TestParseRouteLimitsInvalid(t *testing.T) {
+	for _, flag := range []string{
+		"GET /v1/champions",
+		"GET /v1/champions=5",
+		"/v1/champions=5:10",
+		"GET v1/champions=5:10",
+		"GET /v1/champions=0:10",
+		"GET /v1/champions=-1:10",
+		"GET /v1/champions=fast:10",
+		"GET /v1/champions=5:0",
+		"GET /v1/champions=5:1.5",
+		"GET /v1/champions=5:10,oops",
+	} {
+		if got, err := parseRouteLimits(flag); err == nil {
+			t.Errorf("parseRouteLimits(%q) = %v, want an error", flag, got)
+		}
+	}
+}
+
+func TestRateLimitRouteOverride(t *testing.T) {
+	app := newLimitedApp(10)
+	app.routeLimiters = map[string]ratelimit.Limiter{
+		"GET /v1/champions/synergy-matrix": ratelimit.NewMemory(0.001, 2),
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	router := newAppRouter()
+	router.StaticHandlerFunc(http.MethodGet, "/v1/champions/synergy-matrix", ok)
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id", ok)
+	handler := app.rateLimit(router, router)
+
+	// The same client alternates between the two routes, so both see the same traffic.
+	allowed := map[string]int{}
+	for i := 0; i < 10; i++ {
+		for _, path := range []string{"/v1/champions/synergy-matrix", "/v1/champions/1"} {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, app.contextSetUser(httptest.NewRequest(http.MethodGet, path, nil), data.AnonymousUser))
+			if rr.Code == http.StatusOK {
+				allowed[path]++
+			}
+		}
+	}
+
+	// Requests to the expensive route don't use up the global bucket either.
+	want := map[string]int{"/v1/champions/synergy-matrix": 2, "/v1/champions/1": 10}
+	if !reflect.DeepEqual(allowed, want) {
+		t.Errorf("got requests allowed %v, want %v", allowed, want)
+	}
+}
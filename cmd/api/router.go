@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// appRouter wraps httprouter.Router, adding support for static routes which share a path prefix
+// with a wildcard route, such as "/v1/champions/recommendations/bans" alongside
+// "/v1/champions/:id". httprouter refuses to register these, so they are matched exactly before
+// the request is handed on to httprouter.
+type appRouter struct {
+	*httprouter.Router
+	static   map[string]map[string]http.Handler // path -> method -> handler
+	patterns map[string][]string                // method -> httprouter route patterns
+}
+
+func newAppRouter() *appRouter {
+	return &appRouter{
+		Router:   httprouter.New(),
+		static:   make(map[string]map[string]http.Handler),
+		patterns: make(map[string][]string),
+	}
+}
+
+// Handle registers an httprouter route, remembering its pattern for template. Handler and
+// HandlerFunc are overridden too, since httprouter's versions don't go through this method.
+func (rt *appRouter) Handle(method, path string, handle httprouter.Handle) {
+	rt.patterns[method] = append(rt.patterns[method], path)
+	rt.Router.Handle(method, path, handle)
+}
+
+func (rt *appRouter) Handler(method, path string, handler http.Handler) {
+	rt.patterns[method] = append(rt.patterns[method], path)
+	rt.Router.Handler(method, path, handler)
+}
+
+func (rt *appRouter) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	rt.Handler(method, path, handler)
+}
+
+// StaticHandlerFunc registers a handler for an exact method and path, taking precedence over any
+// httprouter route which would otherwise match it.
+func (rt *appRouter) StaticHandlerFunc(method, path string, handler http.HandlerFunc) {
+	if rt.static[path] == nil {
+		rt.static[path] = make(map[string]http.Handler)
+	}
+	rt.static[path][method] = handler
+}
+
+func (rt *appRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if methods, ok := rt.static[r.URL.Path]; ok {
+		if handler, ok := methods[r.Method]; ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		// Answer OPTIONS and 405s with the same Allow header httprouter sends for its own routes.
+		w.Header().Set("Allow", allowedMethods(methods))
+
+		if r.Method == http.MethodOptions && rt.HandleOPTIONS {
+			if rt.GlobalOPTIONS != nil {
+				rt.GlobalOPTIONS.ServeHTTP(w, r)
+			}
+			return
+		}
+
+		rt.MethodNotAllowed.ServeHTTP(w, r)
+		return
+	}
+
+	rt.Router.ServeHTTP(w, r)
+}
+
+// allowedMethods returns the value of the Allow header for a static path: its methods and
+// OPTIONS, sorted and comma separated, as httprouter formats it.
+func allowedMethods(methods map[string]http.Handler) string {
+	allowed := make([]string, 0, len(methods)+1)
+	for method := range methods {
+		if method != http.MethodOptions {
+			allowed = append(allowed, method)
+		}
+	}
+	allowed = append(allowed, http.MethodOptions)
+	sort.Strings(allowed)
+
+	return strings.Join(allowed, ", ")
+}
+
+// template returns the method and route pattern which match the request, such as
+// "GET /v1/champions/:id". Requests which don't match any route are grouped together under
+// "unmatched", so that arbitrary paths can't grow the set of templates without bound.
+func (rt *appRouter) template(r *http.Request) string {
+	if _, ok := rt.static[r.URL.Path][r.Method]; ok {
+		return r.Method + " " + r.URL.Path
+	}
+
+	handle, _, _ := rt.Lookup(r.Method, r.URL.Path)
+	if handle == nil {
+		return "unmatched"
+	}
+
+	// httprouter doesn't say which route matched, but it refuses to register routes which could
+	// match the same path, so the only registered pattern which matches it must be the one.
+	for _, pattern := range rt.patterns[r.Method] {
+		if patternMatches(pattern, r.URL.Path) {
+			return r.Method + " " + pattern
+		}
+	}
+
+	return "unmatched"
+}
+
+// patternMatches reports whether an httprouter route pattern matches path. A ":name" segment
+// matches any single non-empty segment, and a trailing "*name" matches the rest of the path.
+func patternMatches(pattern, path string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+
+	for i, segment := range patternSegments {
+		switch {
+		case strings.HasPrefix(segment, "*"):
+			return i < len(pathSegments)
+		case i >= len(pathSegments):
+			return false
+		case strings.HasPrefix(segment, ":"):
+			if pathSegments[i] == "" {
+				return false
+			}
+		case segment != pathSegments[i]:
+			return false
+		}
+	}
+
+	return len(patternSegments) == len(pathSegments)
+}
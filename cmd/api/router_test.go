@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAppRouterTemplate(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	router := newAppRouter()
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id", noop)
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/counters", noop)
+	router.HandlerFunc(http.MethodGet, "/v1/summoners/:id/matches/:match", noop)
+	router.HandlerFunc(http.MethodGet, "/static/*filepath", noop)
+	router.StaticHandlerFunc(http.MethodGet, "/v1/champions/recommendations/bans", noop)
+
+	tests := []struct {
+		method, path string
+		want         string
+	}{
+		{http.MethodGet, "/v1/champions/42", "GET /v1/champions/:id"},
+		// A parameter value which repeats a static segment must not be mistaken for it.
+		{http.MethodGet, "/v1/champions/v1", "GET /v1/champions/:id"},
+		{http.MethodGet, "/v1/champions/counters/counters", "GET /v1/champions/:id/counters"},
+		{http.MethodGet, "/v1/summoners/7/matches/7", "GET /v1/summoners/:id/matches/:match"},
+		{http.MethodGet, "/static/css/site.css", "GET /static/*filepath"},
+		{http.MethodGet, "/v1/champions/recommendations/bans", "GET /v1/champions/recommendations/bans"},
+		{http.MethodPost, "/v1/champions/42", "unmatched"},
+		{http.MethodGet, "/v1/unknown", "unmatched"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.path, nil)
+		if got := router.template(r); got != tt.want {
+			t.Errorf("%s %s: got %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/v1/champions/:id", "/v1/champions/1", true},
+		{"/v1/champions/:id", "/v1/champions/", false},
+		{"/v1/champions/:id", "/v1/champions/1/counters", false},
+		{"/v1/champions/:id/counters", "/v1/champions/1", false},
+		{"/files/*filepath", "/files/a/b", true},
+		{"/files/*filepath", "/files", false},
+		{"/v1/healthcheck", "/v1/healthcheck", true},
+		{"/v1/healthcheck", "/v1/health", false},
+	}
+
+	for _, tt := range tests {
+		if got := patternMatches(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("patternMatches(%q, %q) = %t, want %t", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAllowHeader(t *testing.T) {
+	app := &application{features: newFeatureFlags()}
+	router := app.router()
+
+	tests := []struct {
+		method, path string
+		status       int
+		allow        string
+	}{
+		{http.MethodDelete, "/v1/healthcheck", http.StatusMethodNotAllowed, "GET, OPTIONS"},
+		{http.MethodOptions, "/v1/healthcheck", http.StatusOK, "GET, OPTIONS"},
+		{http.MethodPost, "/v1/champions/42", http.StatusMethodNotAllowed, "DELETE, GET, OPTIONS, PATCH, PUT"},
+		// Static routes answer the same way as httprouter's own.
+		{http.MethodPut, "/v1/champions/compare", http.StatusMethodNotAllowed, "GET, OPTIONS"},
+		{http.MethodOptions, "/v1/champions/compare", http.StatusOK, "GET, OPTIONS"},
+		{http.MethodGet, "/v1/champions/bulk", http.StatusMethodNotAllowed, "OPTIONS, POST"},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if rr.Code != tt.status {
+			t.Errorf("%s %s: got status %d, want %d", tt.method, tt.path, rr.Code, tt.status)
+		}
+		if got := rr.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: got Allow %q, want %q", tt.method, tt.path, got, tt.allow)
+		}
+		if tt.status == http.StatusMethodNotAllowed && !strings.Contains(rr.Body.String(), "(allowed methods: "+tt.allow+")") {
+			t.Errorf("%s %s: got body %s, want it to list the allowed methods", tt.method, tt.path, rr.Body)
+		}
+	}
+}
@@ -1,44 +1,116 @@
 package main
 
 import (
+	"expvar"
 	"net/http"
-
-	"github.com/julienschmidt/httprouter"
 )
 
 func (app *application) routes() http.Handler {
-	// Initialize a new httprouter router instance.
-	router := httprouter.New()
+	router := app.router()
+
+	return app.metrics(router, app.recoverPanic(app.enableCORS(app.rateLimitIP(app.authenticate(app.rateLimit(router, router))))))
+}
+
+// router registers every route on a new router, without the middleware which routes() wraps it
+// in.
+func (app *application) router() *appRouter {
+	// Initialize a new router instance.
+	router := newAppRouter()
 
 	router.NotFound = http.HandlerFunc(app.notFoundResponse)
 
 	router.MethodNotAllowed = http.HandlerFunc(app.methodNotAllowedResponse)
 
 	router.HandlerFunc(http.MethodGet, "/v1/healthcheck", app.healthcheckHandler)
+	// The published variables include the command line, which holds secrets such as the DSN.
+	router.HandlerFunc(http.MethodGet, "/debug/vars", app.requirePermissions("system:write", expvar.Handler().ServeHTTP))
 
 	router.HandlerFunc(http.MethodPost, "/v1/summoners", app.createSummonerHandler)
 	router.HandlerFunc(http.MethodGet, "/v1/summoners/:id", app.showSummonerHandler)
 	router.HandlerFunc(http.MethodPost, "/v1/matches", app.createMatchHandler)
+	router.StaticHandlerFunc(http.MethodPost, "/v1/matches/import", app.requireFeature(featureMatchImport, app.requirePermissions("matches:write", app.importMatchHandler)))
 	router.HandlerFunc(http.MethodGet, "/v1/matches/:id", app.showMatchHandler)
 	router.HandlerFunc(http.MethodPost, "/v1/champions", app.createChampionHandler)
+	router.StaticHandlerFunc(http.MethodPost, "/v1/champions/bulk", app.requirePermissions("champions:write", app.createChampionsBulkHandler))
 	router.HandlerFunc(http.MethodGet, "/v1/champions/:id", app.showChampionHandler)
 	router.HandlerFunc(http.MethodPut, "/v1/champions/:id", app.updateChampionHandler)
+	router.HandlerFunc(http.MethodPatch, "/v1/champions/:id", app.patchChampionHandler)
 	router.HandlerFunc(http.MethodPut, "/v1/summoners/:id", app.updateSummonerHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/summoners/:id/streak", app.showSummonerStreakHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/summoners/:id/activity", app.showSummonerActivityHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/summoners/:id/kda-distribution", app.showSummonerKDADistributionHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/summoners/:id/champions", app.showSummonerChampionsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/summoners/:id/matches", app.showSummonerMatchesHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/summoners/:id/ban-suggestions", app.summonerBanSuggestionsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/summoners/:id/decay-forecast", app.showSummonerDecayForecastHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/summoners/:id/transfer", app.requirePermissions("summoners:write", app.transferSummonerHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/summoners/:id/pro", app.requirePermissions("system:write", app.setSummonerProHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/summoners/:id/owner-verified", app.requirePermissions("system:write", app.setSummonerOwnerVerifiedHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/summoners/:id/merge", app.requirePermissions("system:write", app.mergeSummonerHandler))
 	router.HandlerFunc(http.MethodPut, "/v1/matches/:id", app.updateMatchHandler)
 	router.HandlerFunc(http.MethodDelete, "/v1/summoners/:id", app.deleteSummonerHandler)
 	router.HandlerFunc(http.MethodDelete, "/v1/matches/:id", app.deleteMatchHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/matches", app.requirePermissions("matches:write", app.deleteOldMatchesHandler))
 	router.HandlerFunc(http.MethodDelete, "/v1/champions/:id", app.deleteChampionHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/champions/:id/recalculate-role", app.requirePermissions("champions:write", app.recalculateChampionRoleHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/items", app.showChampionItemsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/draft-timing", app.showChampionDraftTimingHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/similar", app.requireFeature(featureSimilarChampions, app.showSimilarChampionsHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/win-rate-history", app.showChampionWinRateHistoryHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/pros", app.showChampionProsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/best-summoners", app.showChampionBestSummonersHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/vs-role", app.showChampionVsRoleHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/lane-matchup", app.showChampionLaneMatchupHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/global", app.showChampionGlobalStatsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/champions/:id/aliases", app.requirePermissions("system:write", app.addChampionAliasesHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/champions/:id/notes", app.requirePermissions("champions:write", app.createChampionNoteHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/champions/:id/matches", app.showChampionMatchesHandler)
+	router.HandlerFunc(http.MethodPut, "/v1/champions/:id/power-spikes", app.requirePermissions("champions:write", app.updateChampionPowerSpikesHandler))
 	router.HandlerFunc(http.MethodGet, "/v1/matches", app.listMatchesHandler)
 	router.HandlerFunc(http.MethodGet, "/v1/champions", app.listChampionsHandler)
+	router.StaticHandlerFunc(http.MethodGet, "/v1/champions/recommendations/bans", app.banRecommendationsHandler)
+	router.StaticHandlerFunc(http.MethodGet, "/v1/champions/compare", app.compareChampionsHandler)
+	router.StaticHandlerFunc(http.MethodGet, "/v1/champions/synergy-matrix", app.requirePermissions("champions:read", app.synergyMatrixHandler))
+	router.StaticHandlerFunc(http.MethodGet, "/v1/champions/role-drift", app.requireFeature(featureRoleDrift, app.requirePermissions("system:write", app.roleDriftReportHandler)))
 	router.HandlerFunc(http.MethodGet, "/v1/summoners", app.listSummonersHandler)
+	router.StaticHandlerFunc(http.MethodGet, "/v1/summoners/count", app.countSummonersHandler)
+	router.StaticHandlerFunc(http.MethodPost, "/v1/summoners/bulk-upsert", app.requirePermissions("system:write", app.upsertSummonersBulkHandler))
+
+	router.HandlerFunc(http.MethodPost, "/v1/draft/suggest", app.suggestDraftHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/predict", app.predictMatchHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/stats/objectives", app.objectiveStatsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/stats/correlations", app.correlationStatsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/stats/summary", app.statsSummaryHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/system/announcement", app.showAnnouncementHandler)
+	router.HandlerFunc(http.MethodPut, "/v1/system/announcement", app.requirePermissions("system:write", app.updateAnnouncementHandler))
+
+	router.HandlerFunc(http.MethodPost, "/v1/admin/recompute", app.requirePermissions("system:write", app.startRecomputeHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/admin/recompute/:id", app.requirePermissions("system:write", app.showRecomputeHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/admin/explain", app.requirePermissions("system:write", app.explainHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/admin/features", app.requirePermissions("system:write", app.listFeaturesHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/admin/features/:name", app.requirePermissions("system:write", app.updateFeatureHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/admin/webhooks", app.requireFeature(featureWebhooks, app.requirePermissions("system:write", app.listWebhooksHandler)))
+	router.HandlerFunc(http.MethodPost, "/v1/admin/webhooks", app.requireFeature(featureWebhooks, app.requirePermissions("system:write", app.createWebhookHandler)))
+	router.HandlerFunc(http.MethodDelete, "/v1/admin/webhooks/:id", app.requireFeature(featureWebhooks, app.requirePermissions("system:write", app.deleteWebhookHandler)))
+	router.HandlerFunc(http.MethodGet, "/v1/admin/api-keys", app.requirePermissions("system:write", app.listAPIKeysHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/admin/api-keys", app.requirePermissions("system:write", app.createAPIKeyHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/admin/api-keys/:id", app.requirePermissions("system:write", app.revokeAPIKeyHandler))
 
 	router.HandlerFunc(http.MethodPost, "/v1/users", app.registerUserHandler)
 	router.HandlerFunc(http.MethodPut, "/v1/users/activated", app.activateUserHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/users/me/permissions", app.requireAuthenticatedUser(app.showUserPermissionsHandler))
 
 	router.HandlerFunc(http.MethodPost, "/v1/tokens/authentication", app.createAuthenticationTokenHandler)
 	// Return the httprouter instance.
 
 	router.HandlerFunc(http.MethodGet, "/v1/matches/:id/summoners", app.getSummonersByMatch)
+	router.HandlerFunc(http.MethodGet, "/v1/matches/:id/damage-profile", app.showMatchDamageProfileHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/matches/:id/timeline", app.showMatchTimelineHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/matches/:id/timeline", app.requirePermissions("matches:write", app.addMatchEventsHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/matches/:id/gold-diff", app.showMatchGoldDiffHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/matches/:id/tags", app.requirePermissions("matches:write", app.addMatchTagsHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/matches/:id/tags", app.requirePermissions("matches:write", app.removeMatchTagsHandler))
 
-	return app.metrics(app.recoverPanic(app.enableCORS(app.rateLimit(app.authenticate(router)))))
+	return router
 }
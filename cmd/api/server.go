@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// serve starts the API server and blocks until it stops. If a TLS certificate and key were
+// configured the API is served over HTTPS (with HTTP/2, which net/http negotiates automatically
+// for TLS servers), otherwise over plain HTTP as before. With -grpc-addr the gRPC read API is
+// served alongside it, over TLS too when it's configured. On SIGINT or SIGTERM both servers stop
+// accepting connections and are given shutdownTimeout to finish their in-flight requests.
+func (app *application) serve() error {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", app.config.port))
+	if err != nil {
+		return err
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	return app.serveListener(ln, app.routes(), quit)
+}
+
+// serveListener serves handler on ln, as described for serve, until a signal is received on quit.
+func (app *application) serveListener(ln net.Listener, handler http.Handler, quit <-chan os.Signal) error {
+	srv := &http.Server{
+		Addr:         ln.Addr().String(),
+		Handler:      handler,
+		IdleTimeout:  time.Minute,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	var grpcSrv *grpc.Server
+	if app.config.grpcAddr != "" {
+		var err error
+		grpcSrv, err = app.serveGRPC()
+		if err != nil {
+			return err
+		}
+	}
+
+	shutdownError := make(chan error)
+
+	go func() {
+		s := <-quit
+
+		app.logger.PrintInfo("shutting down server", map[string]string{
+			"signal": s.String(),
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if grpcSrv != nil {
+			stopGRPC(ctx, grpcSrv)
+		}
+
+		shutdownError <- srv.Shutdown(ctx)
+	}()
+
+	var err error
+
+	if !app.config.tls.enabled() {
+		app.logger.PrintInfo("starting server", map[string]string{
+			"addr": srv.Addr,
+			"env":  app.config.env,
+		})
+
+		err = srv.Serve(ln)
+	} else {
+		srv.TLSConfig = serverTLSConfig()
+
+		if app.config.tls.redirectAddr != "" {
+			go app.serveHTTPSRedirect()
+		}
+
+		app.logger.PrintInfo("starting server", map[string]string{
+			"addr": srv.Addr,
+			"env":  app.config.env,
+			"tls":  "true",
+		})
+
+		err = srv.ServeTLS(ln, app.config.tls.certFile, app.config.tls.keyFile)
+	}
+
+	// Serve returns ErrServerClosed as soon as Shutdown is called, so wait for the shutdown itself
+	// to finish.
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	err = <-shutdownError
+	if err != nil {
+		return err
+	}
+
+	app.logger.PrintInfo("stopped server", map[string]string{
+		"addr": srv.Addr,
+	})
+
+	return nil
+}
+
+// shutdownTimeout is how long in-flight requests are given to finish once a shutdown starts.
+const shutdownTimeout = 30 * time.Second
+
+// serverTLSConfig returns the TLS settings shared by the HTTPS and gRPC servers.
+func serverTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{
+			tls.X25519,
+			tls.CurveP256,
+		},
+	}
+}
+
+// serveGRPC starts the gRPC server on the configured address in the background and returns it.
+// Listening happens before it returns, so a bad address stops startup like it would for HTTP.
+func (app *application) serveGRPC() (*grpc.Server, error) {
+	var opts []grpc.ServerOption
+	if app.config.tls.enabled() {
+		cert, err := tls.LoadX509KeyPair(app.config.tls.certFile, app.config.tls.keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig := serverTLSConfig()
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	lis, err := net.Listen("tcp", app.config.grpcAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	srv := app.newGRPCServer(opts...)
+
+	app.logger.PrintInfo("starting grpc server", map[string]string{
+		"addr": lis.Addr().String(),
+		"tls":  strconv.FormatBool(app.config.tls.enabled()),
+	})
+
+	go func() {
+		err := srv.Serve(lis)
+		if err != nil {
+			app.logger.PrintError(err, map[string]string{"addr": lis.Addr().String()})
+		}
+	}()
+
+	return srv, nil
+}
+
+// stopGRPC stops the gRPC server gracefully, waiting for in-flight calls to finish, but cancels
+// any which are still running once ctx is done.
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
+
+// serveHTTPSRedirect listens for plain HTTP requests on the configured redirect address and
+// permanently redirects them to the same path on the HTTPS server. It only logs if the listener
+// fails, as the HTTPS server is still usable without it.
+func (app *application) serveHTTPSRedirect() {
+	port := strconv.Itoa(app.config.port)
+
+	srv := &http.Server{
+		Addr: app.config.tls.redirectAddr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host, _, err := net.SplitHostPort(r.Host)
+			if err != nil {
+				host = r.Host
+			}
+
+			target := "https://" + net.JoinHostPort(host, port) + r.URL.RequestURI()
+			if port == "443" {
+				target = "https://" + host + r.URL.RequestURI()
+			}
+
+			http.Redirect(w, r, target, http.StatusPermanentRedirect)
+		}),
+		IdleTimeout:  time.Minute,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}
+
+	app.logger.PrintInfo("starting https redirect server", map[string]string{
+		"addr": srv.Addr,
+	})
+
+	err := srv.ListenAndServe()
+	app.logger.PrintError(err, map[string]string{"addr": srv.Addr})
+}
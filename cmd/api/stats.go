@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+// objectiveStatsHandler returns league-wide averages for each objective (turrets, dragons,
+// barons, ...) split by winning and losing team, and how strongly taking each one goes with
+// winning.
+func (app *application) objectiveStatsHandler(w http.ResponseWriter, r *http.Request) {
+	objectives, err := app.models.Matches.GetObjectiveStats()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.matches)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"objectives": objectives}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// correlationStatsHandler returns how strongly each early objective (first blood, first tower,
+// first dragon) goes with winning the match.
+func (app *application) correlationStatsHandler(w http.ResponseWriter, r *http.Request) {
+	correlations, err := app.models.Matches.GetFirstObjectiveCorrelations()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.matches)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"correlations": correlations}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// statsSummaryHandler returns the objective averages and first objective correlations together.
+// Both are expensive aggregates over every match, so the summary is served from
+// app.statsSummary, which refreshes it in the background rather than making a request wait.
+func (app *application) statsSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	summary, err := app.statsSummary.get(app.loadStatsSummary, app.background)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setCacheControl(w, app.config.cache.matches)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"summary": summary}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// loadStatsSummary runs the aggregate queries behind the stats summary.
+func (app *application) loadStatsSummary() (*statsSummary, error) {
+	objectives, err := app.models.Matches.GetObjectiveStats()
+	if err != nil {
+		return nil, err
+	}
+
+	correlations, err := app.models.Matches.GetFirstObjectiveCorrelations()
+	if err != nil {
+		return nil, err
+	}
+
+	return &statsSummary{Objectives: objectives, Correlations: correlations, GeneratedAt: time.Now()}, nil
+}
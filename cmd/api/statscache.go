@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+)
+
+// statsSummary holds the league-wide aggregates served by statsSummaryHandler.
+type statsSummary struct {
+	Objectives   []*data.ObjectiveStats   `json:"objectives"`
+	Correlations []*data.CorrelationStats `json:"correlations"`
+	GeneratedAt  time.Time                `json:"generatedAt"`
+}
+
+// statsSummaryCache keeps the stats summary in memory. A summary younger than ttl is served as
+// is. For a further swr (stale-while-revalidate) it is still served, but the first request to see
+// it stale starts a refresh in the background. Only once it is older than ttl+swr does a request
+// wait for a fresh summary. At most one refresh runs at a time, and requests which arrive while a
+// blocking refresh runs wait for it rather than starting their own.
+type statsSummaryCache struct {
+	ttl time.Duration
+	swr time.Duration
+
+	mu         sync.Mutex
+	summary    *statsSummary
+	refreshing bool
+	done       chan struct{} // Closed when the running refresh finishes
+	err        error         // Error of the last refresh
+}
+
+func newStatsSummaryCache(ttl, swr time.Duration) *statsSummaryCache {
+	return &statsSummaryCache{ttl: ttl, swr: swr}
+}
+
+// get returns the cached summary, refreshing it with load as described on statsSummaryCache. The
+// background refresh is started with background, so that it is tracked like our other
+// background work.
+func (c *statsSummaryCache) get(load func() (*statsSummary, error), background func(func())) (*statsSummary, error) {
+	c.mu.Lock()
+
+	if c.summary != nil {
+		age := time.Since(c.summary.GeneratedAt)
+
+		if age < c.ttl {
+			defer c.mu.Unlock()
+			return c.summary, nil
+		}
+
+		if age < c.ttl+c.swr {
+			if !c.refreshing {
+				done := c.startRefresh()
+				background(func() { c.refresh(load, done) })
+			}
+			defer c.mu.Unlock()
+			return c.summary, nil
+		}
+	}
+
+	// There is no summary we may serve, so wait for a refresh, joining one if it's running.
+	done := c.done
+	if !c.refreshing {
+		done = c.startRefresh()
+		c.mu.Unlock()
+		c.refresh(load, done)
+	} else {
+		c.mu.Unlock()
+		<-done
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.summary == nil || time.Since(c.summary.GeneratedAt) >= c.ttl+c.swr {
+		return nil, c.err
+	}
+	return c.summary, nil
+}
+
+// startRefresh marks a refresh as running and returns the channel closed when it finishes. The
+// caller must hold the lock.
+func (c *statsSummaryCache) startRefresh() chan struct{} {
+	c.refreshing = true
+	c.done = make(chan struct{})
+	return c.done
+}
+
+// refresh loads a new summary and stores it, or the error, for the waiting requests. The refresh
+// is marked finished even if load panics, so that later requests don't wait for it forever.
+func (c *statsSummaryCache) refresh(load func() (*statsSummary, error), done chan struct{}) {
+	var summary *statsSummary
+	err := errors.New("stats summary refresh did not complete")
+
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if err == nil {
+			c.summary = summary
+		}
+		c.err = err
+		c.refreshing = false
+		close(done)
+	}()
+
+	summary, err = load()
+}
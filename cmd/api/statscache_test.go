@@ -0,0 +1,77 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStatsSummaryCacheStaleWhileRevalidate(t *testing.T) {
+	cache := newStatsSummaryCache(time.Hour, time.Hour)
+
+	// The cached summary is past its TTL but within the SWR window.
+	stale := &statsSummary{GeneratedAt: time.Now().Add(-90 * time.Minute)}
+	cache.summary = stale
+
+	fresh := &statsSummary{GeneratedAt: time.Now()}
+	release := make(chan struct{})
+	var loads int32
+	load := func() (*statsSummary, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return fresh, nil
+	}
+
+	var wg sync.WaitGroup
+	background := func(fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+
+	// The load blocks until released, so these only return because they don't wait for it.
+	for i := 0; i < 3; i++ {
+		got, err := cache.get(load, background)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != stale {
+			t.Errorf("request %d: got %v, want the stale summary", i+1, got)
+		}
+	}
+
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Errorf("got %d refreshes, want 1", n)
+	}
+
+	got, err := cache.get(load, background)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != fresh {
+		t.Errorf("after the refresh: got %v, want the fresh summary", got)
+	}
+}
+
+func TestStatsSummaryCacheExpired(t *testing.T) {
+	cache := newStatsSummaryCache(time.Hour, time.Hour)
+	cache.summary = &statsSummary{GeneratedAt: time.Now().Add(-3 * time.Hour)}
+
+	fresh := &statsSummary{GeneratedAt: time.Now()}
+	load := func() (*statsSummary, error) { return fresh, nil }
+
+	// Past the SWR window the request waits for the refresh itself.
+	got, err := cache.get(load, func(func()) { t.Error("refresh ran in the background") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != fresh {
+		t.Errorf("got %v, want the fresh summary", got)
+	}
+}
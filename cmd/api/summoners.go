@@ -1,14 +1,12 @@
 package main
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
+	"net/url"
 	"time"
 
-	"github.com/julienschmidt/httprouter"
 	"league_of_graphs.satellite.net/internal/data"
 	"league_of_graphs.satellite.net/internal/validator"
 )
@@ -17,8 +15,9 @@ import (
 // return a plain-text placeholder response.
 func (app *application) createSummonerHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Username string `json:"username"`
-		Region   string `json:"region"`
+		Username  string `json:"username"`
+		Region    string `json:"region"`
+		IsPrivate bool   `json:"is_private"`
 	}
 
 	err := app.readJSON(w, r, &input)
@@ -27,12 +26,37 @@ func (app *application) createSummonerHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Copy the values from the input struct to a new Summoner struct.
+	// Copy the values from the input struct to a new Summoner struct. A summoner created by an
+	// authenticated user is owned by them. The user of an API key has no ID, so a summoner created
+	// with one is left unowned.
 	summoner := &data.Summoner{
-		Username: input.Username,
-		Region:   input.Region,
+		Username:  input.Username,
+		Region:    input.Region,
+		IsPrivate: input.IsPrivate,
 	}
 
+	if user := app.contextGetUser(r); !user.IsAnonymous() {
+		summoner.UserID = user.ID
+	}
+
+	// A new summoner's owner isn't verified yet, so only admins can create a private profile (see
+	// summonerViewer.canSetPrivate).
+	if input.IsPrivate {
+		viewer, err := app.summonerViewer(r)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		if !viewer.canSetPrivate(summoner) {
+			app.notPermittedResponse(w, r)
+			return
+		}
+	}
+
+	// Trim and canonicalize the input so near-duplicates like " Faker " aren't stored.
+	data.NormalizeSummoner(summoner)
+
 	// Initialize a new Validator.
 	v := validator.New()
 
@@ -44,14 +68,84 @@ func (app *application) createSummonerHandler(w http.ResponseWriter, r *http.Req
 
 	err = app.models.Summoners.Insert(summoner)
 	if err != nil {
-		app.serverErrorResponse(w, r, err)
+		switch {
+		case errors.Is(err, data.ErrDuplicateSummoner):
+			v.AddError("username", "a summoner with this username already exists in this region")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
 		return
 	}
 
 	headers := make(http.Header)
-	headers.Set("Location", fmt.Sprintf("/v1/summoners/%d", summoner.ID))
+	headers.Set("Location", app.url("/v1/summoners/%d", summoner.ID))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"summoner": summoner, "_links": app.summonerLinks(summoner.ID)}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// upsertSummonersBulkHandler inserts or updates a batch of summoners by username and region, for
+// sync jobs which mirror ratings from elsewhere. The batch is all or nothing: if any entry is
+// invalid, nothing is written and the errors are listed by index.
+func (app *application) upsertSummonersBulkHandler(w http.ResponseWriter, r *http.Request) {
+	var input []*data.SummonerUpsert
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(len(input) > 0, "summoners", "must contain at least one summoner")
+	v.Check(len(input) <= data.MaxSummonerUpsertBatch, "summoners", fmt.Sprintf("must not contain more than %d summoners", data.MaxSummonerUpsertBatch))
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	itemErrors := []bulkItemError{}
+
+	for i, upsert := range input {
+		if upsert == nil {
+			upsert = &data.SummonerUpsert{}
+			input[i] = upsert
+		}
+		upsert.Username = data.NormalizeName(upsert.Username)
+		upsert.Region = data.NormalizeRegion(upsert.Region)
+
+		itemValidator := validator.New()
+		data.ValidateSummonerUpsert(itemValidator, upsert)
+
+		for j := 0; j < i; j++ {
+			if data.DuplicateSummonerUpsert(input[j], upsert) {
+				itemValidator.AddError("username", fmt.Sprintf("duplicates entry %d", j))
+				break
+			}
+		}
+
+		if !itemValidator.Valid() {
+			itemErrors = append(itemErrors, bulkItemError{Index: i, Errors: itemValidator.Errors})
+		}
+	}
+
+	if len(itemErrors) > 0 {
+		app.errorResponse(w, r, http.StatusUnprocessableEntity, itemErrors)
+		return
+	}
+
+	result, err := app.models.Summoners.UpsertBulk(input)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
 
-	err = app.writeJSON(w, http.StatusCreated, envelope{"summoner": summoner}, headers)
+	err = app.writeJSON(w, http.StatusOK, envelope{"result": result}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -72,20 +166,61 @@ func (app *application) showSummonerHandler(w http.ResponseWriter, r *http.Reque
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.summonerNotFoundResponse(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
 		return
 	}
 
+	v := validator.New()
+
+	// Percentiles are among all summoners unless ?percentile_scope=region asks for only those of
+	// the summoner's region.
+	qs := r.URL.Query()
+	scope := app.readString(qs, "percentile_scope", percentileScopeGlobal)
+	fields := app.readFields(qs, summonerFields, v)
+
+	if v.Check(validator.In(scope, percentileScopeGlobal, percentileScopeRegion), "percentile_scope", "must be global or region"); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	viewer, err := app.summonerViewer(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.setSummonerPercentiles(summoner, scope)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	selected, err := fields.apply(viewer.view(summoner))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.setSummonerCacheControl(w, summoner.IsPrivate)
+
+	env := envelope{"summoner": selected, "_links": app.summonerLinks(summoner.ID)}
+	if v.HasWarnings() {
+		env["warnings"] = v.Warnings
+	}
+
 	// Encode the struct to JSON and send it as the HTTP response.
-	err = app.writeJSON(w, http.StatusOK, envelope{"summoner": summoner}, nil)
+	err = app.writeJSON(w, http.StatusOK, env, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
 }
 
+// updateSummonerHandler replaces a summoner's username and region. A body sent as
+// application/merge-patch+json (RFC 7386) only changes the fields it includes, and an explicit
+// null is_private resets the profile to public; the username and region can't be cleared.
 func (app *application) updateSummonerHandler(w http.ResponseWriter, r *http.Request) {
 
 	id, err := app.readIDParam(r)
@@ -98,7 +233,7 @@ func (app *application) updateSummonerHandler(w http.ResponseWriter, r *http.Req
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.summonerNotFoundResponse(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -106,21 +241,62 @@ func (app *application) updateSummonerHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	var input struct {
-		Username string `json:"username"`
-		Region   string `json:"region"`
+		Username  string `json:"username"`
+		Region    string `json:"region"`
+		IsPrivate *bool  `json:"is_private"`
 	}
 
-	err = app.readJSON(w, r, &input)
+	var nulls map[string]bool
+
+	if isMergePatch(r) {
+		// Members left out of a merge patch keep their current values.
+		input.Username = summoner.Username
+		input.Region = summoner.Region
+		nulls, err = app.readMergePatch(w, r, map[string]interface{}{
+			"username":   &input.Username,
+			"region":     &input.Region,
+			"is_private": &input.IsPrivate,
+		})
+	} else {
+		err = app.readJSON(w, r, &input)
+	}
 	if err != nil {
 		app.badRequestResponse(w, r, err)
 		return
 	}
 
+	if nulls["is_private"] {
+		public := false
+		input.IsPrivate = &public
+	}
+
+	// Only a verified owner or an admin can change whether a profile is private (see
+	// summonerViewer.canSetPrivate).
+	if input.IsPrivate != nil && *input.IsPrivate != summoner.IsPrivate {
+		viewer, err := app.summonerViewer(r)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		if !viewer.canSetPrivate(summoner) {
+			app.notPermittedResponse(w, r)
+			return
+		}
+
+		summoner.IsPrivate = *input.IsPrivate
+	}
+
 	summoner.Username = input.Username
 	summoner.Region = input.Region
 
+	data.NormalizeSummoner(summoner)
+
 	v := validator.New()
 
+	v.Check(!nulls["username"], "username", "cannot be cleared")
+	v.Check(!nulls["region"], "region", "cannot be cleared")
+
 	if data.ValidateSummoner(v, summoner); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
@@ -128,11 +304,19 @@ func (app *application) updateSummonerHandler(w http.ResponseWriter, r *http.Req
 
 	err = app.models.Summoners.Update(summoner)
 	if err != nil {
-		app.serverErrorResponse(w, r, err)
+		switch {
+		case errors.Is(err, data.ErrDuplicateSummoner):
+			v.AddError("username", "a summoner with this username already exists in this region")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"summoner": summoner}, nil)
+	err = app.writeJSON(w, http.StatusOK, envelope{"summoner": summoner, "_links": app.summonerLinks(summoner.ID)}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -150,7 +334,7 @@ func (app *application) deleteSummonerHandler(w http.ResponseWriter, r *http.Req
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.summonerNotFoundResponse(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -163,10 +347,17 @@ func (app *application) deleteSummonerHandler(w http.ResponseWriter, r *http.Req
 	}
 }
 
+// summonerSortSafelist holds the values accepted by the ?sort= parameter of the summoner list.
+var summonerSortSafelist = []string{"id", "username", "region", "-id", "-username", "-region"}
+
+// summonerFields holds the fields a summoner response can be restricted to with ?fields=.
+var summonerFields = jsonFields(data.Summoner{})
+
 func (app *application) listSummonersHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Username string
-		Region   string
+		data.SummonerFilter
+		Rank   string
+		Stream bool
 		data.Filters
 	}
 
@@ -174,96 +365,782 @@ func (app *application) listSummonersHandler(w http.ResponseWriter, r *http.Requ
 
 	qs := r.URL.Query()
 
-	input.Username = app.readString(qs, "username", "")
-	input.Region = app.readString(qs, "region", "")
+	input.SummonerFilter = app.readSummonerFilter(qs, v)
+	input.Rank = app.readString(qs, "rank", "")
+	input.Stream = app.readBool(qs, "stream", false, v)
 
-	input.Filters.Page = app.readInt(qs, "page", 1, v)
-	input.Filters.PageSize = app.readInt(qs, "page_size", 20, v)
+	v.Check(validator.In(input.Rank, "", "relevance"), "rank", "invalid rank value")
+	v.Check(input.Rank == "" || input.Search != "", "q", "must be provided when ranking by relevance")
 
-	input.Filters.Sort = app.readString(qs, "sort", "id")
-	input.Filters.SortSafelist = []string{"id", "username", "region", "-id", "-username", "-region"}
+	input.Filters = app.readFilters(qs, "id", summonerSortSafelist, v)
+	fields := app.readFields(qs, summonerFields, v)
 
 	if data.ValidateFilters(v, input.Filters); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
-	summoners, err := app.models.Summoners.GetAll(input.Username, input.Region, input.Filters)
+	viewer, err := app.summonerViewer(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	// Private summoners are listed, but their stats are only shown to their owner and admins, so
+	// the page depends on who is asking once they're authenticated.
+	app.setSummonerCacheControl(w, viewer.userID != 0)
+
+	// In streaming mode every matching summoner is written out as it's read from the database,
+	// rather than a single page being assembled in memory first.
+	if input.Stream {
+		input.Filters.PageSize = 0
+
+		err := app.streamJSON(w, "summoners", func(emit func(interface{}) error) error {
+			return app.models.Summoners.Stream(r.Context(), input.SummonerFilter, input.Rank == "relevance", input.Filters, func(summoner *data.Summoner) error {
+				selected, err := fields.apply(viewer.view(summoner))
+				if err != nil {
+					return err
+				}
+				return emit(selected)
+			})
+		})
+		if err != nil {
+			app.logError(r, err)
+		}
+		return
+	}
+
+	summoners, metadata, err := app.models.Summoners.GetAll(input.SummonerFilter, input.Rank == "relevance", input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if metadata.OutOfRange && app.config.pagination.outOfRange == pageOutOfRangeNotFound {
+		app.pageOutOfRangeResponse(w, r, metadata)
+		return
+	}
+
+	views := make([]interface{}, len(summoners))
+	for i, summoner := range summoners {
+		views[i] = viewer.view(summoner)
+	}
+
+	selected, err := fields.apply(views)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env := envelope{"summoners": selected, "metadata": metadata}
+	if v.HasWarnings() {
+		env["warnings"] = v.Warnings
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// countSummonersHandler returns the number of summoners matching the same filters accepted by
+// listSummonersHandler.
+func (app *application) countSummonersHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	filter := app.readSummonerFilter(r.URL.Query(), v)
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	count, err := app.models.Summoners.Count(filter)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"summoners": summoners}, nil)
+	app.setCacheControl(w, app.config.cache.summoners)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"count": count}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
 }
 
-type SummonerMatchPerformance struct {
-	Username      string
-	Champion      ChampionData
-	NetWorth      int
-	KDA           KDA
-	BoughtItems   []string
-	MatchDuration time.Duration
-	MatchDate     time.Time
-	MatchResult   string
-	MatchID       int
+// readSummonerFilter reads the summoner filter parameters shared by the list and count endpoints
+// from the query string.
+func (app *application) readSummonerFilter(qs url.Values, v *validator.Validator) data.SummonerFilter {
+	filter := data.SummonerFilter{
+		Username:     app.readString(qs, "username", ""),
+		Region:       app.readString(qs, "region", ""),
+		Search:       app.readString(qs, "q", ""),
+		MinRating:    app.readInt(qs, "min_rating", 0, v),
+		InactiveDays: app.readInt(qs, "inactive_days", 0, v),
+	}
+
+	v.Check(filter.MinRating >= 0, "min_rating", "must not be negative")
+	v.Check(filter.InactiveDays >= 0, "inactive_days", "must not be negative")
+
+	return filter
 }
 
-type ChampionData struct {
-	Name     string
-	MainRole string
+// showSummonerStreakHandler returns the summoner's current win or loss streak, and their longest
+// ever streaks.
+func (app *application) showSummonerStreakHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if !app.showSummonerStats(w, r, summoner) {
+		return
+	}
+
+	streak, err := app.models.Summoners.GetCurrentStreak(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"streak": streak}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
 }
 
-type KDA struct {
-	Kills   int
-	Deaths  int
-	Assists int
+// matchHistorySortSafelist holds the sorts accepted for a summoner's or champion's match history,
+// which is most useful newest first.
+var matchHistorySortSafelist = []string{"played_date", "-played_date", "duration", "-duration", "id", "-id"}
+
+// showSummonerMatchesHandler returns a page of the matches the summoner played in, most recent
+// first. ?outcome=win or ?outcome=loss keeps only the matches the summoner won or lost, and the
+// match list filters (tag, objectives, VOD) apply as well.
+func (app *application) showSummonerMatchesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	filter := app.readMatchFilter(qs, v)
+	filter.SummonerID = id
+	filter.Outcome = app.readString(qs, "outcome", "")
+
+	filters := app.readFilters(qs, "-played_date", matchHistorySortSafelist, v)
+
+	v.Check(filter.Outcome == "" || validator.In(filter.Outcome, data.OutcomeWin, data.OutcomeLoss), "outcome", "must be win or loss")
+
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if !app.showSummonerStats(w, r, summoner) {
+		return
+	}
+
+	matches, metadata, err := app.models.Matches.GetAll(filter, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env := envelope{"matches": matches, "metadata": metadata}
+	if v.HasWarnings() {
+		env["warnings"] = v.Warnings
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
 }
 
-func (app *application) getSummonersByMatch(w http.ResponseWriter, r *http.Request) {
-	params := httprouter.ParamsFromContext(r.Context())
-	matchID, err := strconv.Atoi(params.ByName("id"))
+// transferSummonerHandler moves a summoner to a new region, keeping their history.
+func (app *application) transferSummonerHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
 	if err != nil {
-		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
 		return
 	}
 
-	query := `
-        SELECT s.username, c.name, c.main_role, mp.net_worth, mp.kills, mp.deaths, mp.assists, mp.bought_items
-        FROM summoners s
-        JOIN match_performance mp ON s.id = mp.summoner_id
-        JOIN champions c ON mp.champion_id = c.id
-        WHERE mp.match_id = $1
-    `
+	var input struct {
+		Region string `json:"region"`
+	}
 
-	rows, err := app.DB.Query(query, matchID)
+	err = app.readJSON(w, r, &input)
 	if err != nil {
-		http.Error(w, "Query error", http.StatusInternalServerError)
+		app.badRequestResponse(w, r, err)
 		return
 	}
-	defer rows.Close()
 
-	summoners := []SummonerMatchPerformance{}
+	region := data.NormalizeRegion(input.Region)
 
-	for rows.Next() {
-		var summoner SummonerMatchPerformance
-		var boughtItems string
-		if err := rows.Scan(&summoner.Username, &summoner.Champion.Name, &summoner.Champion.MainRole, &summoner.NetWorth, &summoner.KDA.Kills, &summoner.KDA.Deaths, &summoner.KDA.Assists, &boughtItems); err != nil {
-			http.Error(w, "Row scan error", http.StatusInternalServerError)
-			return
+	v := validator.New()
+
+	if data.ValidateTransfer(v, summoner, region); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	transfer, err := app.models.Summoners.Transfer(summoner, region)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateSummoner):
+			v.AddError("region", "a summoner with this username already exists in the target region")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
 		}
-		json.Unmarshal([]byte(boughtItems), &summoner.BoughtItems)
-		summoners = append(summoners, summoner)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"summoner": summoner, "transfer": transfer, "_links": app.summonerLinks(summoner.ID)}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
 	}
+}
 
-	if err := rows.Err(); err != nil {
-		http.Error(w, "Rows error", http.StatusInternalServerError)
+// mergeSummonerHandler merges a duplicate summoner (source_id) into this one, moving all of the
+// duplicate's matches and stats across and then deleting it.
+func (app *application) mergeSummonerHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(summoners)
+	_, err = app.models.Summoners.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	var input struct {
+		SourceID int64 `json:"source_id"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(input.SourceID > 0, "source_id", "must be provided")
+	v.Check(input.SourceID != id, "source_id", "must be different from the summoner being merged into")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	_, err = app.models.Summoners.Get(input.SourceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			v.AddError("source_id", "must be an existing summoner")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.models.Summoners.Merge(id, input.SourceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			// The source was deleted by someone else after we checked it.
+			v.AddError("source_id", "must be an existing summoner")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"summoner": summoner, "_links": app.summonerLinks(summoner.ID)}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showSummonerChampionsHandler returns the summoner's record on each champion they've played,
+// including their KDA on that champion.
+func (app *application) showSummonerChampionsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if !app.showSummonerStats(w, r, summoner) {
+		return
+	}
+
+	champions, err := app.models.Summoners.GetChampionStats(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"champions": champions}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showSummonerActivityHandler returns a 7x24 grid counting the summoner's matches by day of the
+// week and hour of the day, bucketed in the time zone given by ?tz= (UTC by default).
+func (app *application) showSummonerActivityHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	timezone := app.readString(r.URL.Query(), "tz", "UTC")
+	_, err = time.LoadLocation(timezone)
+	v.Check(err == nil, "tz", "must be a valid IANA time zone")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if !app.showSummonerStats(w, r, summoner) {
+		return
+	}
+
+	activity, err := app.models.Summoners.GetActivity(id, timezone)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"timezone": timezone, "activity": activity}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showSummonerKDADistributionHandler returns a histogram and percentiles of the summoner's
+// per-match KDA ratios, showing how consistent their play is rather than just its average.
+func (app *application) showSummonerKDADistributionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if !app.showSummonerStats(w, r, summoner) {
+		return
+	}
+
+	distribution, err := app.models.Summoners.GetKDADistribution(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"kdaDistribution": distribution}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// getSummonersByMatch returns a page of the summoner performances for a match, ordered by team
+// and then net worth. The stats of private summoners are hidden from everyone but their owner and
+// admins.
+func (app *application) getSummonersByMatch(w http.ResponseWriter, r *http.Request) {
+	matchID, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		data.Filters
+	}
+
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	// Performances are always ordered by team then net worth, so that's the only sort we accept.
+	input.Filters = app.readFilters(qs, "team", []string{"team"}, v)
+
+	if data.ValidateFilters(v, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	viewer, err := app.summonerViewer(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	performances, err := app.models.Matches.GetPerformances(matchID, input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	// Private summoners' stats are only shown to their owner, so the response depends on who asks.
+	personalized := false
+	views := make([]interface{}, len(performances))
+	for i, performance := range performances {
+		views[i] = viewer.viewPerformance(performance)
+		personalized = personalized || performance.IsPrivate
+	}
+
+	app.setSummonerCacheControl(w, personalized)
+
+	env := envelope{"summoners": views}
+	if v.HasWarnings() {
+		env["warnings"] = v.Warnings
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// setSummonerProHandler marks a summoner as a professional player's account, or unmarks it.
+func (app *application) setSummonerProHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		IsPro *bool `json:"is_pro"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(input.IsPro != nil, "is_pro", "must be provided")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Summoners.SetPro(id, *input.IsPro)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"summoner": summoner, "_links": app.summonerLinks(summoner.ID)}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// setSummonerOwnerVerifiedHandler records whether an admin has verified that a summoner's owner
+// really plays it, which lets the owner make the profile private.
+func (app *application) setSummonerOwnerVerifiedHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		OwnerVerified *bool `json:"owner_verified"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+
+	v.Check(input.OwnerVerified != nil, "owner_verified", "must be provided")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.Summoners.VerifyOwner(id, *input.OwnerVerified)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		case errors.Is(err, data.ErrNoOwner):
+			v.AddError("owner_verified", "the summoner has no owner to verify")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"summoner": summoner, "_links": app.summonerLinks(summoner.ID)}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// summonerViewer is the user requesting a summoner, which decides whether the detailed stats of a
+// private summoner are shown to them.
+type summonerViewer struct {
+	userID int64 // 0 for an anonymous user
+	admin  bool
+}
+
+// summonerViewer returns the viewer for the authenticated user of the request.
+func (app *application) summonerViewer(r *http.Request) (summonerViewer, error) {
+	user := app.contextGetUser(r)
+	if user.IsAnonymous() {
+		return summonerViewer{}, nil
+	}
+
+	permissions, err := app.permissionsFor(r)
+	if err != nil {
+		return summonerViewer{}, err
+	}
+
+	return summonerViewer{userID: user.ID, admin: permissions.Include("system:write")}, nil
+}
+
+// owns reports whether the viewer owns the summoner, or is an admin and so may act as its owner.
+func (sv summonerViewer) owns(summoner *data.Summoner) bool {
+	return sv.ownsUser(summoner.UserID)
+}
+
+// ownsUser reports whether the viewer is the given owning user (0 if unclaimed), or an admin.
+func (sv summonerViewer) ownsUser(userID int64) bool {
+	return sv.admin || (sv.userID != 0 && userID == sv.userID)
+}
+
+// canSetPrivate reports whether the viewer may make the summoner's profile private or public.
+// Anyone can create a summoner and so become its owner, so an owner can only hide a profile's
+// stats once an admin has verified they really play the summoner; admins always can.
+func (sv summonerViewer) canSetPrivate(summoner *data.Summoner) bool {
+	return sv.admin || (sv.owns(summoner) && summoner.OwnerVerified)
+}
+
+// canView reports whether the viewer may see the summoner's detailed stats.
+func (sv summonerViewer) canView(summoner *data.Summoner) bool {
+	return !summoner.IsPrivate || sv.owns(summoner)
+}
+
+// viewPerformance returns a match performance as the viewer may see it: in full, or without the
+// stats of a private summoner.
+func (sv summonerViewer) viewPerformance(performance *data.SummonerMatchPerformance) interface{} {
+	if !performance.IsPrivate || sv.ownsUser(performance.UserID) {
+		return performance
+	}
+	return performance.Public()
+}
+
+// view returns the summoner as the viewer may see it: in full, or only its public fields.
+func (sv summonerViewer) view(summoner *data.Summoner) interface{} {
+	if sv.canView(summoner) {
+		return summoner
+	}
+	return summoner.Public()
+}
+
+// showSummonerStats reports whether the profile sub-endpoints may show the stats of a summoner to
+// the requesting user. If not, it has already written out the summoner's public fields instead.
+func (app *application) showSummonerStats(w http.ResponseWriter, r *http.Request, summoner *data.Summoner) bool {
+	viewer, err := app.summonerViewer(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return false
+	}
+
+	app.setSummonerCacheControl(w, summoner.IsPrivate)
+
+	if viewer.canView(summoner) {
+		return true
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"summoner": summoner.Public()}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+	return false
+}
+
+// setSummonerCacheControl sets the Cache-Control header for a summoner response. A personalized
+// response, which depends on who is asking, may only be cached by the client itself.
+func (app *application) setSummonerCacheControl(w http.ResponseWriter, personalized bool) {
+	maxAge := app.config.cache.summoners
+	if !personalized || maxAge <= 0 {
+		app.setCacheControl(w, maxAge)
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds())))
+}
+
+// showSummonerDecayForecastHandler projects when an inactive summoner's rating decays, following
+// the decay rules in the rating_decay_rules table. ?limit= caps the upcoming decays listed.
+func (app *application) showSummonerDecayForecastHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	v := validator.New()
+
+	limit := app.readInt(r.URL.Query(), "limit", 10, v)
+
+	v.Check(limit > 0, "limit", "must be greater than zero")
+	v.Check(limit <= 100, "limit", "must be a maximum of 100")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	summoner, err := app.models.Summoners.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.summonerNotFoundResponse(w, r, id)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if !app.showSummonerStats(w, r, summoner) {
+		return
+	}
+
+	rules, err := app.models.Summoners.GetDecayRules()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	forecast := data.ForecastDecay(summoner.Rating, summoner.LastMatchAt, time.Now(), rules, limit)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"forecast": forecast}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
 }
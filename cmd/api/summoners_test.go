@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+func TestSummonerViewer(t *testing.T) {
+	private := &data.Summoner{ID: 1, UserID: 7, IsPrivate: true}
+	public := &data.Summoner{ID: 2, UserID: 7}
+	verified := &data.Summoner{ID: 3, UserID: 7, OwnerVerified: true}
+	performance := &data.SummonerMatchPerformance{Username: "Faker", NetWorth: 12000, UserID: 7, IsPrivate: true}
+
+	tests := []struct {
+		name           string
+		viewer         summonerViewer
+		owns           bool
+		canViewPrivate bool
+		canSetPrivate  bool // on a summoner whose owner isn't verified
+		canSetVerified bool // on a summoner whose owner is verified
+	}{
+		{"anonymous", summonerViewer{}, false, false, false, false},
+		{"stranger", summonerViewer{userID: 8}, false, false, false, false},
+		{"owner", summonerViewer{userID: 7}, true, true, false, true},
+		{"admin", summonerViewer{userID: 9, admin: true}, true, true, true, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.viewer.owns(private); got != tt.owns {
+			t.Errorf("%s: owns = %t, want %t", tt.name, got, tt.owns)
+		}
+		if got := tt.viewer.canView(private); got != tt.canViewPrivate {
+			t.Errorf("%s: canView(private) = %t, want %t", tt.name, got, tt.canViewPrivate)
+		}
+		if !tt.viewer.canView(public) {
+			t.Errorf("%s: can't view a public summoner", tt.name)
+		}
+		if got := tt.viewer.canSetPrivate(public); got != tt.canSetPrivate {
+			t.Errorf("%s: canSetPrivate(unverified) = %t, want %t", tt.name, got, tt.canSetPrivate)
+		}
+		if got := tt.viewer.canSetPrivate(verified); got != tt.canSetVerified {
+			t.Errorf("%s: canSetPrivate(verified) = %t, want %t", tt.name, got, tt.canSetVerified)
+		}
+
+		if _, full := tt.viewer.view(private).(*data.Summoner); full != tt.canViewPrivate {
+			t.Errorf("%s: got full view %t of a private summoner, want %t", tt.name, full, tt.canViewPrivate)
+		}
+		if _, full := tt.viewer.viewPerformance(performance).(*data.SummonerMatchPerformance); full != tt.canViewPrivate {
+			t.Errorf("%s: got full view %t of a private summoner's performance, want %t", tt.name, full, tt.canViewPrivate)
+		}
+	}
+}
+
+func TestSummonerViewerAdminAPIKey(t *testing.T) {
+	app := &application{}
+
+	r := withAPIKey(app, httptest.NewRequest(http.MethodGet, "/v1/summoners/1", nil), "system:write")
+	viewer, err := app.summonerViewer(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !viewer.canSetPrivate(&data.Summoner{ID: 1}) {
+		t.Error("an API key with system:write can't set is_private")
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/v1/summoners/1", nil)
+	viewer, err = app.summonerViewer(app.contextSetUser(r, data.AnonymousUser))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if viewer.canSetPrivate(&data.Summoner{ID: 1}) || viewer.userID != 0 {
+		t.Errorf("got viewer %+v for an anonymous user", viewer)
+	}
+}
+
+func TestCreatePrivateSummonerRequiresAdmin(t *testing.T) {
+	app := &application{}
+
+	requests := map[string]func(*http.Request) *http.Request{
+		"anonymous": func(r *http.Request) *http.Request {
+			return app.contextSetUser(r, data.AnonymousUser)
+		},
+		"non-admin": func(r *http.Request) *http.Request {
+			return withAPIKey(app, r, "summoners:write")
+		},
+	}
+
+	for name, authenticate := range requests {
+		body := strings.NewReader(`{"username": "Faker", "region": "KR", "is_private": true}`)
+		r := authenticate(httptest.NewRequest(http.MethodPost, "/v1/summoners", body))
+		rr := httptest.NewRecorder()
+
+		app.createSummonerHandler(rr, r)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("%s: got status %d, want %d", name, rr.Code, http.StatusForbidden)
+		}
+	}
+}
+
+func TestListSummonersPageOutOfRange(t *testing.T) {
+	app := &application{
+		logger: jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models: data.NewModels(newTestDB(t), nil),
+	}
+
+	for _, username := range []string{"Faker", "Caps", "Chovy"} {
+		if err := app.models.Summoners.Insert(&data.Summoner{Username: username, Region: "EUW"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/v1/summoners?page=99&page_size=2", nil)
+		rr := httptest.NewRecorder()
+		app.listSummonersHandler(rr, app.contextSetUser(r, data.AnonymousUser))
+		return rr
+	}
+
+	// By default a page past the last one is an empty page, flagged as out of range.
+	app.config.pagination.outOfRange = pageOutOfRangeFlag
+
+	rr := list()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rr.Code, rr.Body)
+	}
+
+	var body struct {
+		Summoners []json.RawMessage `json:"summoners"`
+		Metadata  data.Metadata     `json:"metadata"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	want := data.Metadata{CurrentPage: 99, PageSize: 2, FirstPage: 1, LastPage: 2, TotalRecords: 3, OutOfRange: true}
+	if len(body.Summoners) != 0 || body.Metadata != want {
+		t.Errorf("got %d summoners and metadata %+v, want none and %+v", len(body.Summoners), body.Metadata, want)
+	}
+
+	app.config.pagination.outOfRange = pageOutOfRangeNotFound
+
+	if rr := list(); rr.Code != http.StatusNotFound {
+		t.Errorf("got status %d with -page-out-of-range=404, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestUpdateSummonerMergePatch(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+
+	summoner := &data.Summoner{Username: "Faker", Region: "KR"}
+	if err := app.models.Summoners.Insert(summoner); err != nil {
+		t.Fatal(err)
+	}
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPut, "/v1/summoners/"+strconv.FormatInt(summoner.ID, 10), strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/merge-patch+json")
+		rr := httptest.NewRecorder()
+		app.router().ServeHTTP(rr, r)
+		return rr
+	}
+
+	// The username is left out, so it's kept.
+	if rr := patch(`{"region": "EUW"}`); rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", rr.Code, http.StatusOK, rr.Body)
+	}
+
+	got, err := app.models.Summoners.Get(summoner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Username != "Faker" || got.Region != "EUW" {
+		t.Errorf("got %s in %s, want Faker in EUW", got.Username, got.Region)
+	}
+
+	if rr := patch(`{"username": null}`); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("clearing the username: got status %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestVerifiedOwnerCanSetPrivate(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+
+	owner := &data.User{Name: "Faker", Email: "faker@example.com", Activated: true}
+	if err := owner.Password.Set("pa55word1234", data.MinBcryptCost); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.models.Users.Insert(owner); err != nil {
+		t.Fatal(err)
+	}
+
+	summoner := &data.Summoner{Username: "Faker", Region: "KR", UserID: owner.ID}
+	if err := app.models.Summoners.Insert(summoner); err != nil {
+		t.Fatal(err)
+	}
+	path := "/v1/summoners/" + strconv.FormatInt(summoner.ID, 10)
+
+	setPrivate := func() int {
+		r := httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"username": "Faker", "region": "KR", "is_private": true}`))
+		rr := httptest.NewRecorder()
+		app.router().ServeHTTP(rr, app.contextSetUser(r, owner))
+		return rr.Code
+	}
+
+	if code := setPrivate(); code != http.StatusForbidden {
+		t.Fatalf("before verification: got status %d, want %d", code, http.StatusForbidden)
+	}
+
+	r := httptest.NewRequest(http.MethodPut, path+"/owner-verified", strings.NewReader(`{"owner_verified": true}`))
+	rr := httptest.NewRecorder()
+	app.router().ServeHTTP(rr, withAPIKey(app, r, "system:write"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("verifying the owner: got status %d, want %d: %s", rr.Code, http.StatusOK, rr.Body)
+	}
+
+	if code := setPrivate(); code != http.StatusOK {
+		t.Fatalf("after verification: got status %d, want %d", code, http.StatusOK)
+	}
+
+	got, err := app.models.Summoners.Get(summoner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsPrivate || !got.OwnerVerified {
+		t.Errorf("got private %t and owner verified %t, want both true", got.IsPrivate, got.OwnerVerified)
+	}
+}
+
+func TestGetSummonersByMatchHidesPrivateStats(t *testing.T) {
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+
+	owner := &data.User{Name: "Faker", Email: "faker@example.com", Activated: true}
+	if err := owner.Password.Set("pa55word1234", data.MinBcryptCost); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.models.Users.Insert(owner); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, summoner := range []*data.Summoner{
+		{Username: "Faker", Region: "KR", UserID: owner.ID, IsPrivate: true},
+		{Username: "Chovy", Region: "KR"},
+	} {
+		if err := app.models.Summoners.Insert(summoner); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := app.models.Champions.Insert(&data.Champion{Name: "Ahri", MainRole: "Mid", Classes: data.ChampionClasses{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{
+        "duration": 1800,
+        "result": "blue",
+        "played_date": "2024-03-01T12:00:00Z",
+        "blue_team": {"summoners": [{"username": "Faker", "champion": {"name": "Ahri"}, "role": "Mid", "net_worth": 12000, "kda": {"kills": 6, "deaths": 2, "assists": 8}}]},
+        "red_team": {"summoners": [{"username": "Chovy", "champion": {"name": "Ahri"}, "role": "Mid", "net_worth": 11000, "kda": {"kills": 2, "deaths": 6, "assists": 4}}]}
+    }`
+
+	rr := httptest.NewRecorder()
+	app.router().ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/matches", strings.NewReader(body)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", rr.Code, http.StatusCreated, rr.Body)
+	}
+	path := rr.Header().Get("Location") + "/summoners"
+
+	// Net worth by username, as seen by the given user.
+	netWorths := func(user *data.User) map[string]*int {
+		rr := httptest.NewRecorder()
+		app.router().ServeHTTP(rr, app.contextSetUser(httptest.NewRequest(http.MethodGet, path, nil), user))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", rr.Code, http.StatusOK, rr.Body)
+		}
+
+		var got struct {
+			Summoners []struct {
+				Username string
+				NetWorth *int
+			} `json:"summoners"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		netWorths := make(map[string]*int)
+		for _, summoner := range got.Summoners {
+			netWorths[summoner.Username] = summoner.NetWorth
+		}
+		return netWorths
+	}
+
+	anonymous := netWorths(data.AnonymousUser)
+	if anonymous["Faker"] != nil {
+		t.Errorf("an anonymous user got the private summoner's net worth %d", *anonymous["Faker"])
+	}
+	if anonymous["Chovy"] == nil || *anonymous["Chovy"] != 11000 {
+		t.Errorf("an anonymous user got the public summoner's net worth %v, want 11000", anonymous["Chovy"])
+	}
+
+	if own := netWorths(owner); own["Faker"] == nil || *own["Faker"] != 12000 {
+		t.Errorf("the owner got their net worth %v, want 12000", own["Faker"])
+	}
+}
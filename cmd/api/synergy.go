@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// Synergy matrix response formats.
+const (
+	synergyFormatEdges  = "edges"
+	synergyFormatMatrix = "matrix"
+)
+
+// synergyMatrices caches the synergy edges of each role and minimum sample. Computing them joins
+// every match performance with its teammates', so each is reused until it is older than the TTL.
+type synergyMatrices struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[synergyKey]*synergyEntry
+}
+
+type synergyKey struct {
+	role     string
+	minGames int
+}
+
+type synergyEntry struct {
+	edges    []*data.SynergyEdge
+	loadedAt time.Time
+}
+
+func newSynergyMatrices(ttl time.Duration) *synergyMatrices {
+	return &synergyMatrices{ttl: ttl, entries: make(map[synergyKey]*synergyEntry)}
+}
+
+// get returns the cached edges of the role and minimum sample, loading them with load if there
+// are none or they have expired. The lock is held while loading so concurrent requests don't all
+// run the query.
+func (s *synergyMatrices) get(role string, minGames int, load func(string, int) ([]*data.SynergyEdge, error)) ([]*data.SynergyEdge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := synergyKey{role: role, minGames: minGames}
+
+	if entry, ok := s.entries[key]; ok && time.Since(entry.loadedAt) < s.ttl {
+		return entry.edges, nil
+	}
+
+	edges, err := load(role, minGames)
+	if err != nil {
+		return nil, err
+	}
+
+	s.entries[key] = &synergyEntry{edges: edges, loadedAt: time.Now()}
+
+	return edges, nil
+}
+
+// synergyMatrixHandler exports the same-team win rate of every pair of champions played together
+// in at least ?min_games= matches (30 by default), optionally only counting matches where one of
+// the pair played ?role=. With ?format=matrix the pairs are returned as a dense, symmetric matrix
+// rather than a list of edges.
+func (app *application) synergyMatrixHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+
+	role := data.NormalizeRole(app.readString(qs, "role", ""))
+	minGames := app.readInt(qs, "min_games", 30, v)
+	format := app.readString(qs, "format", synergyFormatEdges)
+
+	v.Check(minGames > 0, "min_games", "must be greater than zero")
+	v.Check(validator.In(format, synergyFormatEdges, synergyFormatMatrix), "format", "must be edges or matrix")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	edges, err := app.synergyMatrices.get(role, minGames, app.models.Champions.GetSynergyEdges)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	env := envelope{"role": role, "minGames": minGames}
+	if format == synergyFormatMatrix {
+		env["matrix"] = data.NewSynergyMatrix(edges)
+	} else {
+		env["edges"] = edges
+	}
+
+	err = app.writeJSON(w, http.StatusOK, env, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// showAnnouncementHandler returns the current system announcement. Announcements which have been
+// switched off or have expired are reported with active set to false so clients can hide them.
+func (app *application) showAnnouncementHandler(w http.ResponseWriter, r *http.Request) {
+	announcement, err := app.models.System.GetAnnouncement()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	announcement.Active = announcement.IsLive(time.Now())
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"announcement": announcement}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateAnnouncementHandler replaces the system announcement. Omitting expiresAt leaves the
+// announcement up until it's switched off.
+func (app *application) updateAnnouncementHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Message   string     `json:"message"`
+		Active    bool       `json:"active"`
+		ExpiresAt *time.Time `json:"expiresAt"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	announcement := &data.Announcement{
+		Message:   data.NormalizeName(input.Message),
+		Active:    input.Active,
+		ExpiresAt: input.ExpiresAt,
+	}
+
+	v := validator.New()
+
+	if data.ValidateAnnouncement(v, announcement); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.System.UpdateAnnouncement(announcement)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"announcement": announcement}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -35,7 +35,7 @@ func (app *application) registerUserHandler(w http.ResponseWriter, r *http.Reque
 
 	// Use the Password.Set() method to generate and store the hashed and plaintext
 	// passwords.
-	err = user.Password.Set(input.Password)
+	err = user.Password.Set(input.Password, app.config.bcryptCost)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
@@ -50,8 +50,19 @@ func (app *application) registerUserHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Insert the user data into the database.
-	err = app.models.Users.Insert(user)
+	// Insert the user data into the database and, after the user record has been created,
+	// generate a new activation token for the user. Both writes run in one transaction, so a
+	// failure to create the token doesn't leave behind a user who can never be activated.
+	var token *data.Token
+	err = app.models.Transact(r.Context(), func(tx data.Models) error {
+		err := tx.Users.Insert(user)
+		if err != nil {
+			return err
+		}
+
+		token, err = tx.Tokens.New(user.ID, 3*24*time.Hour, data.ScopeActivation)
+		return err
+	})
 	if err != nil {
 		switch {
 		// If we get an ErrDuplicateEmail error, use the v.AddError() method to manually add
@@ -67,14 +78,6 @@ func (app *application) registerUserHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// After the user record has been created in the database, generate a new activation
-	// token for the user.
-	token, err := app.models.Tokens.New(user.ID, 3*24*time.Hour, data.ScopeActivation)
-	if err != nil {
-		app.serverErrorResponse(w, r, err)
-		return
-	}
-
 	var res struct {
 		Token *string    `json:"token"`
 		User  *data.User `json:"user"`
@@ -148,3 +151,18 @@ func (app *application) activateUserHandler(w http.ResponseWriter, r *http.Reque
 
 	app.writeJSON(w, http.StatusOK, envelope{"user": user}, nil)
 }
+
+// showUserPermissionsHandler returns the permission codes of the authenticated user, so that
+// clients can hide the controls the user isn't allowed to use.
+func (app *application) showUserPermissionsHandler(w http.ResponseWriter, r *http.Request) {
+	permissions, err := app.permissionsFor(r)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"permissions": permissions}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+func TestRegisterUserBcryptCost(t *testing.T) {
+	db := newTestDB(t)
+
+	app := &application{
+		logger: jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models: data.NewModels(db, nil),
+	}
+	app.config.bcryptCost = data.MinBcryptCost + 1
+
+	body := `{"name": "Alice", "email": "alice@example.com", "password": "pa55word1234"}`
+	rr := httptest.NewRecorder()
+	app.registerUserHandler(rr, httptest.NewRequest(http.MethodPost, "/v1/users", strings.NewReader(body)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("got status %d: %s", rr.Code, rr.Body)
+	}
+
+	var hash []byte
+	if err := db.QueryRow(`SELECT password_hash FROM users WHERE email = 'alice@example.com'`).Scan(&hash); err != nil {
+		t.Fatal(err)
+	}
+
+	cost, err := bcrypt.Cost(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != app.config.bcryptCost {
+		t.Errorf("got a password hash of cost %d, want the configured %d", cost, app.config.bcryptCost)
+	}
+}
+
+func TestShowUserPermissions(t *testing.T) {
+	db := newTestDB(t)
+
+	app := &application{
+		logger: jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models: data.NewModels(db, nil),
+	}
+
+	user := &data.User{Name: "Alice", Email: "alice@example.com", Activated: true}
+	if err := user.Password.Set("pa55word1234", data.MinBcryptCost); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.models.Users.Insert(user); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.models.Permissions.AddForUser(user.ID, "champions:read"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/users/me/permissions", nil)
+	rr := httptest.NewRecorder()
+	app.router().ServeHTTP(rr, app.contextSetUser(r, user))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rr.Code, rr.Body)
+	}
+
+	var body struct {
+		Permissions []string `json:"permissions"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(body.Permissions, []string{"champions:read"}) {
+		t.Errorf("got permissions %q, want exactly champions:read", body.Permissions)
+	}
+}
+
+func TestShowUserPermissionsAPIKey(t *testing.T) {
+	app := &application{features: newFeatureFlags()}
+
+	// A request made with an API key lists the key's permissions, without reading the database.
+	r := withAPIKey(app, httptest.NewRequest(http.MethodGet, "/v1/users/me/permissions", nil), "champions:read", "matches:write")
+	rr := httptest.NewRecorder()
+	app.router().ServeHTTP(rr, r)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rr.Code, rr.Body)
+	}
+
+	var body struct {
+		Permissions []string `json:"permissions"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"champions:read", "matches:write"}; !reflect.DeepEqual(body.Permissions, want) {
+		t.Errorf("got permissions %q, want %q", body.Permissions, want)
+	}
+
+	r = app.contextSetUser(httptest.NewRequest(http.MethodGet, "/v1/users/me/permissions", nil), data.AnonymousUser)
+	rr = httptest.NewRecorder()
+	app.router().ServeHTTP(rr, r)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d for an anonymous user, want %d", rr.Code, http.StatusUnauthorized)
+	}
+}
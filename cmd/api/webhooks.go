@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// webhookEventChampionStatsChanged is sent when a recompute moves champions' win rates by more
+// than the configured threshold.
+const webhookEventChampionStatsChanged = "champion.stats_changed"
+
+// webhookSignatureHeader carries the hex HMAC-SHA256 of the request body, keyed with the
+// webhook's secret, so receivers can check that an event came from us.
+const webhookSignatureHeader = "X-Signature-256"
+
+// webhookEvent is the body POSTed to each webhook.
+type webhookEvent struct {
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// createWebhookHandler registers a URL to be sent events. The response includes the secret the
+// events are signed with, which isn't shown again.
+func (app *application) createWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		URL string `json:"url"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	webhook := &data.Webhook{URL: input.URL}
+
+	v := validator.New()
+
+	if data.ValidateWebhook(v, webhook); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	webhook.Secret, err = data.GenerateWebhookSecret()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Webhooks.Insert(webhook)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"webhook": webhook}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// listWebhooksHandler returns the registered webhooks, without their secrets.
+func (app *application) listWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := app.models.Webhooks.GetAll()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"webhooks": webhooks}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) deleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Webhooks.Delete(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "webhook successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// notifyChampionStatsChanged sends a webhook event listing the champions whose win rate moved by
+// more than the configured threshold in a recompute. Nothing is sent if none did, or while the
+// webhooks feature is disabled.
+func (app *application) notifyChampionStatsChanged(changes []*data.ChampionStatsChange) {
+	if !app.features.isEnabled(featureWebhooks) {
+		return
+	}
+
+	significant := []*data.ChampionStatsChange{}
+	for _, change := range changes {
+		if math.Abs(change.NewWinRate-change.OldWinRate) > app.config.webhooks.winRateThreshold {
+			significant = append(significant, change)
+		}
+	}
+
+	if len(significant) == 0 {
+		return
+	}
+
+	app.sendWebhookEvent(webhookEvent{
+		Type:      webhookEventChampionStatsChanged,
+		CreatedAt: time.Now().UTC(),
+		Data:      envelope{"changes": significant},
+	})
+}
+
+// sendWebhookEvent delivers an event to every registered webhook, each in its own background
+// goroutine. Failures are logged rather than returned, since no caller can act on them.
+func (app *application) sendWebhookEvent(event webhookEvent) {
+	webhooks, err := app.models.Webhooks.GetAll()
+	if err != nil {
+		app.logger.PrintError(fmt.Errorf("load webhooks: %w", err), map[string]string{"event": event.Type})
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		app.logger.PrintError(fmt.Errorf("encode webhook event: %w", err), map[string]string{"event": event.Type})
+		return
+	}
+
+	for _, webhook := range webhooks {
+		webhook := webhook
+
+		app.background(func() {
+			err := app.deliverWebhook(webhook, body)
+			if err != nil {
+				app.logger.PrintError(err, map[string]string{"event": event.Type, "webhook": strconv.FormatInt(webhook.ID, 10)})
+			}
+		})
+	}
+}
+
+// deliverWebhook POSTs a signed event body to a webhook, retrying with exponential backoff (1s,
+// 2s, 4s, ...) until it's accepted or the configured number of attempts is used up.
+func (app *application) deliverWebhook(webhook *data.Webhook, body []byte) error {
+	signature := signWebhookBody(webhook.Secret, body)
+
+	var err error
+	for attempt := 1; attempt <= app.config.webhooks.maxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(1<<(attempt-2)) * time.Second)
+		}
+
+		err = postWebhook(webhook.URL, signature, body)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("deliver webhook after %d attempts: %w", app.config.webhooks.maxAttempts, err)
+}
+
+// postWebhook makes a single delivery attempt. Any 2xx response counts as accepted.
+func postWebhook(url, signature string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookSignatureHeader, "sha256="+signature)
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain (a bounded amount of) the body so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// signWebhookBody returns the hex-encoded HMAC-SHA256 of body, keyed with secret.
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/data"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+// webhookDelivery is a request received by a fake webhook receiver.
+type webhookDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookReceiver starts a fake webhook receiver which answers every request with status and
+// passes what it received on to the returned channel.
+func newWebhookReceiver(t *testing.T, status int) (*httptest.Server, chan webhookDelivery) {
+	t.Helper()
+
+	deliveries := make(chan webhookDelivery, 10)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		deliveries <- webhookDelivery{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, deliveries
+}
+
+// checkSignature asserts that a delivery is signed with secret.
+func checkSignature(t *testing.T, delivery webhookDelivery, secret string) {
+	t.Helper()
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(delivery.body)
+
+	if got, want := delivery.header.Get(webhookSignatureHeader), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("got signature %q, want %q", got, want)
+	}
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	srv, deliveries := newWebhookReceiver(t, http.StatusNoContent)
+
+	app := &application{}
+	app.config.webhooks.maxAttempts = 1
+
+	webhook := &data.Webhook{URL: srv.URL, Secret: "s3cret"}
+	body := []byte(`{"type":"champion.stats_changed"}`)
+
+	if err := app.deliverWebhook(webhook, body); err != nil {
+		t.Fatal(err)
+	}
+
+	delivery := <-deliveries
+	if string(delivery.body) != string(body) {
+		t.Errorf("got body %s, want %s", delivery.body, body)
+	}
+	if got := delivery.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("got Content-Type %q, want application/json", got)
+	}
+	checkSignature(t, delivery, webhook.Secret)
+}
+
+func TestDeliverWebhookRejected(t *testing.T) {
+	srv, deliveries := newWebhookReceiver(t, http.StatusInternalServerError)
+
+	app := &application{}
+	app.config.webhooks.maxAttempts = 1
+
+	if err := app.deliverWebhook(&data.Webhook{URL: srv.URL}, []byte(`{}`)); err == nil {
+		t.Error("got no error for a receiver which failed every attempt")
+	}
+	if got := len(deliveries); got != 1 {
+		t.Errorf("got %d attempts, want 1", got)
+	}
+}
+
+func TestNotifyChampionStatsChanged(t *testing.T) {
+	srv, deliveries := newWebhookReceiver(t, http.StatusOK)
+
+	app := &application{
+		logger:   jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+		models:   data.NewModels(newTestDB(t), nil),
+		features: newFeatureFlags(),
+	}
+	app.config.webhooks.winRateThreshold = 0.05
+	app.config.webhooks.maxAttempts = 1
+
+	webhook := &data.Webhook{URL: srv.URL, Secret: "s3cret"}
+	if err := app.models.Webhooks.Insert(webhook); err != nil {
+		t.Fatal(err)
+	}
+
+	app.notifyChampionStatsChanged([]*data.ChampionStatsChange{
+		{ChampionID: 1, Name: "Ahri", OldWinRate: 0.50, NewWinRate: 0.51},
+		{ChampionID: 2, Name: "Syndra", OldWinRate: 0.50, NewWinRate: 0.42},
+	})
+
+	var delivery webhookDelivery
+	select {
+	case delivery = <-deliveries:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the receiver got no event")
+	}
+
+	checkSignature(t, delivery, webhook.Secret)
+
+	var event struct {
+		Type string `json:"type"`
+		Data struct {
+			Changes []data.ChampionStatsChange `json:"changes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(delivery.body, &event); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the change beyond the threshold is sent.
+	if event.Type != webhookEventChampionStatsChanged {
+		t.Errorf("got event type %q, want %q", event.Type, webhookEventChampionStatsChanged)
+	}
+	if len(event.Data.Changes) != 1 || event.Data.Changes[0].Name != "Syndra" {
+		t.Errorf("got changes %+v, want only Syndra's", event.Data.Changes)
+	}
+}
@@ -0,0 +1,186 @@
+package data
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// apiKeyLength is the length of a plaintext API key: 32 random bytes, base-32 encoded without
+// padding.
+const apiKeyLength = 52
+
+// APIKey is a long-lived credential for service-to-service callers, sent in the X-API-Key header
+// instead of a user's bearer token. A key carries its own permissions rather than a user's. Only
+// the SHA-256 hash of the key is stored, so the plaintext can't be shown again after creation.
+type APIKey struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Plaintext   string      `json:"key,omitempty"` // Only sent back when the key is created
+	Hash        []byte      `json:"-"`
+	Permissions Permissions `json:"permissions"`
+	Expiry      *time.Time  `json:"expiry,omitempty"` // Never expires if nil
+	RevokedAt   *time.Time  `json:"revokedAt,omitempty"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
+
+// User returns the user a request authenticated with the key acts as: an activated user with no ID
+// of its own, named after the key. Its permissions are those of the key, not looked up by ID.
+func (key *APIKey) User() *User {
+	return &User{Name: key.Name, Activated: true}
+}
+
+func ValidateAPIKey(v *validator.Validator, key *APIKey) {
+	v.Check(key.Name != "", "name", "must be provided")
+	v.Check(len(key.Name) <= 100, "name", "must not be more than 100 bytes long")
+
+	v.Check(len(key.Permissions) > 0, "permissions", "must contain at least one permission")
+	v.Check(validator.Unique(key.Permissions), "permissions", "must not contain duplicate values")
+
+	if key.Expiry != nil {
+		v.Check(key.Expiry.After(time.Now()), "expiry", "must be in the future")
+	}
+}
+
+func ValidateAPIKeyPlaintext(v *validator.Validator, plaintext string) {
+	v.Check(plaintext != "", "key", "must be provided")
+	v.Check(len(plaintext) == apiKeyLength, "key", "must be 52 bytes long")
+}
+
+// GenerateAPIKey sets a new random plaintext key and its hash.
+func GenerateAPIKey(key *APIKey) error {
+	randomBytes := make([]byte, 32)
+
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return err
+	}
+
+	key.Plaintext = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(randomBytes)
+
+	hash := sha256.Sum256([]byte(key.Plaintext))
+	key.Hash = hash[:]
+
+	return nil
+}
+
+type APIKeyModel struct {
+	DB *DB
+}
+
+// Insert adds an API key, setting its ID and CreatedAt from the database.
+func (m APIKeyModel) Insert(key *APIKey) error {
+	query := `
+        INSERT INTO api_keys (name, hash, permissions, expiry)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, key.Name, key.Hash, pq.Array(key.Permissions), key.Expiry).Scan(&key.ID, &key.CreatedAt)
+}
+
+// GetForKey returns the API key with the given plaintext, or ErrRecordNotFound if there is no such
+// key or it has been revoked or has expired.
+func (m APIKeyModel) GetForKey(plaintext string) (*APIKey, error) {
+	hash := sha256.Sum256([]byte(plaintext))
+
+	query := `
+        SELECT id, name, permissions, expiry, revoked_at, created_at
+        FROM api_keys
+        WHERE hash = $1 AND revoked_at IS NULL AND (expiry IS NULL OR expiry > $2)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var key APIKey
+
+	err := m.DB.QueryRowContext(ctx, query, hash[:], time.Now()).Scan(
+		&key.ID,
+		&key.Name,
+		pq.Array(&key.Permissions),
+		&key.Expiry,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &key, nil
+}
+
+// GetAll returns every API key, including revoked and expired ones, in the order they were
+// created. The keys themselves aren't stored, so Plaintext is always empty.
+func (m APIKeyModel) GetAll() ([]*APIKey, error) {
+	query := `
+        SELECT id, name, permissions, expiry, revoked_at, created_at
+        FROM api_keys
+        ORDER BY id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*APIKey{}
+
+	for rows.Next() {
+		var key APIKey
+		err := rows.Scan(&key.ID, &key.Name, pq.Array(&key.Permissions), &key.Expiry, &key.RevokedAt, &key.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Revoke stops an API key from authenticating any further requests. It returns ErrRecordNotFound
+// if the key doesn't exist or was already revoked.
+func (m APIKeyModel) Revoke(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package data
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	var a, b APIKey
+	if err := GenerateAPIKey(&a); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateAPIKey(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Plaintext == b.Plaintext {
+		t.Error("generated the same key twice")
+	}
+
+	v := validator.New()
+	if ValidateAPIKeyPlaintext(v, a.Plaintext); !v.Valid() {
+		t.Errorf("generated key %q fails validation: %v", a.Plaintext, v.Errors)
+	}
+
+	// Only the hash is stored, and it must be the one GetForKey looks the key up by.
+	hash := sha256.Sum256([]byte(a.Plaintext))
+	if string(a.Hash) != string(hash[:]) {
+		t.Errorf("got hash %x, want the SHA-256 of the key", a.Hash)
+	}
+}
@@ -0,0 +1,73 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+// BestSummoner is a summoner's record on a champion, for listing the summoners who play it best.
+type BestSummoner struct {
+	Summoner             *PublicSummoner `json:"summoner"`
+	Rating               int             `json:"rating"`
+	CountOfPlayedMatches int             `json:"countOfPlayedMatches"` // Count of matches played with the champion
+	WinRate              float64         `json:"winRate"`              // Winrate with the champion
+	KDA                  KDA             `json:"kda"`                  // Total kills, deaths and assists with the champion
+	KDARatio             float64         `json:"kdaRatio"`             // (kills + assists) / deaths with the champion
+}
+
+// GetBestSummoners returns up to limit of the summoners with the best record on a champion,
+// highest win rate first, among those who have played it at least minGames times. If prosOnly is
+// true, only summoners marked as professional players are considered. Private summoners are never
+// listed.
+func (c ChampionModel) GetBestSummoners(id int64, minGames int, prosOnly bool, limit int) ([]*BestSummoner, error) {
+	query := `
+        SELECT s.id, s.username, s.region, s.is_pro, s.rating,
+            scs.count_of_played_matches, scs.win_rate, scs.kills, scs.deaths, scs.assists
+        FROM summoner_champion_stats scs
+        JOIN summoners s ON s.id = scs.summoner_id
+        WHERE scs.champion_id = $1
+        AND scs.count_of_played_matches >= $2
+        AND (NOT $3 OR s.is_pro)
+        AND NOT s.is_private
+        ORDER BY scs.win_rate DESC, scs.count_of_played_matches DESC, s.id ASC
+        LIMIT $4`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, id, minGames, prosOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	best := []*BestSummoner{}
+
+	for rows.Next() {
+		b := BestSummoner{Summoner: &PublicSummoner{}}
+		err := rows.Scan(
+			&b.Summoner.ID,
+			&b.Summoner.Username,
+			&b.Summoner.Region,
+			&b.Summoner.IsPro,
+			&b.Rating,
+			&b.CountOfPlayedMatches,
+			&b.WinRate,
+			&b.KDA.Kills,
+			&b.KDA.Deaths,
+			&b.KDA.Assists,
+		)
+		if err != nil {
+			return nil, err
+		}
+		b.KDARatio = b.KDA.Ratio()
+
+		best = append(best, &b)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return best, nil
+}
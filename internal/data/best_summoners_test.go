@@ -0,0 +1,58 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetBestSummonersProsOnly(t *testing.T) {
+	models := newTestModels(t)
+
+	champion := insertTestChampion(t, models, "Ahri")
+
+	pro := insertTestSummoner(t, models, "Faker")
+	amateur := insertTestSummoner(t, models, "Bronze Ahri")
+	if err := models.Summoners.SetPro(pro.ID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// The amateur has the better record, so would be listed first if pros weren't filtered.
+	for _, stats := range []struct {
+		summonerID int64
+		winRate    float64
+	}{{pro.ID, 0.6}, {amateur.ID, 0.8}} {
+		_, err := models.Champions.DB.Exec(`
+            INSERT INTO summoner_champion_stats (summoner_id, champion_id, win_rate, count_of_played_matches)
+            VALUES ($1, $2, $3, 20)`, stats.summonerID, champion.ID, stats.winRate)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		prosOnly bool
+		want     []int64
+	}{
+		{false, []int64{amateur.ID, pro.ID}},
+		{true, []int64{pro.ID}},
+	}
+
+	for _, tt := range tests {
+		best, err := models.Champions.GetBestSummoners(champion.ID, 1, tt.prosOnly, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []int64
+		for _, b := range best {
+			got = append(got, b.Summoner.ID)
+			if tt.prosOnly && !b.Summoner.IsPro {
+				t.Errorf("pros only: got summoner %d, which isn't a pro", b.Summoner.ID)
+			}
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("prosOnly=%t: got summoners %v, want %v", tt.prosOnly, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// ErrDuplicateAlias is returned when an alias is already used by another champion.
+var ErrDuplicateAlias = errors.New("duplicate alias")
+
+// NormalizeAlias normalizes the whitespace in an alias and lowercases it, so that "Mundo" and
+// " mundo " are the same alias.
+func NormalizeAlias(alias string) string {
+	return strings.ToLower(NormalizeName(alias))
+}
+
+// NormalizeAliases normalizes each alias and removes empty and duplicate aliases, keeping the
+// aliases in the order they were first given.
+func NormalizeAliases(aliases []string) []string {
+	seen := make(map[string]bool, len(aliases))
+	normalized := []string{}
+
+	for _, alias := range aliases {
+		alias = NormalizeAlias(alias)
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		normalized = append(normalized, alias)
+	}
+
+	return normalized
+}
+
+func ValidateAliases(v *validator.Validator, aliases []string) {
+	v.Check(len(aliases) > 0, "aliases", "must contain at least one alias")
+	v.Check(len(aliases) <= 20, "aliases", "must not contain more than 20 aliases")
+
+	for _, alias := range aliases {
+		v.Check(len(alias) <= 50, "aliases", "must not contain aliases more than 50 bytes long")
+	}
+}
+
+// AddAliases adds search aliases to a champion. Aliases which the champion already has are
+// ignored. If any of the aliases belongs to another champion, ErrDuplicateAlias is returned and
+// none are added.
+func (c ChampionModel) AddAliases(championID int64, aliases []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO champion_aliases (alias, champion_id)
+        SELECT unnest($2::text[]), $1
+        ON CONFLICT DO NOTHING`, championID, pq.Array(aliases))
+	if err != nil {
+		return err
+	}
+
+	var taken bool
+	err = tx.QueryRowContext(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM champion_aliases
+            WHERE alias = ANY($2) AND champion_id <> $1
+        )`, championID, pq.Array(aliases)).Scan(&taken)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrDuplicateAlias
+	}
+
+	return tx.Commit()
+}
+
+// GetAliases returns the aliases of a champion in alphabetical order.
+func (c ChampionModel) GetAliases(championID int64) ([]string, error) {
+	query := `
+        SELECT COALESCE(array_agg(alias ORDER BY alias), '{}')
+        FROM champion_aliases
+        WHERE champion_id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var aliases []string
+	err := c.DB.QueryRowContext(ctx, query, championID).Scan(pq.Array(&aliases))
+	if err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
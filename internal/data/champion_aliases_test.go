@@ -0,0 +1,56 @@
+package data
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeAliases(t *testing.T) {
+	got := NormalizeAliases([]string{" Mundo ", "mundo", "", "Dr  Mundo", "DOC"})
+
+	want := []string{"mundo", "dr mundo", "doc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSearchByAlias(t *testing.T) {
+	models := newTestModels(t)
+
+	mundo := insertTestChampion(t, models, "Dr. Mundo")
+	ahri := insertTestChampion(t, models, "Ahri")
+
+	if err := models.Champions.AddAliases(mundo.ID, NormalizeAliases([]string{"Mundo", "doc"})); err != nil {
+		t.Fatal(err)
+	}
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}}
+
+	// Aliases match whatever the case, and the champion's own name still matches.
+	for _, name := range []string{"Mundo", "DOC", "dr. mundo"} {
+		champions, _, err := models.Champions.GetAll(name, "", false, MetaThresholds{}, nil, nil, time.Time{}, StatsScope{}, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(champions) != 1 || champions[0].ID != mundo.ID {
+			t.Errorf("searching for %q: got %d champions, want only Dr. Mundo", name, len(champions))
+		}
+	}
+
+	// An alias can only belong to one champion.
+	err := models.Champions.AddAliases(ahri.ID, []string{"fox", "mundo"})
+	if !errors.Is(err, ErrDuplicateAlias) {
+		t.Errorf("got error %v, want %v", err, ErrDuplicateAlias)
+	}
+
+	// The rejected batch was rolled back as a whole.
+	aliases, err := models.Champions.GetAliases(ahri.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases) != 0 {
+		t.Errorf("got aliases %q for Ahri, want none", aliases)
+	}
+}
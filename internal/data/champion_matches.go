@@ -0,0 +1,98 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ChampionMatch is a match a champion was picked in, along with the summoner who played it and
+// how the match went for them.
+type ChampionMatch struct {
+	Match      *Match `json:"match"`
+	SummonerID int64  `json:"summonerId"`
+	Username   string `json:"username"`
+	Team       string `json:"team"`
+	Role       string `json:"role"`
+	Outcome    string `json:"outcome"` // OutcomeWin, OutcomeLoss or ResultRemake
+}
+
+// championMatchWhere is the WHERE clause shared by the champion match history and count queries.
+// Its placeholders are the champion ID, the role, the outcome and the remake result, in that order.
+const championMatchWhere = `
+        WHERE mp.champion_id = $1
+        AND ($2 = '' OR LOWER(mp.role) = LOWER($2))
+        AND ($3 = ''
+            OR ($3 = 'win' AND LOWER(m.result) = mp.team)
+            OR ($3 = 'loss' AND LOWER(m.result) NOT IN (mp.team, $4)))`
+
+// GetMatchHistory returns a page of the matches the champion was picked in, with the performance
+// which picked it, along with the paging metadata. role and outcome (OutcomeWin or OutcomeLoss)
+// are optional filters. A champion can't be picked twice in one match, so each match appears at
+// most once.
+func (c ChampionModel) GetMatchHistory(id int64, role, outcome string, filters Filters) ([]*ChampionMatch, Metadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	args := []interface{}{id, role, outcome, ResultRemake}
+
+	var totalRecords int
+	err := c.DB.QueryRowContext(ctx, `
+        SELECT count(*)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id`+championMatchWhere, args...).Scan(&totalRecords)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	query := fmt.Sprintf(`
+        SELECT m.id, m.duration, m.result, m.match_type, m.played_date, m.blue_team, m.red_team, m.replay_url, m.vod_url,
+            mp.summoner_id, s.username, mp.team, mp.role,
+            CASE WHEN LOWER(m.result) = $4 THEN $4 WHEN LOWER(m.result) = mp.team THEN 'win' ELSE 'loss' END
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        JOIN summoners s ON s.id = mp.summoner_id %s
+        ORDER BY m.%s %s, m.id ASC
+        LIMIT $5 OFFSET $6`, championMatchWhere, filters.sortColumn(), filters.sortDirection())
+
+	rows, err := c.DB.QueryContext(ctx, query, append(args, filters.limit(), filters.offset())...)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	history := []*ChampionMatch{}
+
+	for rows.Next() {
+		var match Match
+		entry := ChampionMatch{Match: &match}
+
+		err := rows.Scan(
+			&match.ID,
+			&match.Duration,
+			&match.Result,
+			&match.MatchType,
+			&match.PlayedDate,
+			&match.BlueTeam,
+			&match.RedTeam,
+			&match.ReplayURL,
+			&match.VODURL,
+			&entry.SummonerID,
+			&entry.Username,
+			&entry.Team,
+			&entry.Role,
+			&entry.Outcome,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		history = append(history, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return history, calculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
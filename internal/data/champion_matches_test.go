@@ -0,0 +1,56 @@
+package data
+
+import "testing"
+
+func TestChampionMatchHistory(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	ahri := insertTestChampion(t, models, "Ahri")
+	syndra := insertTestChampion(t, models, "Syndra")
+
+	insertTestPerformance(t, models, summoner.ID, ahri.ID, "pro", ResultBlue)
+	insertTestPerformance(t, models, summoner.ID, syndra.ID, "pro", ResultBlue)
+	insertTestPerformance(t, models, summoner.ID, ahri.ID, "pro", ResultRed)
+	insertTestPerformance(t, models, summoner.ID, ahri.ID, "pro", ResultRemake)
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}}
+
+	tests := []struct {
+		outcome  string
+		outcomes []string
+	}{
+		{"", []string{OutcomeWin, OutcomeLoss, ResultRemake}},
+		{OutcomeWin, []string{OutcomeWin}},
+		{OutcomeLoss, []string{OutcomeLoss}},
+	}
+
+	for _, tt := range tests {
+		history, metadata, err := models.Champions.GetMatchHistory(ahri.ID, "", tt.outcome, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(history) != len(tt.outcomes) || metadata.TotalRecords != len(tt.outcomes) {
+			t.Fatalf("outcome %q: got %d matches (%d in total), want %d", tt.outcome, len(history), metadata.TotalRecords, len(tt.outcomes))
+		}
+
+		for i, entry := range history {
+			if entry.SummonerID != summoner.ID || entry.Username != "Faker" || entry.Team != ResultBlue {
+				t.Errorf("outcome %q: got %+v, want Faker's performance", tt.outcome, entry)
+			}
+			if entry.Outcome != tt.outcomes[i] {
+				t.Errorf("outcome %q: match %d has outcome %q, want %q", tt.outcome, i, entry.Outcome, tt.outcomes[i])
+			}
+		}
+	}
+
+	// The Syndra match is the only one which doesn't feature Ahri.
+	history, _, err := models.Champions.GetMatchHistory(syndra.ID, "", "", filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Outcome != OutcomeWin {
+		t.Errorf("got %d Syndra matches, want the one win", len(history))
+	}
+}
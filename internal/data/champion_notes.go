@@ -0,0 +1,73 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// ChampionNote is an editorial note attached to a champion, such as a buff or nerf description
+// for a patch.
+type ChampionNote struct {
+	ID         int64     `json:"id"`
+	ChampionID int64     `json:"championId"`
+	UserID     int64     `json:"userId"` // Author of the note (0 if written with an API key, or the user was deleted)
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func ValidateChampionNote(v *validator.Validator, note *ChampionNote) {
+	v.Check(note.Body != "", "body", "must be provided")
+	v.Check(len(note.Body) <= 2000, "body", "must not be more than 2000 bytes long")
+}
+
+// InsertNote adds a note to a champion, setting the note's ID and CreatedAt from the database. A
+// note with no UserID is stored without an author.
+func (c ChampionModel) InsertNote(note *ChampionNote) error {
+	query := `
+        INSERT INTO champion_notes (champion_id, user_id, body)
+        VALUES ($1, NULLIF($2, 0), $3)
+        RETURNING id, created_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return c.DB.QueryRowContext(ctx, query, note.ChampionID, note.UserID, note.Body).Scan(&note.ID, &note.CreatedAt)
+}
+
+// GetLatestNotes returns up to limit of the most recent notes for a champion, newest first.
+func (c ChampionModel) GetLatestNotes(championID int64, limit int) ([]*ChampionNote, error) {
+	query := `
+        SELECT id, champion_id, COALESCE(user_id, 0), body, created_at
+        FROM champion_notes
+        WHERE champion_id = $1
+        ORDER BY created_at DESC, id DESC
+        LIMIT $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, championID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []*ChampionNote{}
+
+	for rows.Next() {
+		var note ChampionNote
+		err := rows.Scan(&note.ID, &note.ChampionID, &note.UserID, &note.Body, &note.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, &note)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
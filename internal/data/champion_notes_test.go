@@ -0,0 +1,74 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChampionNotes(t *testing.T) {
+	models := newTestModels(t)
+
+	user := &User{Name: "Editor", Email: "editor@example.com", Activated: true}
+	if err := user.Password.Set("pa55word1234", 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := models.Users.Insert(user); err != nil {
+		t.Fatal(err)
+	}
+
+	ahri := insertTestChampion(t, models, "Ahri")
+	syndra := insertTestChampion(t, models, "Syndra")
+
+	notes := []*ChampionNote{
+		{ChampionID: ahri.ID, UserID: user.ID, Body: "14.1: base AD increased"},
+		{ChampionID: syndra.ID, UserID: user.ID, Body: "14.1: Q cooldown reduced"},
+		{ChampionID: ahri.ID, UserID: user.ID, Body: "14.2: charm duration reduced"},
+		{ChampionID: ahri.ID, UserID: user.ID, Body: "14.3: ultimate mana cost increased"},
+	}
+	for _, note := range notes {
+		if err := models.Champions.InsertNote(note); err != nil {
+			t.Fatal(err)
+		}
+		if note.ID == 0 || note.CreatedAt.IsZero() {
+			t.Errorf("got ID %d and created at %v after insert, want both set", note.ID, note.CreatedAt)
+		}
+	}
+
+	got, err := models.Champions.GetLatestNotes(ahri.ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Newest first, limited to two, and without Syndra's note.
+	var bodies []string
+	for _, note := range got {
+		bodies = append(bodies, note.Body)
+		if note.ChampionID != ahri.ID || note.UserID != user.ID {
+			t.Errorf("%q: got champion %d and user %d, want %d and %d", note.Body, note.ChampionID, note.UserID, ahri.ID, user.ID)
+		}
+	}
+	want := []string{"14.3: ultimate mana cost increased", "14.2: charm duration reduced"}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Errorf("got notes %q, want %q", bodies, want)
+	}
+}
+
+func TestChampionNoteWithoutUser(t *testing.T) {
+	models := newTestModels(t)
+
+	ahri := insertTestChampion(t, models, "Ahri")
+
+	// A note written with an API key has no user to reference.
+	note := &ChampionNote{ChampionID: ahri.ID, Body: "14.1: base AD increased"}
+	if err := models.Champions.InsertNote(note); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := models.Champions.GetLatestNotes(ahri.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != note.ID || got[0].UserID != 0 {
+		t.Errorf("got notes %+v, want note %d without a user", got, note.ID)
+	}
+}
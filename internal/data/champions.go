@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"league_of_graphs.satellite.net/internal/validator"
 )
 
@@ -14,9 +16,21 @@ type Champion struct {
 	ID            int64                   `json:"id"`
 	Name          string                  `json:"name"`
 	MainRole      string                  `json:"mainRole"`
+	ImageURL      string                  `json:"imageUrl"`
+	DamageProfile string                  `json:"damageProfile"` // AD, AP or mixed (empty if not classified)
+	PowerSpikes   PowerSpikes             `json:"powerSpikes"`   // Points in a game where the champion spikes in strength
+	Classes       ChampionClasses         `json:"classes"`       // Class tags, e.g. Assassin or Tank
+	ReleaseDate   *time.Time              `json:"releaseDate"`   // Date the champion was released (nil if unknown)
+	AgeDays       *int                    `json:"ageDays"`       // Days since the release date (nil if unknown)
 	Popularity    float64                 `json:"popularity"`
 	WinRate       float64                 `json:"winRate"`
 	BanRate       float64                 `json:"banRate"`
+	IsMeta        bool                    `json:"isMeta"`
+	Version       int32                   `json:"version"`
+	Aliases       []string                `json:"aliases,omitempty"`
+	Notes         []*ChampionNote         `json:"notes,omitempty"`
+	WinRateTrend  *WinRateTrend           `json:"winRateTrend,omitempty"`
+	RoleDrift     *RoleDrift              `json:"roleDrift,omitempty"`
 	MatchHistory  []*Match                `json:"-"`
 	BestSummoners []SummonerChampionStats `json:"-"`
 }
@@ -32,44 +46,95 @@ func ValidateChampion(v *validator.Validator, champion *Champion) {
 	v.Check(champion.MainRole != "", "main_role", "must be provided")
 
 	v.Check(champion.Name != "Champion", "name", "must be different from the name of the champion")
+
+	v.Check(len(champion.ImageURL) <= 2000, "image_url", "must not be more than 2000 bytes long")
+	v.Check(champion.ImageURL == "" || strings.HasPrefix(champion.ImageURL, "https://") || strings.HasPrefix(champion.ImageURL, "http://"), "image_url", "must be an http or https URL")
+
+	v.Check(champion.DamageProfile == "" || validator.In(champion.DamageProfile, DamageProfiles...), "damage_profile", "must be one of "+strings.Join(DamageProfiles, ", "))
+
+	ValidateClasses(v, champion.Classes)
+
+	if champion.ReleaseDate != nil {
+		v.Check(!champion.ReleaseDate.After(time.Now()), "release_date", "must not be in the future")
+		v.Check(!champion.ReleaseDate.Before(leagueReleaseDate), "release_date", "must not be before League of Legends was released")
+	}
+}
+
+// ChampionPatch holds the fields of a partial champion update. A nil field is left unchanged.
+type ChampionPatch struct {
+	Name     *string
+	MainRole *string
+	ImageURL *string
+}
+
+// MetaThresholds holds the popularity (our measure of how often a champion is picked) and win
+// rate which a champion must both exceed to be flagged as part of the current meta.
+type MetaThresholds struct {
+	MinPopularity float64
+	MinWinRate    float64
+}
+
+// IsMeta reports whether the champion exceeds both meta thresholds.
+func (t MetaThresholds) IsMeta(champion *Champion) bool {
+	return champion.Popularity > t.MinPopularity && champion.WinRate > t.MinWinRate
+}
+
+// Apply sets the IsMeta flag on each of the provided champions.
+func (t MetaThresholds) Apply(champions ...*Champion) {
+	for _, champion := range champions {
+		champion.IsMeta = t.IsMeta(champion)
+	}
 }
 
 type ChampionModel struct {
-	DB *sql.DB
+	DB *DB
 }
 
 func (m ChampionModel) Insert(champion *Champion) error {
 	query := `
-        INSERT INTO champions (name, main_role)
-        VALUES ($1, $2)
-        RETURNING id, popularity, win_rate, ban_rate
+        INSERT INTO champions (name, main_role, image_url, damage_profile, classes, release_date)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, popularity, win_rate, ban_rate, version, CURRENT_DATE - release_date
     `
 
-	args := []interface{}{champion.Name, champion.MainRole}
+	args := []interface{}{champion.Name, champion.MainRole, champion.ImageURL, champion.DamageProfile, champion.Classes, champion.ReleaseDate}
 
-	return m.DB.QueryRow(query, args...).Scan(&champion.ID, &champion.Popularity, &champion.WinRate, &champion.BanRate)
+	return m.DB.QueryRow(query, args...).Scan(&champion.ID, &champion.Popularity, &champion.WinRate, &champion.BanRate, &champion.Version, &champion.AgeDays)
 }
 
 func (c ChampionModel) Get(id int64) (*Champion, error) {
+	return c.GetInScope(id, StatsScope{})
+}
+
+// GetInScope returns the champion with the given ID, like Get, but with its win rate and
+// popularity counted only from the matches and performances within scope.
+func (c ChampionModel) GetInScope(id int64, scope StatsScope) (*Champion, error) {
 	if id < 1 {
 		return nil, ErrRecordNotFound
 	}
 
 	query := `
-		SELECT id, name, main_role, popularity, win_rate, ban_rate
-		FROM champions
+		SELECT id, name, main_role, image_url, damage_profile, power_spikes, classes, release_date, CURRENT_DATE - release_date, popularity, win_rate, ban_rate, version` +
+		championSource("$2", "$3") + `
 		WHERE id = $1
 	`
 
 	var champion Champion
 
-	err := c.DB.QueryRow(query, id).Scan(
+	err := c.DB.QueryRow(query, id, scope.MatchType, pq.Array(scope.Tiers)).Scan(
 		&champion.ID,
 		&champion.Name,
 		&champion.MainRole,
+		&champion.ImageURL,
+		&champion.DamageProfile,
+		&champion.PowerSpikes,
+		&champion.Classes,
+		&champion.ReleaseDate,
+		&champion.AgeDays,
 		&champion.Popularity,
 		&champion.WinRate,
 		&champion.BanRate,
+		&champion.Version,
 	)
 
 	if err != nil {
@@ -84,16 +149,84 @@ func (c ChampionModel) Get(id int64) (*Champion, error) {
 	return &champion, nil
 }
 
+// Update replaces the champion's editable fields. Like UserModel.Update, it checks against the
+// version field so that a concurrent edit made since the champion was read returns
+// ErrEditConflict rather than being silently overwritten.
 func (c ChampionModel) Update(champion *Champion) error {
 	query := `
 		UPDATE champions
-		SET name = $1, main_role = $2
-		WHERE id = $3
+		SET name = $1, main_role = $2, image_url = $3, damage_profile = $4, classes = $5, release_date = $6, version = version + 1,
+			name_version = version + 1, main_role_version = version + 1, image_url_version = version + 1
+		WHERE id = $7 AND version = $8
+		RETURNING version, CURRENT_DATE - release_date
 	`
 
-	args := []interface{}{champion.Name, champion.MainRole, champion.ID}
+	args := []interface{}{champion.Name, champion.MainRole, champion.ImageURL, champion.DamageProfile, champion.Classes, champion.ReleaseDate, champion.ID, champion.Version}
+
+	err := c.DB.QueryRow(query, args...).Scan(&champion.Version, &champion.AgeDays)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Patch writes only the fields set in patch. Each field records the version at which it was last
+// written, so an edit based on an older version of the champion only conflicts (returning
+// ErrEditConflict) if one of the fields it writes has been changed since that version. Two
+// editors changing different fields of the same champion therefore both succeed.
+func (c ChampionModel) Patch(id int64, version int32, patch ChampionPatch) (*Champion, error) {
+	query := `
+		UPDATE champions
+		SET name = COALESCE($2, name),
+			name_version = CASE WHEN $2::text IS NULL THEN name_version ELSE version + 1 END,
+			main_role = COALESCE($3, main_role),
+			main_role_version = CASE WHEN $3::text IS NULL THEN main_role_version ELSE version + 1 END,
+			image_url = COALESCE($4, image_url),
+			image_url_version = CASE WHEN $4::text IS NULL THEN image_url_version ELSE version + 1 END,
+			version = version + 1
+		WHERE id = $1
+		AND ($2::text IS NULL OR name_version <= $5)
+		AND ($3::text IS NULL OR main_role_version <= $5)
+		AND ($4::text IS NULL OR image_url_version <= $5)
+		RETURNING id, name, main_role, image_url, damage_profile, power_spikes, classes, release_date, CURRENT_DATE - release_date, popularity, win_rate, ban_rate, version
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var champion Champion
+
+	err := c.DB.QueryRowContext(ctx, query, id, patch.Name, patch.MainRole, patch.ImageURL, version).Scan(
+		&champion.ID,
+		&champion.Name,
+		&champion.MainRole,
+		&champion.ImageURL,
+		&champion.DamageProfile,
+		&champion.PowerSpikes,
+		&champion.Classes,
+		&champion.ReleaseDate,
+		&champion.AgeDays,
+		&champion.Popularity,
+		&champion.WinRate,
+		&champion.BanRate,
+		&champion.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrEditConflict
+		default:
+			return nil, err
+		}
+	}
 
-	return c.DB.QueryRow(query, args...).Err()
+	return &champion, nil
 }
 
 func (c ChampionModel) Delete(id int64) error {
@@ -122,53 +255,342 @@ func (c ChampionModel) Delete(id int64) error {
 	return nil
 }
 
-func (f Filters) limit() int {
-	return f.PageSize
+// limit returns the value for the LIMIT clause. A NULL limit (used when PageSize is zero) means
+// no limit in PostgreSQL.
+func (f Filters) limit() sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(f.PageSize), Valid: f.PageSize > 0}
+}
+
+// nullDate returns a date query argument which is NULL if t is zero.
+func nullDate(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
 }
 
 func (f Filters) offset() int {
 	return (f.Page - 1) * f.PageSize
 }
 
-func (c ChampionModel) GetAll(username string, region string, filters Filters) ([]*Champion, error) {
-	query := fmt.Sprintf(`
-        SELECT id, name, main_role, popularity, win_rate, ban_rate
-        FROM champions
-        WHERE (LOWER(name) = LOWER($1) OR $1 = '')
-        AND (LOWER(main_role) = LOWER($2) OR $2 = '')
-        ORDER BY %s %s, id ASC
-        LIMIT $3 OFFSET $4`, filters.sortColumn(), filters.sortDirection())
+// championSource returns the FROM clause of the champion queries, taking the match type and rank
+// tiers of the stats scope from the given placeholders. Within a scope the win rate and popularity
+// are read from champion_scoped_stats instead of the champion's overall aggregates, so filtering
+// and sorting by them use the scoped values too.
+func championSource(matchType, tiers string) string {
+	return fmt.Sprintf(`
+        FROM (
+            SELECT c.id, c.name, c.main_role, c.image_url, c.damage_profile, c.power_spikes, c.classes, c.release_date, c.ban_rate, c.version,
+                CASE WHEN s.played IS NULL THEN c.popularity ELSE s.summoners::float8 END AS popularity,
+                CASE WHEN s.played IS NULL THEN c.win_rate ELSE COALESCE(s.wins::float8 / NULLIF(s.played, 0), 0) END AS win_rate
+            FROM champions c
+            LEFT JOIN LATERAL (
+                SELECT COALESCE(sum(count_of_played_matches), 0) AS played, COALESCE(sum(wins), 0) AS wins,
+                    count(DISTINCT summoner_id) AS summoners
+                FROM champion_scoped_stats
+                WHERE champion_id = c.id
+                AND (match_type = %[1]s OR %[1]s = '')
+                AND (cardinality(%[2]s::text[]) = 0 OR rank_tier = ANY(%[2]s))
+                HAVING %[1]s <> '' OR cardinality(%[2]s::text[]) > 0
+            ) s ON true
+        ) champions`, matchType, tiers)
+}
 
+// championWhere is the WHERE clause shared by the champion list and count queries. Its
+// placeholders are the name, role, metaOnly flag, the two meta thresholds, the power spike tags
+// (see PowerSpikeTags), the classes and the released-after date, in that order. The name also
+// matches any of a champion's aliases.
+const championWhere = `
+        WHERE (LOWER(name) = LOWER($1) OR $1 = ''
+            OR EXISTS (SELECT 1 FROM champion_aliases a WHERE a.champion_id = champions.id AND a.alias = LOWER($1)))
+        AND (LOWER(main_role) = LOWER($2) OR $2 = '')
+        AND (NOT $3 OR (popularity > $4 AND win_rate > $5))
+        AND (cardinality($6::text[]) = 0 OR power_spikes && $6)
+        AND classes @> $7
+        AND ($8::date IS NULL OR release_date > $8)`
+
+// GetAll returns a page of champions matching the name and role filters, along with the paging
+// metadata. If metaOnly is true, only champions which exceed the meta thresholds are returned,
+// if powerSpikes isn't empty, only champions with at least one of those power spikes, and only
+// champions with every one of the classes. Unless releasedAfter is zero, only champions released
+// after it are returned. Win rates and popularity, and the meta filter and sorting which use them,
+// are counted only from the matches and performances within scope.
+func (c ChampionModel) GetAll(username string, region string, metaOnly bool, meta MetaThresholds, powerSpikes []string, classes []string, releasedAfter time.Time, scope StatsScope, filters Filters) ([]*Champion, Metadata, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	rows, err := c.DB.QueryContext(ctx, query, username, region, filters.limit(), filters.offset())
+	var totalRecords int
+	err := c.DB.QueryRowContext(ctx, `SELECT count(*)`+championSource("$9", "$10")+championWhere,
+		username, region, metaOnly, meta.MinPopularity, meta.MinWinRate, pq.Array(powerSpikes), ChampionClasses(classes), nullDate(releasedAfter),
+		scope.MatchType, pq.Array(scope.Tiers)).Scan(&totalRecords)
 	if err != nil {
-		return nil, err
+		return nil, Metadata{}, err
 	}
-	defer rows.Close()
 
 	champions := []*Champion{}
 
+	err = c.Stream(ctx, username, region, metaOnly, meta, powerSpikes, classes, releasedAfter, scope, filters, func(champion *Champion) error {
+		champions = append(champions, champion)
+		return nil
+	})
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return champions, calculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+// championListQuery returns the query run by GetAll and Stream, along with its arguments.
+func championListQuery(username string, region string, metaOnly bool, meta MetaThresholds, powerSpikes []string, classes []string, releasedAfter time.Time, scope StatsScope, filters Filters) (string, []interface{}) {
+	query := fmt.Sprintf(`
+        SELECT id, name, main_role, image_url, damage_profile, power_spikes, classes, release_date, CURRENT_DATE - release_date, popularity, win_rate, ban_rate, version
+        %s %s
+        ORDER BY %s %s NULLS LAST, id ASC
+        LIMIT $11 OFFSET $12`, championSource("$9", "$10"), championWhere, filters.sortColumn(), filters.sortDirection())
+
+	return query, []interface{}{username, region, metaOnly, meta.MinPopularity, meta.MinWinRate, pq.Array(powerSpikes), ChampionClasses(classes), nullDate(releasedAfter),
+		scope.MatchType, pq.Array(scope.Tiers), filters.limit(), filters.offset()}
+}
+
+// Stream runs the same query as GetAll, but passes each champion to fn as it is read from the
+// database instead of collecting them into a slice. Iteration stops at the first error returned
+// by fn.
+func (c ChampionModel) Stream(ctx context.Context, username string, region string, metaOnly bool, meta MetaThresholds, powerSpikes []string, classes []string, releasedAfter time.Time, scope StatsScope, filters Filters, fn func(*Champion) error) error {
+	query, args := championListQuery(username, region, metaOnly, meta, powerSpikes, classes, releasedAfter, scope, filters)
+
+	rows, err := c.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
 	for rows.Next() {
 		var champion Champion
 		err := rows.Scan(
 			&champion.ID,
 			&champion.Name,
 			&champion.MainRole,
+			&champion.ImageURL,
+			&champion.DamageProfile,
+			&champion.PowerSpikes,
+			&champion.Classes,
+			&champion.ReleaseDate,
+			&champion.AgeDays,
 			&champion.Popularity,
 			&champion.WinRate,
 			&champion.BanRate,
+			&champion.Version,
 		)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		champion.IsMeta = meta.IsMeta(&champion)
+
+		if err := fn(&champion); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// RecalculateMainRole sets the champion's main_role to the role it has been played in most often,
+// according to match_performance. To avoid the role flapping between two similarly popular roles,
+// it is only changed when the most played role's share of games beats the runner-up's share by at
+// least margin (e.g. 0.1 for ten percentage points). It reports whether the role was changed.
+func (c ChampionModel) RecalculateMainRole(id int64, margin float64) (bool, error) {
+	if id < 1 {
+		return false, ErrRecordNotFound
+	}
+
+	query := `
+        SELECT role, count(*), sum(count(*)) OVER ()
+        FROM match_performance
+        WHERE champion_id = $1 AND role <> ''
+        GROUP BY role
+        ORDER BY count(*) DESC, role ASC
+        LIMIT 2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var roles []roleGames
+	var total float64
+
+	for rows.Next() {
+		var role roleGames
+		err := rows.Scan(&role.role, &role.games, &total)
+		if err != nil {
+			return false, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+
+	role, ok := dominantRole(roles, total, margin)
+	if !ok {
+		return false, nil
+	}
+
+	result, err := c.DB.ExecContext(ctx, `
+        UPDATE champions
+        SET main_role = $1, version = version + 1, main_role_version = version + 1
+        WHERE id = $2 AND main_role <> $1`, NormalizeRole(role), id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// roleGames is the number of games a champion has been played in a role.
+type roleGames struct {
+	role  string
+	games float64
+}
+
+// dominantRole picks the role a champion should have from its most played roles, most played
+// first, out of total games. The most played role only wins when its share of the games beats the
+// runner-up's by at least margin; otherwise, or with no play data at all, ok is false and the
+// stored role should be kept.
+func dominantRole(roles []roleGames, total, margin float64) (role string, ok bool) {
+	if len(roles) == 0 || total == 0 {
+		return "", false
+	}
+
+	runnerUp := 0.0
+	if len(roles) > 1 {
+		runnerUp = roles[1].games
+	}
+
+	if (roles[0].games-runnerUp)/total < margin {
+		return "", false
+	}
+
+	return roles[0].role, true
+}
+
+// RecalculateAllMainRoles runs RecalculateMainRole for every champion and returns the number of
+// champions whose role was changed.
+func (c ChampionModel) RecalculateAllMainRoles(margin float64) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, `SELECT id FROM champions ORDER BY id`)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
 		}
-		champions = append(champions, &champion)
+		ids = append(ids, id)
 	}
 
 	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, id := range ids {
+		ok, err := c.RecalculateMainRole(id, margin)
+		if err != nil {
+			return changed, err
+		}
+		if ok {
+			changed++
+		}
+	}
+
+	return changed, nil
+}
+
+// BulkInsertError is returned by InsertMany in atomic mode when a champion can't be inserted,
+// identifying which one rolled back the batch.
+type BulkInsertError struct {
+	Index int
+	Err   error
+}
+
+func (e *BulkInsertError) Error() string {
+	return fmt.Sprintf("champion %d: %v", e.Index, e.Err)
+}
+
+func (e *BulkInsertError) Unwrap() error {
+	return e.Err
+}
+
+// ConstraintViolation reports whether err is the database rejecting a row for breaking an
+// integrity constraint (a unique, check, foreign key or not-null constraint), and if so which.
+func ConstraintViolation(err error) (constraint string, ok bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Class() == "23" {
+		return pqErr.Constraint, true
+	}
+	return "", false
+}
+
+// InsertMany inserts a batch of champions in a single transaction. In atomic mode (bestEffort
+// false) the first failure rolls back the whole batch and is returned as a *BulkInsertError. In
+// best-effort mode each champion is inserted under its own savepoint, so a failure only discards
+// that champion; the failures are returned keyed by their index in the batch and the rest are
+// committed.
+func (c ChampionModel) InsertMany(champions []*Champion, bestEffort bool) (failed map[int]error, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO champions (name, main_role, image_url, damage_profile, classes, release_date)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, popularity, win_rate, ban_rate, version, CURRENT_DATE - release_date`
+
+	failed = make(map[int]error)
+
+	for i, champion := range champions {
+		if bestEffort {
+			if _, err := tx.ExecContext(ctx, "SAVEPOINT bulk_champion"); err != nil {
+				return nil, err
+			}
+		}
+
+		err := tx.QueryRowContext(ctx, query, champion.Name, champion.MainRole, champion.ImageURL, champion.DamageProfile, champion.Classes, champion.ReleaseDate).Scan(
+			&champion.ID, &champion.Popularity, &champion.WinRate, &champion.BanRate, &champion.Version, &champion.AgeDays)
+
+		switch {
+		case err != nil && !bestEffort:
+			return nil, &BulkInsertError{Index: i, Err: err}
+		case err != nil:
+			failed[i] = err
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_champion"); err != nil {
+				return nil, err
+			}
+		case bestEffort:
+			if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT bulk_champion"); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	return champions, nil
+	return failed, tx.Commit()
 }
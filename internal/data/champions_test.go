@@ -0,0 +1,278 @@
+package data
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// insertTestPerformance stores a match of the given type and result, in which the summoner played
+// the champion for the blue team.
+func insertTestPerformance(t *testing.T, models Models, summonerID, championID int64, matchType, result string) {
+	t.Helper()
+
+	var matchID int64
+	err := models.Matches.DB.QueryRow(`
+        INSERT INTO matches (duration, result, match_type, blue_team, red_team)
+        VALUES (1800, $1, $2, '{}', '{}')
+        RETURNING id`, result, matchType).Scan(&matchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = models.Matches.DB.Exec(`
+        INSERT INTO match_performance (match_id, summoner_id, champion_id, team)
+        VALUES ($1, $2, $3, $4)`, matchID, summonerID, championID, ResultBlue)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChampionWinRateByMatchType(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	// Three pro games, all won, and four solo queue games with one win. The remake counts for
+	// neither. The summoner is unrated, so every game is in the Iron tier.
+	for i := 0; i < 3; i++ {
+		insertTestPerformance(t, models, summoner.ID, champion.ID, "pro", ResultBlue)
+	}
+	for i := 0; i < 4; i++ {
+		result := ResultRed
+		if i == 0 {
+			result = ResultBlue
+		}
+		insertTestPerformance(t, models, summoner.ID, champion.ID, "solo_queue", result)
+	}
+	insertTestPerformance(t, models, summoner.ID, champion.ID, "pro", ResultRemake)
+
+	check := func(when string) {
+		t.Helper()
+
+		for _, tt := range []struct {
+			scope StatsScope
+			want  float64
+		}{
+			{StatsScope{MatchType: "pro"}, 1},
+			{StatsScope{MatchType: "solo_queue"}, 0.25},
+			{StatsScope{MatchType: "tournament"}, 0},
+			{StatsScope{MatchType: "pro", Tiers: []string{"Challenger"}}, 0},
+			{StatsScope{MatchType: "pro", Tiers: []string{"Iron"}}, 1},
+		} {
+			got, err := models.Champions.GetInScope(champion.ID, tt.scope)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got.WinRate-tt.want) > 1e-9 {
+				t.Errorf("%s, scope %+v: got win rate %v, want %v", when, tt.scope, got.WinRate, tt.want)
+			}
+
+			champions, _, err := models.Champions.GetAll("", "", false, MetaThresholds{}, nil, nil, time.Time{}, tt.scope,
+				Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(champions) != 1 {
+				t.Fatalf("%s, scope %+v: got %d champions, want 1", when, tt.scope, len(champions))
+			}
+			if math.Abs(champions[0].WinRate-tt.want) > 1e-9 {
+				t.Errorf("%s, scope %+v: got listed win rate %v, want %v", when, tt.scope, champions[0].WinRate, tt.want)
+			}
+		}
+	}
+
+	check("as stored")
+
+	// The recompute rebuilds the scoped aggregates from match history, with the same result.
+	if _, err := models.Champions.RecomputeStats(false); err != nil {
+		t.Fatal(err)
+	}
+
+	check("after the recompute")
+}
+
+func TestMetaThresholds(t *testing.T) {
+	thresholds := MetaThresholds{MinPopularity: 0.1, MinWinRate: 0.5}
+
+	tests := []struct {
+		popularity float64
+		winRate    float64
+		meta       bool
+	}{
+		{0.2, 0.55, true},
+		{0.05, 0.55, false},
+		{0.2, 0.45, false},
+		// Both thresholds must be exceeded, not just met.
+		{0.1, 0.55, false},
+		{0.2, 0.5, false},
+	}
+
+	for _, tt := range tests {
+		champion := &Champion{Popularity: tt.popularity, WinRate: tt.winRate}
+		if got := thresholds.IsMeta(champion); got != tt.meta {
+			t.Errorf("IsMeta(popularity %v, win rate %v) = %t, want %t", tt.popularity, tt.winRate, got, tt.meta)
+		}
+	}
+
+	champions := []*Champion{{Popularity: 0.2, WinRate: 0.6}, {Popularity: 0.2, WinRate: 0.4, IsMeta: true}}
+	thresholds.Apply(champions...)
+	if !champions[0].IsMeta || champions[1].IsMeta {
+		t.Errorf("Apply: got IsMeta %t and %t, want true and false", champions[0].IsMeta, champions[1].IsMeta)
+	}
+}
+
+func TestValidateChampionReleaseDate(t *testing.T) {
+	date := func(d time.Time) *time.Time { return &d }
+
+	tests := []struct {
+		name        string
+		releaseDate *time.Time
+		valid       bool
+	}{
+		{"unknown", nil, true},
+		{"past", date(time.Date(2012, time.December, 13, 0, 0, 0, 0, time.UTC)), true},
+		{"with the game", date(leagueReleaseDate), true},
+		{"future", date(time.Now().AddDate(0, 0, 7)), false},
+		{"before the game", date(leagueReleaseDate.AddDate(0, 0, -1)), false},
+	}
+
+	for _, tt := range tests {
+		champion := &Champion{Name: "Zed", MainRole: "Mid", ReleaseDate: tt.releaseDate}
+
+		v := validator.New()
+		ValidateChampion(v, champion)
+
+		if _, invalid := v.Errors["release_date"]; invalid == tt.valid {
+			t.Errorf("%s: got errors %v, want valid=%t", tt.name, v.Errors, tt.valid)
+		}
+	}
+}
+
+func TestGetAllChampionsByReleaseDate(t *testing.T) {
+	models := newTestModels(t)
+
+	date := func(year int, month time.Month, day int) *time.Time {
+		d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	for _, champion := range []*Champion{
+		{Name: "Ahri", MainRole: "Mid", ReleaseDate: date(2011, time.December, 14)},
+		{Name: "Hwei", MainRole: "Mid", ReleaseDate: date(2023, time.December, 5)},
+		{Name: "Smolder", MainRole: "Bot", ReleaseDate: date(2024, time.January, 31)},
+		{Name: "Naafiri", MainRole: "Mid", ReleaseDate: date(2023, time.July, 19)},
+		// Champions without a release date are listed last, whichever way the list is sorted.
+		{Name: "Ryze", MainRole: "Mid"},
+	} {
+		champion.Classes = ChampionClasses{}
+		if err := models.Champions.Insert(champion); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	safelist := []string{"release_date", "-release_date"}
+
+	tests := []struct {
+		sort          string
+		releasedAfter time.Time
+		want          []string
+	}{
+		{"release_date", time.Time{}, []string{"Ahri", "Naafiri", "Hwei", "Smolder", "Ryze"}},
+		{"-release_date", time.Time{}, []string{"Smolder", "Hwei", "Naafiri", "Ahri", "Ryze"}},
+		{"-release_date", time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), []string{"Smolder", "Hwei", "Naafiri"}},
+		// The date itself is excluded.
+		{"release_date", time.Date(2023, time.December, 5, 0, 0, 0, 0, time.UTC), []string{"Smolder"}},
+	}
+
+	for _, tt := range tests {
+		filters := Filters{Page: 1, PageSize: 20, Sort: tt.sort, SortSafelist: safelist}
+
+		champions, _, err := models.Champions.GetAll("", "", false, MetaThresholds{}, nil, nil, tt.releasedAfter, StatsScope{}, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, champion := range champions {
+			got = append(got, champion.Name)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort %s, released after %v: got %v, want %v", tt.sort, tt.releasedAfter.Format(time.DateOnly), got, tt.want)
+		}
+	}
+}
+
+func TestDominantRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		roles  []roleGames
+		total  float64
+		margin float64
+		want   string
+		wantOK bool
+	}{
+		{"no play data", nil, 0, 0.1, "", false},
+		{"single role", []roleGames{{"Top", 5}}, 5, 0.1, "Top", true},
+		{"clear lead", []roleGames{{"Top", 6}, {"Mid", 3}}, 10, 0.1, "Top", true},
+		// A lead of exactly the margin is enough.
+		{"lead equal to margin", []roleGames{{"Top", 6}, {"Mid", 5}}, 10, 0.1, "Top", true},
+		// Two roles this close would flap back and forth between recalculations.
+		{"lead within margin", []roleGames{{"Top", 5}, {"Mid", 5}}, 10, 0.1, "", false},
+		{"narrow lead within margin", []roleGames{{"Top", 11}, {"Mid", 10}}, 21, 0.1, "", false},
+		{"narrow lead without margin", []roleGames{{"Top", 11}, {"Mid", 10}}, 21, 0, "Top", true},
+	}
+
+	for _, tt := range tests {
+		got, ok := dominantRole(tt.roles, tt.total, tt.margin)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: got %q, %t, want %q, %t", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRecalculateMainRole(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	// Stored as a mid laner, but mostly played top.
+	champion := insertTestChampion(t, models, "Ahri")
+
+	for _, role := range []string{"Top", "Top", "Top", "Mid"} {
+		insertTestRolePerformance(t, models, summoner.ID, champion.ID, role, time.Now())
+	}
+
+	changed, err := models.Champions.RecalculateMainRole(champion.ID, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := models.Champions.Get(champion.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || got.MainRole != "Top" {
+		t.Errorf("got main role %q (changed %t), want Top (changed true)", got.MainRole, changed)
+	}
+
+	// Evened out at three games each, the lead is inside the margin so the role stays put.
+	for _, role := range []string{"Mid", "Mid"} {
+		insertTestRolePerformance(t, models, summoner.ID, champion.ID, role, time.Now())
+	}
+
+	changed, err = models.Champions.RecalculateMainRole(champion.ID, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = models.Champions.Get(champion.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || got.MainRole != "Top" {
+		t.Errorf("got main role %q (changed %t), want Top (changed false)", got.MainRole, changed)
+	}
+}
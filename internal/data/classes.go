@@ -0,0 +1,66 @@
+package data
+
+import (
+	"database/sql/driver"
+	"strings"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// ChampionClassList is the set of class tags a champion can have.
+var ChampionClassList = []string{"Assassin", "Fighter", "Mage", "Marksman", "Support", "Tank"}
+
+// ChampionClasses holds a champion's class tags (e.g. "Assassin" or "Tank"). A champion can have
+// more than one class. It is stored as a PostgreSQL text array.
+type ChampionClasses []string
+
+// Scan implements the sql.Scanner interface for ChampionClasses.
+func (c *ChampionClasses) Scan(value interface{}) error {
+	return pq.Array((*[]string)(c)).Scan(value)
+}
+
+// Value implements the driver.Valuer interface for ChampionClasses. A nil ChampionClasses is
+// stored as an empty array rather than NULL.
+func (c ChampionClasses) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	return pq.Array([]string(c)).Value()
+}
+
+// NormalizeClass trims a class and converts a known one to its canonical casing (e.g. "mage"
+// becomes "Mage"). Unknown classes are returned trimmed, to fail validation.
+func NormalizeClass(class string) string {
+	class = strings.TrimSpace(class)
+	for _, canonical := range ChampionClassList {
+		if strings.EqualFold(class, canonical) {
+			return canonical
+		}
+	}
+	return class
+}
+
+// NormalizeClasses normalizes each class and removes empty and duplicate classes, keeping the
+// classes in the order they were first given.
+func NormalizeClasses(classes ChampionClasses) ChampionClasses {
+	seen := make(map[string]bool, len(classes))
+	normalized := ChampionClasses{}
+
+	for _, class := range classes {
+		class = NormalizeClass(class)
+		if class == "" || seen[class] {
+			continue
+		}
+		seen[class] = true
+		normalized = append(normalized, class)
+	}
+
+	return normalized
+}
+
+func ValidateClasses(v *validator.Validator, classes ChampionClasses) {
+	for _, class := range classes {
+		v.Check(validator.In(class, ChampionClassList...), "classes", "must only contain "+strings.Join(ChampionClassList, ", "))
+	}
+}
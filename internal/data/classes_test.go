@@ -0,0 +1,113 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func TestNormalizeClasses(t *testing.T) {
+	tests := []struct {
+		classes ChampionClasses
+		want    ChampionClasses
+	}{
+		{nil, ChampionClasses{}},
+		{ChampionClasses{"mage", " ASSASSIN "}, ChampionClasses{"Mage", "Assassin"}},
+		{ChampionClasses{"Tank", "tank", "", "  ", "Fighter"}, ChampionClasses{"Tank", "Fighter"}},
+		// Unknown classes are only trimmed, so validation rejects them.
+		{ChampionClasses{" Bruiser "}, ChampionClasses{"Bruiser"}},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeClasses(tt.classes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NormalizeClasses(%q) = %q, want %q", tt.classes, got, tt.want)
+		}
+	}
+}
+
+func TestValidateClasses(t *testing.T) {
+	v := validator.New()
+	ValidateClasses(v, ChampionClasses{"Mage", "Support"})
+	if !v.Valid() {
+		t.Errorf("got errors %v for known classes", v.Errors)
+	}
+
+	v = validator.New()
+	ValidateClasses(v, ChampionClasses{"Mage", "mage"})
+	if _, ok := v.Errors["classes"]; !ok {
+		t.Error("got no error for a class which wasn't normalized")
+	}
+}
+
+func TestChampionClassesValue(t *testing.T) {
+	// A champion without classes is stored as an empty array rather than NULL.
+	got, err := ChampionClasses(nil).Value()
+	if err != nil || got != "{}" {
+		t.Errorf("got %v (%v) for no classes, want {}", got, err)
+	}
+
+	var classes ChampionClasses
+	if err := classes.Scan([]byte(`{Mage,Support}`)); err != nil {
+		t.Fatal(err)
+	}
+	if want := (ChampionClasses{"Mage", "Support"}); !reflect.DeepEqual(classes, want) {
+		t.Errorf("got %q, want %q", classes, want)
+	}
+}
+
+func TestChampionClassesRoundTrip(t *testing.T) {
+	models := newTestModels(t)
+
+	zed := &Champion{Name: "Zed", MainRole: "Mid", Classes: ChampionClasses{"Assassin"}}
+	pyke := &Champion{Name: "Pyke", MainRole: "Support", Classes: ChampionClasses{"Support", "Assassin"}}
+	malphite := &Champion{Name: "Malphite", MainRole: "Top", Classes: ChampionClasses{"Tank"}}
+	// A champion without classes is stored with an empty array, not NULL.
+	ryze := &Champion{Name: "Ryze", MainRole: "Mid"}
+
+	for _, champion := range []*Champion{zed, pyke, malphite, ryze} {
+		if err := models.Champions.Insert(champion); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, champion := range []*Champion{pyke, ryze} {
+		got, err := models.Champions.Get(champion.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := NormalizeClasses(champion.Classes); !reflect.DeepEqual(got.Classes, want) {
+			t.Errorf("%s: got classes %#v, want %#v", champion.Name, got.Classes, want)
+		}
+	}
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}}
+
+	tests := []struct {
+		classes []string
+		want    []string
+	}{
+		{nil, []string{"Zed", "Pyke", "Malphite", "Ryze"}},
+		{[]string{"Assassin"}, []string{"Zed", "Pyke"}},
+		// Every class must match.
+		{[]string{"Assassin", "Support"}, []string{"Pyke"}},
+		{[]string{"Marksman"}, nil},
+	}
+
+	for _, tt := range tests {
+		champions, _, err := models.Champions.GetAll("", "", false, MetaThresholds{}, nil, tt.classes, time.Time{}, StatsScope{}, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, champion := range champions {
+			got = append(got, champion.Name)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("classes %v: got champions %v, want %v", tt.classes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+// MatchCompletion is one summoner's result in a finished match, as applied to the statistics by
+// UpdateSummonerStatistics and UpdateChampionStatistics.
+type MatchCompletion struct {
+	Champion Champion
+	KDA      KDA
+	Role     string
+	Won      bool
+}
+
+// CompleteMatches applies every completion to the summoner's and champions' statistics at once,
+// each in its own goroutine, and waits for them all. Afterwards the aggregates must be the same as
+// if the completions had been applied one by one. The errors of any failed updates are joined.
+func (m *MatchModel) CompleteMatches(summonerID int64, completions []MatchCompletion) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(completions))
+
+	for i, completion := range completions {
+		wg.Add(1)
+		go func(i int, completion MatchCompletion) {
+			defer wg.Done()
+
+			err := m.UpdateSummonerStatistics(summonerID, completion.Champion, completion.KDA, completion.Role, completion.Won)
+			if err == nil {
+				err = m.UpdateChampionStatistics(completion.Champion.ID, summonerID, completion.Won)
+			}
+			errs[i] = err
+		}(i, completion)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// StatAggregates holds the incrementally maintained statistics of a summoner and one of their
+// champions, for checking them against the matches which were applied.
+type StatAggregates struct {
+	SummonerGames   int
+	SummonerWins    int
+	SummonerWinRate float64
+	ChampionGames   int
+	ChampionWins    int
+	ChampionWinRate float64
+}
+
+// GetStatAggregates reads the summoner's and the champion's current statistics.
+func (m *MatchModel) GetStatAggregates(summonerID, championID int64) (*StatAggregates, error) {
+	query := `
+        SELECT s.count_of_played_games, s.wins, s.win_rate,
+            c.count_of_played_matches, c.wins, c.win_rate
+        FROM summoners s, champions c
+        WHERE s.id = $1 AND c.id = $2`
+
+	var a StatAggregates
+
+	err := m.DB.QueryRow(query, summonerID, championID).Scan(
+		&a.SummonerGames, &a.SummonerWins, &a.SummonerWinRate,
+		&a.ChampionGames, &a.ChampionWins, &a.ChampionWinRate,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &a, nil
+}
+
+func TestCompleteMatchesConcurrently(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	const games, wins = 40, 25
+
+	completions := make([]MatchCompletion, games)
+	for i := range completions {
+		completions[i] = MatchCompletion{
+			Champion: *champion,
+			KDA:      KDA{Kills: 5, Deaths: 2, Assists: 7},
+			Role:     "Mid",
+			Won:      i < wins,
+		}
+	}
+
+	if err := models.Matches.CompleteMatches(summoner.ID, completions); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := models.Matches.GetStatAggregates(summoner.ID, champion.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.SummonerGames != games || got.SummonerWins != wins {
+		t.Errorf("got summoner %d/%d games won, want %d/%d", got.SummonerWins, got.SummonerGames, wins, games)
+	}
+	if got.ChampionGames != games || got.ChampionWins != wins {
+		t.Errorf("got champion %d/%d games won, want %d/%d", got.ChampionWins, got.ChampionGames, wins, games)
+	}
+
+	want := float64(wins) / games
+	if math.Abs(got.SummonerWinRate-want) > 1e-9 || math.Abs(got.ChampionWinRate-want) > 1e-9 {
+		t.Errorf("got win rates %v and %v, want %v", got.SummonerWinRate, got.ChampionWinRate, want)
+	}
+}
+
+func TestCompleteMatchesAcrossChampions(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Caps")
+	ahri := insertTestChampion(t, models, "Ahri")
+	syndra := insertTestChampion(t, models, "Syndra")
+
+	var completions []MatchCompletion
+	for i := 0; i < 30; i++ {
+		champion := ahri
+		if i%2 == 1 {
+			champion = syndra
+		}
+		completions = append(completions, MatchCompletion{Champion: *champion, Role: "Mid", Won: i%3 == 0})
+	}
+
+	if err := models.Matches.CompleteMatches(summoner.ID, completions); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ahri has the even games and Syndra the odd ones; every third game is a win.
+	for _, tt := range []struct {
+		champion    *Champion
+		games, wins int
+	}{{ahri, 15, 5}, {syndra, 15, 5}} {
+		got, err := models.Matches.GetStatAggregates(summoner.ID, tt.champion.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.SummonerGames != 30 || got.SummonerWins != 10 {
+			t.Errorf("got summoner %d/%d games won, want 10/30", got.SummonerWins, got.SummonerGames)
+		}
+		if got.ChampionGames != tt.games || got.ChampionWins != tt.wins {
+			t.Errorf("%s: got %d/%d games won, want %d/%d", tt.champion.Name, got.ChampionWins, got.ChampionGames, tt.wins, tt.games)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package data
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Damage profiles, the kind of damage a champion mostly deals. A champion without one hasn't
+// been classified.
+const (
+	DamageProfileAD    = "AD"
+	DamageProfileAP    = "AP"
+	DamageProfileMixed = "mixed"
+)
+
+var DamageProfiles = []string{DamageProfileAD, DamageProfileAP, DamageProfileMixed}
+
+// damageProfileSkew is the share of a team's damage which must be of one kind for the team to
+// be classified as AD or AP rather than mixed.
+const damageProfileSkew = 0.7
+
+// ChampionDamage is the damage profile of a champion played in a match.
+type ChampionDamage struct {
+	Name          string `json:"name"`
+	DamageProfile string `json:"damageProfile"` // Empty if the champion hasn't been classified
+}
+
+// TeamDamageProfile summarizes the damage composition of one team of a match.
+type TeamDamageProfile struct {
+	Profile       string           `json:"profile"`       // AD, AP or mixed (empty if no champion is classified)
+	PhysicalShare float64          `json:"physicalShare"` // Share of the classified champions' damage which is physical
+	MagicShare    float64          `json:"magicShare"`    // Share of the classified champions' damage which is magic
+	Unclassified  int              `json:"unclassified"`  // Champions without a damage profile, left out of the shares
+	Champions     []ChampionDamage `json:"champions"`
+}
+
+// SummarizeDamageProfile works out a team's damage composition from its champions' profiles. An
+// AD or AP champion counts fully towards its kind of damage, and a mixed champion half towards
+// each.
+func SummarizeDamageProfile(champions []ChampionDamage) *TeamDamageProfile {
+	summary := &TeamDamageProfile{Champions: champions}
+
+	var physical, magic float64
+	for _, champion := range champions {
+		switch champion.DamageProfile {
+		case DamageProfileAD:
+			physical++
+		case DamageProfileAP:
+			magic++
+		case DamageProfileMixed:
+			physical += 0.5
+			magic += 0.5
+		default:
+			summary.Unclassified++
+		}
+	}
+
+	total := physical + magic
+	if total == 0 {
+		return summary
+	}
+
+	summary.PhysicalShare = physical / total
+	summary.MagicShare = magic / total
+
+	switch {
+	case summary.PhysicalShare >= damageProfileSkew:
+		summary.Profile = DamageProfileAD
+	case summary.MagicShare >= damageProfileSkew:
+		summary.Profile = DamageProfileAP
+	default:
+		summary.Profile = DamageProfileMixed
+	}
+
+	return summary
+}
+
+// GetDamageProfiles returns the damage profile of each of the named champions, keyed by the
+// lowercased name. Names which don't match a champion are left out.
+func (c ChampionModel) GetDamageProfiles(names []string) (map[string]string, error) {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	query := `
+        SELECT LOWER(name), damage_profile
+        FROM champions
+        WHERE LOWER(name) = ANY($1)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, pq.Array(lowered))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := make(map[string]string, len(names))
+
+	for rows.Next() {
+		var name, profile string
+		err := rows.Scan(&name, &profile)
+		if err != nil {
+			return nil, err
+		}
+
+		profiles[name] = profile
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
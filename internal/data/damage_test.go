@@ -0,0 +1,47 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarizeDamageProfile(t *testing.T) {
+	tests := []struct {
+		name         string
+		profiles     []string
+		want         string
+		physical     float64
+		unclassified int
+	}{
+		{"all AD", []string{"AD", "AD", "AD", "AD", "AD"}, DamageProfileAD, 1, 0},
+		{"mostly AP", []string{"AP", "AP", "AP", "AP", "AD"}, DamageProfileAP, 0.2, 0},
+		{"even split", []string{"AD", "AD", "AP", "AP", "mixed"}, DamageProfileMixed, 0.5, 0},
+		// Mixed champions count half towards each kind, so 3.5 of 5 is exactly the skew.
+		{"AD with mixed", []string{"AD", "AD", "AD", "mixed", "AP"}, DamageProfileAD, 0.7, 0},
+		{"unclassified left out", []string{"AP", "AP", "", "", ""}, DamageProfileAP, 0, 3},
+		{"nothing classified", []string{"", ""}, "", 0, 2},
+		{"no champions", nil, "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		var champions []ChampionDamage
+		for _, profile := range tt.profiles {
+			champions = append(champions, ChampionDamage{DamageProfile: profile})
+		}
+
+		got := SummarizeDamageProfile(champions)
+
+		if got.Profile != tt.want {
+			t.Errorf("%s: got profile %q, want %q", tt.name, got.Profile, tt.want)
+		}
+		if math.Abs(got.PhysicalShare-tt.physical) > 1e-9 {
+			t.Errorf("%s: got physical share %v, want %v", tt.name, got.PhysicalShare, tt.physical)
+		}
+		if got.PhysicalShare+got.MagicShare != 0 && math.Abs(got.PhysicalShare+got.MagicShare-1) > 1e-9 {
+			t.Errorf("%s: got shares %v and %v, want them to add up to 1", tt.name, got.PhysicalShare, got.MagicShare)
+		}
+		if got.Unclassified != tt.unclassified {
+			t.Errorf("%s: got %d unclassified, want %d", tt.name, got.Unclassified, tt.unclassified)
+		}
+	}
+}
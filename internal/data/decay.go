@@ -0,0 +1,107 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+// DecayRule describes how an inactive summoner's rating decays within one rank tier.
+type DecayRule struct {
+	GraceDays    int // Days without a match before the first decay
+	IntervalDays int // Days between later decays
+	DecayPoints  int // Rating lost at each decay
+	MinRating    int // Rating decay never takes a summoner below
+}
+
+// DecayEvent is a single projected rating decay.
+type DecayEvent struct {
+	Date   time.Time `json:"date"`
+	Rating int       `json:"rating"` // Rating after the decay
+	Tier   string    `json:"tier"`   // Rank tier after the decay
+}
+
+// DecayForecast projects how a summoner's rating decays if they stay inactive.
+type DecayForecast struct {
+	Rating          int          `json:"rating"`          // Stored rating
+	ProjectedRating int          `json:"projectedRating"` // Rating once the decays due by now are applied
+	LastActiveAt    *time.Time   `json:"lastActiveAt"`    // Played date of the summoner's latest match (nil if none)
+	InactiveDays    int          `json:"inactiveDays"`
+	Upcoming        []DecayEvent `json:"upcoming"` // Decays still to come, soonest first
+}
+
+// ForecastDecay projects the decay of a rating last active at lastActive, as of now, listing at
+// most limit upcoming decays. The grace period is that of the tier the summoner was in when last
+// active; after that each decay uses the rule of the tier the rating has fallen to, and decay
+// stops in a tier without a rule or at the rule's minimum rating. A summoner with no matches
+// doesn't decay.
+func ForecastDecay(rating int, lastActive *time.Time, now time.Time, rules map[string]DecayRule, limit int) *DecayForecast {
+	forecast := &DecayForecast{Rating: rating, ProjectedRating: rating, LastActiveAt: lastActive, Upcoming: []DecayEvent{}}
+	if lastActive == nil {
+		return forecast
+	}
+
+	forecast.InactiveDays = int(now.Sub(*lastActive).Hours() / 24)
+
+	rule, ok := rules[TierForRating(rating)]
+	if !ok {
+		return forecast
+	}
+
+	date := lastActive.AddDate(0, 0, rule.GraceDays)
+
+	for len(forecast.Upcoming) < limit {
+		rule, ok = rules[TierForRating(rating)]
+		if !ok || rule.DecayPoints <= 0 || rating <= rule.MinRating {
+			break
+		}
+
+		rating -= rule.DecayPoints
+		if rating < rule.MinRating {
+			rating = rule.MinRating
+		}
+
+		if date.After(now) {
+			forecast.Upcoming = append(forecast.Upcoming, DecayEvent{Date: date, Rating: rating, Tier: TierForRating(rating)})
+		} else {
+			forecast.ProjectedRating = rating
+		}
+
+		date = date.AddDate(0, 0, rule.IntervalDays)
+	}
+
+	return forecast
+}
+
+// GetDecayRules returns the rating decay rules, keyed by rank tier.
+func (m SummonerModel) GetDecayRules() (map[string]DecayRule, error) {
+	query := `
+        SELECT rank_tier, grace_days, interval_days, decay_points, min_rating
+        FROM rating_decay_rules`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make(map[string]DecayRule)
+
+	for rows.Next() {
+		var tier string
+		var rule DecayRule
+		err := rows.Scan(&tier, &rule.GraceDays, &rule.IntervalDays, &rule.DecayPoints, &rule.MinRating)
+		if err != nil {
+			return nil, err
+		}
+		rules[tier] = rule
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
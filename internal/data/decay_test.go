@@ -0,0 +1,60 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestForecastDecay(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *time.Time {
+		date := now.AddDate(0, 0, -days)
+		return &date
+	}
+
+	rules := map[string]DecayRule{
+		"Master":  {GraceDays: 14, IntervalDays: 7, DecayPoints: 100},
+		"Diamond": {GraceDays: 28, IntervalDays: 7, DecayPoints: 50, MinRating: 2400},
+	}
+
+	tests := []struct {
+		name         string
+		rating       int
+		lastActive   *time.Time
+		limit        int
+		wantRating   int      // Projected rating
+		wantUpcoming []string // Days after lastActive:rating:tier of each upcoming decay
+	}{
+		{"never played", 2900, nil, 3, 2900, nil},
+		{"tier without a rule", 1500, daysAgo(100), 3, 1500, nil},
+		{"within the grace period", 2900, daysAgo(3), 2, 2900, []string{"14:2800:Master", "21:2700:Diamond"}},
+		// One decay has already happened. The decay into Diamond is still a Master tier decay, so
+		// the next follows a Master interval later; after that Diamond's rule applies.
+		{"inactive for 20 days", 2900, daysAgo(20), 3, 2800, []string{"21:2700:Diamond", "28:2650:Diamond", "35:2600:Diamond"}},
+		{"down to the minimum", 2500, daysAgo(30), 10, 2450, []string{"35:2400:Diamond"}},
+		{"at the minimum", 2400, daysAgo(100), 3, 2400, nil},
+	}
+
+	for _, tt := range tests {
+		forecast := ForecastDecay(tt.rating, tt.lastActive, now, rules, tt.limit)
+
+		var got []string
+		for _, e := range forecast.Upcoming {
+			days := int(e.Date.Sub(*tt.lastActive).Hours() / 24)
+			got = append(got, fmt.Sprintf("%d:%d:%s", days, e.Rating, e.Tier))
+		}
+
+		if forecast.ProjectedRating != tt.wantRating {
+			t.Errorf("%s: got projected rating %d, want %d", tt.name, forecast.ProjectedRating, tt.wantRating)
+		}
+		if !reflect.DeepEqual(got, tt.wantUpcoming) {
+			t.Errorf("%s: got upcoming decays %v, want %v", tt.name, got, tt.wantUpcoming)
+		}
+	}
+
+	if forecast := ForecastDecay(2900, daysAgo(20), now, rules, 3); forecast.InactiveDays != 20 {
+		t.Errorf("got %d inactive days, want 20", forecast.InactiveDays)
+	}
+}
@@ -0,0 +1,226 @@
+package data
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// Draft is the state of a champion draft from one team's point of view: the champions already
+// picked by each team, the champions banned by either team and the role still to be filled.
+type Draft struct {
+	Role    string
+	Allies  []int64
+	Enemies []int64
+	Bans    []int64
+}
+
+// Unavailable returns every champion which can no longer be picked in the draft.
+func (d *Draft) Unavailable() []int64 {
+	ids := make([]int64, 0, len(d.Allies)+len(d.Enemies)+len(d.Bans))
+	ids = append(ids, d.Allies...)
+	ids = append(ids, d.Enemies...)
+	return append(ids, d.Bans...)
+}
+
+func ValidateDraft(v *validator.Validator, draft *Draft) {
+	v.Check(draft.Role != "", "role", "must be provided")
+	v.Check(len(draft.Allies) <= 4, "allies", "must not contain more than 4 champions")
+	v.Check(len(draft.Enemies) <= 5, "enemies", "must not contain more than 5 champions")
+	v.Check(len(draft.Bans) <= 10, "bans", "must not contain more than 10 champions")
+
+	seen := make(map[int64]bool)
+	for _, id := range draft.Unavailable() {
+		v.Check(id > 0, "draft", "must only contain valid champion ids")
+		v.Check(!seen[id], "draft", "must not pick or ban the same champion more than once")
+		seen[id] = true
+	}
+}
+
+// DraftWeights tunes the scoring used by RankDraft. A candidate's score is
+//
+//	(WinRate-0.5) + (CounterWinRate-0.5)*CounterWeight + (SynergyWinRate-0.5)*SynergyWeight
+//
+// where each win rate is shrunk towards 50% in proportion to how few games it's based on, so a
+// lucky 3-0 record doesn't outrank a solid record over hundreds of games.
+type DraftWeights struct {
+	CounterWeight float64
+	SynergyWeight float64
+}
+
+// draftPriorGames is the number of imaginary 50% games blended into each win rate by RankDraft.
+const draftPriorGames = 10
+
+// PairRecord is a champion's combined record in games alongside (or against) a set of other
+// champions. A game counts once for each of those champions present.
+type PairRecord struct {
+	Games int `json:"games"`
+	Wins  int `json:"wins"`
+}
+
+// shrunkWinRate returns the win rate blended with draftPriorGames games at 50%.
+func (r PairRecord) shrunkWinRate() float64 {
+	return (float64(r.Wins) + 0.5*draftPriorGames) / float64(r.Games+draftPriorGames)
+}
+
+// DraftSuggestion is a champion recommended to fill the open role, with the records used to
+// score it.
+type DraftSuggestion struct {
+	ChampionRoleStats
+	Counter PairRecord `json:"counter"` // Record against the enemy picks
+	Synergy PairRecord `json:"synergy"` // Record alongside the allied picks
+	Score   float64    `json:"score"`
+}
+
+// RankDraft scores every available champion with at least minGames games in the role and returns
+// the top limit suggestions, highest score first. Champions which have already been picked or
+// banned are never suggested.
+func RankDraft(draft *Draft, stats []*ChampionRoleStats, counters, synergies map[int64]PairRecord, weights DraftWeights, minGames int, limit int) []*DraftSuggestion {
+	unavailable := make(map[int64]bool)
+	for _, id := range draft.Unavailable() {
+		unavailable[id] = true
+	}
+
+	suggestions := []*DraftSuggestion{}
+
+	for _, s := range stats {
+		if s.Games < minGames || unavailable[s.ChampionID] {
+			continue
+		}
+
+		suggestion := &DraftSuggestion{
+			ChampionRoleStats: *s,
+			Counter:           counters[s.ChampionID],
+			Synergy:           synergies[s.ChampionID],
+		}
+
+		base := PairRecord{Games: s.Games, Wins: s.Wins}
+		suggestion.Score = (base.shrunkWinRate() - 0.5) +
+			(suggestion.Counter.shrunkWinRate()-0.5)*weights.CounterWeight +
+			(suggestion.Synergy.shrunkWinRate()-0.5)*weights.SynergyWeight
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions
+}
+
+// GetPairRecords returns, for every champion played in the role, its record in games where any
+// of the given champions were on the same team (sameTeam true, i.e. synergy) or on the enemy team
+// (sameTeam false, i.e. counters). Remakes are excluded.
+func (c ChampionModel) GetPairRecords(role string, others []int64, sameTeam bool) (map[int64]PairRecord, error) {
+	records := make(map[int64]PairRecord)
+	if len(others) == 0 {
+		return records, nil
+	}
+
+	query := `
+        SELECT a.champion_id, count(*), count(*) FILTER (WHERE LOWER(m.result) = a.team)
+        FROM match_performance a
+        JOIN match_performance b ON b.match_id = a.match_id AND b.id <> a.id
+        JOIN matches m ON m.id = a.match_id
+        WHERE LOWER(a.role) = LOWER($1) AND LOWER(m.result) <> $2
+        AND b.champion_id = ANY($3) AND (b.team = a.team) = $4
+        GROUP BY a.champion_id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, role, ResultRemake, pq.Array(others), sameTeam)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var record PairRecord
+		if err := rows.Scan(&id, &record.Games, &record.Wins); err != nil {
+			return nil, err
+		}
+		records[id] = record
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// DraftPhases is the number of positions in each of the pick and ban sequences of a draft: ten
+// picks and ten bans, alternating between the two teams.
+const DraftPhases = 10
+
+// earlyDraftPhase is the last position of the first round of picks and bans, which is when the
+// highest priority champions are taken.
+const earlyDraftPhase = 6
+
+// DraftTiming describes how early in the draft a champion is typically picked and banned. The
+// averages are zero when there are no picks (or bans) with a recorded position.
+type DraftTiming struct {
+	Picks            int     `json:"picks"`
+	AveragePickPhase float64 `json:"averagePickPhase"`
+	EarlyPickRate    float64 `json:"earlyPickRate"` // Share of picks made in the first round
+	Bans             int     `json:"bans"`
+	AverageBanPhase  float64 `json:"averageBanPhase"`
+	EarlyBanRate     float64 `json:"earlyBanRate"` // Share of bans made in the first round
+}
+
+// GetDraftTiming aggregates the positions at which the champion was picked and banned, counting
+// only the matches within scope. Bans aren't made by a summoner, so the scope's rank tiers only
+// narrow the picks. Picks and bans recorded without a position are ignored.
+func (c ChampionModel) GetDraftTiming(id int64, scope StatsScope) (*DraftTiming, error) {
+	query := `
+        SELECT p.picks, COALESCE(p.average, 0), p.early, b.bans, COALESCE(b.average, 0), b.early
+        FROM (
+            SELECT count(*) AS picks, avg(mp.pick_phase) AS average,
+                count(*) FILTER (WHERE mp.pick_phase <= $2) AS early
+            FROM match_performance mp
+            JOIN matches m ON m.id = mp.match_id
+            WHERE mp.champion_id = $1 AND mp.pick_phase IS NOT NULL
+            AND (m.match_type = $3 OR $3 = '')
+            AND (cardinality($4::text[]) = 0 OR mp.rank_tier = ANY($4))
+        ) p, (
+            SELECT count(*) AS bans, avg(mb.ban_phase) AS average,
+                count(*) FILTER (WHERE mb.ban_phase <= $2) AS early
+            FROM match_bans mb
+            JOIN matches m ON m.id = mb.match_id
+            WHERE mb.champion_id = $1
+            AND (m.match_type = $3 OR $3 = '')
+        ) b`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var timing DraftTiming
+	var earlyPicks, earlyBans int
+
+	err := c.DB.QueryRowContext(ctx, query, id, earlyDraftPhase, scope.MatchType, pq.Array(scope.Tiers)).Scan(
+		&timing.Picks, &timing.AveragePickPhase, &earlyPicks,
+		&timing.Bans, &timing.AverageBanPhase, &earlyBans,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if timing.Picks > 0 {
+		timing.EarlyPickRate = float64(earlyPicks) / float64(timing.Picks)
+	}
+	if timing.Bans > 0 {
+		timing.EarlyBanRate = float64(earlyBans) / float64(timing.Bans)
+	}
+
+	return &timing, nil
+}
@@ -0,0 +1,145 @@
+package data
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func TestValidateDraft(t *testing.T) {
+	tests := []struct {
+		name  string
+		draft Draft
+		valid bool
+	}{
+		{"valid", Draft{Role: "Mid", Allies: []int64{1, 2}, Enemies: []int64{3}, Bans: []int64{4, 5}}, true},
+		{"no role", Draft{Allies: []int64{1}}, false},
+		{"picked twice", Draft{Role: "Mid", Allies: []int64{1}, Enemies: []int64{1}}, false},
+		{"picked and banned", Draft{Role: "Mid", Allies: []int64{1}, Bans: []int64{1}}, false},
+		{"invalid id", Draft{Role: "Mid", Bans: []int64{0}}, false},
+		{"too many allies", Draft{Role: "Mid", Allies: []int64{1, 2, 3, 4, 5}}, false},
+	}
+
+	for _, tt := range tests {
+		v := validator.New()
+		if ValidateDraft(v, &tt.draft); v.Valid() != tt.valid {
+			t.Errorf("%s: got valid %t, want %t (%v)", tt.name, v.Valid(), tt.valid, v.Errors)
+		}
+	}
+}
+
+func TestRankDraftExcludesUnavailable(t *testing.T) {
+	// Zed, the strongest champion in the role, has been banned and Syndra picked by the enemy,
+	// so neither can be suggested however well they score.
+	const ahri, zed, syndra, lux, orianna = 1, 2, 3, 4, 5
+
+	draft := &Draft{Role: "Mid", Allies: []int64{10}, Enemies: []int64{syndra, 11}, Bans: []int64{zed, 12}}
+
+	stats := []*ChampionRoleStats{
+		{ChampionID: ahri, Name: "Ahri", Games: 100, Wins: 55},
+		{ChampionID: zed, Name: "Zed", Games: 100, Wins: 70},
+		{ChampionID: syndra, Name: "Syndra", Games: 100, Wins: 65},
+		{ChampionID: lux, Name: "Lux", Games: 100, Wins: 50},
+		// Too few games to be suggested at all.
+		{ChampionID: orianna, Name: "Orianna", Games: 2, Wins: 2},
+	}
+	counters := map[int64]PairRecord{
+		zed: {Games: 50, Wins: 40},
+		lux: {Games: 50, Wins: 35},
+	}
+
+	suggestions := RankDraft(draft, stats, counters, nil, DraftWeights{CounterWeight: 1, SynergyWeight: 1}, 10, 5)
+
+	var got []string
+	for _, suggestion := range suggestions {
+		got = append(got, suggestion.Name)
+	}
+
+	// Lux's record against the enemy picks lifts her above Ahri.
+	if want := []string{"Lux", "Ahri"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got suggestions %q, want %q", got, want)
+	}
+}
+
+func TestGetDraftTiming(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	ahri := insertTestChampion(t, models, "Ahri")
+	syndra := insertTestChampion(t, models, "Syndra")
+
+	insertMatch := func(matchType string) int64 {
+		t.Helper()
+		var matchID int64
+		err := models.Matches.DB.QueryRow(`
+            INSERT INTO matches (duration, result, match_type, blue_team, red_team)
+            VALUES (1800, 'blue', $1, '{}', '{}')
+            RETURNING id`, matchType).Scan(&matchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return matchID
+	}
+
+	// Picks in positions 2, 5 and 9 of solo queue drafts and 8 of a pro one. A pick recorded
+	// without a position is ignored.
+	picks := []struct {
+		matchType string
+		phase     interface{}
+	}{
+		{"solo_queue", 2},
+		{"solo_queue", 5},
+		{"solo_queue", 9},
+		{"pro", 8},
+		{"solo_queue", nil},
+	}
+	for _, p := range picks {
+		_, err := models.Matches.DB.Exec(`
+            INSERT INTO match_performance (match_id, summoner_id, champion_id, team, pick_phase)
+            VALUES ($1, $2, $3, 'blue', $4)`, insertMatch(p.matchType), summoner.ID, ahri.ID, p.phase)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Bans in positions 1 and 3; Syndra's ban doesn't count towards Ahri's.
+	matchID := insertMatch("solo_queue")
+	for _, b := range []struct {
+		championID int64
+		phase      int
+	}{{ahri.ID, 1}, {ahri.ID, 3}, {syndra.ID, 2}} {
+		_, err := models.Matches.DB.Exec(`
+            INSERT INTO match_bans (match_id, team, champion_id, ban_phase)
+            VALUES ($1, 'red', $2, $3)`, matchID, b.championID, b.phase)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		scope StatsScope
+		want  DraftTiming
+	}{
+		{StatsScope{}, DraftTiming{Picks: 4, AveragePickPhase: 6, EarlyPickRate: 0.5, Bans: 2, AverageBanPhase: 2, EarlyBanRate: 1}},
+		{StatsScope{MatchType: "solo_queue"}, DraftTiming{Picks: 3, AveragePickPhase: 16.0 / 3, EarlyPickRate: 2.0 / 3, Bans: 2, AverageBanPhase: 2, EarlyBanRate: 1}},
+		// No pro match had a ban, so the ban average stays zero.
+		{StatsScope{MatchType: "pro"}, DraftTiming{Picks: 1, AveragePickPhase: 8, EarlyPickRate: 0}},
+	}
+
+	for _, tt := range tests {
+		got, err := models.Champions.GetDraftTiming(ahri.ID, tt.scope)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Picks != tt.want.Picks || got.Bans != tt.want.Bans ||
+			math.Abs(got.AveragePickPhase-tt.want.AveragePickPhase) > 1e-9 ||
+			math.Abs(got.EarlyPickRate-tt.want.EarlyPickRate) > 1e-9 ||
+			math.Abs(got.AverageBanPhase-tt.want.AverageBanPhase) > 1e-9 ||
+			math.Abs(got.EarlyBanRate-tt.want.EarlyBanRate) > 1e-9 {
+			t.Errorf("scope %+v: got %+v, want %+v", tt.scope, *got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// explain runs EXPLAIN ANALYZE on a query and returns the lines of the plan. EXPLAIN ANALYZE
+// executes the query, so it's run in a read-only transaction which is always rolled back.
+func explain(db *DB, query string, args []interface{}) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "EXPLAIN (ANALYZE, BUFFERS) "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plan := []string{}
+
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		plan = append(plan, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// ExplainGetAll returns the EXPLAIN ANALYZE plan of the query GetAll would run with the same
+// arguments.
+func (c ChampionModel) ExplainGetAll(username string, region string, metaOnly bool, meta MetaThresholds, powerSpikes []string, classes []string, releasedAfter time.Time, scope StatsScope, filters Filters) ([]string, error) {
+	query, args := championListQuery(username, region, metaOnly, meta, powerSpikes, classes, releasedAfter, scope, filters)
+	return explain(c.DB, query, args)
+}
+
+// ExplainGetAll returns the EXPLAIN ANALYZE plan of the query GetAll would run with the same
+// arguments.
+func (m SummonerModel) ExplainGetAll(filter SummonerFilter, byRelevance bool, filters Filters) ([]string, error) {
+	query, args := summonerListQuery(filter, byRelevance, filters)
+	return explain(m.DB, query, args)
+}
+
+// ExplainGetAll returns the EXPLAIN ANALYZE plan of the query GetAll would run with the same
+// arguments.
+func (m MatchModel) ExplainGetAll(filter MatchFilter, filters Filters) ([]string, error) {
+	query, args := matchListQuery(filter, filters)
+	return explain(m.DB, query, args)
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+type FeatureFlagModel struct {
+	DB *DB
+}
+
+// GetAll returns the stored state of every feature which has been switched on or off at runtime,
+// keyed by feature name.
+func (m FeatureFlagModel) GetAll() (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, `SELECT name, enabled FROM feature_flags`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flags := make(map[string]bool)
+
+	for rows.Next() {
+		var name string
+		var enabled bool
+		if err := rows.Scan(&name, &enabled); err != nil {
+			return nil, err
+		}
+		flags[name] = enabled
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+// Set stores whether a feature is enabled.
+func (m FeatureFlagModel) Set(name string, enabled bool) error {
+	query := `
+        INSERT INTO feature_flags (name, enabled)
+        VALUES ($1, $2)
+        ON CONFLICT (name) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = NOW()`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, name, enabled)
+	return err
+}
@@ -1,32 +1,76 @@
 package data
 
 import (
+	"fmt"
+	"math"
 	"strings"
 
 	"league_of_graphs.satellite.net/internal/validator"
 )
 
+// DefaultMaxPageSize is the largest page size ValidateFilters accepts when Filters.MaxPageSize
+// isn't set.
+const DefaultMaxPageSize = 100
+
+// Filters holds the paging and sorting options for a list query. A PageSize of zero means the
+// results aren't limited, which is only used when streaming a full result set.
+//
+// Sort is a column from SortSafelist, prefixed with "-" to sort descending or "+" to sort
+// ascending. Without a prefix the column sorts in its natural direction: descending for the
+// columns in DescendingSorts (such as win_rate, where the best come first) and ascending for the
+// rest.
 type Filters struct {
-	Page         int
-	PageSize     int
-	Sort         string
-	SortSafelist []string
+	Page            int
+	PageSize        int
+	MaxPageSize     int // Largest PageSize accepted (DefaultMaxPageSize if zero)
+	Sort            string
+	SortSafelist    []string
+	DescendingSorts []string // Columns which sort descending without a prefix
+}
+
+func (f Filters) maxPageSize() int {
+	if f.MaxPageSize > 0 {
+		return f.MaxPageSize
+	}
+	return DefaultMaxPageSize
+}
+
+// ClampPageSize reduces a PageSize above the maximum to the maximum, recording a warning rather
+// than the error ValidateFilters would otherwise report.
+func (f *Filters) ClampPageSize(v *validator.Validator) {
+	if maxSize := f.maxPageSize(); f.PageSize > maxSize {
+		v.AddWarning("page_size", fmt.Sprintf("was reduced to the maximum of %d", maxSize))
+		f.PageSize = maxSize
+	}
 }
 
 func (f Filters) sortColumn() string {
-	for _, safeValue := range f.SortSafelist {
-		if f.Sort == safeValue {
-			return strings.TrimPrefix(f.Sort, "-")
-		}
+	if f.safeSort() {
+		return strings.TrimPrefix(strings.TrimPrefix(f.Sort, "+"), "-")
 	}
 	panic("unsafe sort parameter: " + f.Sort)
 }
 
 func (f Filters) sortDirection() string {
-	if strings.HasPrefix(f.Sort, "-") {
+	switch {
+	case strings.HasPrefix(f.Sort, "-"):
 		return "DESC"
+	case strings.HasPrefix(f.Sort, "+"):
+		return "ASC"
+	case validator.In(f.Sort, f.DescendingSorts...):
+		return "DESC"
+	default:
+		return "ASC"
+	}
+}
+
+// safeSort reports whether Sort is in the safelist, or is a "+" followed by an unprefixed column
+// in the safelist.
+func (f Filters) safeSort() bool {
+	if column, ok := strings.CutPrefix(f.Sort, "+"); ok {
+		return !strings.HasPrefix(column, "-") && validator.In(column, f.SortSafelist...)
 	}
-	return "ASC"
+	return validator.In(f.Sort, f.SortSafelist...)
 }
 
 func ValidateFilters(v *validator.Validator, f Filters) {
@@ -34,7 +78,35 @@ func ValidateFilters(v *validator.Validator, f Filters) {
 	v.Check(f.Page > 0, "page", "must be greater than zero")
 	v.Check(f.Page <= 10_000_000, "page", "must be a maximum of 10 million")
 	v.Check(f.PageSize > 0, "page_size", "must be greater than zero")
-	v.Check(f.PageSize <= 100, "page_size", "must be a maximum of 100")
+	v.Check(f.PageSize <= f.maxPageSize(), "page_size", fmt.Sprintf("must be a maximum of %d", f.maxPageSize()))
 	// Check that the sort parameter matches a value in the safelist.
-	v.Check(validator.In(f.Sort, f.SortSafelist...), "sort", "invalid sort value")
+	v.Check(f.safeSort(), "sort", "invalid sort value")
+}
+
+// Metadata describes where a page of results sits within the full result set.
+type Metadata struct {
+	CurrentPage  int  `json:"currentPage"`
+	PageSize     int  `json:"pageSize"`
+	FirstPage    int  `json:"firstPage"`
+	LastPage     int  `json:"lastPage"`
+	TotalRecords int  `json:"totalRecords"`
+	OutOfRange   bool `json:"outOfRange,omitempty"` // CurrentPage is beyond LastPage
+}
+
+// calculateMetadata builds the Metadata for the given page of a result set of totalRecords rows.
+// An empty result set has a single (empty) page, so any page after the first is out of range.
+func calculateMetadata(totalRecords, page, pageSize int) Metadata {
+	lastPage := 1
+	if pageSize > 0 && totalRecords > 0 {
+		lastPage = int(math.Ceil(float64(totalRecords) / float64(pageSize)))
+	}
+
+	return Metadata{
+		CurrentPage:  page,
+		PageSize:     pageSize,
+		FirstPage:    1,
+		LastPage:     lastPage,
+		TotalRecords: totalRecords,
+		OutOfRange:   page > lastPage,
+	}
 }
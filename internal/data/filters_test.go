@@ -0,0 +1,135 @@
+package data
+
+import (
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func TestCalculateMetadata(t *testing.T) {
+	tests := []struct {
+		totalRecords, page, pageSize int
+		want                         Metadata
+	}{
+		{0, 1, 20, Metadata{CurrentPage: 1, PageSize: 20, FirstPage: 1, LastPage: 1}},
+		{0, 2, 20, Metadata{CurrentPage: 2, PageSize: 20, FirstPage: 1, LastPage: 1, OutOfRange: true}},
+		{20, 1, 20, Metadata{CurrentPage: 1, PageSize: 20, FirstPage: 1, LastPage: 1, TotalRecords: 20}},
+		{21, 2, 20, Metadata{CurrentPage: 2, PageSize: 20, FirstPage: 1, LastPage: 2, TotalRecords: 21}},
+		{21, 3, 20, Metadata{CurrentPage: 3, PageSize: 20, FirstPage: 1, LastPage: 2, TotalRecords: 21, OutOfRange: true}},
+		{21, 99, 20, Metadata{CurrentPage: 99, PageSize: 20, FirstPage: 1, LastPage: 2, TotalRecords: 21, OutOfRange: true}},
+		// An unlimited page size puts everything on one page.
+		{50, 1, 0, Metadata{CurrentPage: 1, FirstPage: 1, LastPage: 1, TotalRecords: 50}},
+	}
+
+	for _, tt := range tests {
+		if got := calculateMetadata(tt.totalRecords, tt.page, tt.pageSize); got != tt.want {
+			t.Errorf("calculateMetadata(%d, %d, %d) = %+v, want %+v", tt.totalRecords, tt.page, tt.pageSize, got, tt.want)
+		}
+	}
+}
+
+func TestClampPageSize(t *testing.T) {
+	tests := []struct {
+		pageSize, maxPageSize int
+		want                  int
+		warn                  bool
+	}{
+		{20, 0, 20, false},
+		{DefaultMaxPageSize, 0, DefaultMaxPageSize, false},
+		{DefaultMaxPageSize + 1, 0, DefaultMaxPageSize, true},
+		{500, 250, 250, true},
+		{200, 250, 200, false},
+	}
+
+	for _, tt := range tests {
+		f := Filters{Page: 1, PageSize: tt.pageSize, MaxPageSize: tt.maxPageSize, Sort: "id", SortSafelist: []string{"id"}}
+
+		v := validator.New()
+		f.ClampPageSize(v)
+
+		if f.PageSize != tt.want {
+			t.Errorf("page size %d (max %d): got %d, want %d", tt.pageSize, tt.maxPageSize, f.PageSize, tt.want)
+		}
+		if _, warned := v.Warnings["page_size"]; warned != tt.warn {
+			t.Errorf("page size %d (max %d): got warnings %v, want warning=%t", tt.pageSize, tt.maxPageSize, v.Warnings, tt.warn)
+		}
+
+		// A clamped page size always passes validation.
+		if ValidateFilters(v, f); !v.Valid() {
+			t.Errorf("page size %d (max %d): got errors %v after clamping", tt.pageSize, tt.maxPageSize, v.Errors)
+		}
+	}
+}
+
+func TestValidateFiltersMaxPageSize(t *testing.T) {
+	tests := []struct {
+		pageSize, maxPageSize int
+		valid                 bool
+	}{
+		{DefaultMaxPageSize, 0, true},
+		{DefaultMaxPageSize + 1, 0, false},
+		{250, 250, true},
+		{251, 250, false},
+		{0, 250, false},
+	}
+
+	for _, tt := range tests {
+		v := validator.New()
+		ValidateFilters(v, Filters{Page: 1, PageSize: tt.pageSize, MaxPageSize: tt.maxPageSize, Sort: "id", SortSafelist: []string{"id"}})
+
+		if _, invalid := v.Errors["page_size"]; invalid == tt.valid {
+			t.Errorf("page size %d (max %d): got errors %v, want valid=%t", tt.pageSize, tt.maxPageSize, v.Errors, tt.valid)
+		}
+	}
+}
+
+func TestFiltersSort(t *testing.T) {
+	safelist := []string{"id", "name", "win_rate", "-id", "-name", "-win_rate"}
+	descending := []string{"win_rate"}
+
+	tests := []struct {
+		sort      string
+		column    string
+		direction string
+	}{
+		{"id", "id", "ASC"},
+		{"-id", "id", "DESC"},
+		{"+id", "id", "ASC"},
+		// Stat columns sort descending unless asked otherwise.
+		{"win_rate", "win_rate", "DESC"},
+		{"-win_rate", "win_rate", "DESC"},
+		{"+win_rate", "win_rate", "ASC"},
+	}
+
+	for _, tt := range tests {
+		f := Filters{Sort: tt.sort, SortSafelist: safelist, DescendingSorts: descending}
+
+		if !f.safeSort() {
+			t.Errorf("sort %q: got unsafe, want safe", tt.sort)
+			continue
+		}
+		if got := f.sortColumn(); got != tt.column {
+			t.Errorf("sort %q: got column %q, want %q", tt.sort, got, tt.column)
+		}
+		if got := f.sortDirection(); got != tt.direction {
+			t.Errorf("sort %q: got direction %q, want %q", tt.sort, got, tt.direction)
+		}
+	}
+
+	for _, sort := range []string{"", "password", "+-id", "++id", "+", "-password", "+password"} {
+		f := Filters{Sort: sort, SortSafelist: safelist, DescendingSorts: descending}
+		if f.safeSort() {
+			t.Errorf("sort %q: got safe, want unsafe", sort)
+		}
+	}
+}
+
+func TestFiltersSortColumnPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("got no panic for an unsafe sort")
+		}
+	}()
+
+	Filters{Sort: "id; DROP TABLE champions", SortSafelist: []string{"id"}}.sortColumn()
+}
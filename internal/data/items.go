@@ -0,0 +1,80 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ItemStats is how often an item was bought on a champion, and how often those games were won.
+type ItemStats struct {
+	Item     string  `json:"item"`
+	Games    int     `json:"games"`
+	Wins     int     `json:"wins"`
+	WinRate  float64 `json:"winRate"`
+	PickRate float64 `json:"pickRate"` // Share of the champion's games in which the item was bought
+}
+
+// GetPopularItems aggregates the items bought in the champion's games, optionally only in the
+// given role, most frequently bought first. An item counts once per game however many times it
+// was bought, and items bought in fewer than minGames games are left out. Remakes and games
+// outside scope are excluded.
+func (c ChampionModel) GetPopularItems(id int64, role string, minGames int, scope StatsScope) ([]*ItemStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var totalGames int
+	err := c.DB.QueryRowContext(ctx, `
+        SELECT count(*)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        WHERE mp.champion_id = $1 AND ($2 = '' OR LOWER(mp.role) = LOWER($2)) AND LOWER(m.result) <> $3
+        AND (m.match_type = $4 OR $4 = '')
+        AND (cardinality($5::text[]) = 0 OR mp.rank_tier = ANY($5))`,
+		id, role, ResultRemake, scope.MatchType, pq.Array(scope.Tiers)).Scan(&totalGames)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+        SELECT items.item, count(*), count(*) FILTER (WHERE LOWER(m.result) = mp.team)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        CROSS JOIN LATERAL (SELECT DISTINCT jsonb_array_elements_text(mp.bought_items) AS item) items
+        WHERE mp.champion_id = $1 AND ($2 = '' OR LOWER(mp.role) = LOWER($2)) AND LOWER(m.result) <> $3
+        AND (m.match_type = $4 OR $4 = '')
+        AND (cardinality($5::text[]) = 0 OR mp.rank_tier = ANY($5))
+        GROUP BY items.item
+        HAVING count(*) >= $6
+        ORDER BY count(*) DESC, items.item ASC`
+
+	rows, err := c.DB.QueryContext(ctx, query, id, role, ResultRemake, scope.MatchType, pq.Array(scope.Tiers), minGames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*ItemStats{}
+
+	for rows.Next() {
+		var s ItemStats
+		err := rows.Scan(&s.Item, &s.Games, &s.Wins)
+		if err != nil {
+			return nil, err
+		}
+
+		s.WinRate = float64(s.Wins) / float64(s.Games)
+		if totalGames > 0 {
+			s.PickRate = float64(s.Games) / float64(totalGames)
+		}
+
+		items = append(items, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,65 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetPopularItems(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	ahri := insertTestChampion(t, models, "Ahri")
+
+	games := []struct {
+		role, result, items string
+	}{
+		// Boots bought twice in one game still count once.
+		{"Mid", ResultBlue, `["Luden's Companion", "Boots", "Boots"]`},
+		{"Mid", ResultRed, `["Luden's Companion", "Rabadon's Deathcap"]`},
+		{"Mid", ResultBlue, `["Luden's Companion", "Boots"]`},
+		// Neither a game in another role nor a remake counts.
+		{"Top", ResultBlue, `["Luden's Companion", "Riftmaker"]`},
+		{"Mid", ResultRemake, `["Luden's Companion"]`},
+	}
+
+	for _, g := range games {
+		var matchID int64
+		err := models.Matches.DB.QueryRow(`
+            INSERT INTO matches (duration, result, match_type, blue_team, red_team)
+            VALUES (1800, $1, 'solo_queue', '{}', '{}')
+            RETURNING id`, g.result).Scan(&matchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = models.Matches.DB.Exec(`
+            INSERT INTO match_performance (match_id, summoner_id, champion_id, team, role, bought_items)
+            VALUES ($1, $2, $3, $4, $5, $6)`, matchID, summoner.ID, ahri.ID, ResultBlue, g.role, g.items)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Rabadon's Deathcap was only bought once, below the two game minimum.
+	items, err := models.Champions.GetPopularItems(ahri.ID, "mid", 2, StatsScope{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ItemStats{
+		{Item: "Luden's Companion", Games: 3, Wins: 2, WinRate: 2.0 / 3, PickRate: 1},
+		{Item: "Boots", Games: 2, Wins: 2, WinRate: 1, PickRate: 2.0 / 3},
+	}
+
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, got := range items {
+		w := want[i]
+		if got.Item != w.Item || got.Games != w.Games || got.Wins != w.Wins ||
+			math.Abs(got.WinRate-w.WinRate) > 1e-9 || math.Abs(got.PickRate-w.PickRate) > 1e-9 {
+			t.Errorf("item %d: got %+v, want %+v", i, *got, w)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package data
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+// kdaBucketCount is the number of buckets in a KDA histogram. Each bucket is one KDA ratio wide,
+// except the last, which holds every ratio of kdaBucketCount-1 and above.
+const kdaBucketCount = 11
+
+// KDABucket counts the matches whose KDA ratio was at least Min and below Max. The last bucket
+// has no upper bound, and Max is nil.
+type KDABucket struct {
+	Min     float64  `json:"min"`
+	Max     *float64 `json:"max"`
+	Matches int      `json:"matches"`
+}
+
+// KDADistribution describes the spread of a summoner's per-match KDA ratios, to tell consistent
+// players apart from feast-or-famine ones with the same average. Percentiles are interpolated
+// between the nearest ratios, as by PostgreSQL's percentile_cont.
+type KDADistribution struct {
+	Matches   int         `json:"matches"`
+	Mean      float64     `json:"mean"`
+	StdDev    float64     `json:"stdDev"` // Population standard deviation
+	P10       float64     `json:"p10"`
+	P25       float64     `json:"p25"`
+	Median    float64     `json:"median"`
+	P75       float64     `json:"p75"`
+	P90       float64     `json:"p90"`
+	Histogram []KDABucket `json:"histogram"`
+}
+
+// NewKDADistribution builds the distribution of a set of KDA ratios. With no ratios every
+// statistic is zero and every bucket empty.
+func NewKDADistribution(ratios []float64) *KDADistribution {
+	d := &KDADistribution{Matches: len(ratios), Histogram: make([]KDABucket, kdaBucketCount)}
+
+	for i := range d.Histogram {
+		d.Histogram[i].Min = float64(i)
+		if i < kdaBucketCount-1 {
+			max := float64(i + 1)
+			d.Histogram[i].Max = &max
+		}
+	}
+
+	if len(ratios) == 0 {
+		return d
+	}
+
+	sorted := append([]float64(nil), ratios...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, ratio := range sorted {
+		sum += ratio
+		d.Histogram[int(math.Min(math.Floor(ratio), kdaBucketCount-1))].Matches++
+	}
+	d.Mean = sum / float64(len(sorted))
+
+	var squares float64
+	for _, ratio := range sorted {
+		squares += (ratio - d.Mean) * (ratio - d.Mean)
+	}
+	d.StdDev = math.Sqrt(squares / float64(len(sorted)))
+
+	d.P10 = percentile(sorted, 0.10)
+	d.P25 = percentile(sorted, 0.25)
+	d.Median = percentile(sorted, 0.50)
+	d.P75 = percentile(sorted, 0.75)
+	d.P90 = percentile(sorted, 0.90)
+
+	return d
+}
+
+// percentile returns the p-th percentile (0 to 1) of a non-empty sorted slice, interpolating
+// linearly between the two nearest values.
+func percentile(sorted []float64, p float64) float64 {
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// GetKDADistribution returns the distribution of the summoner's KDA ratios over their matches,
+// excluding remakes.
+func (m SummonerModel) GetKDADistribution(id int64) (*KDADistribution, error) {
+	query := `
+        SELECT mp.kills, mp.deaths, mp.assists
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        WHERE mp.summoner_id = $1 AND LOWER(m.result) <> $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, id, ResultRemake)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratios := []float64{}
+
+	for rows.Next() {
+		var kda KDA
+		if err := rows.Scan(&kda.Kills, &kda.Deaths, &kda.Assists); err != nil {
+			return nil, err
+		}
+		ratios = append(ratios, kda.Ratio())
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewKDADistribution(ratios), nil
+}
@@ -0,0 +1,131 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewKDADistribution(t *testing.T) {
+	// Ratios on a bucket's lower bound count towards that bucket, and everything from 10 up lands
+	// in the last.
+	d := NewKDADistribution([]float64{15, 2, 0.5, 1, 3, 10, 2.5})
+
+	want := map[float64]int{0: 1, 1: 1, 2: 2, 3: 1, 10: 2}
+	for _, bucket := range d.Histogram {
+		if bucket.Matches != want[bucket.Min] {
+			t.Errorf("bucket %v: got %d matches, want %d", bucket.Min, bucket.Matches, want[bucket.Min])
+		}
+	}
+	if last := d.Histogram[len(d.Histogram)-1]; last.Max != nil {
+		t.Errorf("got last bucket max %v, want nil", *last.Max)
+	}
+
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"matches", float64(d.Matches), 7},
+		{"mean", d.Mean, 34.0 / 7},
+		{"p10", d.P10, 0.8},
+		{"p25", d.P25, 1.5},
+		{"median", d.Median, 2.5},
+		{"p75", d.P75, 6.5},
+		{"p90", d.P90, 12},
+	}
+
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-9 {
+			t.Errorf("got %s %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestNewKDADistributionMedian(t *testing.T) {
+	tests := []struct {
+		ratios []float64
+		want   float64
+	}{
+		{[]float64{4}, 4},
+		{[]float64{3, 1}, 2},
+		// With an even count the median falls between the middle two ratios.
+		{[]float64{1, 4, 2, 8}, 3},
+		{[]float64{1, 2, 3}, 2},
+	}
+
+	for _, tt := range tests {
+		if got := NewKDADistribution(tt.ratios).Median; got != tt.want {
+			t.Errorf("NewKDADistribution(%v).Median = %v, want %v", tt.ratios, got, tt.want)
+		}
+	}
+}
+
+func TestNewKDADistributionEmpty(t *testing.T) {
+	d := NewKDADistribution(nil)
+
+	if d.Matches != 0 || d.Mean != 0 || d.Median != 0 || len(d.Histogram) != kdaBucketCount {
+		t.Errorf("got %+v, want zeroes and %d empty buckets", d, kdaBucketCount)
+	}
+	for _, bucket := range d.Histogram {
+		if bucket.Matches != 0 {
+			t.Errorf("bucket %v: got %d matches, want 0", bucket.Min, bucket.Matches)
+		}
+	}
+}
+
+func TestGetKDADistribution(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	// Ratios of 0.5, 2, 4 and 12; the deathless game counts as one death. The remake's ratio
+	// would be 20 and isn't counted.
+	games := []struct {
+		kills, deaths, assists int
+		result                 string
+	}{
+		{1, 4, 1, ResultBlue},
+		{2, 2, 2, ResultRed},
+		{5, 2, 3, ResultBlue},
+		{8, 0, 4, ResultBlue},
+		{20, 1, 0, ResultRemake},
+	}
+
+	for _, g := range games {
+		var matchID int64
+		err := models.Matches.DB.QueryRow(`
+            INSERT INTO matches (duration, result, match_type, blue_team, red_team)
+            VALUES (1800, $1, 'solo_queue', '{}', '{}')
+            RETURNING id`, g.result).Scan(&matchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = models.Matches.DB.Exec(`
+            INSERT INTO match_performance (match_id, summoner_id, champion_id, team, kills, deaths, assists)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			matchID, summoner.ID, champion.ID, ResultBlue, g.kills, g.deaths, g.assists)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := models.Summoners.GetKDADistribution(summoner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Matches != 4 {
+		t.Errorf("got %d matches, want 4", d.Matches)
+	}
+	if d.Median != 3 {
+		t.Errorf("got median %v, want 3", d.Median)
+	}
+
+	want := map[float64]int{0: 1, 2: 1, 4: 1, 10: 1}
+	for _, bucket := range d.Histogram {
+		if bucket.Matches != want[bucket.Min] {
+			t.Errorf("bucket %v: got %d matches, want %d", bucket.Min, bucket.Matches, want[bucket.Min])
+		}
+	}
+}
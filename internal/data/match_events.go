@@ -0,0 +1,218 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// Match event types.
+const (
+	EventChampionKill = "champion_kill"
+	EventFirstBlood   = "first_blood"
+	EventTower        = "tower"
+	EventInhibitor    = "inhibitor"
+	EventDragon       = "dragon"
+	EventRiftHerald   = "rift_herald"
+	EventBaron        = "baron"
+	EventNexus        = "nexus"
+
+	// EventTeamStats is a snapshot of a team's total gold and XP, normally recorded every minute.
+	EventTeamStats = "team_stats"
+)
+
+// MatchEventTypes lists every valid match event type.
+var MatchEventTypes = []string{
+	EventChampionKill, EventFirstBlood, EventTower, EventInhibitor,
+	EventDragon, EventRiftHerald, EventBaron, EventNexus, EventTeamStats,
+}
+
+// MaxMatchEvents is the largest number of events which can be added to a match at once.
+const MaxMatchEvents = 1000
+
+// MatchEvent is something which happened during a match, such as a kill or an objective being
+// taken, at a point in the match's timeline.
+type MatchEvent struct {
+	ID        int64  `json:"id"`
+	Timestamp int    `json:"timestamp"`      // Seconds since the start of the match
+	Type      string `json:"type"`           // One of MatchEventTypes
+	Team      string `json:"team,omitempty"` // The team credited with the event, if any
+	Gold      int    `json:"gold,omitempty"` // The team's total gold, for a team_stats event
+	XP        int    `json:"xp,omitempty"`   // The team's total XP, for a team_stats event
+}
+
+// NormalizeMatchEvents trims and lowercases the type and team of each event.
+func NormalizeMatchEvents(events []*MatchEvent) {
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		event.Type = strings.ToLower(strings.TrimSpace(event.Type))
+		event.Team = strings.ToLower(strings.TrimSpace(event.Team))
+	}
+}
+
+// ValidateMatchEvents checks a batch of events for a match lasting duration seconds. Events may
+// be given in any order.
+func ValidateMatchEvents(v *validator.Validator, events []*MatchEvent, duration int) {
+	v.Check(len(events) > 0, "events", "must contain at least one event")
+	v.Check(len(events) <= MaxMatchEvents, "events", fmt.Sprintf("must not contain more than %d events", MaxMatchEvents))
+
+	for i, event := range events {
+		key := fmt.Sprintf("events[%d]", i)
+		if event == nil {
+			v.AddError(key, "must be provided")
+			continue
+		}
+
+		v.Check(event.Timestamp >= 0, key+".timestamp", "must not be negative")
+		v.Check(event.Timestamp <= duration, key+".timestamp", "must be within the match duration")
+		v.Check(validator.In(event.Type, MatchEventTypes...), key+".type", "must be one of "+strings.Join(MatchEventTypes, ", "))
+		v.Check(validator.In(event.Team, "", ResultBlue, ResultRed), key+".team", "must be blue or red")
+		v.Check(event.Type != EventTeamStats || event.Team != "", key+".team", "must be provided for team_stats events")
+		v.Check(event.Gold >= 0, key+".gold", "must not be negative")
+		v.Check(event.XP >= 0, key+".xp", "must not be negative")
+	}
+}
+
+// InsertEvents adds events to a match's timeline.
+func (m MatchModel) InsertEvents(matchID int64, events []*MatchEvent) error {
+	timestamps := make([]int64, len(events))
+	types := make([]string, len(events))
+	teams := make([]string, len(events))
+	gold := make([]int64, len(events))
+	xp := make([]int64, len(events))
+
+	for i, event := range events {
+		timestamps[i] = int64(event.Timestamp)
+		types[i] = event.Type
+		teams[i] = event.Team
+		gold[i] = int64(event.Gold)
+		xp[i] = int64(event.XP)
+	}
+
+	query := `
+        INSERT INTO match_events (match_id, game_time, type, team, gold, xp)
+        SELECT $1, e.game_time, e.type, e.team, e.gold, e.xp
+        FROM unnest($2::integer[], $3::text[], $4::text[], $5::integer[], $6::integer[]) AS e(game_time, type, team, gold, xp)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, matchID, pq.Array(timestamps), pq.Array(types), pq.Array(teams), pq.Array(gold), pq.Array(xp))
+	return err
+}
+
+// GetEvents returns a match's timeline: its events in the order they happened. Events at the
+// same timestamp are returned in the order they were added.
+func (m MatchModel) GetEvents(matchID int64) ([]*MatchEvent, error) {
+	query := `
+        SELECT id, game_time, type, team, gold, xp
+        FROM match_events
+        WHERE match_id = $1
+        ORDER BY game_time, id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*MatchEvent{}
+
+	for rows.Next() {
+		var event MatchEvent
+		if err := rows.Scan(&event.ID, &event.Timestamp, &event.Type, &event.Team, &event.Gold, &event.XP); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// DiffPoint is the blue team's lead in gold and XP (negative when red is ahead) at a minute of a
+// match.
+type DiffPoint struct {
+	Minute int `json:"minute"`
+	Gold   int `json:"gold"`
+	XP     int `json:"xp"`
+}
+
+// teamStatsPoint is a team's totals at a point in the match.
+type teamStatsPoint struct {
+	timestamp int
+	gold      int
+	xp        int
+}
+
+// DiffCurve samples the blue-minus-red gold and XP difference at every whole minute of a match
+// lasting duration seconds, from the teams' team_stats events. Each team starts on zero, and
+// between two of its snapshots its totals are interpolated linearly; after its last snapshot they
+// are held. Events must be sorted by timestamp, as GetEvents returns them. It returns false if
+// either team has no team_stats events.
+func DiffCurve(events []*MatchEvent, duration int) ([]DiffPoint, bool) {
+	series := map[string][]teamStatsPoint{
+		ResultBlue: {{}},
+		ResultRed:  {{}},
+	}
+
+	for _, event := range events {
+		if event.Type != EventTeamStats {
+			continue
+		}
+		series[event.Team] = append(series[event.Team], teamStatsPoint{event.Timestamp, event.Gold, event.XP})
+	}
+
+	if len(series[ResultBlue]) == 1 || len(series[ResultRed]) == 1 {
+		return nil, false
+	}
+
+	points := []DiffPoint{}
+	for minute := 0; minute*60 <= duration; minute++ {
+		blueGold, blueXP := interpolateTeamStats(series[ResultBlue], minute*60)
+		redGold, redXP := interpolateTeamStats(series[ResultRed], minute*60)
+
+		points = append(points, DiffPoint{
+			Minute: minute,
+			Gold:   int(math.Round(blueGold - redGold)),
+			XP:     int(math.Round(blueXP - redXP)),
+		})
+	}
+
+	return points, true
+}
+
+// interpolateTeamStats returns a team's gold and XP at a timestamp, interpolated between the
+// snapshots either side of it.
+func interpolateTeamStats(snapshots []teamStatsPoint, timestamp int) (gold, xp float64) {
+	for i := 1; i < len(snapshots); i++ {
+		prev, next := snapshots[i-1], snapshots[i]
+		if timestamp > next.timestamp {
+			continue
+		}
+		if next.timestamp == prev.timestamp {
+			return float64(next.gold), float64(next.xp)
+		}
+
+		t := float64(timestamp-prev.timestamp) / float64(next.timestamp-prev.timestamp)
+		gold = float64(prev.gold) + t*float64(next.gold-prev.gold)
+		xp = float64(prev.xp) + t*float64(next.xp-prev.xp)
+		return gold, xp
+	}
+
+	last := snapshots[len(snapshots)-1]
+	return float64(last.gold), float64(last.xp)
+}
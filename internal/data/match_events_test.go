@@ -0,0 +1,130 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func TestDiffCurve(t *testing.T) {
+	events := []*MatchEvent{
+		{Timestamp: 30, Type: EventFirstBlood, Team: ResultRed},
+		{Timestamp: 60, Type: EventTeamStats, Team: ResultBlue, Gold: 1000, XP: 500},
+		{Timestamp: 120, Type: EventTeamStats, Team: ResultRed, Gold: 1200, XP: 600},
+		{Timestamp: 180, Type: EventTeamStats, Team: ResultBlue, Gold: 4000, XP: 1500},
+	}
+
+	got, ok := DiffCurve(events, 200)
+	if !ok {
+		t.Fatal("got no curve")
+	}
+
+	// Each team starts on zero, is interpolated between its snapshots and is held after its last.
+	want := []DiffPoint{
+		{Minute: 0, Gold: 0, XP: 0},
+		{Minute: 1, Gold: 400, XP: 200},
+		{Minute: 2, Gold: 1300, XP: 400},
+		{Minute: 3, Gold: 2800, XP: 900},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffCurveSnapshotAtStart(t *testing.T) {
+	events := []*MatchEvent{
+		{Timestamp: 0, Type: EventTeamStats, Team: ResultBlue, Gold: 500, XP: 100},
+		{Timestamp: 0, Type: EventTeamStats, Team: ResultRed, Gold: 500},
+	}
+
+	got, ok := DiffCurve(events, 60)
+	if !ok {
+		t.Fatal("got no curve")
+	}
+
+	want := []DiffPoint{{Minute: 0, Gold: 0, XP: 100}, {Minute: 1, Gold: 0, XP: 100}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffCurveMissingTeam(t *testing.T) {
+	events := []*MatchEvent{
+		{Timestamp: 60, Type: EventTeamStats, Team: ResultBlue, Gold: 1000},
+		{Timestamp: 90, Type: EventDragon, Team: ResultRed},
+	}
+
+	if got, ok := DiffCurve(events, 1800); ok {
+		t.Errorf("got %+v, want no curve without red team_stats events", got)
+	}
+	if got, ok := DiffCurve(nil, 1800); ok {
+		t.Errorf("got %+v, want no curve without events", got)
+	}
+}
+
+func TestValidateMatchEvents(t *testing.T) {
+	events := []*MatchEvent{
+		{Timestamp: 600, Type: EventDragon, Team: ResultBlue},
+		{Timestamp: 1801, Type: EventBaron, Team: ResultRed},
+		{Timestamp: -1, Type: EventTower},
+		{Timestamp: 60, Type: "ace"},
+		{Timestamp: 60, Type: EventTeamStats},
+		nil,
+	}
+
+	v := validator.New()
+	ValidateMatchEvents(v, events, 1800)
+
+	want := []string{
+		"events[1].timestamp",
+		"events[2].timestamp",
+		"events[3].type",
+		"events[4].team",
+		"events[5]",
+	}
+	for _, key := range want {
+		if _, ok := v.Errors[key]; !ok {
+			t.Errorf("missing error for %s; got %v", key, v.Errors)
+		}
+	}
+	if len(v.Errors) != len(want) {
+		t.Errorf("got %d errors, want %d: %v", len(v.Errors), len(want), v.Errors)
+	}
+}
+
+func TestGetEventsSorted(t *testing.T) {
+	models := newTestModels(t)
+
+	match := validMatch()
+	if err := models.Matches.Insert(match); err != nil {
+		t.Fatal(err)
+	}
+
+	events := []*MatchEvent{
+		{Timestamp: 1500, Type: EventBaron, Team: ResultBlue},
+		{Timestamp: 300, Type: EventFirstBlood, Team: ResultRed},
+		{Timestamp: 900, Type: EventDragon, Team: ResultBlue},
+		{Timestamp: 300, Type: EventChampionKill, Team: ResultRed},
+	}
+	if err := models.Matches.InsertEvents(match.ID, events); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := models.Matches.GetEvents(match.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	for _, event := range got {
+		order = append(order, fmt.Sprintf("%d:%s", event.Timestamp, event.Type))
+	}
+
+	// Events at the same time keep the order they were added in.
+	want := []string{"300:first_blood", "300:champion_kill", "900:dragon", "1500:baron"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("got events %v, want %v", order, want)
+	}
+}
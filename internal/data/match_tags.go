@@ -0,0 +1,90 @@
+package data
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// TagRX matches a normalized match tag: lowercase letters, digits and hyphens, starting with a
+// letter or digit (e.g. "pentakill", "baron-steal").
+var TagRX = regexp.MustCompile("^[a-z0-9][a-z0-9-]*$")
+
+// NormalizeTags trims and lowercases each tag and removes empty and duplicate tags, keeping the
+// tags in the order they were first given.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := []string{}
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+func ValidateTags(v *validator.Validator, tags []string) {
+	v.Check(len(tags) > 0, "tags", "must contain at least one tag")
+	v.Check(len(tags) <= 20, "tags", "must not contain more than 20 tags")
+
+	for _, tag := range tags {
+		v.Check(len(tag) <= 32, "tags", "must not contain tags more than 32 bytes long")
+		v.Check(validator.Matches(tag, TagRX), "tags", "must only contain letters, digits and hyphens")
+	}
+}
+
+// AddTags tags a match. Tags which the match already has are ignored.
+func (m MatchModel) AddTags(matchID int64, tags []string) error {
+	query := `
+        INSERT INTO match_tags (match_id, tag)
+        SELECT $1, unnest($2::text[])
+        ON CONFLICT DO NOTHING`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, matchID, pq.Array(tags))
+	return err
+}
+
+// RemoveTags removes tags from a match. Tags which the match doesn't have are ignored.
+func (m MatchModel) RemoveTags(matchID int64, tags []string) error {
+	query := `
+        DELETE FROM match_tags
+        WHERE match_id = $1 AND tag = ANY($2)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, matchID, pq.Array(tags))
+	return err
+}
+
+// GetTags returns the tags of a match in alphabetical order.
+func (m MatchModel) GetTags(matchID int64) ([]string, error) {
+	query := `
+        SELECT COALESCE(array_agg(tag ORDER BY tag), '{}')
+        FROM match_tags
+        WHERE match_id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tags := []string{}
+
+	err := m.DB.QueryRowContext(ctx, query, matchID).Scan(pq.Array(&tags))
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
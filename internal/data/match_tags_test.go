@@ -0,0 +1,127 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want []string
+	}{
+		{[]string{"pentakill"}, []string{"pentakill"}},
+		{[]string{" Pentakill ", "COMEBACK"}, []string{"pentakill", "comeback"}},
+		// Duplicates, including ones differing only in case, keep the first position.
+		{[]string{"comeback", "pentakill", "Comeback"}, []string{"comeback", "pentakill"}},
+		{[]string{"", "  ", "baron-steal"}, []string{"baron-steal"}},
+		{nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeTags(tt.tags); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NormalizeTags(%q) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tooMany := make([]string, 21)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%d", i)
+	}
+
+	tests := []struct {
+		tags  []string
+		valid bool
+	}{
+		{[]string{"pentakill", "baron-steal", "1v5"}, true},
+		{[]string{}, false},
+		{[]string{"-comeback"}, false},
+		{[]string{"pentakill!"}, false},
+		{[]string{"base race"}, false},
+		{[]string{strings.Repeat("a", 32)}, true},
+		{[]string{strings.Repeat("a", 33)}, false},
+		{tooMany[:20], true},
+		{tooMany, false},
+	}
+
+	for _, tt := range tests {
+		v := validator.New()
+		if ValidateTags(v, tt.tags); v.Valid() != tt.valid {
+			t.Errorf("ValidateTags(%q): got valid %t, want %t", tt.tags, v.Valid(), tt.valid)
+		}
+	}
+}
+
+func TestMatchTags(t *testing.T) {
+	models := newTestModels(t)
+
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		match := validMatch()
+		if err := models.Matches.Insert(match); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, match.ID)
+	}
+
+	// Adding a tag twice is harmless.
+	for _, tag := range []struct {
+		matchID int64
+		tags    []string
+	}{
+		{ids[0], []string{"pentakill", "comeback"}},
+		{ids[2], []string{"pentakill"}},
+		{ids[2], []string{"pentakill"}},
+		{ids[1], []string{"comeback"}},
+	} {
+		if err := models.Matches.AddTags(tag.matchID, tag.tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := models.Matches.RemoveTags(ids[1], []string{"comeback", "never-added"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := models.Matches.GetTags(ids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tags, []string{"comeback", "pentakill"}) {
+		t.Errorf("got tags %q, want comeback and pentakill", tags)
+	}
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}}
+
+	tests := []struct {
+		tag  string
+		want []int64
+	}{
+		{"pentakill", []int64{ids[0], ids[2]}},
+		// The comeback tag was removed from the second match.
+		{"comeback", []int64{ids[0]}},
+		{"throw", nil},
+	}
+
+	for _, tt := range tests {
+		matches, metadata, err := models.Matches.GetAll(MatchFilter{Tag: tt.tag}, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []int64
+		for _, match := range matches {
+			got = append(got, match.ID)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) || metadata.TotalRecords != len(tt.want) {
+			t.Errorf("tag %q: got matches %v (%d in total), want %v", tt.tag, got, metadata.TotalRecords, tt.want)
+		}
+	}
+}
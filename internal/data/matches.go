@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"league_of_graphs.satellite.net/internal/validator"
@@ -17,10 +18,32 @@ type Match struct {
 	PlayedDate time.Time `json:"playedDate"`
 	Duration   int       `json:"duration"`
 	Result     string    `json:"result"`
+	MatchType  string    `json:"matchType"`
 	BlueTeam   *Team     `json:"blueTeam"`
 	RedTeam    *Team     `json:"redTeam"`
+	ReplayURL  string    `json:"replayUrl,omitempty"` // Link to the replay file (optional)
+	VODURL     string    `json:"vodUrl,omitempty"`    // Link to a video of the match (optional)
 }
 
+// Match results. The result of a match names the winning team, which matches the team recorded
+// against each summoner's performance, unless the match was a remake.
+const (
+	ResultBlue   = "blue"
+	ResultRed    = "red"
+	ResultRemake = "remake"
+)
+
+// Match types. Pro and tournament play have a very different meta to solo queue, so champion
+// statistics can be scoped to a single type.
+const (
+	MatchTypeSoloQueue  = "solo_queue"
+	MatchTypePro        = "pro"
+	MatchTypeTournament = "tournament"
+)
+
+// MatchTypes lists every valid match type.
+var MatchTypes = []string{MatchTypeSoloQueue, MatchTypePro, MatchTypeTournament}
+
 type Team struct {
 	TeamKDA             KDA                         // Team's total KDA
 	TurretsDestroyed    int                         // Number of turrets destroyed
@@ -28,16 +51,88 @@ type Team struct {
 	RiftHeraldsKilled   int                         // Number of Rift Heralds killed
 	DragonsKilled       int                         // Number of dragons killed
 	BaronNashorsKilled  int                         // Number of Baron Nashors killed
+	TotalNetWorth       int                         // Team's combined net worth at the end of the match (0 if not recorded)
+	TotalGold           int                         // Total gold earned by the team (0 if not recorded)
+	FirstBlood          bool                        // Whether the team drew first blood
+	FirstTower          bool                        // Whether the team destroyed the first turret
+	FirstDragon         bool                        // Whether the team killed the first dragon
 	Summoners           []*SummonerMatchPerformance // List of summoners in the team
 	BannedChampions     []Champion                  // List of banned champions
+	BanPhases           []int                       `json:",omitempty"` // Position in the ban sequence (1-10) of each banned champion, in the same order (optional)
+}
+
+// NetWorth returns the team's total net worth. Teams stored before TotalNetWorth was recorded
+// decode it as zero, so for those we fall back to summing the summoners' net worth.
+func (t *Team) NetWorth() int {
+	if t == nil {
+		return 0
+	}
+	if t.TotalNetWorth > 0 {
+		return t.TotalNetWorth
+	}
+
+	total := 0
+	for _, performance := range t.Summoners {
+		if performance != nil {
+			total += performance.NetWorth
+		}
+	}
+	return total
+}
+
+// GoldDiff returns the blue team's gold lead over the red team (negative if red was ahead).
+func (m *Match) GoldDiff() int {
+	if m.BlueTeam == nil || m.RedTeam == nil {
+		return 0
+	}
+	return m.BlueTeam.TotalGold - m.RedTeam.TotalGold
+}
+
+// NetWorthDiff returns the blue team's net worth lead over the red team (negative if red was
+// ahead).
+func (m *Match) NetWorthDiff() int {
+	return m.BlueTeam.NetWorth() - m.RedTeam.NetWorth()
 }
 
 type SummonerMatchPerformance struct {
+	MatchID     int64        `json:",omitempty"` // Match the performance belongs to
+	Team        string       `json:",omitempty"` // Team the summoner played on ("blue" or "red")
+	Role        string       `json:",omitempty"` // Role the summoner played (e.g. "Mid")
 	Username    string       // Summoner information
 	Champion    ChampionData // Champion played by the summoner
+	PickPhase   int          `json:",omitempty"` // Position in the pick sequence (1-10), or 0 if unknown
 	NetWorth    int          // Net worth of the summoner in the match
 	KDA         KDA          // KDA of the summoner in the match
 	BoughtItems []string     // List of items bought by the summoner
+
+	// Owner and privacy of the summoner's profile, set by GetPerformances so private summoners'
+	// stats can be hidden.
+	UserID    int64 `json:"-"`
+	IsPrivate bool  `json:"-"`
+
+	// Records the performance was stored against, set by InsertPerformances and Import for
+	// UpdateMatchStatistics.
+	summonerID int64
+	championID int64
+}
+
+// PublicMatchPerformance holds the fields of a performance which are shown to everyone, even when
+// the summoner's profile is private.
+type PublicMatchPerformance struct {
+	MatchID   int64  `json:",omitempty"`
+	Team      string `json:",omitempty"`
+	Username  string
+	IsPrivate bool
+}
+
+// Public returns the performance without any of the summoner's stats.
+func (p *SummonerMatchPerformance) Public() *PublicMatchPerformance {
+	return &PublicMatchPerformance{
+		MatchID:   p.MatchID,
+		Team:      p.Team,
+		Username:  p.Username,
+		IsPrivate: p.IsPrivate,
+	}
 }
 
 type ChampionData struct {
@@ -45,21 +140,154 @@ type ChampionData struct {
 	MainRole string `json:"mainRole"`
 }
 
+// leagueReleaseDate is the date League of Legends was released. No real match can have been
+// played before it.
+var leagueReleaseDate = time.Date(2009, time.October, 27, 0, 0, 0, 0, time.UTC)
+
+// A match is always played between two teams of at most five summoners, each banning at most
+// five champions. Bounding the team arrays keeps oversized payloads out of the database.
+const (
+	MaxTeamSummoners = 5
+	MaxTeamBans      = 5
+)
+
+// playedDateClockSkew is how far into the future a played date may be, to tolerate clocks on
+// ingestion machines which run slightly ahead of ours.
+const playedDateClockSkew = 5 * time.Minute
+
 func ValidateMatch(v *validator.Validator, match *Match) {
+	v.Check(!match.PlayedDate.IsZero(), "played_date", "must be provided")
+	v.Check(match.PlayedDate.Before(time.Now().Add(playedDateClockSkew)), "played_date", "must not be in the future")
+	v.Check(!match.PlayedDate.Before(leagueReleaseDate), "played_date", "must not be before League of Legends was released")
 	v.Check(match.Result != "", "result", "must be provided")
+	v.Check(match.Result == "" || validator.In(strings.ToLower(match.Result), ResultBlue, ResultRed, ResultRemake), "result", "must be one of blue, red or remake")
+	v.Check(validator.In(match.MatchType, MatchTypes...), "match_type", "must be one of solo_queue, pro or tournament")
 	v.Check(match.Duration > 0, "duration", "must be provided")
 	v.Check(match.BlueTeam != nil, "blue_team", "must be provided")
 	v.Check(match.RedTeam != nil, "red_team", "must be provided")
+
+	teams := map[string]*Team{"blue_team": match.BlueTeam, "red_team": match.RedTeam}
+	for name, team := range teams {
+		if team == nil {
+			continue
+		}
+		v.Check(team.TotalNetWorth >= 0, name+".total_net_worth", "must not be negative")
+		v.Check(team.TotalGold >= 0, name+".total_gold", "must not be negative")
+		v.Check(len(team.Summoners) <= MaxTeamSummoners, name+".summoners", fmt.Sprintf("must not contain more than %d summoners", MaxTeamSummoners))
+		v.Check(len(team.BannedChampions) <= MaxTeamBans, name+".banned_champions", fmt.Sprintf("must not contain more than %d champions", MaxTeamBans))
+
+		// Every performance is stored against a summoner and a champion, so both must be named.
+		for i, performance := range team.Summoners {
+			key := fmt.Sprintf("%s.summoners[%d]", name, i)
+			if performance == nil {
+				v.AddError(key, "must be provided")
+				continue
+			}
+			v.Check(performance.Username != "", key+".username", "must be provided")
+			v.Check(performance.Champion.Name != "", key+".champion.name", "must be provided")
+		}
+	}
+
+	// Only one team can take each first objective.
+	if match.BlueTeam != nil && match.RedTeam != nil {
+		v.Check(!(match.BlueTeam.FirstBlood && match.RedTeam.FirstBlood), "first_blood", "must not be set for both teams")
+		v.Check(!(match.BlueTeam.FirstTower && match.RedTeam.FirstTower), "first_tower", "must not be set for both teams")
+		v.Check(!(match.BlueTeam.FirstDragon && match.RedTeam.FirstDragon), "first_dragon", "must not be set for both teams")
+	}
+
+	for key, value := range map[string]string{"replay_url": match.ReplayURL, "vod_url": match.VODURL} {
+		v.Check(len(value) <= 2048, key, "must not be more than 2048 bytes long")
+		v.Check(value == "" || validator.IsURL(value), key, "must be an absolute http or https URL")
+	}
+
+	validateDraftPhases(v, match)
+}
+
+// validateDraftPhases checks the pick and ban ordinals recorded for a match. Each must fall
+// within the draft sequence and no two picks (or two bans) can share a position, since picks
+// and bans are made one at a time across both teams.
+func validateDraftPhases(v *validator.Validator, match *Match) {
+	picks := make(map[int]bool)
+	bans := make(map[int]bool)
+
+	for name, team := range map[string]*Team{"blue_team": match.BlueTeam, "red_team": match.RedTeam} {
+		if team == nil {
+			continue
+		}
+
+		for i, performance := range team.Summoners {
+			if performance == nil || performance.PickPhase == 0 {
+				continue
+			}
+
+			key := fmt.Sprintf("%s.summoners[%d].pick_phase", name, i)
+			v.Check(performance.PickPhase >= 1 && performance.PickPhase <= DraftPhases, key, fmt.Sprintf("must be between 1 and %d", DraftPhases))
+			v.Check(!picks[performance.PickPhase], key, "must not be shared with another pick")
+			picks[performance.PickPhase] = true
+		}
+
+		if len(team.BanPhases) == 0 {
+			continue
+		}
+
+		v.Check(len(team.BanPhases) == len(team.BannedChampions), name+".ban_phases", "must have one entry for each banned champion")
+
+		for i, phase := range team.BanPhases {
+			key := fmt.Sprintf("%s.ban_phases[%d]", name, i)
+			v.Check(phase >= 1 && phase <= DraftPhases, key, fmt.Sprintf("must be between 1 and %d", DraftPhases))
+			v.Check(!bans[phase], key, "must not be shared with another ban")
+			bans[phase] = true
+		}
+	}
+}
+
+// MatchPlausibilityLimits holds the thresholds used by CheckMatchPlausibility to flag match data
+// which is valid but statistically implausible.
+type MatchPlausibilityLimits struct {
+	MaxNetWorthPerMinute int // Highest net worth per minute of match duration we consider believable
+}
+
+// CheckMatchPlausibility adds warnings (not errors) to the validator for combinations of values
+// which almost certainly come from corrupt ingestion data, such as a summoner with kills but no
+// net worth. The match duration is in seconds.
+func CheckMatchPlausibility(v *validator.Validator, match *Match, limits MatchPlausibilityLimits) {
+	teams := map[string]*Team{"blue_team": match.BlueTeam, "red_team": match.RedTeam}
+
+	for name, team := range teams {
+		if team == nil {
+			continue
+		}
+
+		for i, performance := range team.Summoners {
+			if performance == nil {
+				continue
+			}
+
+			key := fmt.Sprintf("%s.summoners[%d].net_worth", name, i)
+
+			v.Warn(performance.NetWorth > 0 || (performance.KDA.Kills == 0 && performance.KDA.Assists == 0), key,
+				"is zero despite kills or assists being recorded")
+
+			if limits.MaxNetWorthPerMinute > 0 && match.Duration > 0 {
+				ceiling := limits.MaxNetWorthPerMinute * match.Duration / 60
+				v.Warn(performance.NetWorth <= ceiling, key,
+					fmt.Sprintf("is implausibly high for the match duration (more than %d per minute)", limits.MaxNetWorthPerMinute))
+			}
+		}
+	}
 }
 
 type MatchModel struct {
-	DB *sql.DB
+	DB *DB
+	// StatUpdates limits how many statistics update transactions run at once, so a burst of
+	// inserted matches doesn't swamp the database with contending transactions. Nil means no limit.
+	StatUpdates Semaphore
 }
 
 func (m MatchModel) Insert(match *Match) error {
 	query := `
-        INSERT INTO matches (duration, result, played_date, blue_team, red_team)
-        VALUES ($1, $2, $3, $4, $5)
+        INSERT INTO matches (duration, result, match_type, played_date, blue_team, red_team, replay_url, vod_url)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
         RETURNING id
     `
 
@@ -73,11 +301,100 @@ func (m MatchModel) Insert(match *Match) error {
 		return fmt.Errorf("Insert: %v", err)
 	}
 
-	args := []interface{}{match.Duration, match.Result, match.PlayedDate, blueTeamJSON, redTeamJSON}
+	args := []interface{}{match.Duration, match.Result, match.MatchType, match.PlayedDate, blueTeamJSON, redTeamJSON, match.ReplayURL, match.VODURL}
 
 	return m.DB.QueryRow(query, args...).Scan(&match.ID)
 }
 
+// InsertPerformances stores a match_performance row for each summoner of an inserted match, so that
+// the match counts towards the statistics derived from performance history. Summoners are matched
+// by username (in region, unless it's empty) and champions by name, ignoring case; neither is
+// created if missing. A summoner who can't be matched to exactly one stored summoner, or whose
+// champion isn't stored, is left out of the performance history and the statistics, with a
+// warning added to v; the match itself is still stored. It runs no transaction of its own, so it
+// should be called with the models from Models.Transact to commit or roll back together with the
+// match.
+func (m MatchModel) InsertPerformances(v *validator.Validator, match *Match, region string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	teams := []struct {
+		side string
+		team *Team
+	}{{ResultBlue, match.BlueTeam}, {ResultRed, match.RedTeam}}
+
+	for _, t := range teams {
+		for i, p := range t.team.Summoners {
+			field := fmt.Sprintf("%s_team.summoners[%d]", t.side, i)
+			p.MatchID = match.ID
+			p.Team = t.side
+
+			var summonerIDs []int64
+			rows, err := m.DB.QueryContext(ctx, `
+                SELECT id FROM summoners
+                WHERE LOWER(username) = LOWER($1) AND ($2 = '' OR LOWER(region) = LOWER($2))
+                ORDER BY id LIMIT 2`, p.Username, region)
+			if err != nil {
+				return err
+			}
+			for rows.Next() {
+				var id int64
+				if err := rows.Scan(&id); err != nil {
+					rows.Close()
+					return err
+				}
+				summonerIDs = append(summonerIDs, id)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			switch len(summonerIDs) {
+			case 0:
+				v.AddWarning(field+".username", "matches no summoner, so isn't counted in the statistics")
+				continue
+			case 2:
+				v.AddWarning(field+".username", "matches summoners in several regions, so isn't counted in the statistics unless region is provided")
+				continue
+			}
+
+			var championID int64
+			err = m.DB.QueryRowContext(ctx, `SELECT id FROM champions WHERE LOWER(name) = LOWER($1) ORDER BY id LIMIT 1`,
+				p.Champion.Name).Scan(&championID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					v.AddWarning(field+".champion.name", "matches no champion, so isn't counted in the statistics")
+					continue
+				}
+				return err
+			}
+
+			boughtItems := []byte("[]")
+			if p.BoughtItems != nil {
+				boughtItems, err = json.Marshal(p.BoughtItems)
+				if err != nil {
+					return err
+				}
+			}
+
+			p.summonerID = summonerIDs[0]
+			p.championID = championID
+
+			_, err = m.DB.ExecContext(ctx, `
+                INSERT INTO match_performance (match_id, summoner_id, champion_id, team, role, pick_phase, net_worth, kills, deaths, assists, bought_items)
+                VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8, $9, $10, $11)`,
+				match.ID, summonerIDs[0], championID, p.Team, p.Role, p.PickPhase,
+				p.NetWorth, p.KDA.Kills, p.KDA.Deaths, p.KDA.Assists, boughtItems)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (t *Team) Scan(value interface{}) error {
 	byteValue, ok := value.([]byte)
 	if !ok {
@@ -97,7 +414,7 @@ func (m MatchModel) Get(id int64) (*Match, error) {
 	}
 
 	query := `
-		SELECT id, duration, result, played_date, blue_team, red_team
+		SELECT id, duration, result, match_type, played_date, blue_team, red_team, replay_url, vod_url
 		FROM matches
 		WHERE id = $1
 	`
@@ -108,9 +425,12 @@ func (m MatchModel) Get(id int64) (*Match, error) {
 		&match.ID,
 		&match.Duration,
 		&match.Result,
+		&match.MatchType,
 		&match.PlayedDate,
 		&match.BlueTeam,
 		&match.RedTeam,
+		&match.ReplayURL,
+		&match.VODURL,
 	)
 
 	if err != nil {
@@ -128,16 +448,20 @@ func (m MatchModel) Get(id int64) (*Match, error) {
 func (m MatchModel) Update(match *Match) error {
 	query := `
 		UPDATE matches
-		SET duration = $1, result = $2, played_date = $3, blue_team = $4, red_team = $5
-		WHERE id = $6
+		SET duration = $1, result = $2, match_type = $3, played_date = $4, blue_team = $5, red_team = $6,
+			replay_url = $7, vod_url = $8
+		WHERE id = $9
 	`
 
 	args := []interface{}{
 		match.Duration,
 		match.Result,
+		match.MatchType,
 		match.PlayedDate,
 		match.BlueTeam,
 		match.RedTeam,
+		match.ReplayURL,
+		match.VODURL,
 		match.ID,
 	}
 
@@ -171,44 +495,217 @@ func (m MatchModel) Delete(id int64) error {
 	return nil
 }
 
-func (m MatchModel) GetAll(filters Filters) ([]*Match, error) {
-	query := fmt.Sprintf(`
-        SELECT id, duration, result, played_date, blue_team, red_team
-        FROM matches
-        ORDER BY %s %s, id ASC
-        LIMIT $1 OFFSET $2`, filters.sortColumn(), filters.sortDirection())
+// MatchFilter holds the predicates shared by the match list and count queries.
+type MatchFilter struct {
+	Tag           string // Only matches with this tag (ignored when empty)
+	MinDragons    int    // Dragons killed by both teams combined (0 means no limit)
+	MinBarons     int    // Baron Nashors killed by both teams combined
+	MinTurrets    int    // Turrets destroyed by both teams combined
+	MinHeralds    int    // Rift Heralds killed by both teams combined
+	MinInhibitors int    // Inhibitors destroyed by both teams combined
+	HasVOD        bool   // Only matches with a VOD link
+	SummonerID    int64  // Only matches the summoner played in (0 means any match)
+	Outcome       string // With SummonerID, only matches the summoner won or lost (OutcomeWin or OutcomeLoss)
+}
 
+// Match outcomes from one summoner's point of view. A remake is neither.
+const (
+	OutcomeWin  = "win"
+	OutcomeLoss = "loss"
+)
+
+// matchObjectiveTotal sums an objective count over both teams' JSON.
+func matchObjectiveTotal(key string) string {
+	return fmt.Sprintf("(COALESCE((blue_team->>'%[1]s')::int, 0) + COALESCE((red_team->>'%[1]s')::int, 0))", key)
+}
+
+// matchWhere is the WHERE clause for a MatchFilter. Its placeholders are filled by
+// MatchFilter.args().
+var matchWhere = `
+        WHERE ($1 = '' OR EXISTS (SELECT 1 FROM match_tags t WHERE t.match_id = matches.id AND t.tag = $1))
+        AND ($2 = 0 OR ` + matchObjectiveTotal("DragonsKilled") + ` >= $2)
+        AND ($3 = 0 OR ` + matchObjectiveTotal("BaronNashorsKilled") + ` >= $3)
+        AND ($4 = 0 OR ` + matchObjectiveTotal("TurretsDestroyed") + ` >= $4)
+        AND ($5 = 0 OR ` + matchObjectiveTotal("RiftHeraldsKilled") + ` >= $5)
+        AND ($6 = 0 OR ` + matchObjectiveTotal("InhibitorsDestroyed") + ` >= $6)
+        AND (NOT $7 OR vod_url <> '')
+        AND ($8 = 0 OR EXISTS (
+            SELECT 1 FROM match_performance mp
+            WHERE mp.match_id = matches.id AND mp.summoner_id = $8
+            AND ($9 = ''
+                OR ($9 = 'win' AND LOWER(matches.result) = mp.team)
+                OR ($9 = 'loss' AND LOWER(matches.result) NOT IN (mp.team, $10)))))`
+
+// args returns the arguments for the placeholders in matchWhere.
+func (f MatchFilter) args() []interface{} {
+	return []interface{}{f.Tag, f.MinDragons, f.MinBarons, f.MinTurrets, f.MinHeralds, f.MinInhibitors, f.HasVOD,
+		f.SummonerID, f.Outcome, ResultRemake}
+}
+
+// GetAll returns a page of matches matching the filter, along with the paging metadata.
+func (m MatchModel) GetAll(filter MatchFilter, filters Filters) ([]*Match, Metadata, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	rows, err := m.DB.QueryContext(ctx, query, filters.limit(), filters.offset())
+	var totalRecords int
+	err := m.DB.QueryRowContext(ctx, `SELECT count(*) FROM matches`+matchWhere, filter.args()...).Scan(&totalRecords)
 	if err != nil {
-		return nil, err
+		return nil, Metadata{}, err
 	}
 
-	defer rows.Close()
-
 	matches := []*Match{}
 
+	err = m.Stream(ctx, filter, filters, func(match *Match) error {
+		matches = append(matches, match)
+		return nil
+	})
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return matches, calculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+// matchListQuery returns the query run by GetAll and Stream, along with its arguments.
+func matchListQuery(filter MatchFilter, filters Filters) (string, []interface{}) {
+	args := filter.args()
+
+	query := fmt.Sprintf(`
+        SELECT id, duration, result, match_type, played_date, blue_team, red_team, replay_url, vod_url
+        FROM matches %s
+        ORDER BY %s %s, id ASC
+        LIMIT $%d OFFSET $%d`, matchWhere, filters.sortColumn(), filters.sortDirection(), len(args)+1, len(args)+2)
+
+	return query, append(args, filters.limit(), filters.offset())
+}
+
+// Stream runs the same query as GetAll, but passes each match to fn as it is read from the
+// database instead of collecting them into a slice. Iteration stops at the first error returned
+// by fn.
+func (m MatchModel) Stream(ctx context.Context, filter MatchFilter, filters Filters, fn func(*Match) error) error {
+	query, args := matchListQuery(filter, filters)
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
 	for rows.Next() {
 		var match Match
 		err := rows.Scan(
 			&match.ID,
 			&match.Duration,
 			&match.Result,
+			&match.MatchType,
 			&match.PlayedDate,
 			&match.BlueTeam,
 			&match.RedTeam,
+			&match.ReplayURL,
+			&match.VODURL,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(&match); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetPerformances returns a page of the summoner performances recorded for a match, ordered by
+// team and then by net worth (highest first). Each performance records whether the summoner's
+// profile is private, and who owns it, but the caller decides who may see it.
+func (m MatchModel) GetPerformances(matchID int64, filters Filters) ([]*SummonerMatchPerformance, error) {
+	query := `
+        SELECT mp.match_id, mp.team, s.username, c.name, c.main_role, COALESCE(mp.pick_phase, 0), mp.net_worth,
+            mp.kills, mp.deaths, mp.assists, mp.bought_items, COALESCE(s.user_id, 0), s.is_private
+        FROM match_performance mp
+        JOIN summoners s ON s.id = mp.summoner_id
+        JOIN champions c ON c.id = mp.champion_id
+        WHERE mp.match_id = $1
+        ORDER BY mp.team ASC, mp.net_worth DESC, mp.id ASC
+        LIMIT $2 OFFSET $3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, matchID, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	performances := []*SummonerMatchPerformance{}
+
+	for rows.Next() {
+		var performance SummonerMatchPerformance
+		var boughtItems []byte
+		err := rows.Scan(
+			&performance.MatchID,
+			&performance.Team,
+			&performance.Username,
+			&performance.Champion.Name,
+			&performance.Champion.MainRole,
+			&performance.PickPhase,
+			&performance.NetWorth,
+			&performance.KDA.Kills,
+			&performance.KDA.Deaths,
+			&performance.KDA.Assists,
+			&boughtItems,
+			&performance.UserID,
+			&performance.IsPrivate,
 		)
 		if err != nil {
 			return nil, err
 		}
-		matches = append(matches, &match)
+
+		err = json.Unmarshal(boughtItems, &performance.BoughtItems)
+		if err != nil {
+			return nil, fmt.Errorf("GetPerformances: %v", err)
+		}
+
+		performances = append(performances, &performance)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return matches, nil
+	return performances, nil
+}
+
+// DeleteOlderThan deletes every match played before the given time and returns how many were
+// deleted. When dryRun is true the delete is run inside a transaction which is then rolled back,
+// so the returned count is exactly what would have been deleted but nothing is changed.
+func (m MatchModel) DeleteOlderThan(before time.Time, dryRun bool) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM matches WHERE played_date < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if dryRun {
+		return rowsAffected, tx.Rollback()
+	}
+
+	return rowsAffected, tx.Commit()
 }
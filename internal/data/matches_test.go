@@ -0,0 +1,584 @@
+package data
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func validMatch() *Match {
+	return &Match{
+		PlayedDate: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Duration:   1800,
+		Result:     ResultBlue,
+		MatchType:  MatchTypes[0],
+		BlueTeam:   &Team{},
+		RedTeam:    &Team{},
+	}
+}
+
+func TestValidateMatchResult(t *testing.T) {
+	tests := []struct {
+		result string
+		valid  bool
+	}{
+		{"blue", true},
+		{"red", true},
+		{"remake", true},
+		{"Blue", true},
+		{"RED", true},
+		{"", false},
+		{"draw", false},
+		{"blue team", false},
+	}
+
+	for _, tt := range tests {
+		match := validMatch()
+		match.Result = tt.result
+
+		v := validator.New()
+		ValidateMatch(v, match)
+
+		_, invalid := v.Errors["result"]
+		if invalid == tt.valid {
+			t.Errorf("result %q: got errors %v, want valid=%t", tt.result, v.Errors, tt.valid)
+		}
+	}
+}
+
+func TestValidateMatchPerformances(t *testing.T) {
+	match := validMatch()
+	match.BlueTeam.Summoners = []*SummonerMatchPerformance{
+		{Username: "Faker", Champion: ChampionData{Name: "Ahri"}},
+		{Champion: ChampionData{Name: "Lee Sin"}},
+		nil,
+	}
+	match.RedTeam.Summoners = []*SummonerMatchPerformance{
+		{Username: "Caps"},
+	}
+
+	v := validator.New()
+	ValidateMatch(v, match)
+
+	want := []string{
+		"blue_team.summoners[1].username",
+		"blue_team.summoners[2]",
+		"red_team.summoners[0].champion.name",
+	}
+	for _, key := range want {
+		if _, ok := v.Errors[key]; !ok {
+			t.Errorf("missing error for %s; got %v", key, v.Errors)
+		}
+	}
+	if len(v.Errors) != len(want) {
+		t.Errorf("got %d errors, want %d: %v", len(v.Errors), len(want), v.Errors)
+	}
+}
+
+func TestUpdateMatchStatisticsSkipsRemakes(t *testing.T) {
+	match := validMatch()
+	match.Result = "Remake"
+	match.BlueTeam.Summoners = []*SummonerMatchPerformance{
+		{Team: ResultBlue, Username: "Faker", summonerID: 1, championID: 1},
+	}
+
+	// The model has no database, so any statistics update would panic.
+	var m MatchModel
+	if err := m.UpdateMatchStatistics(match); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckMatchPlausibility(t *testing.T) {
+	limits := MatchPlausibilityLimits{MaxNetWorthPerMinute: 1000}
+
+	tests := []struct {
+		name        string
+		performance SummonerMatchPerformance
+		warn        bool
+	}{
+		{"typical", SummonerMatchPerformance{NetWorth: 12000, KDA: KDA{Kills: 5, Assists: 7}}, false},
+		{"no kills or gold", SummonerMatchPerformance{}, false},
+		{"kills without gold", SummonerMatchPerformance{KDA: KDA{Kills: 3}}, true},
+		{"assists without gold", SummonerMatchPerformance{KDA: KDA{Assists: 3}}, true},
+		{"at the ceiling", SummonerMatchPerformance{NetWorth: 30000}, false},
+		{"above the ceiling", SummonerMatchPerformance{NetWorth: 30001}, true},
+	}
+
+	for _, tt := range tests {
+		match := validMatch()
+		performance := tt.performance
+		match.RedTeam.Summoners = []*SummonerMatchPerformance{nil, &performance}
+
+		v := validator.New()
+		CheckMatchPlausibility(v, match, limits)
+
+		_, warned := v.Warnings["red_team.summoners[1].net_worth"]
+		if warned != tt.warn || len(v.Warnings) > 1 {
+			t.Errorf("%s: got warnings %v, want warning=%t", tt.name, v.Warnings, tt.warn)
+		}
+		if !v.Valid() {
+			t.Errorf("%s: got errors %v, want only warnings", tt.name, v.Errors)
+		}
+	}
+}
+
+func TestCheckMatchPlausibilityWithoutCeiling(t *testing.T) {
+	match := validMatch()
+	match.BlueTeam.Summoners = []*SummonerMatchPerformance{{NetWorth: 1000000}}
+
+	// A zero limit turns the net worth ceiling off.
+	v := validator.New()
+	CheckMatchPlausibility(v, match, MatchPlausibilityLimits{})
+	if v.HasWarnings() {
+		t.Errorf("got warnings %v, want none", v.Warnings)
+	}
+
+	// So does a match without a duration, since the ceiling would be zero.
+	match.Duration = 0
+	v = validator.New()
+	CheckMatchPlausibility(v, match, MatchPlausibilityLimits{MaxNetWorthPerMinute: 1000})
+	if v.HasWarnings() {
+		t.Errorf("got warnings %v for a match without a duration, want none", v.Warnings)
+	}
+}
+
+func TestValidateMatchPlayedDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		playedDate time.Time
+		valid      bool
+	}{
+		{"recent", time.Now().Add(-time.Hour), true},
+		{"on release day", leagueReleaseDate, true},
+		{"within the clock skew", time.Now().Add(playedDateClockSkew / 2), true},
+		{"missing", time.Time{}, false},
+		{"in the future", time.Now().Add(2 * playedDateClockSkew), false},
+		{"before release", leagueReleaseDate.Add(-time.Second), false},
+	}
+
+	for _, tt := range tests {
+		match := validMatch()
+		match.PlayedDate = tt.playedDate
+
+		v := validator.New()
+		ValidateMatch(v, match)
+
+		_, invalid := v.Errors["played_date"]
+		if invalid == tt.valid {
+			t.Errorf("%s: got errors %v, want valid=%t", tt.name, v.Errors, tt.valid)
+		}
+	}
+}
+
+func TestTeamNetWorth(t *testing.T) {
+	tests := []struct {
+		name string
+		team *Team
+		want int
+	}{
+		{"missing team", nil, 0},
+		{"stored total", &Team{TotalNetWorth: 60000, Summoners: []*SummonerMatchPerformance{{NetWorth: 10}}}, 60000},
+		// Older rows have no stored total, so it's summed from the summoners.
+		{"summed", &Team{Summoners: []*SummonerMatchPerformance{{NetWorth: 12000}, nil, {NetWorth: 9000}}}, 21000},
+	}
+
+	for _, tt := range tests {
+		if got := tt.team.NetWorth(); got != tt.want {
+			t.Errorf("%s: got net worth %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchGoldAndNetWorthDiff(t *testing.T) {
+	match := validMatch()
+	match.BlueTeam = &Team{TotalGold: 50000, TotalNetWorth: 55000}
+	match.RedTeam = &Team{TotalGold: 52000, Summoners: []*SummonerMatchPerformance{{NetWorth: 50000}}}
+
+	if got := match.GoldDiff(); got != -2000 {
+		t.Errorf("got gold diff %d, want -2000", got)
+	}
+	if got := match.NetWorthDiff(); got != 5000 {
+		t.Errorf("got net worth diff %d, want 5000", got)
+	}
+
+	match.RedTeam = nil
+	if got := match.GoldDiff(); got != 0 {
+		t.Errorf("got gold diff %d without a red team, want 0", got)
+	}
+	if got := match.NetWorthDiff(); got != 55000 {
+		t.Errorf("got net worth diff %d without a red team, want 55000", got)
+	}
+}
+
+func TestValidateMatchTeamTotals(t *testing.T) {
+	match := validMatch()
+	match.BlueTeam.TotalNetWorth = -1
+	match.RedTeam.TotalGold = -1
+
+	v := validator.New()
+	ValidateMatch(v, match)
+
+	for _, key := range []string{"blue_team.total_net_worth", "red_team.total_gold"} {
+		if _, ok := v.Errors[key]; !ok {
+			t.Errorf("missing error for %s; got %v", key, v.Errors)
+		}
+	}
+}
+
+func TestValidateMatchTeamSize(t *testing.T) {
+	newTeam := func(summoners, bans int) *Team {
+		team := &Team{}
+		for i := 0; i < summoners; i++ {
+			team.Summoners = append(team.Summoners, &SummonerMatchPerformance{Username: "Faker", Champion: ChampionData{Name: "Ahri"}})
+		}
+		for i := 0; i < bans; i++ {
+			team.BannedChampions = append(team.BannedChampions, Champion{Name: "Zed"})
+		}
+		return team
+	}
+
+	match := validMatch()
+	match.BlueTeam = newTeam(MaxTeamSummoners, MaxTeamBans)
+	match.RedTeam = newTeam(MaxTeamSummoners, MaxTeamBans)
+
+	v := validator.New()
+	if ValidateMatch(v, match); !v.Valid() {
+		t.Errorf("got errors %v for full teams", v.Errors)
+	}
+
+	match.BlueTeam = newTeam(MaxTeamSummoners+1, 0)
+	match.RedTeam = newTeam(0, MaxTeamBans+1)
+
+	v = validator.New()
+	ValidateMatch(v, match)
+
+	for _, key := range []string{"blue_team.summoners", "red_team.banned_champions"} {
+		if _, ok := v.Errors[key]; !ok {
+			t.Errorf("missing error for %s; got %v", key, v.Errors)
+		}
+	}
+}
+
+func TestValidateMatchV5Participants(t *testing.T) {
+	doc := &MatchV5{}
+	doc.Info.Participants = make([]MatchV5Participant, 2*MaxTeamSummoners+1)
+
+	v := validator.New()
+	ValidateMatchV5(v, doc)
+
+	if _, ok := v.Errors["info.participants"]; !ok {
+		t.Errorf("got no error for %d participants; got %v", len(doc.Info.Participants), v.Errors)
+	}
+}
+
+func TestGetAllMatchesByObjectives(t *testing.T) {
+	models := newTestModels(t)
+
+	insert := func(blueDragons, redDragons, barons int) int64 {
+		match := validMatch()
+		match.BlueTeam.DragonsKilled = blueDragons
+		match.RedTeam.DragonsKilled = redDragons
+		match.RedTeam.BaronNashorsKilled = barons
+		if err := models.Matches.Insert(match); err != nil {
+			t.Fatal(err)
+		}
+		return match.ID
+	}
+
+	both := insert(3, 1, 2)
+	dragons := insert(2, 2, 0)
+	insert(1, 1, 1)
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}}
+
+	tests := []struct {
+		name   string
+		filter MatchFilter
+		want   []int64
+	}{
+		// Dragons are counted over both teams.
+		{"min dragons", MatchFilter{MinDragons: 4}, []int64{both, dragons}},
+		{"min dragons and barons", MatchFilter{MinDragons: 4, MinBarons: 1}, []int64{both}},
+		{"unmet", MatchFilter{MinDragons: 5}, nil},
+	}
+
+	for _, tt := range tests {
+		matches, metadata, err := models.Matches.GetAll(tt.filter, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []int64
+		for _, match := range matches {
+			got = append(got, match.ID)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) || metadata.TotalRecords != len(tt.want) {
+			t.Errorf("%s: got matches %v (%d in total), want %v", tt.name, got, metadata.TotalRecords, tt.want)
+		}
+	}
+}
+
+func TestValidateMatchLinks(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"", true},
+		{"https://www.youtube.com/watch?v=abc", true},
+		{"http://replays.example.com/match/1.rofl", true},
+		{"www.youtube.com/watch?v=abc", false},
+		{"ftp://replays.example.com/1.rofl", false},
+		{"https://", false},
+		{"https://example.com/%zz", false},
+		{"https://example.com/" + strings.Repeat("a", 2048), false},
+	}
+
+	for _, tt := range tests {
+		match := validMatch()
+		match.ReplayURL = tt.url
+		match.VODURL = tt.url
+
+		v := validator.New()
+		ValidateMatch(v, match)
+
+		for _, key := range []string{"replay_url", "vod_url"} {
+			if _, invalid := v.Errors[key]; invalid == tt.valid {
+				t.Errorf("%s %q: got errors %v, want valid=%t", key, tt.url, v.Errors, tt.valid)
+			}
+		}
+	}
+}
+
+func TestGetAllMatchesHasVOD(t *testing.T) {
+	models := newTestModels(t)
+
+	withVOD := validMatch()
+	withVOD.VODURL = "https://www.youtube.com/watch?v=abc"
+	// A replay alone doesn't count as a VOD.
+	withReplay := validMatch()
+	withReplay.ReplayURL = "https://replays.example.com/1.rofl"
+
+	for _, match := range []*Match{withVOD, withReplay, validMatch()} {
+		if err := models.Matches.Insert(match); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}}
+
+	matches, metadata, err := models.Matches.GetAll(MatchFilter{HasVOD: true}, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 1 || matches[0].ID != withVOD.ID || metadata.TotalRecords != 1 {
+		t.Fatalf("got %d matches (%d in total), want only match %d", len(matches), metadata.TotalRecords, withVOD.ID)
+	}
+	if matches[0].VODURL != withVOD.VODURL {
+		t.Errorf("got VOD URL %q, want %q", matches[0].VODURL, withVOD.VODURL)
+	}
+
+	all, _, err := models.Matches.GetAll(MatchFilter{}, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("got %d matches without the filter, want 3", len(all))
+	}
+}
+
+func TestGetAllMatchesBySummonerOutcome(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := insertTestSummoner(t, models, "Faker")
+	caps := insertTestSummoner(t, models, "Caps")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	// Faker plays on the blue team, so a blue result is a win and a red one a loss.
+	for _, result := range []string{ResultBlue, ResultBlue, ResultRed, ResultRemake} {
+		insertTestPerformance(t, models, faker.ID, champion.ID, MatchTypes[0], result)
+	}
+	insertTestPerformance(t, models, caps.ID, champion.ID, MatchTypes[0], ResultRed)
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}}
+
+	tests := []struct {
+		outcome string
+		want    []string
+	}{
+		{"", []string{ResultBlue, ResultBlue, ResultRed, ResultRemake}},
+		{OutcomeWin, []string{ResultBlue, ResultBlue}},
+		// A remake is neither a win nor a loss.
+		{OutcomeLoss, []string{ResultRed}},
+	}
+
+	for _, tt := range tests {
+		matches, metadata, err := models.Matches.GetAll(MatchFilter{SummonerID: faker.ID, Outcome: tt.outcome}, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, match := range matches {
+			got = append(got, match.Result)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) || metadata.TotalRecords != len(tt.want) {
+			t.Errorf("outcome %q: got results %v (%d in total), want %v", tt.outcome, got, metadata.TotalRecords, tt.want)
+		}
+	}
+}
+
+func TestGetPerformancesOrderAndPaging(t *testing.T) {
+	models := newTestModels(t)
+
+	insertTestChampion(t, models, "Ahri")
+
+	match := validMatch()
+	for _, p := range []struct {
+		team     *Team
+		username string
+		netWorth int
+	}{
+		{match.RedTeam, "Chovy", 14000},
+		{match.BlueTeam, "Faker", 12000},
+		{match.BlueTeam, "Keria", 7000},
+		{match.RedTeam, "Peyz", 15000},
+		{match.BlueTeam, "Gumayusi", 13000},
+	} {
+		insertTestSummoner(t, models, p.username)
+		p.team.Summoners = append(p.team.Summoners, &SummonerMatchPerformance{
+			Username: p.username, Champion: ChampionData{Name: "Ahri"}, NetWorth: p.netWorth, BoughtItems: []string{},
+		})
+	}
+
+	if err := models.Matches.Insert(match); err != nil {
+		t.Fatal(err)
+	}
+	v := validator.New()
+	if err := models.Matches.InsertPerformances(v, match, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Blue comes before red, and within a team the richest summoner comes first.
+	tests := []struct {
+		page int
+		want []string
+	}{
+		{1, []string{"blue:Gumayusi", "blue:Faker"}},
+		{2, []string{"blue:Keria", "red:Peyz"}},
+		{3, []string{"red:Chovy"}},
+		{4, nil},
+	}
+
+	for _, tt := range tests {
+		filters := Filters{Page: tt.page, PageSize: 2, Sort: "team", SortSafelist: []string{"team"}}
+
+		performances, err := models.Matches.GetPerformances(match.ID, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, p := range performances {
+			got = append(got, p.Team+":"+p.Username)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("page %d: got %v, want %v", tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestInsertPerformancesUnknownParticipants(t *testing.T) {
+	models := newTestModels(t)
+
+	insertTestSummoner(t, models, "Faker")
+	insertTestChampion(t, models, "Ahri")
+
+	match := validMatch()
+	match.BlueTeam.Summoners = []*SummonerMatchPerformance{
+		{Username: "faker", Champion: ChampionData{Name: "ahri"}},
+		{Username: "Nobody", Champion: ChampionData{Name: "Ahri"}},
+	}
+	match.RedTeam.Summoners = []*SummonerMatchPerformance{
+		{Username: "Faker", Champion: ChampionData{Name: "Nobody"}},
+	}
+
+	if err := models.Matches.Insert(match); err != nil {
+		t.Fatal(err)
+	}
+
+	// Participants who can't be matched are left out with a warning, rather than failing the
+	// whole match.
+	v := validator.New()
+	if err := models.Matches.InsertPerformances(v, match, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.Valid() {
+		t.Errorf("got errors %v, want none", v.Errors)
+	}
+	for _, key := range []string{"blue_team.summoners[1].username", "red_team.summoners[0].champion.name"} {
+		if _, ok := v.Warnings[key]; !ok {
+			t.Errorf("got warnings %v, want one for %s", v.Warnings, key)
+		}
+	}
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "team", SortSafelist: []string{"team"}}
+	performances, err := models.Matches.GetPerformances(match.ID, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(performances) != 1 || performances[0].Username != "Faker" || performances[0].Champion.Name != "Ahri" {
+		t.Errorf("got %d performances, want only Faker's on Ahri", len(performances))
+	}
+}
+
+func TestDeleteOlderThan(t *testing.T) {
+	models := newTestModels(t)
+
+	for _, played := range []time.Time{
+		time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		match := validMatch()
+		match.PlayedDate = played
+		if err := models.Matches.Insert(match); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	countMatches := func() int {
+		var count int
+		if err := models.Matches.DB.QueryRow(`SELECT count(*) FROM matches`).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	before := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// A dry run reports what would go without deleting anything.
+	deleted, err := models.Matches.DeleteOlderThan(before, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 || countMatches() != 3 {
+		t.Errorf("dry run: got %d deleted and %d left, want 2 and 3", deleted, countMatches())
+	}
+
+	deleted, err = models.Matches.DeleteOlderThan(before, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 || countMatches() != 1 {
+		t.Errorf("got %d deleted and %d left, want 2 and 1", deleted, countMatches())
+	}
+}
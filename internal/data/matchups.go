@@ -0,0 +1,218 @@
+package data
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Matchup is one champion's head-to-head record against another, from the first champion's point
+// of view. Reliable is false when there are too few games for the win rate to mean much, so that
+// clients don't present e.g. a 100% win rate from two games as meaningful.
+type Matchup struct {
+	ChampionID int64   `json:"championId"`
+	OpponentID int64   `json:"opponentId"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	WinRate    float64 `json:"winRate"`
+	LowerBound float64 `json:"winRateLowerBound"` // Lower bound of the 95% Wilson score interval
+	UpperBound float64 `json:"winRateUpperBound"` // Upper bound of the 95% Wilson score interval
+	Reliable   bool    `json:"reliable"`
+}
+
+// wilsonZ is the z-score for a 95% confidence interval.
+const wilsonZ = 1.96
+
+// WilsonInterval returns the Wilson score interval for a win rate of wins out of games at the
+// given z-score. Unlike the naive interval, it behaves sensibly for small samples and for win
+// rates close to 0 or 1. With no games the interval is the whole range [0, 1].
+func WilsonInterval(wins, games int, z float64) (lower, upper float64) {
+	if games == 0 {
+		return 0, 1
+	}
+
+	n := float64(games)
+	p := float64(wins) / n
+	z2 := z * z
+
+	centre := p + z2/(2*n)
+	margin := z * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	denominator := 1 + z2/n
+
+	return math.Max(0, (centre-margin)/denominator), math.Min(1, (centre+margin)/denominator)
+}
+
+// SetReliability fills in the matchup's win rate, Wilson interval and Reliable flag from its
+// Games and Wins. A matchup is reliable once it has at least minGames games.
+func (m *Matchup) SetReliability(minGames int) {
+	if m.Games > 0 {
+		m.WinRate = float64(m.Wins) / float64(m.Games)
+	}
+	m.LowerBound, m.UpperBound = WilsonInterval(m.Wins, m.Games, wilsonZ)
+	m.Reliable = m.Games >= minGames
+}
+
+// GetMatchup returns the head-to-head record of a champion against an opponent on the enemy
+// team. Remakes are excluded, as are matches outside scope; the rank tier filter applies to the
+// first champion's player.
+func (c ChampionModel) GetMatchup(championID, opponentID int64, minGames int, scope StatsScope) (*Matchup, error) {
+	query := `
+        SELECT count(*), count(*) FILTER (WHERE LOWER(m.result) = a.team)
+        FROM match_performance a
+        JOIN match_performance b ON b.match_id = a.match_id AND b.team <> a.team
+        JOIN matches m ON m.id = a.match_id
+        WHERE a.champion_id = $1 AND b.champion_id = $2 AND LOWER(m.result) <> $3
+        AND (m.match_type = $4 OR $4 = '')
+        AND (cardinality($5::text[]) = 0 OR a.rank_tier = ANY($5))`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	matchup := &Matchup{ChampionID: championID, OpponentID: opponentID}
+
+	err := c.DB.QueryRowContext(ctx, query, championID, opponentID, ResultRemake, scope.MatchType, pq.Array(scope.Tiers)).Scan(&matchup.Games, &matchup.Wins)
+	if err != nil {
+		return nil, err
+	}
+
+	matchup.SetReliability(minGames)
+
+	return matchup, nil
+}
+
+// RoleMatchup is a champion's record in games where the enemy team included at least one
+// champion whose main role is Role, e.g. how a mid laner fares when the enemy has a Support
+// champion in any position. A game counts once per role however many such enemies there were.
+type RoleMatchup struct {
+	Role       string  `json:"role"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	WinRate    float64 `json:"winRate"`
+	LowerBound float64 `json:"winRateLowerBound"` // Lower bound of the 95% Wilson score interval
+	UpperBound float64 `json:"winRateUpperBound"` // Upper bound of the 95% Wilson score interval
+	Reliable   bool    `json:"reliable"`
+}
+
+// GetRoleMatchups returns the champion's record against each enemy role archetype, ordered by
+// role. Remakes are excluded, as are matches outside scope; the rank tier filter applies to the
+// champion's player.
+func (c ChampionModel) GetRoleMatchups(championID int64, minGames int, scope StatsScope) ([]*RoleMatchup, error) {
+	query := `
+        SELECT e.main_role, count(DISTINCT a.id), count(DISTINCT a.id) FILTER (WHERE LOWER(m.result) = a.team)
+        FROM match_performance a
+        JOIN match_performance b ON b.match_id = a.match_id AND b.team <> a.team
+        JOIN champions e ON e.id = b.champion_id
+        JOIN matches m ON m.id = a.match_id
+        WHERE a.champion_id = $1 AND LOWER(m.result) <> $2
+        AND (m.match_type = $3 OR $3 = '')
+        AND (cardinality($4::text[]) = 0 OR a.rank_tier = ANY($4))
+        GROUP BY e.main_role
+        ORDER BY e.main_role ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, championID, ResultRemake, scope.MatchType, pq.Array(scope.Tiers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matchups := []*RoleMatchup{}
+
+	for rows.Next() {
+		var rm RoleMatchup
+		err := rows.Scan(&rm.Role, &rm.Games, &rm.Wins)
+		if err != nil {
+			return nil, err
+		}
+
+		// Reuse the head-to-head reliability rules so both endpoints agree on what's meaningful.
+		m := Matchup{Games: rm.Games, Wins: rm.Wins}
+		m.SetReliability(minGames)
+		rm.WinRate, rm.LowerBound, rm.UpperBound, rm.Reliable = m.WinRate, m.LowerBound, m.UpperBound, m.Reliable
+
+		matchups = append(matchups, &rm)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return matchups, nil
+}
+
+// LanePhaseEnd is the game time, in seconds, at which the lane phase is taken to be over.
+const LanePhaseEnd = 15 * 60
+
+// laneSnapshotWindow is how long before LanePhaseEnd a team_stats snapshot may have been taken
+// and still count as the team's gold at the end of the lane phase.
+const laneSnapshotWindow = 2 * 60
+
+// LaneMatchup splits a champion's head-to-head record against an opponent into the lane phase and
+// the full game, as a champion may win lane and still lose the game, or vice versa. The lane
+// counts as won if the champion's team led in gold at LanePhaseEnd. Only team gold is recorded
+// (see EventTeamStats), so this is the team's early lead rather than that of the lane alone.
+type LaneMatchup struct {
+	ChampionID  int64   `json:"championId"`
+	OpponentID  int64   `json:"opponentId"`
+	Games       int     `json:"games"`
+	Wins        int     `json:"wins"`
+	WinRate     float64 `json:"winRate"`
+	LaneGames   int     `json:"laneGames"` // Games with a gold snapshot for both teams at the end of the lane phase
+	LaneWins    int     `json:"laneWins"`  // Of LaneGames, those in which the champion's team led in gold
+	LaneWinRate float64 `json:"laneWinRate"`
+}
+
+// GetLaneMatchup returns the lane-phase and full-game records of a champion against an opponent
+// on the enemy team. Matches are filtered as by GetMatchup. A match only counts towards the lane
+// record if it lasted beyond the lane phase and both teams have a team_stats snapshot in the two
+// minutes leading up to its end.
+func (c ChampionModel) GetLaneMatchup(championID, opponentID int64, scope StatsScope) (*LaneMatchup, error) {
+	query := `
+        WITH games AS (
+            SELECT a.match_id, a.team, LOWER(m.result) = a.team AS won, m.duration >= $6 AS laned
+            FROM match_performance a
+            JOIN match_performance b ON b.match_id = a.match_id AND b.team <> a.team
+            JOIN matches m ON m.id = a.match_id
+            WHERE a.champion_id = $1 AND b.champion_id = $2 AND LOWER(m.result) <> $3
+            AND (m.match_type = $4 OR $4 = '')
+            AND (cardinality($5::text[]) = 0 OR a.rank_tier = ANY($5))
+        ), gold AS (
+            SELECT DISTINCT ON (e.match_id, e.team) e.match_id, e.team, e.gold
+            FROM match_events e
+            WHERE e.type = $7 AND e.game_time BETWEEN $6 - $8 AND $6
+            AND e.match_id IN (SELECT match_id FROM games)
+            ORDER BY e.match_id, e.team, e.game_time DESC, e.id DESC
+        )
+        SELECT count(*),
+            count(*) FILTER (WHERE g.won),
+            count(*) FILTER (WHERE g.laned AND own.gold IS NOT NULL AND enemy.gold IS NOT NULL),
+            count(*) FILTER (WHERE g.laned AND own.gold > enemy.gold)
+        FROM games g
+        LEFT JOIN gold own ON own.match_id = g.match_id AND own.team = g.team
+        LEFT JOIN gold enemy ON enemy.match_id = g.match_id AND enemy.team <> g.team`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	matchup := &LaneMatchup{ChampionID: championID, OpponentID: opponentID}
+
+	args := []interface{}{championID, opponentID, ResultRemake, scope.MatchType, pq.Array(scope.Tiers), LanePhaseEnd, EventTeamStats, laneSnapshotWindow}
+
+	err := c.DB.QueryRowContext(ctx, query, args...).Scan(&matchup.Games, &matchup.Wins, &matchup.LaneGames, &matchup.LaneWins)
+	if err != nil {
+		return nil, err
+	}
+
+	if matchup.Games > 0 {
+		matchup.WinRate = float64(matchup.Wins) / float64(matchup.Games)
+	}
+	if matchup.LaneGames > 0 {
+		matchup.LaneWinRate = float64(matchup.LaneWins) / float64(matchup.LaneGames)
+	}
+
+	return matchup, nil
+}
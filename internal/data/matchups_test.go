@@ -0,0 +1,196 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		wins, games  int
+		lower, upper float64
+	}{
+		{0, 0, 0, 1},
+		{5, 10, 0.2366, 0.7634},
+		{0, 10, 0, 0.2775},
+		{10, 10, 0.7225, 1},
+		{550, 1000, 0.5190, 0.5806},
+	}
+
+	for _, tt := range tests {
+		lower, upper := WilsonInterval(tt.wins, tt.games, wilsonZ)
+		if math.Abs(lower-tt.lower) > 1e-4 || math.Abs(upper-tt.upper) > 1e-4 {
+			t.Errorf("WilsonInterval(%d, %d) = [%.4f, %.4f], want [%.4f, %.4f]", tt.wins, tt.games, lower, upper, tt.lower, tt.upper)
+		}
+	}
+}
+
+func TestMatchupSetReliability(t *testing.T) {
+	matchup := &Matchup{Games: 2, Wins: 2}
+	matchup.SetReliability(20)
+
+	// A perfect record from two games is flagged as unreliable, and its interval stays wide.
+	if matchup.WinRate != 1 || matchup.Reliable {
+		t.Errorf("got win rate %v and reliable %t, want 1 and false", matchup.WinRate, matchup.Reliable)
+	}
+	if matchup.LowerBound > 0.5 || matchup.UpperBound != 1 {
+		t.Errorf("got interval [%v, %v], want a wide one", matchup.LowerBound, matchup.UpperBound)
+	}
+
+	matchup = &Matchup{Games: 20, Wins: 11}
+	matchup.SetReliability(20)
+	if matchup.WinRate != 0.55 || !matchup.Reliable {
+		t.Errorf("got win rate %v and reliable %t, want 0.55 and true", matchup.WinRate, matchup.Reliable)
+	}
+
+	matchup = &Matchup{}
+	matchup.SetReliability(0)
+	if matchup.WinRate != 0 || matchup.LowerBound != 0 || matchup.UpperBound != 1 {
+		t.Errorf("got %+v for a matchup without games", matchup)
+	}
+}
+
+func TestGetLaneMatchup(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := insertTestSummoner(t, models, "Faker")
+	caps := insertTestSummoner(t, models, "Caps")
+	ahri := insertTestChampion(t, models, "Ahri")
+	zed := insertTestChampion(t, models, "Zed")
+
+	// Ahri plays for the blue team against Zed on the red team. blueGold and redGold are the teams'
+	// gold in team_stats snapshots at snapshotAt; there are no snapshots if snapshotAt is zero.
+	play := func(result string, snapshotAt, blueGold, redGold int) {
+		match := validMatch()
+		match.Result = result
+		if err := models.Matches.Insert(match); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := models.Matches.DB.Exec(`
+            INSERT INTO match_performance (match_id, summoner_id, champion_id, team)
+            VALUES ($1, $2, $3, $4), ($1, $5, $6, $7)`,
+			match.ID, faker.ID, ahri.ID, ResultBlue, caps.ID, zed.ID, ResultRed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if snapshotAt == 0 {
+			return
+		}
+		err = models.Matches.InsertEvents(match.ID, []*MatchEvent{
+			{Timestamp: snapshotAt, Type: EventTeamStats, Team: ResultBlue, Gold: blueGold},
+			{Timestamp: snapshotAt, Type: EventTeamStats, Team: ResultRed, Gold: redGold},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	play(ResultRed, LanePhaseEnd, 25000, 23000)     // Won lane, lost the game
+	play(ResultBlue, LanePhaseEnd, 26000, 22000)    // Won lane and the game
+	play(ResultBlue, LanePhaseEnd-60, 22000, 24000) // Lost lane, won the game
+	play(ResultBlue, 0, 0, 0)                       // No timeline, so only the game counts
+	play(ResultRed, 10*60, 15000, 14000)            // Snapshot too early to count for the lane
+	play(ResultRemake, LanePhaseEnd, 25000, 23000)  // Remakes don't count at all
+
+	got, err := models.Champions.GetLaneMatchup(ahri.ID, zed.ID, StatsScope{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := LaneMatchup{
+		ChampionID: ahri.ID, OpponentID: zed.ID,
+		Games: 5, Wins: 3, WinRate: 0.6,
+		LaneGames: 3, LaneWins: 2, LaneWinRate: 2.0 / 3,
+	}
+	if *got != want {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+}
+
+func TestGetRoleMatchups(t *testing.T) {
+	models := newTestModels(t)
+
+	champions := map[string]*Champion{}
+	for name, role := range map[string]string{"Ahri": "Mid", "Leona": "Support", "Thresh": "Support", "Garen": "Top"} {
+		champion := &Champion{Name: name, MainRole: role, Classes: ChampionClasses{}}
+		if err := models.Champions.Insert(champion); err != nil {
+			t.Fatal(err)
+		}
+		champions[name] = champion
+	}
+
+	summoners := []*Summoner{
+		insertTestSummoner(t, models, "Faker"),
+		insertTestSummoner(t, models, "Caps"),
+		insertTestSummoner(t, models, "Chovy"),
+	}
+
+	type pick struct {
+		champion, team string
+	}
+
+	// Ahri plays for the blue team, with an ally if given, against the enemies on the red team.
+	play := func(result, ally string, enemies ...string) {
+		t.Helper()
+
+		match := validMatch()
+		match.Result = result
+		if err := models.Matches.Insert(match); err != nil {
+			t.Fatal(err)
+		}
+
+		picks := []pick{{"Ahri", ResultBlue}}
+		if ally != "" {
+			picks = append(picks, pick{ally, ResultBlue})
+		}
+		for _, enemy := range enemies {
+			picks = append(picks, pick{enemy, ResultRed})
+		}
+
+		for i, p := range picks {
+			_, err := models.Matches.DB.Exec(`
+                INSERT INTO match_performance (match_id, summoner_id, champion_id, team)
+                VALUES ($1, $2, $3, $4)`, match.ID, summoners[i].ID, champions[p.champion].ID, p.team)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	play(ResultBlue, "", "Leona", "Thresh") // Two enemy supports still count as one game
+	play(ResultRed, "", "Garen", "Leona")
+	play(ResultBlue, "Leona", "Garen") // An allied support doesn't count
+	play(ResultBlue, "", "Garen")
+	play(ResultRemake, "", "Leona") // Remakes don't count
+
+	got, err := models.Champions.GetRoleMatchups(champions["Ahri"].ID, 3, StatsScope{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		role     string
+		games    int
+		wins     int
+		reliable bool
+	}{
+		{"Support", 2, 1, false},
+		{"Top", 3, 2, true},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d roles, want %d", len(got), len(want))
+	}
+	for i, rm := range got {
+		w := want[i]
+		if rm.Role != w.role || rm.Games != w.games || rm.Wins != w.wins || rm.Reliable != w.reliable {
+			t.Errorf("got %s: %d/%d won, reliable %t; want %s: %d/%d won, reliable %t",
+				rm.Role, rm.Wins, rm.Games, rm.Reliable, w.role, w.wins, w.games, w.reliable)
+		}
+		if math.Abs(rm.WinRate-float64(w.wins)/float64(w.games)) > 1e-9 {
+			t.Errorf("%s: got win rate %v, want %v", rm.Role, rm.WinRate, float64(w.wins)/float64(w.games))
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// MatchV5 is the subset of the Riot match-v5 match document which we import. Fields we don't use
+// are ignored when decoding.
+type MatchV5 struct {
+	Metadata struct {
+		MatchID string `json:"matchId"`
+	} `json:"metadata"`
+	Info struct {
+		GameCreation       int64                `json:"gameCreation"`
+		GameStartTimestamp int64                `json:"gameStartTimestamp"`
+		GameEndTimestamp   int64                `json:"gameEndTimestamp"`
+		GameDuration       int64                `json:"gameDuration"`
+		PlatformID         string               `json:"platformId"`
+		Participants       []MatchV5Participant `json:"participants"`
+		Teams              []MatchV5Team        `json:"teams"`
+	} `json:"info"`
+}
+
+type MatchV5Participant struct {
+	SummonerName              string `json:"summonerName"`
+	RiotIDGameName            string `json:"riotIdGameName"`
+	ChampionName              string `json:"championName"`
+	TeamID                    int    `json:"teamId"`
+	TeamPosition              string `json:"teamPosition"`
+	Kills                     int    `json:"kills"`
+	Deaths                    int    `json:"deaths"`
+	Assists                   int    `json:"assists"`
+	GoldEarned                int    `json:"goldEarned"`
+	Item0                     int    `json:"item0"`
+	Item1                     int    `json:"item1"`
+	Item2                     int    `json:"item2"`
+	Item3                     int    `json:"item3"`
+	Item4                     int    `json:"item4"`
+	Item5                     int    `json:"item5"`
+	Item6                     int    `json:"item6"`
+	GameEndedInEarlySurrender bool   `json:"gameEndedInEarlySurrender"`
+}
+
+// username returns the participant's Riot ID game name, falling back to the summoner name used
+// by documents from before Riot IDs.
+func (p *MatchV5Participant) username() string {
+	if p.RiotIDGameName != "" {
+		return NormalizeName(p.RiotIDGameName)
+	}
+	return NormalizeName(p.SummonerName)
+}
+
+type MatchV5Team struct {
+	TeamID     int  `json:"teamId"`
+	Win        bool `json:"win"`
+	Objectives struct {
+		Baron      MatchV5Objective `json:"baron"`
+		Champion   MatchV5Objective `json:"champion"`
+		Dragon     MatchV5Objective `json:"dragon"`
+		Inhibitor  MatchV5Objective `json:"inhibitor"`
+		RiftHerald MatchV5Objective `json:"riftHerald"`
+		Tower      MatchV5Objective `json:"tower"`
+	} `json:"objectives"`
+}
+
+type MatchV5Objective struct {
+	First bool `json:"first"`
+	Kills int  `json:"kills"`
+}
+
+// Riot's team ids for the two sides of the map.
+const (
+	matchV5BlueTeam = 100
+	matchV5RedTeam  = 200
+)
+
+// matchV5Regions maps Riot platform ids to our region codes.
+var matchV5Regions = map[string]string{
+	"BR1": "BR", "EUN1": "EUNE", "EUW1": "EUW", "JP1": "JP", "KR": "KR", "LA1": "LAN", "LA2": "LAS",
+	"ME1": "ME", "NA1": "NA", "OC1": "OCE", "PH2": "PH", "RU": "RU", "SG2": "SG", "TH2": "TH",
+	"TR1": "TR", "TW2": "TW", "VN2": "VN",
+}
+
+// matchV5Roles maps match-v5 team positions to our roles.
+var matchV5Roles = map[string]string{
+	"TOP":     "Top",
+	"JUNGLE":  "Jungle",
+	"MIDDLE":  "Mid",
+	"BOTTOM":  "Bot",
+	"UTILITY": "Support",
+}
+
+// Region returns our region code for the document's platform, or "" if we don't know it.
+func (doc *MatchV5) Region() string {
+	return matchV5Regions[strings.ToUpper(doc.Info.PlatformID)]
+}
+
+// ValidateMatchV5 checks that a match-v5 document has everything we need to map it to a Match.
+// Errors are keyed by the path of the offending field in the document.
+func ValidateMatchV5(v *validator.Validator, doc *MatchV5) {
+	v.Check(doc.Info.PlatformID != "", "info.platformId", "must be provided")
+	v.Check(doc.Info.PlatformID == "" || doc.Region() != "", "info.platformId", "must be a known platform")
+	v.Check(doc.Info.GameStartTimestamp > 0 || doc.Info.GameCreation > 0, "info.gameStartTimestamp", "must be provided")
+	v.Check(doc.Info.GameDuration > 0, "info.gameDuration", "must be greater than zero")
+	v.Check(len(doc.Info.Participants) > 0, "info.participants", "must contain at least one participant")
+	v.Check(len(doc.Info.Participants) <= 2*MaxTeamSummoners, "info.participants", fmt.Sprintf("must not contain more than %d participants", 2*MaxTeamSummoners))
+	v.Check(len(doc.Info.Teams) == 2, "info.teams", "must contain both teams")
+
+	for i, team := range doc.Info.Teams {
+		v.Check(team.TeamID == matchV5BlueTeam || team.TeamID == matchV5RedTeam, fmt.Sprintf("info.teams[%d].teamId", i), "must be 100 or 200")
+	}
+
+	region := doc.Region()
+
+	for i, p := range doc.Info.Participants {
+		path := fmt.Sprintf("info.participants[%d]", i)
+
+		v.Check(p.TeamID == matchV5BlueTeam || p.TeamID == matchV5RedTeam, path+".teamId", "must be 100 or 200")
+		v.Check(NormalizeName(p.ChampionName) != "", path+".championName", "must be provided")
+		v.Check(p.TeamPosition == "" || matchV5Roles[p.TeamPosition] != "", path+".teamPosition", "must be TOP, JUNGLE, MIDDLE, BOTTOM or UTILITY")
+		v.Check(p.Kills >= 0 && p.Deaths >= 0 && p.Assists >= 0, path, "must not have negative kills, deaths or assists")
+
+		// Participants become summoners, so their names must follow the same rules as summoners
+		// created through the API.
+		sv := validator.New()
+		ValidateSummoner(sv, &Summoner{Username: p.username(), Region: region})
+		for _, message := range sv.Errors {
+			v.AddError(path+".riotIdGameName", message)
+		}
+	}
+}
+
+// ToMatch maps the document to a Match of the given type. Bans are not mapped, since match-v5
+// only identifies banned champions by Riot's champion id, which we don't store.
+func (doc *MatchV5) ToMatch(matchType string) *Match {
+	start := doc.Info.GameStartTimestamp
+	if start == 0 {
+		start = doc.Info.GameCreation
+	}
+
+	// Documents from before gameEndTimestamp was added record gameDuration in milliseconds.
+	duration := doc.Info.GameDuration
+	if doc.Info.GameEndTimestamp == 0 {
+		duration /= 1000
+	}
+
+	match := &Match{
+		PlayedDate: time.UnixMilli(start).UTC(),
+		Duration:   int(duration),
+		MatchType:  matchType,
+		BlueTeam:   &Team{},
+		RedTeam:    &Team{},
+	}
+
+	teams := map[int]*Team{matchV5BlueTeam: match.BlueTeam, matchV5RedTeam: match.RedTeam}
+	sides := map[int]string{matchV5BlueTeam: ResultBlue, matchV5RedTeam: ResultRed}
+
+	remake := false
+
+	for i := range doc.Info.Participants {
+		p := &doc.Info.Participants[i]
+		team := teams[p.TeamID]
+		if team == nil {
+			continue
+		}
+
+		var items []string
+		for _, item := range []int{p.Item0, p.Item1, p.Item2, p.Item3, p.Item4, p.Item5, p.Item6} {
+			if item != 0 {
+				items = append(items, strconv.Itoa(item))
+			}
+		}
+
+		kda := KDA{Kills: p.Kills, Deaths: p.Deaths, Assists: p.Assists}
+
+		team.Summoners = append(team.Summoners, &SummonerMatchPerformance{
+			Team:        sides[p.TeamID],
+			Role:        matchV5Roles[p.TeamPosition],
+			Username:    p.username(),
+			Champion:    ChampionData{Name: NormalizeName(p.ChampionName), MainRole: matchV5Roles[p.TeamPosition]},
+			NetWorth:    p.GoldEarned,
+			KDA:         kda,
+			BoughtItems: items,
+		})
+
+		team.TeamKDA.Kills += kda.Kills
+		team.TeamKDA.Deaths += kda.Deaths
+		team.TeamKDA.Assists += kda.Assists
+		team.TotalGold += p.GoldEarned
+
+		remake = remake || p.GameEndedInEarlySurrender
+	}
+
+	for _, t := range doc.Info.Teams {
+		team := teams[t.TeamID]
+		if team == nil {
+			continue
+		}
+
+		team.TurretsDestroyed = t.Objectives.Tower.Kills
+		team.InhibitorsDestroyed = t.Objectives.Inhibitor.Kills
+		team.RiftHeraldsKilled = t.Objectives.RiftHerald.Kills
+		team.DragonsKilled = t.Objectives.Dragon.Kills
+		team.BaronNashorsKilled = t.Objectives.Baron.Kills
+		team.FirstBlood = t.Objectives.Champion.First
+		team.FirstTower = t.Objectives.Tower.First
+		team.FirstDragon = t.Objectives.Dragon.First
+
+		if t.Win {
+			match.Result = sides[t.TeamID]
+		}
+	}
+
+	if remake {
+		match.Result = ResultRemake
+	}
+
+	return match
+}
+
+// Import stores a match along with a performance row for each of its summoners, in a single
+// transaction. Champions and summoners (in region) which don't exist yet are created first;
+// existing ones are matched by name, ignoring case. The names of any champions and summoners
+// which had to be created are returned in created, under "champions" and "summoners".
+func (m MatchModel) Import(match *Match, region string) (created map[string][]string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	created = map[string][]string{"champions": {}, "summoners": {}}
+
+	championIDs := make(map[string]int64)
+	summonerIDs := make(map[string]int64)
+
+	for _, team := range []*Team{match.BlueTeam, match.RedTeam} {
+		for _, p := range team.Summoners {
+			key := strings.ToLower(p.Champion.Name)
+			if _, ok := championIDs[key]; !ok {
+				var id int64
+				var isNew bool
+				err := tx.QueryRowContext(ctx, `
+                    WITH existing AS (
+                        SELECT id FROM champions WHERE LOWER(name) = LOWER($1) ORDER BY id LIMIT 1
+                    ), inserted AS (
+                        INSERT INTO champions (name, main_role)
+                        SELECT $1, $2 WHERE NOT EXISTS (SELECT 1 FROM existing)
+                        RETURNING id
+                    )
+                    SELECT id, false FROM existing UNION ALL SELECT id, true FROM inserted`,
+					p.Champion.Name, p.Champion.MainRole).Scan(&id, &isNew)
+				if err != nil {
+					return nil, err
+				}
+				championIDs[key] = id
+				if isNew {
+					created["champions"] = append(created["champions"], p.Champion.Name)
+				}
+			}
+
+			key = strings.ToLower(p.Username)
+			if _, ok := summonerIDs[key]; !ok {
+				var id int64
+				var isNew bool
+				err := tx.QueryRowContext(ctx, `
+                    WITH existing AS (
+                        SELECT id FROM summoners
+                        WHERE LOWER(username) = LOWER($1) AND LOWER(region) = LOWER($2)
+                        ORDER BY id LIMIT 1
+                    ), inserted AS (
+                        INSERT INTO summoners (username, region, rating, count_of_played_games, win_rate, average_kda)
+                        SELECT $1, $2, 0, 0, 0, $3 WHERE NOT EXISTS (SELECT 1 FROM existing)
+                        RETURNING id
+                    )
+                    SELECT id, false FROM existing UNION ALL SELECT id, true FROM inserted`,
+					p.Username, region, KDA{}).Scan(&id, &isNew)
+				if err != nil {
+					return nil, err
+				}
+				summonerIDs[key] = id
+				if isNew {
+					created["summoners"] = append(created["summoners"], p.Username)
+				}
+			}
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO matches (duration, result, match_type, played_date, blue_team, red_team)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id`,
+		match.Duration, match.Result, match.MatchType, match.PlayedDate, match.BlueTeam, match.RedTeam).Scan(&match.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, team := range []*Team{match.BlueTeam, match.RedTeam} {
+		for _, p := range team.Summoners {
+			boughtItems, err := json.Marshal(p.BoughtItems)
+			if err != nil {
+				return nil, err
+			}
+			if p.BoughtItems == nil {
+				boughtItems = []byte("[]")
+			}
+
+			p.MatchID = match.ID
+			p.summonerID = summonerIDs[strings.ToLower(p.Username)]
+			p.championID = championIDs[strings.ToLower(p.Champion.Name)]
+
+			_, err = tx.ExecContext(ctx, `
+                INSERT INTO match_performance (match_id, summoner_id, champion_id, team, role, net_worth, kills, deaths, assists, bought_items)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+				match.ID, p.summonerID, p.championID, p.Team, p.Role, p.NetWorth, p.KDA.Kills, p.KDA.Deaths, p.KDA.Assists, boughtItems)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return created, tx.Commit()
+}
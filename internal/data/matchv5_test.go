@@ -0,0 +1,199 @@
+package data
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// sampleMatchV5 is a trimmed-down match-v5 document, with fields we don't import left in to check
+// they're ignored.
+const sampleMatchV5 = `{
+    "metadata": {"dataVersion": "2", "matchId": "EUW1_6800000000"},
+    "info": {
+        "gameCreation": 1709294370000,
+        "gameStartTimestamp": 1709294400000,
+        "gameEndTimestamp": 1709296200000,
+        "gameDuration": 1800,
+        "gameMode": "CLASSIC",
+        "platformId": "EUW1",
+        "participants": [
+            {"riotIdGameName": "Faker", "summonerName": "Hide on bush", "championName": "Ahri", "teamId": 100, "teamPosition": "MIDDLE",
+             "kills": 7, "deaths": 1, "assists": 9, "goldEarned": 13000, "item0": 3089, "item1": 0, "item2": 3020, "champLevel": 18},
+            {"summonerName": "Keria", "championName": "Thresh", "teamId": 100, "teamPosition": "UTILITY",
+             "kills": 0, "deaths": 3, "assists": 15, "goldEarned": 7000, "item0": 3190},
+            {"riotIdGameName": "Caps", "championName": "Syndra", "teamId": 200, "teamPosition": "MIDDLE",
+             "kills": 3, "deaths": 4, "assists": 2, "goldEarned": 10500},
+            {"riotIdGameName": "Mikyx", "championName": "Leona", "teamId": 200, "teamPosition": "UTILITY",
+             "kills": 1, "deaths": 5, "assists": 4, "goldEarned": 6500}
+        ],
+        "teams": [
+            {"teamId": 100, "win": true, "bans": [{"championId": 238, "pickTurn": 1}], "objectives": {
+                "baron": {"first": true, "kills": 1}, "champion": {"first": true, "kills": 7},
+                "dragon": {"first": false, "kills": 3}, "inhibitor": {"first": true, "kills": 2},
+                "riftHerald": {"first": true, "kills": 1}, "tower": {"first": true, "kills": 9}}},
+            {"teamId": 200, "win": false, "objectives": {
+                "baron": {"first": false, "kills": 0}, "champion": {"first": false, "kills": 4},
+                "dragon": {"first": true, "kills": 1}, "inhibitor": {"first": false, "kills": 0},
+                "riftHerald": {"first": false, "kills": 0}, "tower": {"first": false, "kills": 2}}}
+        ]
+    }
+}`
+
+func decodeSampleMatchV5(t *testing.T) *MatchV5 {
+	t.Helper()
+
+	var doc MatchV5
+	if err := json.Unmarshal([]byte(sampleMatchV5), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	return &doc
+}
+
+func TestMatchV5ToMatch(t *testing.T) {
+	doc := decodeSampleMatchV5(t)
+
+	v := validator.New()
+	if ValidateMatchV5(v, doc); !v.Valid() {
+		t.Fatalf("got errors %v validating the document", v.Errors)
+	}
+	if doc.Region() != "EUW" {
+		t.Errorf("got region %q, want EUW", doc.Region())
+	}
+
+	match := doc.ToMatch(MatchTypeSoloQueue)
+
+	if ValidateMatch(v, match); !v.Valid() {
+		t.Fatalf("got errors %v validating the match", v.Errors)
+	}
+
+	if want := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC); !match.PlayedDate.Equal(want) {
+		t.Errorf("got played date %v, want %v", match.PlayedDate, want)
+	}
+	if match.Duration != 1800 || match.Result != ResultBlue || match.MatchType != MatchTypeSoloQueue {
+		t.Errorf("got a %s match lasting %d seconds with result %q, want a solo queue one of 1800 won by blue",
+			match.MatchType, match.Duration, match.Result)
+	}
+
+	blue, red := match.BlueTeam, match.RedTeam
+	if blue.TurretsDestroyed != 9 || blue.DragonsKilled != 3 || blue.BaronNashorsKilled != 1 || red.DragonsKilled != 1 {
+		t.Errorf("got blue objectives %+v and red %+v", blue, red)
+	}
+	if !blue.FirstBlood || !blue.FirstTower || blue.FirstDragon || !red.FirstDragon {
+		t.Errorf("got blue first objectives %t/%t/%t and red first dragon %t, want true/true/false and true",
+			blue.FirstBlood, blue.FirstTower, blue.FirstDragon, red.FirstDragon)
+	}
+	if blue.TeamKDA != (KDA{Kills: 7, Deaths: 4, Assists: 24}) || blue.TotalGold != 20000 {
+		t.Errorf("got blue team KDA %+v and %d gold, want 7/4/24 and 20000", blue.TeamKDA, blue.TotalGold)
+	}
+
+	if len(blue.Summoners) != 2 || len(red.Summoners) != 2 {
+		t.Fatalf("got %d blue and %d red summoners, want 2 each", len(blue.Summoners), len(red.Summoners))
+	}
+
+	// The Riot ID is preferred over the summoner name, which older documents fall back to.
+	faker, keria := blue.Summoners[0], blue.Summoners[1]
+	if faker.Username != "Faker" || faker.Role != "Mid" || faker.Champion.Name != "Ahri" || faker.Team != ResultBlue {
+		t.Errorf("got %s playing %s %s for %s, want Faker playing Ahri Mid for blue", faker.Username, faker.Champion.Name, faker.Role, faker.Team)
+	}
+	if !reflect.DeepEqual(faker.BoughtItems, []string{"3089", "3020"}) {
+		t.Errorf("got items %v, want [3089 3020]", faker.BoughtItems)
+	}
+	if keria.Username != "Keria" || keria.Role != "Support" {
+		t.Errorf("got %s playing %s, want Keria playing Support", keria.Username, keria.Role)
+	}
+}
+
+func TestMatchV5ToMatchLegacyAndRemake(t *testing.T) {
+	doc := decodeSampleMatchV5(t)
+
+	// Without gameEndTimestamp the duration is in milliseconds, and without gameStartTimestamp the
+	// creation time is used.
+	doc.Info.GameEndTimestamp = 0
+	doc.Info.GameStartTimestamp = 0
+	doc.Info.GameDuration = 1800000
+	doc.Info.Participants[2].GameEndedInEarlySurrender = true
+
+	match := doc.ToMatch(MatchTypePro)
+
+	if match.Duration != 1800 {
+		t.Errorf("got duration %d, want 1800", match.Duration)
+	}
+	if want := time.UnixMilli(1709294370000).UTC(); !match.PlayedDate.Equal(want) {
+		t.Errorf("got played date %v, want %v", match.PlayedDate, want)
+	}
+	// An early surrender is a remake, whichever team was marked as winning.
+	if match.Result != ResultRemake {
+		t.Errorf("got result %q, want %q", match.Result, ResultRemake)
+	}
+}
+
+func TestValidateMatchV5(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*MatchV5)
+		key    string
+	}{
+		{"unknown platform", func(doc *MatchV5) { doc.Info.PlatformID = "XX1" }, "info.platformId"},
+		{"missing team", func(doc *MatchV5) { doc.Info.Teams = doc.Info.Teams[:1] }, "info.teams"},
+		{"bad position", func(doc *MatchV5) { doc.Info.Participants[0].TeamPosition = "MID" }, "info.participants[0].teamPosition"},
+		{"bad team", func(doc *MatchV5) { doc.Info.Participants[3].TeamID = 300 }, "info.participants[3].teamId"},
+		{"no name", func(doc *MatchV5) {
+			doc.Info.Participants[1].RiotIDGameName = ""
+			doc.Info.Participants[1].SummonerName = " "
+		}, "info.participants[1].riotIdGameName"},
+	}
+
+	for _, tt := range tests {
+		doc := decodeSampleMatchV5(t)
+		tt.modify(doc)
+
+		v := validator.New()
+		ValidateMatchV5(v, doc)
+		if _, ok := v.Errors[tt.key]; !ok {
+			t.Errorf("%s: got errors %v, want one for %s", tt.name, v.Errors, tt.key)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	models := newTestModels(t)
+
+	// Ahri and Faker exist already, under different casing.
+	ahri := &Champion{Name: "AHRI", MainRole: "Mid", Classes: ChampionClasses{}}
+	if err := models.Champions.Insert(ahri); err != nil {
+		t.Fatal(err)
+	}
+	faker := insertTestSummoner(t, models, "faker")
+
+	doc := decodeSampleMatchV5(t)
+	match := doc.ToMatch(MatchTypeSoloQueue)
+
+	created, err := models.Matches.Import(match, doc.Region())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"champions": {"Thresh", "Syndra", "Leona"},
+		"summoners": {"Keria", "Caps", "Mikyx"},
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("got created %v, want %v", created, want)
+	}
+
+	var performances, fakerOnAhri int
+	err = models.Matches.DB.QueryRow(`
+        SELECT count(*), count(*) FILTER (WHERE summoner_id = $2 AND champion_id = $3 AND kills = 7 AND role = 'Mid')
+        FROM match_performance WHERE match_id = $1`, match.ID, faker.ID, ahri.ID).Scan(&performances, &fakerOnAhri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if performances != 4 || fakerOnAhri != 1 {
+		t.Errorf("got %d performances, %d of them Faker's on Ahri, want 4 and 1", performances, fakerOnAhri)
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // Define a custom ErrRecordNotFound error. We'll return this from our Get() method when
@@ -24,22 +28,77 @@ type Models struct {
 	Users       UserModel
 	Tokens      TokenModel
 	Permissions PermissionModel
+	System      SystemModel
+	Webhooks    WebhookModel
+	Features    FeatureFlagModel
+	APIKeys     APIKeyModel
+
+	db *DB
 }
 
 // For ease of use, we also add a New() method which returns a Models struct containing
-// the initialized MovieModel.
-func NewModels(db *sql.DB) Models {
+// the initialized MovieModel. If metrics isn't nil, every query the models run is counted in
+// it against the model method which ran it.
+func NewModels(db *sql.DB, metrics *QueryMetrics) Models {
+	wrapped := NewDB(db, metrics)
+
 	return Models{
-		Champions:   ChampionModel{DB: db},
-		Matches:     MatchModel{DB: db},
-		Summoners:   SummonerModel{DB: db},
-		Users:       UserModel{DB: db},
-		Tokens:      TokenModel{DB: db},
-		Permissions: PermissionModel{DB: db},
+		Champions:   ChampionModel{DB: wrapped},
+		Matches:     MatchModel{DB: wrapped},
+		Summoners:   SummonerModel{DB: wrapped},
+		Users:       UserModel{DB: wrapped},
+		Tokens:      TokenModel{DB: wrapped},
+		Permissions: PermissionModel{DB: wrapped},
+		System:      SystemModel{DB: wrapped},
+		Webhooks:    WebhookModel{DB: wrapped},
+		Features:    FeatureFlagModel{DB: wrapped},
+		APIKeys:     APIKeyModel{DB: wrapped},
+		db:          wrapped,
+	}
+}
+
+// UpdateMatchStatistics adds a stored match to the statistics of every summoner who played in it
+// and of their champions, through UpdateSummonerStatistics and UpdateChampionStatistics. Remakes
+// aren't counted. The performances must have been stored by InsertPerformances or Import, which
+// record the summoner and champion each one belongs to. Every performance is applied even if
+// another fails; the errors of any failed updates are joined.
+func (m *MatchModel) UpdateMatchStatistics(match *Match) error {
+	if strings.ToLower(match.Result) == ResultRemake {
+		return nil
+	}
+
+	var errs []error
+
+	for _, team := range []*Team{match.BlueTeam, match.RedTeam} {
+		for _, p := range team.Summoners {
+			if p.summonerID == 0 || p.championID == 0 {
+				continue
+			}
+
+			won := strings.ToLower(match.Result) == p.Team
+
+			err := m.UpdateSummonerStatistics(p.summonerID, Champion{ID: p.championID}, p.KDA, p.Role, won)
+			if err == nil {
+				err = m.UpdateChampionStatistics(p.championID, p.summonerID, won)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("summoner %d: %w", p.summonerID, err))
+			}
+		}
 	}
+
+	return errors.Join(errs...)
 }
 
+// UpdateSummonerStatistics adds a match result to the summoner's overall, per-champion and
+// per-role statistics. It returns ErrRecordNotFound if the summoner or the champion doesn't
+// exist; per-champion and per-role statistics which haven't been recorded yet start from zero.
 func (m *MatchModel) UpdateSummonerStatistics(summonerID int64, champion Champion, kda KDA, role string, won bool) error {
+	// Queue for a slot before starting the clock, so the wait doesn't eat into the transaction's
+	// timeout.
+	m.StatUpdates.Acquire()
+	defer m.StatUpdates.Release()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
@@ -49,17 +108,27 @@ func (m *MatchModel) UpdateSummonerStatistics(summonerID int64, champion Champio
 	}
 	defer tx.Rollback()
 
-	// Update summoner's count of played games and win rate
+	// Update summoner's count of played games and win rate. The win rate is derived from the
+	// game and win counts, rather than accumulated, so it can't drift. Lock the row so concurrent
+	// updates for the same summoner don't lose a game. Only non-key columns change, so NO KEY
+	// UPDATE is enough, and doesn't block the foreign key checks of a concurrent champion update
+	// inserting stats for this summoner, which would deadlock with it.
 	var playedGames int
 	var wins int
 	var avgKDA KDA
 	err = tx.QueryRowContext(ctx, `
-        SELECT count_of_played_games, win_rate, average_kda
+        SELECT count_of_played_games, wins, average_kda
         FROM summoners
         WHERE id = $1
+        FOR NO KEY UPDATE
     `, summonerID).Scan(&playedGames, &wins, &avgKDA)
 	if err != nil {
-		return err
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
 	}
 
 	playedGames++
@@ -81,17 +150,20 @@ func (m *MatchModel) UpdateSummonerStatistics(summonerID int64, champion Champio
 	// Update summoner's frequently played champions
 	var championStats ChampionStats
 	err = tx.QueryRowContext(ctx, `
-        SELECT count_of_played_matches, win_rate
+        SELECT count_of_played_matches, win_rate, kills, deaths, assists
         FROM summoner_champion_stats
         WHERE summoner_id = $1 AND champion_id = $2
-    `, summonerID, champion.ID).Scan(&championStats.CountOfPlayedMatches, &championStats.WinRate)
-	if err == sql.ErrNoRows {
-		championStats.CountOfPlayedMatches = 0
-		championStats.WinRate = 0
-	} else if err != nil {
+    `, summonerID, champion.ID).Scan(&championStats.CountOfPlayedMatches, &championStats.WinRate,
+		&championStats.KDA.Kills, &championStats.KDA.Deaths, &championStats.KDA.Assists)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return err
 	}
 
+	// Accumulate the summoner's totals on this champion, separately from their overall KDA.
+	championStats.KDA.Kills += kda.Kills
+	championStats.KDA.Deaths += kda.Deaths
+	championStats.KDA.Assists += kda.Assists
+
 	championStats.CountOfPlayedMatches++
 	if won {
 		championStats.WinRate = float64(championStats.WinRate*float64(championStats.CountOfPlayedMatches-1)+1) / float64(championStats.CountOfPlayedMatches)
@@ -101,26 +173,24 @@ func (m *MatchModel) UpdateSummonerStatistics(summonerID int64, champion Champio
 
 	// Upsert the champion stats
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO summoner_champion_stats (summoner_id, champion_id, count_of_played_matches, win_rate)
-        VALUES ($1, $2, $3, $4)
+        INSERT INTO summoner_champion_stats (summoner_id, champion_id, count_of_played_matches, win_rate, kills, deaths, assists)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
         ON CONFLICT (summoner_id, champion_id) DO UPDATE
-        SET count_of_played_matches = $3, win_rate = $4
-    `, summonerID, champion.ID, championStats.CountOfPlayedMatches, championStats.WinRate)
+        SET count_of_played_matches = $3, win_rate = $4, kills = $5, deaths = $6, assists = $7
+    `, summonerID, champion.ID, championStats.CountOfPlayedMatches, championStats.WinRate,
+		championStats.KDA.Kills, championStats.KDA.Deaths, championStats.KDA.Assists)
 	if err != nil {
-		return err
+		return missingParent(err)
 	}
 
 	// Update summoner's frequently played roles
 	var roleStats RoleStats
 	err = tx.QueryRowContext(ctx, `
         SELECT count_of_played_matches, win_rate
-        FROM summoner_role_stats
+        FROM role_stats
         WHERE summoner_id = $1 AND role = $2
     `, summonerID, role).Scan(&roleStats.CountOfPlayedMatches, &roleStats.WinRate)
-	if err == sql.ErrNoRows {
-		roleStats.CountOfPlayedMatches = 0
-		roleStats.WinRate = 0
-	} else if err != nil {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return err
 	}
 
@@ -133,7 +203,7 @@ func (m *MatchModel) UpdateSummonerStatistics(summonerID int64, champion Champio
 
 	// Upsert the role stats
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO summoner_role_stats (summoner_id, role, count_of_played_matches, win_rate)
+        INSERT INTO role_stats (summoner_id, role, count_of_played_matches, win_rate)
         VALUES ($1, $2, $3, $4)
         ON CONFLICT (summoner_id, role) DO UPDATE
         SET count_of_played_matches = $3, win_rate = $4
@@ -145,9 +215,9 @@ func (m *MatchModel) UpdateSummonerStatistics(summonerID int64, champion Champio
 	// Update the summoner's overall statistics
 	_, err = tx.ExecContext(ctx, `
         UPDATE summoners
-        SET count_of_played_games = $1, win_rate = $2, average_kda = $3
-        WHERE id = $4
-    `, playedGames, winRate, averageKDA, summonerID)
+        SET count_of_played_games = $1, wins = $2, win_rate = $3, average_kda = $4
+        WHERE id = $5
+    `, playedGames, wins, winRate, averageKDA, summonerID)
 	if err != nil {
 		return err
 	}
@@ -155,8 +225,15 @@ func (m *MatchModel) UpdateSummonerStatistics(summonerID int64, champion Champio
 	return tx.Commit()
 }
 
-// UpdateChampionStatistics updates the statistics of a champion based on the match result.
+// UpdateChampionStatistics updates the statistics of a champion based on the match result. It
+// returns ErrRecordNotFound if the champion or the summoner doesn't exist; if the summoner has no
+// recorded stats on the champion yet, they're counted as zero.
 func (m *MatchModel) UpdateChampionStatistics(championID int64, summonerID int64, won bool) error {
+	// Queue for a slot before starting the clock, so the wait doesn't eat into the transaction's
+	// timeout.
+	m.StatUpdates.Acquire()
+	defer m.StatUpdates.Release()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
@@ -166,16 +243,24 @@ func (m *MatchModel) UpdateChampionStatistics(championID int64, summonerID int64
 	}
 	defer tx.Rollback()
 
-	// Update champion's match history and win rate
+	// Update champion's match history and win rate. The win rate is derived from the match and
+	// win counts, rather than accumulated, so it can't drift. Lock the row so concurrent updates
+	// for the same champion don't lose a match; NO KEY UPDATE, as in UpdateSummonerStatistics.
 	var matchHistoryCount int
 	var wins int
 	err = tx.QueryRowContext(ctx, `
-        SELECT count_of_played_matches, win_rate
+        SELECT count_of_played_matches, wins
         FROM champions
         WHERE id = $1
+        FOR NO KEY UPDATE
     `, championID).Scan(&matchHistoryCount, &wins)
 	if err != nil {
-		return err
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
 	}
 
 	matchHistoryCount++
@@ -199,9 +284,9 @@ func (m *MatchModel) UpdateChampionStatistics(championID int64, summonerID int64
 	// Update the champion's overall statistics
 	_, err = tx.ExecContext(ctx, `
         UPDATE champions
-        SET count_of_played_matches = $1, win_rate = $2, popularity = $3
-        WHERE id = $4
-    `, matchHistoryCount, winRate, popularity, championID)
+        SET count_of_played_matches = $1, wins = $2, win_rate = $3, popularity = $4
+        WHERE id = $5
+    `, matchHistoryCount, wins, winRate, popularity, championID)
 	if err != nil {
 		return err
 	}
@@ -213,21 +298,32 @@ func (m *MatchModel) UpdateChampionStatistics(championID int64, summonerID int64
         FROM summoner_champion_stats
         WHERE summoner_id = $1 AND champion_id = $2
     `, summonerID, championID).Scan(&summonerStats.WinRate, &summonerStats.CountOfPlayedMatches)
-	if err != nil {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return err
 	}
 
 	// Upsert the best summoners stats
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO champion_best_summoners (champion_id, summoner_id, win_rate, count_of_played_matches)
+        INSERT INTO champion_stats (champion_id, summoner_id, win_rate, count_of_played_matches)
         VALUES ($1, $2, $3, $4)
         ON CONFLICT (champion_id, summoner_id) DO UPDATE
         SET win_rate = $3, count_of_played_matches = $4
     `, championID, summonerID, summonerStats.WinRate, summonerStats.CountOfPlayedMatches)
 	if err != nil {
-		return err
+		return missingParent(err)
 	}
 
 	return tx.Commit()
 }
 
+// missingParent turns the foreign key violation raised when a stats row is written for a summoner
+// or champion which doesn't exist (or was deleted while its stats were being updated) into
+// ErrRecordNotFound. Other errors are returned unchanged.
+func missingParent(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation" {
+		return ErrRecordNotFound
+	}
+	return err
+}
+
@@ -0,0 +1,175 @@
+package data
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestUpdateChampionStatisticsWinRate(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	// The win rate must be the true fraction after every game, not only the first.
+	tests := []struct {
+		won  bool
+		want float64
+	}{
+		{true, 1},
+		{false, 0.5},
+		{false, 1.0 / 3},
+		{true, 0.5},
+		{true, 0.6},
+	}
+
+	for i, tt := range tests {
+		if err := models.Matches.UpdateChampionStatistics(champion.ID, summoner.ID, tt.won); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := models.Matches.GetStatAggregates(summoner.ID, champion.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.ChampionGames != i+1 || math.Abs(got.ChampionWinRate-tt.want) > 1e-9 {
+			t.Errorf("after game %d: got win rate %v over %d games, want %v", i+1, got.ChampionWinRate, got.ChampionGames, tt.want)
+		}
+	}
+}
+
+func TestUpdateSummonerStatisticsWinRate(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	for _, won := range []bool{true, false, true, true} {
+		if err := models.Matches.UpdateSummonerStatistics(summoner.ID, *champion, KDA{Kills: 5, Deaths: 2, Assists: 7}, "Mid", won); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := models.Matches.GetStatAggregates(summoner.ID, champion.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.SummonerGames != 4 || got.SummonerWins != 3 || math.Abs(got.SummonerWinRate-0.75) > 1e-9 {
+		t.Errorf("got %d/%d games won and a win rate of %v, want 3/4 and 0.75", got.SummonerWins, got.SummonerGames, got.SummonerWinRate)
+	}
+
+	// The per-role record is kept alongside.
+	var roleGames int
+	var roleWinRate float64
+	err = models.Matches.DB.QueryRow(`
+        SELECT count_of_played_matches, win_rate FROM role_stats WHERE summoner_id = $1 AND role = 'Mid'`,
+		summoner.ID).Scan(&roleGames, &roleWinRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roleGames != 4 || math.Abs(roleWinRate-0.75) > 1e-9 {
+		t.Errorf("got %d Mid games and a win rate of %v, want 4 and 0.75", roleGames, roleWinRate)
+	}
+}
+
+func TestUpdateSummonerStatisticsChampionKDA(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	ahri := insertTestChampion(t, models, "Ahri")
+	syndra := insertTestChampion(t, models, "Syndra")
+
+	update := func(champion *Champion, kda KDA) {
+		t.Helper()
+		if err := models.Matches.UpdateSummonerStatistics(summoner.ID, *champion, kda, "Mid", true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	update(ahri, KDA{Kills: 6, Deaths: 2, Assists: 8})
+	update(syndra, KDA{Kills: 2, Deaths: 4, Assists: 2})
+
+	// The overall KDA averages both games.
+	got, err := models.Summoners.Get(summoner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (KDA{Kills: 4, Deaths: 3, Assists: 5}); got.AverageKDA != want {
+		t.Errorf("got overall KDA %+v, want %+v", got.AverageKDA, want)
+	}
+
+	// Another Ahri game adds to Ahri's totals only.
+	update(ahri, KDA{Kills: 4, Deaths: 0, Assists: 6})
+
+	stats, err := models.Summoners.GetChampionStats(summoner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int64]KDA{
+		ahri.ID:   {Kills: 10, Deaths: 2, Assists: 14},
+		syndra.ID: {Kills: 2, Deaths: 4, Assists: 2},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("got stats for %d champions, want %d", len(stats), len(want))
+	}
+	for _, s := range stats {
+		if s.KDA != want[s.Champion.ID] {
+			t.Errorf("%s: got KDA %+v, want %+v", s.Champion.Name, s.KDA, want[s.Champion.ID])
+		}
+	}
+}
+
+func TestUpdateStatisticsMissingRecords(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	champion := insertTestChampion(t, models, "Ahri")
+	deletedSummoner := insertTestSummoner(t, models, "Caps")
+	deletedChampion := insertTestChampion(t, models, "Syndra")
+
+	if err := models.Summoners.Delete(deletedSummoner.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := models.Champions.Delete(deletedChampion.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	kda := KDA{Kills: 5, Deaths: 2, Assists: 7}
+
+	tests := []struct {
+		name   string
+		update func() error
+	}{
+		{"summoner stats of a deleted summoner", func() error {
+			return models.Matches.UpdateSummonerStatistics(deletedSummoner.ID, *champion, kda, "Mid", true)
+		}},
+		{"summoner stats on a deleted champion", func() error {
+			return models.Matches.UpdateSummonerStatistics(summoner.ID, *deletedChampion, kda, "Mid", true)
+		}},
+		{"champion stats of a deleted champion", func() error {
+			return models.Matches.UpdateChampionStatistics(deletedChampion.ID, summoner.ID, true)
+		}},
+		{"champion stats for a deleted summoner", func() error {
+			return models.Matches.UpdateChampionStatistics(champion.ID, deletedSummoner.ID, true)
+		}},
+	}
+
+	for _, tt := range tests {
+		if err := tt.update(); !errors.Is(err, ErrRecordNotFound) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, ErrRecordNotFound)
+		}
+	}
+
+	// The failed updates were rolled back, so nothing was counted for the records which exist.
+	got, err := models.Matches.GetStatAggregates(summoner.ID, champion.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SummonerGames != 0 || got.ChampionGames != 0 {
+		t.Errorf("got %d summoner and %d champion games, want none", got.SummonerGames, got.ChampionGames)
+	}
+}
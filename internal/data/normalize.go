@@ -0,0 +1,72 @@
+package data
+
+import (
+	"strings"
+	"unicode"
+)
+
+// canonicalRoles maps the lowercased form of each known role to the casing we store in the
+// database, so that "mid", "MID" and "Mid" all end up as the same value.
+var canonicalRoles = map[string]string{
+	"top":     "Top",
+	"jungle":  "Jungle",
+	"mid":     "Mid",
+	"bot":     "Bot",
+	"adc":     "ADC",
+	"support": "Support",
+}
+
+// NormalizeName trims leading and trailing whitespace from a name and collapses any run of
+// internal whitespace down to a single space, so that " Faker " and "Faker" are stored as the
+// same value.
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeRegion normalizes the whitespace in a region code and uppercases it (e.g. " euw"
+// becomes "EUW").
+func NormalizeRegion(region string) string {
+	return strings.ToUpper(NormalizeName(region))
+}
+
+// NormalizeRole normalizes the whitespace in a role and converts it to its canonical casing. Roles
+// which we don't recognise are returned with only their first letter capitalized.
+func NormalizeRole(role string) string {
+	role = NormalizeName(role)
+	if canonical, ok := canonicalRoles[strings.ToLower(role)]; ok {
+		return canonical
+	}
+	runes := []rune(strings.ToLower(role))
+	if len(runes) == 0 {
+		return role
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// NormalizeChampion applies the normalization rules to the user-supplied fields of a champion.
+func NormalizeChampion(champion *Champion) {
+	champion.Name = NormalizeName(champion.Name)
+	champion.MainRole = NormalizeRole(champion.MainRole)
+	champion.ImageURL = strings.TrimSpace(champion.ImageURL)
+	champion.DamageProfile = NormalizeDamageProfile(champion.DamageProfile)
+	champion.Classes = NormalizeClasses(champion.Classes)
+}
+
+// NormalizeDamageProfile trims a damage profile and converts a known one to its canonical casing
+// (e.g. "ad" becomes "AD"). Unknown profiles are returned trimmed, to fail validation.
+func NormalizeDamageProfile(profile string) string {
+	profile = strings.TrimSpace(profile)
+	for _, canonical := range DamageProfiles {
+		if strings.EqualFold(profile, canonical) {
+			return canonical
+		}
+	}
+	return profile
+}
+
+// NormalizeSummoner applies the normalization rules to the user-supplied fields of a summoner.
+func NormalizeSummoner(summoner *Summoner) {
+	summoner.Username = NormalizeName(summoner.Username)
+	summoner.Region = NormalizeRegion(summoner.Region)
+}
@@ -0,0 +1,104 @@
+package data
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Faker", "Faker"},
+		{"  Faker  ", "Faker"},
+		{"Lee   Sin", "Lee Sin"},
+		{"\tDr.\n Mundo ", "Dr. Mundo"},
+		{"   ", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeName(tt.name); got != tt.want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeRegion(t *testing.T) {
+	tests := []struct {
+		region string
+		want   string
+	}{
+		{"EUW", "EUW"},
+		{" euw", "EUW"},
+		{"Na ", "NA"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeRegion(tt.region); got != tt.want {
+			t.Errorf("NormalizeRegion(%q) = %q, want %q", tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeRole(t *testing.T) {
+	tests := []struct {
+		role string
+		want string
+	}{
+		{"mid", "Mid"},
+		{"MID", "Mid"},
+		{" Jungle ", "Jungle"},
+		{"adc", "ADC"},
+		{"Adc", "ADC"},
+		{"SUPPORT", "Support"},
+		// Unknown roles only have their first letter capitalized.
+		{"roamer", "Roamer"},
+		{"FLEX  pick", "Flex pick"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeRole(tt.role); got != tt.want {
+			t.Errorf("NormalizeRole(%q) = %q, want %q", tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSummoner(t *testing.T) {
+	summoner := &Summoner{Username: "  Hide  on   bush ", Region: " kr "}
+	NormalizeSummoner(summoner)
+
+	if summoner.Username != "Hide on bush" || summoner.Region != "KR" {
+		t.Errorf("got username %q and region %q, want \"Hide on bush\" and \"KR\"", summoner.Username, summoner.Region)
+	}
+}
+
+func TestNormalizeChampion(t *testing.T) {
+	champion := &Champion{Name: " Miss  Fortune", MainRole: "adc", ImageURL: " https://example.com/mf.png\n"}
+	NormalizeChampion(champion)
+
+	if champion.Name != "Miss Fortune" || champion.MainRole != "ADC" || champion.ImageURL != "https://example.com/mf.png" {
+		t.Errorf("got %q, %q and %q", champion.Name, champion.MainRole, champion.ImageURL)
+	}
+}
+
+func TestNormalizeDamageProfile(t *testing.T) {
+	tests := []struct {
+		profile string
+		want    string
+	}{
+		{"AD", "AD"},
+		{" ad ", "AD"},
+		{"Ap", "AP"},
+		{"MIXED", "mixed"},
+		// Unknown profiles are only trimmed, so validation rejects them.
+		{" true ", "true"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeDamageProfile(tt.profile); got != tt.want {
+			t.Errorf("NormalizeDamageProfile(%q) = %q, want %q", tt.profile, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package data
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// PercentilePoint is the PERCENT_RANK() of one value of a summoner stat: the share of summoners
+// (from 0 to 1) with a lower value.
+type PercentilePoint struct {
+	Value      float64
+	Percentile float64
+}
+
+// Percentiles holds the PercentilePoint of each distinct value of a stat, in ascending order.
+type Percentiles []PercentilePoint
+
+// Of returns the percentile of a value: that of the largest value in the distribution which is no
+// greater than it, or 0 if it is below every value (or the distribution is empty).
+func (p Percentiles) Of(value float64) float64 {
+	i := sort.Search(len(p), func(i int) bool { return p[i].Value > value })
+	if i == 0 {
+		return 0
+	}
+	return p[i-1].Percentile
+}
+
+// SummonerDistribution holds the percentiles of the summoner stats we rank summoners by.
+type SummonerDistribution struct {
+	Rating  Percentiles
+	WinRate Percentiles
+}
+
+// GetDistribution returns the rating and win rate percentiles of every summoner in the region, or
+// of every summoner if region is empty. The queries scan the whole summoners table, so callers
+// should cache the result rather than run them per request.
+func (s SummonerModel) GetDistribution(region string) (*SummonerDistribution, error) {
+	rating, err := s.getPercentiles("rating", region)
+	if err != nil {
+		return nil, err
+	}
+
+	winRate, err := s.getPercentiles("win_rate", region)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SummonerDistribution{Rating: rating, WinRate: winRate}, nil
+}
+
+// getPercentiles returns the percentiles of a column of the summoners table. The column is always
+// one of our own constants, never user input.
+func (s SummonerModel) getPercentiles(column, region string) (Percentiles, error) {
+	query := `
+        WITH ranked AS (
+            SELECT ` + column + `::float8 AS value, PERCENT_RANK() OVER (ORDER BY ` + column + `) AS percentile
+            FROM summoners
+            WHERE ` + column + ` IS NOT NULL AND (region = $1 OR $1 = '')
+        )
+        SELECT DISTINCT value, percentile
+        FROM ranked
+        ORDER BY value`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := s.DB.QueryContext(ctx, query, region)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	percentiles := Percentiles{}
+
+	for rows.Next() {
+		var p PercentilePoint
+		err := rows.Scan(&p.Value, &p.Percentile)
+		if err != nil {
+			return nil, err
+		}
+
+		percentiles = append(percentiles, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return percentiles, nil
+}
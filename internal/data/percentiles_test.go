@@ -0,0 +1,80 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPercentilesOf(t *testing.T) {
+	p := Percentiles{{1000, 0}, {1500, 0.25}, {2000, 0.75}, {3000, 1}}
+
+	tests := []struct {
+		value float64
+		want  float64
+	}{
+		{500, 0},
+		{1000, 0},
+		{1499, 0},
+		{1500, 0.25},
+		{1800, 0.25},
+		{2000, 0.75},
+		{3000, 1},
+		{9999, 1},
+	}
+
+	for _, tt := range tests {
+		if got := p.Of(tt.value); got != tt.want {
+			t.Errorf("Of(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	if got := (Percentiles{}).Of(1000); got != 0 {
+		t.Errorf("empty distribution: Of(1000) = %v, want 0", got)
+	}
+}
+
+func TestGetDistribution(t *testing.T) {
+	models := newTestModels(t)
+
+	// Win rates rise with ratings, so both stats rank the summoners in the same order. The best
+	// summoner is in another region, so only counts towards the global distribution.
+	for i, rating := range []int{1200, 1500, 1500, 1800, 2400, 3000} {
+		summoner := &Summoner{Username: fmt.Sprintf("Summoner%d", i), Region: "EUW", Rating: rating, WinRate: float64(rating) / 4000}
+		if rating == 3000 {
+			summoner.Region = "KR"
+		}
+		if err := models.Summoners.Insert(summoner); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		region      string
+		top, bottom float64
+		tied        float64 // Percentile of the two summoners rated 1500
+	}{
+		{"EUW", 2400, 1200, 0.25},
+		{"", 3000, 1200, 0.2},
+	}
+
+	for _, tt := range tests {
+		distribution, err := models.Summoners.GetDistribution(tt.region)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := distribution.Rating.Of(tt.top); got != 1 {
+			t.Errorf("region %q: got top rating percentile %v, want 1", tt.region, got)
+		}
+		if got := distribution.Rating.Of(tt.bottom); got != 0 {
+			t.Errorf("region %q: got bottom rating percentile %v, want 0", tt.region, got)
+		}
+		// Tied summoners share the rank of the first of them.
+		if got := distribution.Rating.Of(1500); got != tt.tied {
+			t.Errorf("region %q: got tied rating percentile %v, want %v", tt.region, got, tt.tied)
+		}
+		if got := distribution.WinRate.Of(tt.top / 4000); got != 1 {
+			t.Errorf("region %q: got top win rate percentile %v, want 1", tt.region, got)
+		}
+	}
+}
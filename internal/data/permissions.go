@@ -2,7 +2,6 @@ package data
 
 import (
 	"context"
-	"database/sql"
 	"log"
 	"time"
 
@@ -24,12 +23,13 @@ func (p Permissions) Include(code string) bool {
 }
 
 type PermissionModel struct {
-	DB       *sql.DB
+	DB       *DB
 	InfoLog  *log.Logger
 	ErrorLog *log.Logger
 }
 
-// GetAllForUser returns all permission codes for a specific user in a Permissions slice.
+// GetAllForUser returns all permission codes for a specific user in a Permissions slice, in
+// alphabetical order. A user without any permissions gets an empty slice.
 func (m PermissionModel) GetAllForUser(userID int64) (Permissions, error) {
 	query := `
 		SELECT permissions.code
@@ -37,6 +37,7 @@ func (m PermissionModel) GetAllForUser(userID int64) (Permissions, error) {
 			INNER JOIN users_permissions ON users_permissions.permission_id = permissions.id
 			INNER JOIN users ON users_permissions.user_id = users.id
 		WHERE users.id = $1
+		ORDER BY permissions.code
 		`
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -52,7 +53,7 @@ func (m PermissionModel) GetAllForUser(userID int64) (Permissions, error) {
 		}
 	}()
 
-	var permissions Permissions
+	permissions := Permissions{}
 
 	for rows.Next() {
 		var permission string
@@ -72,6 +73,20 @@ func (m PermissionModel) GetAllForUser(userID int64) (Permissions, error) {
 	return permissions, nil
 }
 
+// GetAllCodes returns every permission code which exists, in alphabetical order.
+func (m PermissionModel) GetAllCodes() (Permissions, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var codes Permissions
+	err := m.DB.QueryRowContext(ctx, `SELECT COALESCE(array_agg(code ORDER BY code), '{}') FROM permissions`).Scan(pq.Array(&codes))
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
 // AddForUser adds the provided codes for a specific user.
 func (m PermissionModel) AddForUser(userID int64, codes ...string) error {
 	query := `
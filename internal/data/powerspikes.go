@@ -0,0 +1,116 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// Game phases a power spike falls in.
+const (
+	PhaseEarly = "early"
+	PhaseMid   = "mid"
+	PhaseLate  = "late"
+)
+
+// powerSpikePhases maps each allowed power spike tag to the phase of the game it falls in.
+var powerSpikePhases = map[string]string{
+	"level 2":     PhaseEarly,
+	"level 3":     PhaseEarly,
+	"level 6":     PhaseEarly,
+	"first item":  PhaseEarly,
+	"level 11":    PhaseMid,
+	"two items":   PhaseMid,
+	"level 16":    PhaseLate,
+	"three items": PhaseLate,
+	"full build":  PhaseLate,
+}
+
+// PowerSpikes holds the tags of the points in a game where a champion becomes much stronger (e.g.
+// "level 6" or "two items"). It is stored as a PostgreSQL text array.
+type PowerSpikes []string
+
+// Scan implements the sql.Scanner interface for PowerSpikes.
+func (p *PowerSpikes) Scan(value interface{}) error {
+	return pq.Array((*[]string)(p)).Scan(value)
+}
+
+// Value implements the driver.Valuer interface for PowerSpikes. A nil PowerSpikes is stored as an
+// empty array rather than NULL.
+func (p PowerSpikes) Value() (driver.Value, error) {
+	if p == nil {
+		return "{}", nil
+	}
+	return pq.Array([]string(p)).Value()
+}
+
+// NormalizePowerSpikes lowercases each tag, normalizes its whitespace and removes empty and
+// duplicate tags, keeping the tags in the order they were first given.
+func NormalizePowerSpikes(spikes PowerSpikes) PowerSpikes {
+	seen := make(map[string]bool, len(spikes))
+	normalized := PowerSpikes{}
+
+	for _, spike := range spikes {
+		spike = strings.ToLower(NormalizeName(spike))
+		if spike == "" || seen[spike] {
+			continue
+		}
+		seen[spike] = true
+		normalized = append(normalized, spike)
+	}
+
+	return normalized
+}
+
+func ValidatePowerSpikes(v *validator.Validator, spikes PowerSpikes) {
+	for _, spike := range spikes {
+		_, ok := powerSpikePhases[spike]
+		v.Check(ok, "power_spikes", "must only contain known power spike tags")
+	}
+}
+
+// PowerSpikeTags returns the tags matched by a ?power_spike= filter value, which is either a
+// phase (matching every tag in that phase) or a single tag. It returns nil for a value which is
+// neither.
+func PowerSpikeTags(spike string) []string {
+	spike = strings.ToLower(NormalizeName(spike))
+
+	var tags []string
+	for tag, phase := range powerSpikePhases {
+		if tag == spike || phase == spike {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// SetPowerSpikes writes the champion's power spike tags and sets its new version.
+func (c ChampionModel) SetPowerSpikes(champion *Champion) error {
+	query := `
+        UPDATE champions
+        SET power_spikes = $2, version = version + 1
+        WHERE id = $1
+        RETURNING version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := c.DB.QueryRowContext(ctx, query, champion.ID, champion.PowerSpikes).Scan(&champion.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package data
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func TestNormalizePowerSpikes(t *testing.T) {
+	got := NormalizePowerSpikes(PowerSpikes{"Level 6", " two  ITEMS", "", "level 6", "Full build"})
+	want := PowerSpikes{"level 6", "two items", "full build"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := NormalizePowerSpikes(nil); got == nil || len(got) != 0 {
+		t.Errorf("got %#v for no tags, want an empty slice", got)
+	}
+}
+
+func TestValidatePowerSpikes(t *testing.T) {
+	v := validator.New()
+	ValidatePowerSpikes(v, PowerSpikes{"level 6", "three items"})
+	if !v.Valid() {
+		t.Errorf("got errors %v for known tags", v.Errors)
+	}
+
+	v = validator.New()
+	ValidatePowerSpikes(v, PowerSpikes{"level 6", "level 7"})
+	if _, ok := v.Errors["power_spikes"]; !ok {
+		t.Errorf("got no error for an unknown tag")
+	}
+}
+
+func TestPowerSpikeTags(t *testing.T) {
+	tests := []struct {
+		spike string
+		want  []string
+	}{
+		{"level 6", []string{"level 6"}},
+		{" Two  Items ", []string{"two items"}},
+		{"early", []string{"first item", "level 2", "level 3", "level 6"}},
+		{"MID", []string{"level 11", "two items"}},
+		{"late", []string{"full build", "level 16", "three items"}},
+		{"level 7", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		got := PowerSpikeTags(tt.spike)
+		sort.Strings(got)
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PowerSpikeTags(%q) = %q, want %q", tt.spike, got, tt.want)
+		}
+	}
+}
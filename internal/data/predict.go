@@ -0,0 +1,254 @@
+package data
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// TeamComp is one team's composition in a match prediction. Summoners are optional.
+type TeamComp struct {
+	Champions []int64 `json:"champions"`
+	Summoners []int64 `json:"summoners"`
+}
+
+func ValidatePrediction(v *validator.Validator, blue, red *TeamComp) {
+	for _, team := range []struct {
+		key  string
+		comp *TeamComp
+	}{{"blue", blue}, {"red", red}} {
+		v.Check(len(team.comp.Champions) > 0, team.key+".champions", "must contain at least one champion")
+		v.Check(len(team.comp.Champions) <= MaxTeamSummoners, team.key+".champions", "must not contain more than 5 champions")
+		v.Check(len(team.comp.Summoners) <= MaxTeamSummoners, team.key+".summoners", "must not contain more than 5 summoners")
+	}
+
+	champions := make(map[int64]bool)
+	for _, id := range append(append([]int64{}, blue.Champions...), red.Champions...) {
+		v.Check(id > 0, "champions", "must only contain valid champion ids")
+		v.Check(!champions[id], "champions", "must not pick the same champion more than once")
+		champions[id] = true
+	}
+
+	summoners := make(map[int64]bool)
+	for _, id := range append(append([]int64{}, blue.Summoners...), red.Summoners...) {
+		v.Check(id > 0, "summoners", "must only contain valid summoner ids")
+		v.Check(!summoners[id], "summoners", "must not contain the same summoner more than once")
+		summoners[id] = true
+	}
+}
+
+// PredictionWeights tunes PredictOutcome. The blue team's win probability is the logistic
+// function of
+//
+//	ChampionWeight*champion + SynergyWeight*synergy + CounterWeight*counter + SkillWeight*skill
+//
+// where each factor compares the blue team with the red team (see PredictOutcome).
+type PredictionWeights struct {
+	ChampionWeight float64
+	SynergyWeight  float64
+	CounterWeight  float64
+	SkillWeight    float64
+}
+
+// PredictionFactor is one term of a prediction, so clients can show why it came out as it did.
+type PredictionFactor struct {
+	Name         string  `json:"name"`
+	Value        float64 `json:"value"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"` // Value * weight, added to the log-odds of a blue win
+}
+
+// Prediction is the predicted outcome of a match between two team compositions.
+type Prediction struct {
+	BlueWinProbability float64            `json:"blueWinProbability"`
+	Factors            []PredictionFactor `json:"factors"`
+}
+
+// PredictionRecords holds the records a prediction is computed from.
+type PredictionRecords struct {
+	Champions map[int64]PairRecord    // Each champion's overall record
+	Allies    map[[2]int64]PairRecord // Record of the first champion with the second on its team
+	Enemies   map[[2]int64]PairRecord // Record of the first champion against the second
+	Ratings   map[int64]int           // Summoner ratings
+}
+
+// ratingPerTier is the width of a rank tier in rating points, used to scale the skill factor.
+const ratingPerTier = 400
+
+// PredictOutcome predicts the blue team's chance of winning. Every win rate is shrunk towards 50%
+// like those used for drafting, and the factors are:
+//
+//   - champion: the blue champions' mean win rate minus the red champions'
+//   - synergy: the mean win rate of each blue champion alongside each of its teammates, minus the
+//     same for red
+//   - counter: the mean win rate of each blue champion against each red champion, less 50%
+//   - skill: the difference in the teams' mean summoner ratings, in rank tiers (zero unless both
+//     teams name summoners)
+func PredictOutcome(blue, red *TeamComp, records *PredictionRecords, weights PredictionWeights) *Prediction {
+	championFactor := meanChampionWinRate(blue.Champions, records) - meanChampionWinRate(red.Champions, records)
+	synergyFactor := meanSynergy(blue.Champions, records) - meanSynergy(red.Champions, records)
+
+	var counter float64
+	var pairs int
+	for _, a := range blue.Champions {
+		for _, b := range red.Champions {
+			counter += records.Enemies[[2]int64{a, b}].shrunkWinRate() - 0.5
+			pairs++
+		}
+	}
+	counterFactor := counter / float64(pairs)
+
+	var skillFactor float64
+	if len(blue.Summoners) > 0 && len(red.Summoners) > 0 {
+		skillFactor = (meanRating(blue.Summoners, records) - meanRating(red.Summoners, records)) / ratingPerTier
+	}
+
+	prediction := &Prediction{}
+
+	var logOdds float64
+	for _, f := range []PredictionFactor{
+		{Name: "champion", Value: championFactor, Weight: weights.ChampionWeight},
+		{Name: "synergy", Value: synergyFactor, Weight: weights.SynergyWeight},
+		{Name: "counter", Value: counterFactor, Weight: weights.CounterWeight},
+		{Name: "skill", Value: skillFactor, Weight: weights.SkillWeight},
+	} {
+		f.Contribution = f.Value * f.Weight
+		logOdds += f.Contribution
+		prediction.Factors = append(prediction.Factors, f)
+	}
+
+	prediction.BlueWinProbability = 1 / (1 + math.Exp(-logOdds))
+
+	return prediction
+}
+
+// meanChampionWinRate returns the mean shrunk win rate of the champions.
+func meanChampionWinRate(champions []int64, records *PredictionRecords) float64 {
+	var sum float64
+	for _, id := range champions {
+		sum += records.Champions[id].shrunkWinRate()
+	}
+	return sum / float64(len(champions))
+}
+
+// meanSynergy returns the mean shrunk win rate of each champion alongside each of its teammates,
+// less 50%, or zero for a team of one.
+func meanSynergy(champions []int64, records *PredictionRecords) float64 {
+	var sum float64
+	var pairs int
+	for _, a := range champions {
+		for _, b := range champions {
+			if a == b {
+				continue
+			}
+			sum += records.Allies[[2]int64{a, b}].shrunkWinRate() - 0.5
+			pairs++
+		}
+	}
+	if pairs == 0 {
+		return 0
+	}
+	return sum / float64(pairs)
+}
+
+// meanRating returns the mean rating of the summoners.
+func meanRating(summoners []int64, records *PredictionRecords) float64 {
+	var sum float64
+	for _, id := range summoners {
+		sum += float64(records.Ratings[id])
+	}
+	return sum / float64(len(summoners))
+}
+
+// GetPredictionRecords reads the records PredictOutcome needs for the given champions and
+// summoners. Champions and summoners which don't exist are left out of the maps. Remakes are
+// excluded from the pair records.
+func (c ChampionModel) GetPredictionRecords(championIDs, summonerIDs []int64) (*PredictionRecords, error) {
+	records := &PredictionRecords{
+		Champions: make(map[int64]PairRecord),
+		Allies:    make(map[[2]int64]PairRecord),
+		Enemies:   make(map[[2]int64]PairRecord),
+		Ratings:   make(map[int64]int),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, `
+        SELECT id, count_of_played_matches, wins
+        FROM champions
+        WHERE id = ANY($1)`, pq.Array(championIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var record PairRecord
+		if err := rows.Scan(&id, &record.Games, &record.Wins); err != nil {
+			return nil, err
+		}
+		records.Champions[id] = record
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pairRows, err := c.DB.QueryContext(ctx, `
+        SELECT a.champion_id, b.champion_id, b.team = a.team, count(*),
+            count(*) FILTER (WHERE LOWER(m.result) = a.team)
+        FROM match_performance a
+        JOIN match_performance b ON b.match_id = a.match_id AND b.id <> a.id
+        JOIN matches m ON m.id = a.match_id
+        WHERE a.champion_id = ANY($1) AND b.champion_id = ANY($1) AND LOWER(m.result) <> $2
+        GROUP BY a.champion_id, b.champion_id, b.team = a.team`, pq.Array(championIDs), ResultRemake)
+	if err != nil {
+		return nil, err
+	}
+	defer pairRows.Close()
+
+	for pairRows.Next() {
+		var pair [2]int64
+		var sameTeam bool
+		var record PairRecord
+		if err := pairRows.Scan(&pair[0], &pair[1], &sameTeam, &record.Games, &record.Wins); err != nil {
+			return nil, err
+		}
+		if sameTeam {
+			records.Allies[pair] = record
+		} else {
+			records.Enemies[pair] = record
+		}
+	}
+	if err = pairRows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(summonerIDs) == 0 {
+		return records, nil
+	}
+
+	ratingRows, err := c.DB.QueryContext(ctx, `
+        SELECT id, COALESCE(rating, 0)
+        FROM summoners
+        WHERE id = ANY($1)`, pq.Array(summonerIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer ratingRows.Close()
+
+	for ratingRows.Next() {
+		var id int64
+		var rating int
+		if err := ratingRows.Scan(&id, &rating); err != nil {
+			return nil, err
+		}
+		records.Ratings[id] = rating
+	}
+
+	return records, ratingRows.Err()
+}
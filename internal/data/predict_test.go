@@ -0,0 +1,106 @@
+package data
+
+import (
+	"math"
+	"testing"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+func TestPredictOutcomeEvenTeams(t *testing.T) {
+	records := &PredictionRecords{}
+	weights := PredictionWeights{ChampionWeight: 1, SynergyWeight: 1, CounterWeight: 1, SkillWeight: 1}
+
+	// With no records every win rate shrinks to 50%, so neither team is favoured.
+	prediction := PredictOutcome(&TeamComp{Champions: []int64{1, 2}}, &TeamComp{Champions: []int64{3, 4}}, records, weights)
+
+	if prediction.BlueWinProbability != 0.5 {
+		t.Errorf("got blue win probability %v, want 0.5", prediction.BlueWinProbability)
+	}
+	for _, f := range prediction.Factors {
+		if f.Value != 0 || f.Contribution != 0 {
+			t.Errorf("got factor %+v, want it to be zero", f)
+		}
+	}
+}
+
+func TestPredictOutcomeSkill(t *testing.T) {
+	records := &PredictionRecords{Ratings: map[int64]int{10: 2000, 11: 2000, 20: 1600, 21: 1600}}
+	weights := PredictionWeights{SkillWeight: math.Log(3)}
+
+	blue := &TeamComp{Champions: []int64{1}, Summoners: []int64{10, 11}}
+	red := &TeamComp{Champions: []int64{2}, Summoners: []int64{20, 21}}
+
+	// A tier's lead at a weight of ln(3) gives odds of 3 to 1.
+	prediction := PredictOutcome(blue, red, records, weights)
+	if math.Abs(prediction.BlueWinProbability-0.75) > 1e-9 {
+		t.Errorf("got blue win probability %v, want 0.75", prediction.BlueWinProbability)
+	}
+
+	// Swapping the teams swaps the odds.
+	prediction = PredictOutcome(red, blue, records, weights)
+	if math.Abs(prediction.BlueWinProbability-0.25) > 1e-9 {
+		t.Errorf("got blue win probability %v with the teams swapped, want 0.25", prediction.BlueWinProbability)
+	}
+
+	// Skill only counts when both teams name summoners.
+	prediction = PredictOutcome(blue, &TeamComp{Champions: []int64{2}}, records, weights)
+	if prediction.BlueWinProbability != 0.5 {
+		t.Errorf("got blue win probability %v without red summoners, want 0.5", prediction.BlueWinProbability)
+	}
+}
+
+func TestPredictOutcomeFactors(t *testing.T) {
+	strong := PairRecord{Games: 1000, Wins: 700}
+	records := &PredictionRecords{
+		Champions: map[int64]PairRecord{1: strong},
+		Allies:    map[[2]int64]PairRecord{{1, 2}: strong, {2, 1}: strong},
+		Enemies:   map[[2]int64]PairRecord{{1, 3}: strong},
+	}
+	weights := PredictionWeights{ChampionWeight: 1, SynergyWeight: 1, CounterWeight: 1, SkillWeight: 1}
+
+	prediction := PredictOutcome(&TeamComp{Champions: []int64{1, 2}}, &TeamComp{Champions: []int64{3, 4}}, records, weights)
+
+	if prediction.BlueWinProbability <= 0.5 {
+		t.Errorf("got blue win probability %v, want blue favoured", prediction.BlueWinProbability)
+	}
+
+	for _, f := range prediction.Factors {
+		positive := f.Name != "skill"
+		if (f.Value > 0) != positive {
+			t.Errorf("got factor %+v, want positive=%t", f, positive)
+		}
+		if f.Contribution != f.Value*f.Weight {
+			t.Errorf("got contribution %v for factor %+v, want value times weight", f.Contribution, f)
+		}
+	}
+}
+
+func TestValidatePrediction(t *testing.T) {
+	tests := []struct {
+		name      string
+		blue, red TeamComp
+		invalid   []string
+	}{
+		{"valid", TeamComp{Champions: []int64{1, 2}, Summoners: []int64{10}}, TeamComp{Champions: []int64{3}, Summoners: []int64{20}}, nil},
+		{"empty team", TeamComp{Champions: []int64{1}}, TeamComp{}, []string{"red.champions"}},
+		{"too many champions", TeamComp{Champions: []int64{1, 2, 3, 4, 5, 6}}, TeamComp{Champions: []int64{7}}, []string{"blue.champions"}},
+		{"champion on both teams", TeamComp{Champions: []int64{1}}, TeamComp{Champions: []int64{1}}, []string{"champions"}},
+		{"invalid champion", TeamComp{Champions: []int64{0}}, TeamComp{Champions: []int64{1}}, []string{"champions"}},
+		{"summoner on both teams", TeamComp{Champions: []int64{1}, Summoners: []int64{10}}, TeamComp{Champions: []int64{2}, Summoners: []int64{10}}, []string{"summoners"}},
+	}
+
+	for _, tt := range tests {
+		v := validator.New()
+		ValidatePrediction(v, &tt.blue, &tt.red)
+
+		for _, key := range tt.invalid {
+			if _, ok := v.Errors[key]; !ok {
+				t.Errorf("%s: missing error for %s; got %v", tt.name, key, v.Errors)
+			}
+		}
+		if len(v.Errors) != len(tt.invalid) {
+			t.Errorf("%s: got errors %v, want %d", tt.name, v.Errors, len(tt.invalid))
+		}
+	}
+}
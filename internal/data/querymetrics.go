@@ -0,0 +1,137 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// QueryMetrics counts the queries run by each model method and the total time they took, keyed
+// by method name (e.g. "SummonerModel.Get"). The maps aren't published by the data package; the
+// caller decides whether and under which names to expose them through expvar.
+type QueryMetrics struct {
+	Queries  *expvar.Map // Number of queries run by each method
+	Duration *expvar.Map // Total time in microseconds spent running each method's queries
+}
+
+func NewQueryMetrics() *QueryMetrics {
+	return &QueryMetrics{
+		Queries:  new(expvar.Map).Init(),
+		Duration: new(expvar.Map).Init(),
+	}
+}
+
+// DB wraps a sql.DB connection pool so that each query run through it is recorded in its
+// QueryMetrics against the model method which ran it. A transaction counts as a single query,
+// recorded when it begins, since the statements run inside it go through the sql.Tx instead.
+// With nil metrics DB behaves exactly like the wrapped pool.
+//
+// A DB may also be bound to a transaction (see Models.Transact), in which case every statement
+// runs in that transaction rather than on the pool.
+type DB struct {
+	*sql.DB
+	tx         *Tx
+	savepoints int // number of savepoints begun in tx, used to name them
+	metrics    *QueryMetrics
+}
+
+func NewDB(db *sql.DB, metrics *QueryMetrics) *DB {
+	return &DB{DB: db, metrics: metrics}
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer db.record(time.Now())
+	return db.querier().QueryContext(ctx, query, args...)
+}
+
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	defer db.record(time.Now())
+	return db.querier().Query(query, args...)
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer db.record(time.Now())
+	return db.querier().QueryRowContext(ctx, query, args...)
+}
+
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	defer db.record(time.Now())
+	return db.querier().QueryRow(query, args...)
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer db.record(time.Now())
+	return db.querier().ExecContext(ctx, query, args...)
+}
+
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	defer db.record(time.Now())
+	return db.querier().Exec(query, args...)
+}
+
+// BeginTx begins a transaction. When the DB is bound to a transaction by Models.Transact, it
+// begins a savepoint in that transaction instead (see Tx).
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	defer db.record(time.Now())
+
+	if db.tx != nil {
+		return db.savepoint(ctx, opts)
+	}
+
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+// querier returns the transaction the DB is bound to, if any, or otherwise the pool.
+func (db *DB) querier() Querier {
+	if db.tx != nil {
+		return db.tx
+	}
+	return db.DB
+}
+
+// record adds a query which started at start to the metrics of the model method which called
+// the DB method deferring it.
+func (db *DB) record(start time.Time) {
+	if db.metrics == nil {
+		return
+	}
+
+	method := "unknown"
+	// Skip record itself and the DB method which deferred it.
+	if pc, _, _, ok := runtime.Caller(2); ok {
+		method = methodName(runtime.FuncForPC(pc).Name())
+	}
+
+	db.metrics.Queries.Add(method, 1)
+	db.metrics.Duration.Add(method, time.Since(start).Microseconds())
+}
+
+// methodName turns a fully qualified function name such as
+// "league_of_graphs.satellite.net/internal/data.(*MatchModel).Insert.func1" into the
+// "MatchModel.Insert" form used as a metrics key.
+func methodName(fn string) string {
+	if i := strings.LastIndex(fn, "/"); i >= 0 {
+		fn = fn[i+1:]
+	}
+	// Drop the package name.
+	if i := strings.Index(fn, "."); i >= 0 {
+		fn = fn[i+1:]
+	}
+
+	fn = strings.NewReplacer("(*", "", ")", "").Replace(fn)
+
+	// Keep only the type and method, dropping the suffixes of any closures inside the method.
+	parts := strings.SplitN(fn, ".", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+
+	return strings.Join(parts, ".")
+}
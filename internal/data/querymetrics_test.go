@@ -0,0 +1,47 @@
+package data
+
+import (
+	"expvar"
+	"testing"
+)
+
+func TestMethodName(t *testing.T) {
+	tests := []struct {
+		fn   string
+		want string
+	}{
+		{"league_of_graphs.satellite.net/internal/data.SummonerModel.Get", "SummonerModel.Get"},
+		{"league_of_graphs.satellite.net/internal/data.(*MatchModel).Insert", "MatchModel.Insert"},
+		{"league_of_graphs.satellite.net/internal/data.(*MatchModel).Insert.func1", "MatchModel.Insert"},
+		{"league_of_graphs.satellite.net/internal/data.ChampionModel.GetAll.func2.1", "ChampionModel.GetAll"},
+	}
+
+	for _, tt := range tests {
+		if got := methodName(tt.fn); got != tt.want {
+			t.Errorf("methodName(%q) = %q, want %q", tt.fn, got, tt.want)
+		}
+	}
+}
+
+func TestQueryMetricsCountsModelCalls(t *testing.T) {
+	unmetered := newTestModels(t)
+	summoner := insertTestSummoner(t, unmetered, "Faker")
+
+	// newTestModels doesn't record metrics, so wrap the same pool again with some.
+	metrics := NewQueryMetrics()
+	models := NewModels(unmetered.Summoners.DB.DB, metrics)
+
+	for i := 0; i < 2; i++ {
+		if _, err := models.Summoners.Get(summoner.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queries, ok := metrics.Queries.Get("SummonerModel.Get").(*expvar.Int)
+	if !ok || queries.Value() != 2 {
+		t.Errorf("got %v queries recorded for SummonerModel.Get, want 2", metrics.Queries.Get("SummonerModel.Get"))
+	}
+	if _, ok := metrics.Duration.Get("SummonerModel.Get").(*expvar.Int); !ok {
+		t.Error("got no duration recorded for SummonerModel.Get")
+	}
+}
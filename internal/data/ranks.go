@@ -0,0 +1,72 @@
+package data
+
+import (
+	"errors"
+	"strings"
+)
+
+// RankTier is a named band of summoner ratings.
+type RankTier struct {
+	Name      string
+	MinRating int
+}
+
+// RankTiers lists the rank tiers from lowest to highest. The same thresholds are used by the
+// rank_tier() database function, which stamps each match performance with the summoner's tier.
+var RankTiers = []RankTier{
+	{"Iron", 0},
+	{"Bronze", 400},
+	{"Silver", 800},
+	{"Gold", 1200},
+	{"Platinum", 1600},
+	{"Emerald", 2000},
+	{"Diamond", 2400},
+	{"Master", 2800},
+	{"Grandmaster", 3200},
+	{"Challenger", 3600},
+}
+
+// TierForRating returns the name of the rank tier a rating falls in.
+func TierForRating(rating int) string {
+	tier := RankTiers[0].Name
+	for _, t := range RankTiers {
+		if rating >= t.MinRating {
+			tier = t.Name
+		}
+	}
+	return tier
+}
+
+// ParseRankFilter parses a rank filter into the tiers it covers. A tier name on its own ("Gold")
+// covers just that tier, while a trailing "+" ("Diamond+") covers that tier and every tier above
+// it. Tier names are case-insensitive.
+func ParseRankFilter(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	andAbove := strings.HasSuffix(s, "+")
+	s = strings.TrimSpace(strings.TrimSuffix(s, "+"))
+
+	for i, tier := range RankTiers {
+		if !strings.EqualFold(tier.Name, s) {
+			continue
+		}
+
+		if !andAbove {
+			return []string{tier.Name}, nil
+		}
+
+		var tiers []string
+		for _, t := range RankTiers[i:] {
+			tiers = append(tiers, t.Name)
+		}
+		return tiers, nil
+	}
+
+	return nil, errors.New("unknown rank tier")
+}
+
+// StatsScope narrows the matches which champion statistics are computed from. The zero value
+// includes every match.
+type StatsScope struct {
+	MatchType string   // Only count matches of this type, if not empty
+	Tiers     []string // Only count performances by summoners in these rank tiers, if not empty
+}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTierForRating(t *testing.T) {
+	tests := []struct {
+		rating int
+		want   string
+	}{
+		{-50, "Iron"},
+		{0, "Iron"},
+		{399, "Iron"},
+		{400, "Bronze"},
+		{2399, "Emerald"},
+		{2400, "Diamond"},
+		{3600, "Challenger"},
+		{9999, "Challenger"},
+	}
+
+	for _, tt := range tests {
+		if got := TierForRating(tt.rating); got != tt.want {
+			t.Errorf("TierForRating(%d) = %q, want %q", tt.rating, got, tt.want)
+		}
+	}
+}
+
+func TestParseRankFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"Gold", []string{"Gold"}},
+		{"gold", []string{"Gold"}},
+		{" Gold ", []string{"Gold"}},
+		{"Diamond+", []string{"Diamond", "Master", "Grandmaster", "Challenger"}},
+		{"master +", []string{"Master", "Grandmaster", "Challenger"}},
+		{"Challenger+", []string{"Challenger"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRankFilter(tt.filter)
+		if err != nil {
+			t.Errorf("ParseRankFilter(%q): %v", tt.filter, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRankFilter(%q) = %q, want %q", tt.filter, got, tt.want)
+		}
+	}
+
+	for _, filter := range []string{"", "+", "Plastic", "Gold++", "Gold Diamond"} {
+		if got, err := ParseRankFilter(filter); err == nil {
+			t.Errorf("ParseRankFilter(%q) = %q, want an error", filter, got)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package data
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ChampionRoleStats holds a champion's record in a single role, across all non-remake matches.
+type ChampionRoleStats struct {
+	ChampionID int64   `json:"championId"`
+	Name       string  `json:"name"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	WinRate    float64 `json:"winRate"`
+	PickRate   float64 `json:"pickRate"` // Share of matches in which the champion was picked in the role
+}
+
+// BanWeights tunes the scoring used by RankBans. A champion's ban score is
+//
+//	PickRate*PickRateWeight + (WinRate-0.5)*WinRateWeight
+//
+// so a champion which is both picked often and wins more than it loses scores highest.
+type BanWeights struct {
+	PickRateWeight float64
+	WinRateWeight  float64
+}
+
+// BanRecommendation is a champion suggested for banning, with the score which ranked it.
+type BanRecommendation struct {
+	ChampionRoleStats
+	Score float64 `json:"score"`
+}
+
+// Score returns the ban score for a champion's role stats.
+func (w BanWeights) Score(stats *ChampionRoleStats) float64 {
+	return stats.PickRate*w.PickRateWeight + (stats.WinRate-0.5)*w.WinRateWeight
+}
+
+// RankBans scores each champion with at least minGames games, and returns the top limit
+// recommendations, highest score first.
+func RankBans(stats []*ChampionRoleStats, weights BanWeights, minGames int, limit int) []*BanRecommendation {
+	recommendations := []*BanRecommendation{}
+
+	for _, s := range stats {
+		if s.Games < minGames {
+			continue
+		}
+		recommendations = append(recommendations, &BanRecommendation{ChampionRoleStats: *s, Score: weights.Score(s)})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	return recommendations
+}
+
+// GetRoleStats returns the record of every champion which has been played in the given role,
+// counting only the matches and performances within scope.
+func (c ChampionModel) GetRoleStats(role string, scope StatsScope) ([]*ChampionRoleStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Count the matches in which anyone played the role, to work out each champion's pick rate.
+	var totalMatches int
+	err := c.DB.QueryRowContext(ctx, `
+        SELECT count(DISTINCT mp.match_id)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        WHERE LOWER(mp.role) = LOWER($1) AND LOWER(m.result) <> $2
+        AND (m.match_type = $3 OR $3 = '')
+        AND (cardinality($4::text[]) = 0 OR mp.rank_tier = ANY($4))`,
+		role, ResultRemake, scope.MatchType, pq.Array(scope.Tiers)).Scan(&totalMatches)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+        SELECT c.id, c.name, count(*), count(*) FILTER (WHERE LOWER(m.result) = mp.team)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        JOIN champions c ON c.id = mp.champion_id
+        WHERE LOWER(mp.role) = LOWER($1) AND LOWER(m.result) <> $2
+        AND (m.match_type = $3 OR $3 = '')
+        AND (cardinality($4::text[]) = 0 OR mp.rank_tier = ANY($4))
+        GROUP BY c.id, c.name
+        ORDER BY c.id`
+
+	rows, err := c.DB.QueryContext(ctx, query, role, ResultRemake, scope.MatchType, pq.Array(scope.Tiers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []*ChampionRoleStats{}
+
+	for rows.Next() {
+		var s ChampionRoleStats
+		err := rows.Scan(&s.ChampionID, &s.Name, &s.Games, &s.Wins)
+		if err != nil {
+			return nil, err
+		}
+
+		s.WinRate = float64(s.Wins) / float64(s.Games)
+		if totalMatches > 0 {
+			s.PickRate = float64(s.Games) / float64(totalMatches)
+		}
+
+		stats = append(stats, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// SummonerBanSuggestion is an enemy champion against which a summoner has a poor record.
+type SummonerBanSuggestion struct {
+	ChampionID int64   `json:"championId"`
+	Name       string  `json:"name"`
+	Games      int     `json:"games"`   // Games the summoner played with the champion on the enemy team
+	Wins       int     `json:"wins"`    // Games of those the summoner won
+	WinRate    float64 `json:"winRate"` // The summoner's win rate against the champion
+}
+
+// GetBanSuggestions returns the enemy champions the summoner has the lowest win rate against,
+// lowest first, counting only champions faced in at least minGames non-remake matches. Ties are
+// broken by the number of games, so the better evidenced weakness comes first.
+func (m SummonerModel) GetBanSuggestions(id int64, minGames int, limit int) ([]*SummonerBanSuggestion, error) {
+	query := `
+        SELECT e.champion_id, c.name, count(*) AS games,
+            count(*) FILTER (WHERE LOWER(m.result) = mp.team) AS wins
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        JOIN match_performance e ON e.match_id = mp.match_id AND e.team <> mp.team
+        JOIN champions c ON c.id = e.champion_id
+        WHERE mp.summoner_id = $1 AND LOWER(m.result) <> $2
+        GROUP BY e.champion_id, c.name
+        HAVING count(*) >= $3
+        ORDER BY count(*) FILTER (WHERE LOWER(m.result) = mp.team)::float8 / count(*) ASC,
+            count(*) DESC, e.champion_id ASC
+        LIMIT $4`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, id, ResultRemake, minGames, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []*SummonerBanSuggestion{}
+
+	for rows.Next() {
+		var s SummonerBanSuggestion
+		err := rows.Scan(&s.ChampionID, &s.Name, &s.Games, &s.Wins)
+		if err != nil {
+			return nil, err
+		}
+
+		s.WinRate = float64(s.Wins) / float64(s.Games)
+		suggestions = append(suggestions, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
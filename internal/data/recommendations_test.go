@@ -0,0 +1,115 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBanWeightsScore(t *testing.T) {
+	weights := BanWeights{PickRateWeight: 1, WinRateWeight: 2}
+
+	tests := []struct {
+		pickRate float64
+		winRate  float64
+		want     float64
+	}{
+		{0.2, 0.5, 0.2},
+		{0.2, 0.6, 0.4},
+		{0.2, 0.4, 0},
+		{0, 0.55, 0.1},
+	}
+
+	for _, tt := range tests {
+		got := weights.Score(&ChampionRoleStats{PickRate: tt.pickRate, WinRate: tt.winRate})
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Score(pick rate %v, win rate %v) = %v, want %v", tt.pickRate, tt.winRate, got, tt.want)
+		}
+	}
+}
+
+func TestRankBans(t *testing.T) {
+	stats := []*ChampionRoleStats{
+		{ChampionID: 1, Games: 50, PickRate: 0.1, WinRate: 0.5},
+		{ChampionID: 2, Games: 50, PickRate: 0.3, WinRate: 0.55},
+		{ChampionID: 3, Games: 5, PickRate: 0.9, WinRate: 0.9},
+		{ChampionID: 4, Games: 50, PickRate: 0.1, WinRate: 0.5},
+		{ChampionID: 5, Games: 10, PickRate: 0.2, WinRate: 0.45},
+	}
+	weights := BanWeights{PickRateWeight: 1, WinRateWeight: 1}
+
+	got := RankBans(stats, weights, 10, 3)
+
+	// Champion 3 has too few games, champions 1 and 4 tie and keep their order, and the limit
+	// cuts champion 4 off.
+	want := []int64{2, 5, 1}
+	if len(got) != len(want) {
+		t.Fatalf("got %d recommendations, want %d", len(got), len(want))
+	}
+	for i, id := range want {
+		if got[i].ChampionID != id {
+			t.Errorf("recommendation %d: got champion %d, want %d", i, got[i].ChampionID, id)
+		}
+	}
+
+	if got := RankBans(nil, weights, 10, 3); got == nil || len(got) != 0 {
+		t.Errorf("got %v for no stats, want an empty slice", got)
+	}
+}
+
+func TestGetBanSuggestions(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := insertTestSummoner(t, models, "Faker")
+	enemy := insertTestSummoner(t, models, "Caps")
+	ahri := insertTestChampion(t, models, "Ahri")
+	zed := insertTestChampion(t, models, "Zed")
+	syndra := insertTestChampion(t, models, "Syndra")
+	yasuo := insertTestChampion(t, models, "Yasuo")
+
+	// Faker plays Ahri on the blue team against the enemy champion on the red team.
+	face := func(enemyChampion *Champion, result string) {
+		var matchID int64
+		err := models.Matches.DB.QueryRow(`
+            INSERT INTO matches (duration, result, match_type, blue_team, red_team)
+            VALUES (1800, $1, 'solo_queue', '{}', '{}')
+            RETURNING id`, result).Scan(&matchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = models.Matches.DB.Exec(`
+            INSERT INTO match_performance (match_id, summoner_id, champion_id, team)
+            VALUES ($1, $2, $3, $4), ($1, $5, $6, $7)`,
+			matchID, faker.ID, ahri.ID, ResultBlue, enemy.ID, enemyChampion.ID, ResultRed)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, result := range []string{ResultRed, ResultRed, ResultRed, ResultRemake} {
+		face(zed, result)
+	}
+	for _, result := range []string{ResultBlue, ResultBlue, ResultRed} {
+		face(syndra, result)
+	}
+	// A single loss is too few games to suggest a ban.
+	face(yasuo, ResultRed)
+
+	suggestions, err := models.Summoners.GetBanSuggestions(faker.ID, 2, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, s := range suggestions {
+		got = append(got, fmt.Sprintf("%s:%d/%d", s.Name, s.Wins, s.Games))
+	}
+
+	// The remake against Zed isn't counted.
+	want := []string{"Zed:0/3", "Syndra:2/3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got suggestions %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+// ChampionStatsChange is a champion's stats before and after a recompute.
+type ChampionStatsChange struct {
+	ChampionID    int64   `json:"championId"`
+	Name          string  `json:"name"`
+	OldWinRate    float64 `json:"oldWinRate"`
+	NewWinRate    float64 `json:"newWinRate"`
+	OldPopularity float64 `json:"oldPopularity"`
+	NewPopularity float64 `json:"newPopularity"`
+}
+
+// RecomputeStats recalculates the match and win counts, win rate and popularity (the number of
+// distinct summoners who have played it) of every champion with a match history from
+// match_performance, fixing any drift in the incrementally maintained values. Remakes are
+// excluded. Champions without any performances are left alone, since their stats may predate
+// performance history. The per match type and rank tier aggregates in champion_scoped_stats are
+// rebuilt as well. It returns the champions whose overall stats changed. When dryRun is true the
+// update is run inside a transaction which is then rolled back, so the changes are only reported.
+func (c ChampionModel) RecomputeStats(dryRun bool) ([]*ChampionStatsChange, error) {
+	// c2 is read from the snapshot taken before the update, so it holds the old values.
+	query := `
+        UPDATE champions c
+        SET count_of_played_matches = s.played, wins = s.wins, win_rate = s.win_rate, popularity = s.popularity
+        FROM champions c2
+        JOIN (
+            SELECT mp.champion_id,
+                count(*) AS played,
+                count(*) FILTER (WHERE LOWER(m.result) = mp.team) AS wins,
+                avg(CASE WHEN LOWER(m.result) = mp.team THEN 1 ELSE 0 END)::float8 AS win_rate,
+                count(DISTINCT mp.summoner_id)::float8 AS popularity
+            FROM match_performance mp
+            JOIN matches m ON m.id = mp.match_id
+            WHERE LOWER(m.result) <> $1
+            GROUP BY mp.champion_id
+        ) s ON s.champion_id = c2.id
+        WHERE c.id = c2.id
+        AND (c.count_of_played_matches, c.wins, c.win_rate, c.popularity)
+            IS DISTINCT FROM (s.played, s.wins, s.win_rate, s.popularity)
+        RETURNING c.id, c.name, COALESCE(c2.win_rate, 0), c.win_rate, COALESCE(c2.popularity, 0), c.popularity`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, ResultRemake)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []*ChampionStatsChange{}
+
+	for rows.Next() {
+		var change ChampionStatsChange
+		err := rows.Scan(&change.ChampionID, &change.Name, &change.OldWinRate, &change.NewWinRate, &change.OldPopularity, &change.NewPopularity)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM champion_scoped_stats`)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO champion_scoped_stats (champion_id, summoner_id, match_type, rank_tier, count_of_played_matches, wins)
+        SELECT mp.champion_id, mp.summoner_id, m.match_type, mp.rank_tier, count(*),
+            count(*) FILTER (WHERE LOWER(m.result) = mp.team)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        WHERE LOWER(m.result) <> $1
+        GROUP BY mp.champion_id, mp.summoner_id, m.match_type, mp.rank_tier`, ResultRemake)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return changes, tx.Rollback()
+	}
+
+	return changes, tx.Commit()
+}
+
+// RecomputeStats recalculates the game and win counts, win rate and average KDA of every summoner
+// with a match history from match_performance, fixing any drift in the incrementally maintained
+// values. Remakes are excluded, and summoners without any performances are left alone. It returns
+// the number of summoners whose stats changed. When dryRun is true the update is run inside a
+// transaction which is then rolled back, so the changes are only counted.
+func (m SummonerModel) RecomputeStats(dryRun bool) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, recomputeSummonerStatsQuery, ResultRemake, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if dryRun {
+		return rowsAffected, tx.Rollback()
+	}
+
+	return rowsAffected, tx.Commit()
+}
+
+// recomputeSummonerStatsQuery rebuilds summoners' aggregates from match_performance. It expects
+// the remake result as $1 and a summoner ID as $2, or 0 to recompute every summoner. Summoners
+// without any non-remake performances aren't updated.
+const recomputeSummonerStatsQuery = `
+        UPDATE summoners su
+        SET count_of_played_games = s.games, wins = s.wins, win_rate = s.win_rate, average_kda = s.average_kda
+        FROM (
+            SELECT mp.summoner_id,
+                count(*)::int AS games,
+                count(*) FILTER (WHERE LOWER(m.result) = mp.team)::int AS wins,
+                avg(CASE WHEN LOWER(m.result) = mp.team THEN 1 ELSE 0 END)::float8 AS win_rate,
+                jsonb_build_object(
+                    'Kills', sum(mp.kills) / count(*),
+                    'Deaths', sum(mp.deaths) / count(*),
+                    'Assists', sum(mp.assists) / count(*)
+                ) AS average_kda
+            FROM match_performance mp
+            JOIN matches m ON m.id = mp.match_id
+            WHERE LOWER(m.result) <> $1 AND ($2 = 0 OR mp.summoner_id = $2)
+            GROUP BY mp.summoner_id
+        ) s
+        WHERE su.id = s.summoner_id
+        AND (su.count_of_played_games, su.wins, su.win_rate, su.average_kda)
+            IS DISTINCT FROM (s.games, s.wins, s.win_rate, s.average_kda)`
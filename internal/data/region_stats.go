@@ -0,0 +1,159 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// RegionWeights holds the weight each region's win rate carries in a champion's global win rate,
+// keyed by region code. An empty RegionWeights weighs every region equally; otherwise regions
+// without a weight are left out.
+type RegionWeights map[string]float64
+
+// ParseRegionWeights parses a comma-separated list of weights in the form "REGION:weight", e.g.
+// "EUW:1,KR:1,NA:0.5". Region codes are case-insensitive. Weights must not be negative, and at
+// least one must be greater than zero.
+func ParseRegionWeights(s string) (RegionWeights, error) {
+	weights := RegionWeights{}
+
+	var total float64
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		region, weight, ok := strings.Cut(entry, ":")
+		region = NormalizeRegion(region)
+		if !ok || !validator.In(region, ValidRegions...) {
+			return nil, fmt.Errorf("invalid region weight %q: must be a region code and a weight, e.g. EUW:1", entry)
+		}
+		if _, ok := weights[region]; ok {
+			return nil, fmt.Errorf("invalid region weight %q: %s is weighted more than once", entry, region)
+		}
+
+		w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid region weight %q: weight must be a number not less than zero", entry)
+		}
+
+		weights[region] = w
+		total += w
+	}
+
+	if len(weights) > 0 && total <= 0 {
+		return nil, fmt.Errorf("invalid region weights %q: at least one weight must be greater than zero", s)
+	}
+
+	return weights, nil
+}
+
+// weight returns the weight of a region.
+func (w RegionWeights) weight(region string) float64 {
+	if len(w) == 0 {
+		return 1
+	}
+	return w[region]
+}
+
+// RegionStats is a champion's record in the matches played by summoners of one region.
+type RegionStats struct {
+	Region  string  `json:"region"`
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	WinRate float64 `json:"winRate"`
+	Weight  float64 `json:"weight"` // Share of the global win rate, from 0 to 1
+}
+
+// GlobalStats combines a champion's per-region records into a global one. WinRate weighs each
+// region's win rate by its RegionWeights weight, so that the largest regions don't drown out the
+// others; PooledWinRate is the plain win rate over every game, for comparison.
+type GlobalStats struct {
+	ChampionID    int64          `json:"championId"`
+	Games         int            `json:"games"`
+	Wins          int            `json:"wins"`
+	WinRate       float64        `json:"winRate"`
+	PooledWinRate float64        `json:"pooledWinRate"`
+	Regions       []*RegionStats `json:"regions"`
+}
+
+// NewGlobalStats combines per-region records using the given weights. Regions without games don't
+// count towards the weighted win rate, and the remaining weights are scaled to add up to one. If
+// no region with games has any weight, WinRate is zero.
+func NewGlobalStats(championID int64, regions []*RegionStats, weights RegionWeights) *GlobalStats {
+	global := &GlobalStats{ChampionID: championID, Regions: regions}
+
+	var total float64
+	for _, region := range regions {
+		if region.Games > 0 {
+			total += weights.weight(region.Region)
+		}
+	}
+
+	for _, region := range regions {
+		global.Games += region.Games
+		global.Wins += region.Wins
+
+		if region.Games == 0 {
+			continue
+		}
+		region.WinRate = float64(region.Wins) / float64(region.Games)
+
+		if total > 0 {
+			region.Weight = weights.weight(region.Region) / total
+			global.WinRate += region.Weight * region.WinRate
+		}
+	}
+
+	if global.Games > 0 {
+		global.PooledWinRate = float64(global.Wins) / float64(global.Games)
+	}
+
+	return global
+}
+
+// GetRegionStats returns the champion's record in each region its players come from, ordered by
+// region. Remakes are excluded, as are matches outside scope.
+func (c ChampionModel) GetRegionStats(championID int64, scope StatsScope) ([]*RegionStats, error) {
+	query := `
+        SELECT s.region, count(*), count(*) FILTER (WHERE LOWER(m.result) = mp.team)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        JOIN summoners s ON s.id = mp.summoner_id
+        WHERE mp.champion_id = $1 AND LOWER(m.result) <> $2
+        AND (m.match_type = $3 OR $3 = '')
+        AND (cardinality($4::text[]) = 0 OR mp.rank_tier = ANY($4))
+        GROUP BY s.region
+        ORDER BY s.region`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, championID, ResultRemake, scope.MatchType, pq.Array(scope.Tiers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	regions := []*RegionStats{}
+
+	for rows.Next() {
+		var region RegionStats
+		if err := rows.Scan(&region.Region, &region.Games, &region.Wins); err != nil {
+			return nil, err
+		}
+		regions = append(regions, &region)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return regions, nil
+}
@@ -0,0 +1,100 @@
+package data
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseRegionWeights(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    RegionWeights
+		wantErr bool
+	}{
+		{"", RegionWeights{}, false},
+		{"EUW:1,KR:1,NA:0.5", RegionWeights{"EUW": 1, "KR": 1, "NA": 0.5}, false},
+		{" euw : 2 , kr:0 ", RegionWeights{"EUW": 2, "KR": 0}, false},
+		{"EUW", nil, true},
+		{"XX:1", nil, true},
+		{"EUW:1,euw:2", nil, true},
+		{"EUW:-1", nil, true},
+		{"EUW:heavy", nil, true},
+		// Weights of zero alone would leave nothing to weigh by.
+		{"EUW:0,KR:0", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRegionWeights(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRegionWeights(%q): got error %v, want error %t", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRegionWeights(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestNewGlobalStats(t *testing.T) {
+	// EUW plays ten times as many games as KR, so dominates the pooled win rate of 640/1100.
+	regions := func() []*RegionStats {
+		return []*RegionStats{
+			{Region: "EUW", Games: 1000, Wins: 600},
+			{Region: "KR", Games: 100, Wins: 40},
+			{Region: "NA", Games: 0, Wins: 0},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		weights RegionWeights
+		want    float64
+	}{
+		// NA has no games, so doesn't dilute the others' weights.
+		{"equal weights", RegionWeights{}, 0.5},
+		{"KR weighted higher", RegionWeights{"EUW": 1, "KR": 3, "NA": 1}, 0.45},
+		{"KR left out", RegionWeights{"EUW": 1}, 0.6},
+		{"no region with games weighted", RegionWeights{"NA": 1}, 0},
+	}
+
+	for _, tt := range tests {
+		global := NewGlobalStats(7, regions(), tt.weights)
+
+		if global.Games != 1100 || global.Wins != 640 {
+			t.Errorf("%s: got %d wins in %d games, want 640 in 1100", tt.name, global.Wins, global.Games)
+		}
+		if math.Abs(global.PooledWinRate-640.0/1100) > 1e-9 {
+			t.Errorf("%s: got pooled win rate %v, want %v", tt.name, global.PooledWinRate, 640.0/1100)
+		}
+		if math.Abs(global.WinRate-tt.want) > 1e-9 {
+			t.Errorf("%s: got win rate %v, want %v", tt.name, global.WinRate, tt.want)
+		}
+		if global.WinRate == global.PooledWinRate {
+			t.Errorf("%s: got a weighted win rate equal to the pooled one", tt.name)
+		}
+	}
+}
+
+func TestNewGlobalStatsRegionWeights(t *testing.T) {
+	global := NewGlobalStats(7, []*RegionStats{
+		{Region: "EUW", Games: 30, Wins: 15},
+		{Region: "KR", Games: 10, Wins: 8},
+		{Region: "NA", Games: 0, Wins: 0},
+	}, RegionWeights{"EUW": 1, "KR": 3, "NA": 1})
+
+	want := []struct {
+		winRate, weight float64
+	}{
+		{0.5, 0.25},
+		{0.8, 0.75},
+		{0, 0},
+	}
+
+	for i, region := range global.Regions {
+		if math.Abs(region.WinRate-want[i].winRate) > 1e-9 || math.Abs(region.Weight-want[i].weight) > 1e-9 {
+			t.Errorf("%s: got win rate %v and weight %v, want %v and %v",
+				region.Region, region.WinRate, region.Weight, want[i].winRate, want[i].weight)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+// RoleDrift compares the role a champion has mostly been played in recently with its stored main
+// role. A large drift suggests the meta has moved the champion to a new role.
+type RoleDrift struct {
+	ChampionID      int64   `json:"championId"`
+	Name            string  `json:"name"`
+	MainRole        string  `json:"mainRole"`
+	RecentRole      string  `json:"recentRole"`      // Role the champion was played in most in the window
+	Games           int     `json:"games"`           // Games played in the window, in any role
+	MainRoleShare   float64 `json:"mainRoleShare"`   // Share of those games played in the main role
+	RecentRoleShare float64 `json:"recentRoleShare"` // Share of those games played in the recent role
+	Drift           float64 `json:"drift"`           // RecentRoleShare - MainRoleShare (0 if the roles are the same)
+}
+
+// roleDriftQuery computes the RoleDrift of every champion with at least $3 games played since $1,
+// or only of champion $2 unless it's 0, largest drift first.
+const roleDriftQuery = `
+        WITH recent AS (
+            SELECT mp.champion_id, mp.role, count(*) AS games
+            FROM match_performance mp
+            JOIN matches m ON m.id = mp.match_id
+            WHERE mp.role <> '' AND m.played_date >= $1
+            AND ($2 = 0 OR mp.champion_id = $2)
+            GROUP BY mp.champion_id, mp.role
+        ), totals AS (
+            SELECT champion_id, sum(games) AS games
+            FROM recent
+            GROUP BY champion_id
+        ), dominant AS (
+            SELECT DISTINCT ON (champion_id) champion_id, role, games
+            FROM recent
+            ORDER BY champion_id, games DESC, role ASC
+        )
+        SELECT c.id, c.name, c.main_role, d.role, totals.games,
+            COALESCE(mr.games, 0)::float8 / totals.games,
+            d.games::float8 / totals.games
+        FROM dominant d
+        JOIN totals ON totals.champion_id = d.champion_id
+        JOIN champions c ON c.id = d.champion_id
+        LEFT JOIN recent mr ON mr.champion_id = d.champion_id AND LOWER(mr.role) = LOWER(c.main_role)
+        WHERE totals.games >= $3
+        ORDER BY d.games::float8 / totals.games - COALESCE(mr.games, 0)::float8 / totals.games DESC, c.id ASC`
+
+// GetRoleDrifts returns the role drift of every champion with at least minGames games played in
+// the last days days, largest drift first.
+func (c ChampionModel) GetRoleDrifts(days int, minGames int) ([]*RoleDrift, error) {
+	return c.getRoleDrifts(0, days, minGames)
+}
+
+// GetRoleDrift returns the role drift of a champion over the last days days, or nil if it has
+// fewer than minGames games in that time.
+func (c ChampionModel) GetRoleDrift(id int64, days int, minGames int) (*RoleDrift, error) {
+	drifts, err := c.getRoleDrifts(id, days, minGames)
+	if err != nil || len(drifts) == 0 {
+		return nil, err
+	}
+	return drifts[0], nil
+}
+
+func (c ChampionModel) getRoleDrifts(id int64, days int, minGames int) ([]*RoleDrift, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	since := time.Now().AddDate(0, 0, -days)
+
+	rows, err := c.DB.QueryContext(ctx, roleDriftQuery, since, id, minGames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drifts := []*RoleDrift{}
+
+	for rows.Next() {
+		var d RoleDrift
+		err := rows.Scan(&d.ChampionID, &d.Name, &d.MainRole, &d.RecentRole, &d.Games, &d.MainRoleShare, &d.RecentRoleShare)
+		if err != nil {
+			return nil, err
+		}
+
+		d.Drift = d.RecentRoleShare - d.MainRoleShare
+		drifts = append(drifts, &d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return drifts, nil
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// insertTestRolePerformance stores a blue win played at playedDate, in which the summoner played
+// the champion in role.
+func insertTestRolePerformance(t *testing.T, models Models, summonerID, championID int64, role string, playedDate time.Time) {
+	t.Helper()
+
+	var matchID int64
+	err := models.Matches.DB.QueryRow(`
+        INSERT INTO matches (duration, result, match_type, played_date, blue_team, red_team)
+        VALUES (1800, $1, 'solo_queue', $2, '{}', '{}')
+        RETURNING id`, ResultBlue, playedDate).Scan(&matchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = models.Matches.DB.Exec(`
+        INSERT INTO match_performance (match_id, summoner_id, champion_id, team, role)
+        VALUES ($1, $2, $3, $4, $5)`, matchID, summonerID, championID, ResultBlue, role)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetRoleDrifts(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	ahri := insertTestChampion(t, models, "Ahri")
+	syndra := insertTestChampion(t, models, "Syndra")
+
+	recent := time.Now().AddDate(0, 0, -1)
+	old := time.Now().AddDate(0, 0, -30)
+
+	// Ahri has moved from mid to top lately; her older mid games are outside the window.
+	for _, role := range []string{"Mid", "Top", "Top", "Top"} {
+		insertTestRolePerformance(t, models, summoner.ID, ahri.ID, role, recent)
+	}
+	for i := 0; i < 5; i++ {
+		insertTestRolePerformance(t, models, summoner.ID, ahri.ID, "Mid", old)
+	}
+
+	// Syndra is still played mid.
+	for i := 0; i < 4; i++ {
+		insertTestRolePerformance(t, models, summoner.ID, syndra.ID, "Mid", recent)
+	}
+
+	drifts, err := models.Champions.GetRoleDrifts(14, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 2 {
+		t.Fatalf("got %d drifts, want 2", len(drifts))
+	}
+
+	got := drifts[0]
+	if got.ChampionID != ahri.ID || got.RecentRole != "Top" || got.Games != 4 {
+		t.Errorf("got %+v first, want Ahri's drift to top over 4 games", got)
+	}
+	if math.Abs(got.MainRoleShare-0.25) > 1e-9 || math.Abs(got.RecentRoleShare-0.75) > 1e-9 || math.Abs(got.Drift-0.5) > 1e-9 {
+		t.Errorf("got shares %v and %v with drift %v, want 0.25, 0.75 and 0.5", got.MainRoleShare, got.RecentRoleShare, got.Drift)
+	}
+
+	if got := drifts[1]; got.ChampionID != syndra.ID || got.Drift != 0 {
+		t.Errorf("got %+v second, want Syndra without drift", got)
+	}
+
+	// Champions with too few recent games are left out.
+	drifts, err = models.Champions.GetRoleDrifts(14, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("got %d drifts with a minimum of 5 games, want none", len(drifts))
+	}
+}
@@ -0,0 +1,29 @@
+package data
+
+// Semaphore bounds how many goroutines can hold it at once; the rest wait their turn in
+// Acquire. A nil Semaphore never blocks.
+type Semaphore chan struct{}
+
+// NewSemaphore returns a Semaphore which n goroutines can hold at once, or nil (no limit) if n
+// isn't positive.
+func NewSemaphore(n int) Semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(Semaphore, n)
+}
+
+// Acquire waits until the semaphore has room, then takes a slot. Each Acquire must be paired with
+// a Release.
+func (s Semaphore) Acquire() {
+	if s != nil {
+		s <- struct{}{}
+	}
+}
+
+// Release gives back a slot taken by Acquire.
+func (s Semaphore) Release() {
+	if s != nil {
+		<-s
+	}
+}
@@ -0,0 +1,65 @@
+package data
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSemaphoreBoundsConcurrency(t *testing.T) {
+	const limit, workers = 3, 20
+
+	sem := NewSemaphore(limit)
+
+	var running, peak int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem.Acquire()
+			defer sem.Release()
+
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+
+			// Hold the slot long enough for the other workers to pile up behind it.
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+
+	wg.Wait()
+
+	if peak > limit {
+		t.Errorf("got %d holders at once, want at most %d", peak, limit)
+	}
+	if peak < limit {
+		t.Errorf("got at most %d holders at once, want the limit of %d to be reached", peak, limit)
+	}
+}
+
+func TestNewSemaphoreUnlimited(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		sem := NewSemaphore(n)
+		if sem != nil {
+			t.Errorf("NewSemaphore(%d) = %v, want nil", n, sem)
+		}
+
+		// A nil semaphore never blocks, however many times it's acquired.
+		for i := 0; i < 100; i++ {
+			sem.Acquire()
+		}
+		for i := 0; i < 100; i++ {
+			sem.Release()
+		}
+	}
+}
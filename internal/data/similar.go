@@ -0,0 +1,72 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+// SimilarChampion is a champion played by the same summoners as another champion.
+type SimilarChampion struct {
+	ChampionID      int64   `json:"championId"`
+	Name            string  `json:"name"`
+	MainRole        string  `json:"mainRole"`
+	SharedSummoners int     `json:"sharedSummoners"` // Summoners who have played both champions
+	Score           float64 `json:"score"`
+}
+
+// GetSimilar returns the champions most often played by the summoners who play the given
+// champion, highest score first. The score is the cosine similarity of the two champions' sets of
+// summoners, shared / sqrt(players of the champion * players of the other champion), so a
+// champion played by everyone doesn't top every list just for being popular.
+func (c ChampionModel) GetSimilar(id int64, limit int) ([]*SimilarChampion, error) {
+	query := `
+        WITH players AS (
+            SELECT champion_id, count(DISTINCT summoner_id) AS summoners
+            FROM summoner_champion_stats
+            WHERE count_of_played_matches > 0
+            GROUP BY champion_id
+        ), shared AS (
+            SELECT other.champion_id, count(DISTINCT other.summoner_id) AS summoners
+            FROM summoner_champion_stats mine
+            JOIN summoner_champion_stats other
+                ON other.summoner_id = mine.summoner_id AND other.champion_id <> mine.champion_id
+            WHERE mine.champion_id = $1
+            AND mine.count_of_played_matches > 0 AND other.count_of_played_matches > 0
+            GROUP BY other.champion_id
+        )
+        SELECT c.id, c.name, c.main_role, shared.summoners,
+            shared.summoners / sqrt(mine.summoners::float8 * theirs.summoners)
+        FROM shared
+        JOIN champions c ON c.id = shared.champion_id
+        JOIN players theirs ON theirs.champion_id = shared.champion_id
+        JOIN players mine ON mine.champion_id = $1
+        ORDER BY 5 DESC, shared.summoners DESC, c.id ASC
+        LIMIT $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	similar := []*SimilarChampion{}
+
+	for rows.Next() {
+		var s SimilarChampion
+		err := rows.Scan(&s.ChampionID, &s.Name, &s.MainRole, &s.SharedSummoners, &s.Score)
+		if err != nil {
+			return nil, err
+		}
+
+		similar = append(similar, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return similar, nil
+}
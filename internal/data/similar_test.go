@@ -0,0 +1,76 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetSimilar(t *testing.T) {
+	models := newTestModels(t)
+
+	ahri := insertTestChampion(t, models, "Ahri")
+	syndra := insertTestChampion(t, models, "Syndra")
+	orianna := insertTestChampion(t, models, "Orianna")
+	garen := insertTestChampion(t, models, "Garen")
+
+	// Ahri is played by Faker, Caps and Chovy; Syndra by Faker and Caps; Orianna by Faker, Chovy,
+	// Zeus and Keria; Garen by Zeus only. Caps' Garen record has no games, so doesn't count.
+	faker := insertTestSummoner(t, models, "Faker")
+	caps := insertTestSummoner(t, models, "Caps")
+	chovy := insertTestSummoner(t, models, "Chovy")
+	zeus := insertTestSummoner(t, models, "Zeus")
+	keria := insertTestSummoner(t, models, "Keria")
+
+	played := []struct {
+		summoner *Summoner
+		champion *Champion
+		matches  int
+	}{
+		{faker, ahri, 5}, {caps, ahri, 2}, {chovy, ahri, 1},
+		{faker, syndra, 3}, {caps, syndra, 4},
+		{faker, orianna, 1}, {chovy, orianna, 2}, {zeus, orianna, 6}, {keria, orianna, 1},
+		{zeus, garen, 9}, {caps, garen, 0},
+	}
+	for _, p := range played {
+		_, err := models.Champions.DB.Exec(`
+            INSERT INTO summoner_champion_stats (summoner_id, champion_id, count_of_played_matches, win_rate)
+            VALUES ($1, $2, $3, 0.5)`, p.summoner.ID, p.champion.ID, p.matches)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	similar, err := models.Champions.GetSimilar(ahri.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Syndra and Orianna each share two players with Ahri, but Orianna has more players of her
+	// own, so scores lower.
+	want := []struct {
+		id     int64
+		shared int
+		score  float64
+	}{
+		{syndra.ID, 2, 2 / math.Sqrt(3*2)},
+		{orianna.ID, 2, 2 / math.Sqrt(3*4)},
+	}
+
+	if len(similar) != len(want) {
+		t.Fatalf("got %d similar champions, want %d", len(similar), len(want))
+	}
+	for i, s := range similar {
+		if s.ChampionID != want[i].id || s.SharedSummoners != want[i].shared || math.Abs(s.Score-want[i].score) > 1e-9 {
+			t.Errorf("got %s with %d shared summoners and a score of %v, want champion %d with %d and %v",
+				s.Name, s.SharedSummoners, s.Score, want[i].id, want[i].shared, want[i].score)
+		}
+	}
+
+	limited, err := models.Champions.GetSimilar(ahri.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited) != 1 || limited[0].ChampionID != syndra.ID {
+		t.Errorf("got %d similar champions with a limit of 1, want only Syndra", len(limited))
+	}
+}
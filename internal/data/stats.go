@@ -0,0 +1,197 @@
+package data
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ObjectiveStats summarizes one objective (e.g. dragons) across every non-remake match, from the
+// point of view of each team in each match.
+type ObjectiveStats struct {
+	Objective          string  `json:"objective"`
+	AvgWinningTeam     float64 `json:"avgWinningTeam"`     // Average taken by the team which won
+	AvgLosingTeam      float64 `json:"avgLosingTeam"`      // Average taken by the team which lost
+	TeamsSecured       int     `json:"teamsSecured"`       // Teams which took at least one
+	WinRateWhenSecured float64 `json:"winRateWhenSecured"` // Win rate of those teams
+	WinCorrelation     float64 `json:"winCorrelation"`     // Phi coefficient between taking at least one and winning
+}
+
+// objectiveKeys maps the objective names used in responses to the keys they're stored under in
+// the teams' JSON.
+var objectiveKeys = [][2]string{
+	{"turrets", "TurretsDestroyed"},
+	{"inhibitors", "InhibitorsDestroyed"},
+	{"heralds", "RiftHeraldsKilled"},
+	{"dragons", "DragonsKilled"},
+	{"barons", "BaronNashorsKilled"},
+}
+
+// firstObjectiveKeys maps the early objective names used in responses to the keys of the flags
+// stored in the teams' JSON.
+var firstObjectiveKeys = [][2]string{
+	{"first_blood", "FirstBlood"},
+	{"first_tower", "FirstTower"},
+	{"first_dragon", "FirstDragon"},
+}
+
+// matchTeamsCTE unpacks every non-remake match into one row per team, with the team's JSON, the
+// opposing team's JSON and whether the team won. It expects the remake result as $1.
+const matchTeamsCTE = `
+        WITH teams AS (
+            SELECT blue_team AS team, red_team AS opponent, LOWER(result) = 'blue' AS won FROM matches WHERE LOWER(result) <> $1
+            UNION ALL
+            SELECT red_team AS team, blue_team AS opponent, LOWER(result) = 'red' AS won FROM matches WHERE LOWER(result) <> $1
+        )`
+
+// splitKeys splits a list of (name, key) pairs into separate name and key slices, for passing to
+// unnest.
+func splitKeys(pairs [][2]string) (names, keys []string) {
+	for _, pair := range pairs {
+		names = append(names, pair[0])
+		keys = append(keys, pair[1])
+	}
+	return names, keys
+}
+
+// WinCorrelation returns the phi coefficient between a team achieving something and winning,
+// given how many teams achieved it, how many of those won, and the totals across all teams. It
+// ranges from -1 to 1, with 0 meaning no relationship; it's 0 if either variable never varies.
+func WinCorrelation(achieved, achievedWins, teams, wins int) float64 {
+	n11 := float64(achievedWins)
+	n10 := float64(achieved - achievedWins)
+	n01 := float64(wins - achievedWins)
+	n00 := float64(teams - achieved - wins + achievedWins)
+
+	denominator := math.Sqrt((n11 + n10) * (n01 + n00) * (n11 + n01) * (n10 + n00))
+	if denominator == 0 {
+		return 0
+	}
+
+	return (n11*n00 - n10*n01) / denominator
+}
+
+// GetObjectiveStats returns league-wide statistics for each objective tracked on Team.
+func (m MatchModel) GetObjectiveStats() ([]*ObjectiveStats, error) {
+	names, keys := splitKeys(objectiveKeys)
+
+	query := matchTeamsCTE + `
+        SELECT o.name,
+            COALESCE(avg(COALESCE((t.team->>o.key)::int, 0)) FILTER (WHERE t.won), 0),
+            COALESCE(avg(COALESCE((t.team->>o.key)::int, 0)) FILTER (WHERE NOT t.won), 0),
+            count(*) FILTER (WHERE COALESCE((t.team->>o.key)::int, 0) > 0),
+            count(*) FILTER (WHERE COALESCE((t.team->>o.key)::int, 0) > 0 AND t.won),
+            count(*),
+            count(*) FILTER (WHERE t.won)
+        FROM teams t
+        CROSS JOIN unnest($2::text[], $3::text[]) WITH ORDINALITY AS o(name, key, ord)
+        GROUP BY o.name, o.ord
+        ORDER BY o.ord`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, ResultRemake, pq.Array(names), pq.Array(keys))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []*ObjectiveStats{}
+
+	for rows.Next() {
+		var s ObjectiveStats
+		var securedWins, teams, wins int
+		err := rows.Scan(&s.Objective, &s.AvgWinningTeam, &s.AvgLosingTeam, &s.TeamsSecured, &securedWins, &teams, &wins)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.TeamsSecured > 0 {
+			s.WinRateWhenSecured = float64(securedWins) / float64(s.TeamsSecured)
+		}
+		s.WinCorrelation = WinCorrelation(s.TeamsSecured, securedWins, teams, wins)
+
+		stats = append(stats, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// CorrelationStats measures how strongly taking an early objective goes with winning the match.
+type CorrelationStats struct {
+	Objective          string  `json:"objective"`
+	Matches            int     `json:"matches"`            // Matches in which either team was recorded as taking it
+	WinRateWhenSecured float64 `json:"winRateWhenSecured"` // Win rate of the team which took it
+	WinCorrelation     float64 `json:"winCorrelation"`     // Phi coefficient between taking it and winning
+}
+
+// GetFirstObjectiveCorrelations returns the win correlation of each early objective (first blood,
+// first tower, first dragon). Matches in which neither team has the flag set, such as those stored
+// before the flags were recorded, are left out of that objective's figures.
+func (m MatchModel) GetFirstObjectiveCorrelations() ([]*CorrelationStats, error) {
+	names, keys := splitKeys(firstObjectiveKeys)
+
+	query := matchTeamsCTE + `
+        SELECT o.name,
+            count(*) FILTER (WHERE COALESCE((t.team->>o.key)::boolean, false)),
+            count(*) FILTER (WHERE COALESCE((t.team->>o.key)::boolean, false) AND t.won),
+            count(*),
+            count(*) FILTER (WHERE t.won)
+        FROM teams t
+        CROSS JOIN unnest($2::text[], $3::text[]) WITH ORDINALITY AS o(name, key, ord)
+        WHERE COALESCE((t.team->>o.key)::boolean, false) OR COALESCE((t.opponent->>o.key)::boolean, false)
+        GROUP BY o.name, o.ord
+        ORDER BY o.ord`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, ResultRemake, pq.Array(names), pq.Array(keys))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make(map[string]*CorrelationStats)
+
+	for rows.Next() {
+		var s CorrelationStats
+		var secured, securedWins, teams, wins int
+		err := rows.Scan(&s.Objective, &secured, &securedWins, &teams, &wins)
+		if err != nil {
+			return nil, err
+		}
+
+		// Each match contributes one row per team.
+		s.Matches = teams / 2
+		if secured > 0 {
+			s.WinRateWhenSecured = float64(securedWins) / float64(secured)
+		}
+		s.WinCorrelation = WinCorrelation(secured, securedWins, teams, wins)
+
+		found[s.Objective] = &s
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Objectives with no recorded matches produce no row, but are still listed.
+	stats := []*CorrelationStats{}
+	for _, name := range names {
+		s, ok := found[name]
+		if !ok {
+			s = &CorrelationStats{Objective: name}
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+// insertTeamsMatch stores a match with the given result and teams' JSON, and no performances.
+func insertTeamsMatch(t *testing.T, models Models, result, blueTeam, redTeam string) {
+	t.Helper()
+
+	_, err := models.Matches.DB.Exec(`
+        INSERT INTO matches (duration, result, match_type, blue_team, red_team)
+        VALUES (1800, $1, 'solo_queue', $2, $3)`, result, blueTeam, redTeam)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetObjectiveStats(t *testing.T) {
+	models := newTestModels(t)
+
+	insertTeamsMatch(t, models, ResultBlue, `{"DragonsKilled": 3, "BaronNashorsKilled": 1}`, `{"DragonsKilled": 1}`)
+	insertTeamsMatch(t, models, ResultRed, `{"DragonsKilled": 0}`, `{"DragonsKilled": 2, "BaronNashorsKilled": 1}`)
+	insertTeamsMatch(t, models, ResultBlue, `{"DragonsKilled": 4}`, `{"DragonsKilled": 1, "BaronNashorsKilled": 1}`)
+	// The remake isn't counted.
+	insertTeamsMatch(t, models, ResultRemake, `{"DragonsKilled": 9, "BaronNashorsKilled": 3}`, `{}`)
+
+	stats, err := models.Matches.GetObjectiveStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]ObjectiveStats{
+		// Nobody took a turret, so there's nothing to correlate.
+		"turrets": {},
+		"dragons": {AvgWinningTeam: 3, AvgLosingTeam: 2.0 / 3, TeamsSecured: 5, WinRateWhenSecured: 0.6, WinCorrelation: 3 / math.Sqrt(45)},
+		"barons":  {AvgWinningTeam: 2.0 / 3, AvgLosingTeam: 1.0 / 3, TeamsSecured: 3, WinRateWhenSecured: 2.0 / 3, WinCorrelation: 1.0 / 3},
+	}
+
+	if len(stats) != len(objectiveKeys) {
+		t.Fatalf("got %d objectives, want %d", len(stats), len(objectiveKeys))
+	}
+	for _, s := range stats {
+		w, ok := want[s.Objective]
+		if !ok {
+			continue
+		}
+		if math.Abs(s.AvgWinningTeam-w.AvgWinningTeam) > 1e-9 || math.Abs(s.AvgLosingTeam-w.AvgLosingTeam) > 1e-9 {
+			t.Errorf("%s: got averages of %v winning and %v losing, want %v and %v",
+				s.Objective, s.AvgWinningTeam, s.AvgLosingTeam, w.AvgWinningTeam, w.AvgLosingTeam)
+		}
+		if s.TeamsSecured != w.TeamsSecured || math.Abs(s.WinRateWhenSecured-w.WinRateWhenSecured) > 1e-9 {
+			t.Errorf("%s: got %d teams securing it with a win rate of %v, want %d and %v",
+				s.Objective, s.TeamsSecured, s.WinRateWhenSecured, w.TeamsSecured, w.WinRateWhenSecured)
+		}
+		if math.Abs(s.WinCorrelation-w.WinCorrelation) > 1e-9 {
+			t.Errorf("%s: got win correlation %v, want %v", s.Objective, s.WinCorrelation, w.WinCorrelation)
+		}
+	}
+}
+
+func TestGetFirstObjectiveCorrelations(t *testing.T) {
+	models := newTestModels(t)
+
+	insertTeamsMatch(t, models, ResultBlue, `{"FirstBlood": true, "FirstTower": true}`, `{}`)
+	insertTeamsMatch(t, models, ResultRed, `{"FirstBlood": true}`, `{"FirstTower": true}`)
+	// A match without flags, like those stored before they were recorded, is left out.
+	insertTeamsMatch(t, models, ResultBlue, `{}`, `{}`)
+	insertTeamsMatch(t, models, ResultRemake, `{"FirstBlood": true}`, `{}`)
+
+	stats, err := models.Matches.GetFirstObjectiveCorrelations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CorrelationStats{
+		// First blood went once to the winner and once to the loser.
+		{Objective: "first_blood", Matches: 2, WinRateWhenSecured: 0.5, WinCorrelation: 0},
+		{Objective: "first_tower", Matches: 2, WinRateWhenSecured: 1, WinCorrelation: 1},
+		// No match recorded a first dragon, but it's still listed.
+		{Objective: "first_dragon"},
+	}
+
+	if len(stats) != len(want) {
+		t.Fatalf("got %d objectives, want %d", len(stats), len(want))
+	}
+	for i, s := range stats {
+		w := want[i]
+		if s.Objective != w.Objective || s.Matches != w.Matches ||
+			math.Abs(s.WinRateWhenSecured-w.WinRateWhenSecured) > 1e-9 || math.Abs(s.WinCorrelation-w.WinCorrelation) > 1e-9 {
+			t.Errorf("got %+v, want %+v", *s, w)
+		}
+	}
+}
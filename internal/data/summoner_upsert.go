@@ -0,0 +1,97 @@
+package data
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// MaxSummonerUpsertBatch is the largest number of summoners UpsertBulk accepts at once.
+const MaxSummonerUpsertBatch = 500
+
+// SummonerUpsert is one entry of a bulk summoner upsert.
+type SummonerUpsert struct {
+	Username string `json:"username"`
+	Region   string `json:"region"`
+	Rating   int    `json:"rating"`
+}
+
+// SummonerUpsertResult counts what a bulk upsert did. Entries which matched an existing summoner
+// with the same username casing and rating are left alone and counted as unchanged, so running
+// the same batch twice updates nothing the second time.
+type SummonerUpsertResult struct {
+	Inserted  int `json:"inserted"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+func ValidateSummonerUpsert(v *validator.Validator, upsert *SummonerUpsert) {
+	ValidateSummoner(v, &Summoner{Username: upsert.Username, Region: upsert.Region})
+	v.Check(upsert.Rating >= 0, "rating", "must not be negative")
+}
+
+// DuplicateSummonerUpsert reports whether two entries of a batch are the same summoner. A batch
+// must not contain the same summoner twice, as PostgreSQL can't update a row twice in one INSERT.
+func DuplicateSummonerUpsert(a, b *SummonerUpsert) bool {
+	return strings.EqualFold(a.Username, b.Username) && a.Region == b.Region
+}
+
+// UpsertBulk inserts the summoners which don't exist yet (matching usernames case-insensitively
+// within a region), and sets the username and rating of those which do, in a single statement.
+func (m SummonerModel) UpsertBulk(upserts []*SummonerUpsert) (*SummonerUpsertResult, error) {
+	usernames := make([]string, len(upserts))
+	regions := make([]string, len(upserts))
+	ratings := make([]int64, len(upserts))
+
+	for i, upsert := range upserts {
+		usernames[i] = upsert.Username
+		regions[i] = upsert.Region
+		ratings[i] = int64(upsert.Rating)
+	}
+
+	// xmax is zero for a row version created by an insert, and set for one created by the update.
+	// Conflicting rows which already hold the same values aren't updated, so aren't returned.
+	query := `
+        INSERT INTO summoners (username, region, rating)
+        SELECT * FROM unnest($1::text[], $2::text[], $3::int[])
+        ON CONFLICT (LOWER(username), region) DO UPDATE
+        SET username = EXCLUDED.username, rating = EXCLUDED.rating
+        WHERE summoners.username <> EXCLUDED.username OR summoners.rating IS DISTINCT FROM EXCLUDED.rating
+        RETURNING xmax = 0`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, pq.Array(usernames), pq.Array(regions), pq.Array(ratings))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result SummonerUpsertResult
+
+	for rows.Next() {
+		var inserted bool
+		err := rows.Scan(&inserted)
+		if err != nil {
+			return nil, err
+		}
+
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result.Unchanged = len(upserts) - result.Inserted - result.Updated
+
+	return &result, nil
+}
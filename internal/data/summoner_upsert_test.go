@@ -0,0 +1,73 @@
+package data
+
+import (
+	"testing"
+)
+
+func TestDuplicateSummonerUpsert(t *testing.T) {
+	tests := []struct {
+		a, b SummonerUpsert
+		want bool
+	}{
+		{SummonerUpsert{Username: "Faker", Region: "KR"}, SummonerUpsert{Username: "Faker", Region: "KR", Rating: 3000}, true},
+		{SummonerUpsert{Username: "Faker", Region: "KR"}, SummonerUpsert{Username: "FAKER", Region: "KR"}, true},
+		{SummonerUpsert{Username: "Faker", Region: "KR"}, SummonerUpsert{Username: "Faker", Region: "EUW"}, false},
+		{SummonerUpsert{Username: "Faker", Region: "KR"}, SummonerUpsert{Username: "Caps", Region: "KR"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := DuplicateSummonerUpsert(&tt.a, &tt.b); got != tt.want {
+			t.Errorf("DuplicateSummonerUpsert(%+v, %+v) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestUpsertBulk(t *testing.T) {
+	models := newTestModels(t)
+
+	existing := []*Summoner{
+		{Username: "Faker", Region: "KR", Rating: 3000},
+		{Username: "Caps", Region: "EUW", Rating: 2800},
+	}
+	for _, summoner := range existing {
+		if err := models.Summoners.Insert(summoner); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	batch := []*SummonerUpsert{
+		{Username: "Faker", Region: "KR", Rating: 3000}, // Unchanged
+		{Username: "caps", Region: "EUW", Rating: 2900}, // Matches Caps case-insensitively
+		{Username: "Chovy", Region: "KR", Rating: 2950},
+		{Username: "Caps", Region: "KR", Rating: 2000}, // Same username, another region
+	}
+
+	result, err := models.Summoners.UpsertBulk(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := SummonerUpsertResult{Inserted: 2, Updated: 1, Unchanged: 1}
+	if *result != want {
+		t.Errorf("got %+v, want %+v", *result, want)
+	}
+
+	caps, err := models.Summoners.Get(existing[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps.Username != "caps" || caps.Rating != 2900 {
+		t.Errorf("got %q rated %d, want the update to %q rated 2900", caps.Username, caps.Rating, "caps")
+	}
+
+	// Running the same batch again changes nothing.
+	result, err = models.Summoners.UpsertBulk(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = SummonerUpsertResult{Unchanged: len(batch)}
+	if *result != want {
+		t.Errorf("second run: got %+v, want %+v", *result, want)
+	}
+}
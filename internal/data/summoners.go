@@ -9,9 +9,18 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"league_of_graphs.satellite.net/internal/validator"
 )
 
+var (
+	ErrDuplicateSummoner = errors.New("duplicate summoner")
+	ErrNoOwner           = errors.New("summoner has no owner")
+)
+
+// ValidRegions holds the region codes of the Riot game servers.
+var ValidRegions = []string{"BR", "EUNE", "EUW", "JP", "KR", "LAN", "LAS", "ME", "NA", "OCE", "PH", "RU", "SG", "TH", "TR", "TW", "VN"}
+
 type Summoner struct {
 	ID                        int64           `json:"id"`
 	Username                  string          `json:"username"`
@@ -22,13 +31,43 @@ type Summoner struct {
 	FrequentlyPlayedChampions []ChampionStats `json:"-"`
 	MatchHistory              []*Match        `json:"-"`
 	AverageKDA                KDA             `json:"average_kda"`
+	LastMatchAt               *time.Time      `json:"lastMatchAt"` // Played date of the summoner's most recent match (nil if none)
 	FrequentlyPlayedRoles     []RoleStats     `json:"-"`
+	UserID                    int64           `json:"-"`                           // User who owns the profile (0 if unclaimed)
+	OwnerVerified             bool            `json:"ownerVerified"`               // Whether an admin has verified that the owner plays the summoner
+	IsPrivate                 bool            `json:"isPrivate"`                   // Whether detailed stats are hidden from everyone but the owner and admins
+	IsPro                     bool            `json:"isPro"`                       // Whether the account belongs to a professional player
+	RatingPercentile          *float64        `json:"ratingPercentile,omitempty"`  // Percentile of the rating among all summoners (0-100), only in the profile
+	WinRatePercentile         *float64        `json:"winRatePercentile,omitempty"` // Percentile of the win rate among all summoners (0-100), only in the profile
+}
+
+// PublicSummoner holds the fields of a summoner which are shown to everyone, even when the
+// summoner's profile is private.
+type PublicSummoner struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	Region    string `json:"region"`
+	IsPrivate bool   `json:"isPrivate"`
+	IsPro     bool   `json:"isPro"`
+}
+
+// Public returns the summoner without any of their stats.
+func (s *Summoner) Public() *PublicSummoner {
+	return &PublicSummoner{
+		ID:        s.ID,
+		Username:  s.Username,
+		Region:    s.Region,
+		IsPrivate: s.IsPrivate,
+		IsPro:     s.IsPro,
+	}
 }
 
 type ChampionStats struct {
-	Champion             Champion // Champion information
-	CountOfPlayedMatches int      // Count of matches played with the champion
-	WinRate              float64  // Winrate with the champion
+	Champion             Champion `json:"champion"`             // Champion information
+	CountOfPlayedMatches int      `json:"countOfPlayedMatches"` // Count of matches played with the champion
+	WinRate              float64  `json:"winRate"`              // Winrate with the champion
+	KDA                  KDA      `json:"kda"`                  // Total kills, deaths and assists with the champion
+	KDARatio             float64  `json:"kdaRatio"`             // (kills + assists) / deaths with the champion
 }
 
 type RoleStats struct {
@@ -43,19 +82,48 @@ type KDA struct {
 	Assists int
 }
 
+// Ratio returns the KDA ratio, (kills + assists) / deaths. A deathless record is divided by one
+// rather than zero, as is usual in the game's own statistics.
+func (k KDA) Ratio() float64 {
+	deaths := k.Deaths
+	if deaths == 0 {
+		deaths = 1
+	}
+	return float64(k.Kills+k.Assists) / float64(deaths)
+}
+
 func ValidateSummoner(v *validator.Validator, summoner *Summoner) {
 	v.Check(summoner.Username != "", "username", "must be provided")
 	v.Check(summoner.Region != "", "region", "must be provided")
+
+	// Name rules differ between regions (e.g. KR allows Hangul but NA only Latin letters), so
+	// check the username against the rule for the summoner's region.
+	if summoner.Username != "" {
+		rule := UsernameRuleFor(summoner.Region)
+		v.Check(rule.ValidLength(summoner.Username), "username",
+			fmt.Sprintf("must be between %d and %d characters long", rule.MinLength, rule.MaxLength))
+		v.Check(rule.ValidCharacters(summoner.Username), "username",
+			"must only contain letters, digits and spaces allowed in the summoner's region")
+	}
 }
 
 type SummonerModel struct {
-	DB *sql.DB
+	DB *DB
 }
 
+// isDuplicateSummoner reports whether err is a unique violation (SQLSTATE 23505) of the index
+// which keeps usernames unique within a region.
+func isDuplicateSummoner(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "summoners_username_region_key"
+}
+
+// Insert adds a new summoner with empty statistics. If a summoner in the region already has the
+// username, ErrDuplicateSummoner is returned.
 func (m SummonerModel) Insert(summoner *Summoner) error {
 	query := `
-        INSERT INTO summoners (username, region, rating, count_of_played_games, win_rate, average_kda)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO summoners (username, region, rating, count_of_played_games, win_rate, average_kda, user_id, is_private)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), $8)
         RETURNING id
     `
 
@@ -67,8 +135,11 @@ func (m SummonerModel) Insert(summoner *Summoner) error {
 	}
 
 	// Execute the insert query
-	err = m.DB.QueryRow(query, summoner.Username, summoner.Region, 0, 0, 0, averageKDAJSON).Scan(&summoner.ID)
+	err = m.DB.QueryRow(query, summoner.Username, summoner.Region, 0, 0, 0, averageKDAJSON, summoner.UserID, summoner.IsPrivate).Scan(&summoner.ID)
 	if err != nil {
+		if isDuplicateSummoner(err) {
+			return ErrDuplicateSummoner
+		}
 		if err == sql.ErrNoRows {
 			return fmt.Errorf("Insert: no rows were returned by the query")
 		}
@@ -97,7 +168,8 @@ func (m SummonerModel) Get(id int64) (*Summoner, error) {
 	}
 
 	query := `
-		SELECT id, username, region, rating, count_of_played_games, win_rate, average_kda
+		SELECT id, username, region, rating, count_of_played_games, win_rate, average_kda, last_match_at,
+			COALESCE(user_id, 0), owner_verified, is_private, is_pro
 		FROM summoners
 		WHERE id = $1
 	`
@@ -112,6 +184,11 @@ func (m SummonerModel) Get(id int64) (*Summoner, error) {
 		&summoner.CountOfPlayedGames,
 		&summoner.WinRate,
 		&summoner.AverageKDA,
+		&summoner.LastMatchAt,
+		&summoner.UserID,
+		&summoner.OwnerVerified,
+		&summoner.IsPrivate,
+		&summoner.IsPro,
 	)
 
 	if err != nil {
@@ -126,24 +203,60 @@ func (m SummonerModel) Get(id int64) (*Summoner, error) {
 	return &summoner, nil
 }
 
+// Update saves a summoner's profile. A change of region is recorded in summoner_transfers, as
+// Transfer would. The rating and statistics are maintained by their own updates, so they aren't
+// written back: doing so could undo a match recorded since the summoner was read, or leave more
+// wins than games. If another summoner in the region already has the username,
+// ErrDuplicateSummoner is returned.
 func (m SummonerModel) Update(summoner *Summoner) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The subquery locks the row and returns its region from before the update.
 	query := `
-		UPDATE summoners
-		SET username = $1, region = $2, rating = $3, count_of_played_games = $4, win_rate = $5, average_kda = $6
-		WHERE id = $7
+		UPDATE summoners s
+		SET username = $1, region = $2, is_private = $3
+		FROM (SELECT id, region FROM summoners WHERE id = $4 FOR UPDATE) old
+		WHERE s.id = old.id
+		RETURNING old.region
 	`
 
 	args := []interface{}{
 		summoner.Username,
 		summoner.Region,
-		summoner.Rating,
-		summoner.CountOfPlayedGames,
-		summoner.WinRate,
-		summoner.AverageKDA,
+		summoner.IsPrivate,
 		summoner.ID,
 	}
 
-	return m.DB.QueryRow(query, args...).Err()
+	var fromRegion string
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&fromRegion)
+	if err != nil {
+		switch {
+		case isDuplicateSummoner(err):
+			return ErrDuplicateSummoner
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	if fromRegion != summoner.Region {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO summoner_transfers (summoner_id, from_region, to_region)
+            VALUES ($1, $2, $3)`, summoner.ID, fromRegion, summoner.Region)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (m SummonerModel) Delete(id int64) error {
@@ -173,26 +286,170 @@ func (m SummonerModel) Delete(id int64) error {
 	return nil
 }
 
-func (m SummonerModel) GetAll(username string, region string, filters Filters) ([]*Summoner, error) {
-	query := fmt.Sprintf(`
-        SELECT id, username, region, rating, count_of_played_games, win_rate, average_kda
-        FROM summoners
+// VerifyOwner records whether an admin has verified that the summoner's owner really plays it.
+// It returns ErrRecordNotFound if the summoner doesn't exist, and ErrNoOwner if nobody owns it.
+func (m SummonerModel) VerifyOwner(id int64, verified bool) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var hasOwner bool
+	err := m.DB.QueryRowContext(ctx, `
+		UPDATE summoners SET owner_verified = $1 AND user_id IS NOT NULL
+		WHERE id = $2
+		RETURNING user_id IS NOT NULL`, verified, id).Scan(&hasOwner)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	if verified && !hasOwner {
+		return ErrNoOwner
+	}
+
+	return nil
+}
+
+// SetPro marks a summoner as a professional player's account, or unmarks it. It returns
+// ErrRecordNotFound if the summoner doesn't exist.
+func (m SummonerModel) SetPro(id int64, isPro bool) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, `UPDATE summoners SET is_pro = $1 WHERE id = $2`, isPro, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SummonerFilter holds the predicates shared by the summoner list and count queries, so that a
+// count always agrees with the rows a list with the same filter would return.
+type SummonerFilter struct {
+	Username     string // Exact (case-insensitive) username
+	Region       string
+	Search       string // Matches any part of the username
+	MinRating    int
+	InactiveDays int // No match in this many days, or never played (0 means no limit)
+}
+
+// summonerWhere is the WHERE clause for a SummonerFilter. Its placeholders are filled by
+// SummonerFilter.args().
+const summonerWhere = `
         WHERE (LOWER(username) = LOWER($1) OR $1 = '')
         AND (LOWER(region) = LOWER($2) OR $2 = '')
-        ORDER BY %s %s, id ASC
-        LIMIT $3 OFFSET $4`, filters.sortColumn(), filters.sortDirection())
+        AND (strpos(LOWER(username), LOWER($3)) > 0 OR $3 = '')
+        AND ($4 = 0 OR rating >= $4)
+        AND ($5 = 0 OR last_match_at IS NULL OR last_match_at < now() - make_interval(days => $5))`
+
+// args returns the arguments for the placeholders in summonerWhere.
+func (f SummonerFilter) args() []interface{} {
+	return []interface{}{f.Username, f.Region, f.Search, f.MinRating, f.InactiveDays}
+}
+
+// relevanceOrder ranks summoners by how closely their username matches the search term in $3:
+// exact matches first, then prefix matches, then substring matches, then by rating.
+const relevanceOrder = `
+        CASE
+            WHEN LOWER(username) = LOWER($3) THEN 3
+            WHEN strpos(LOWER(username), LOWER($3)) = 1 THEN 2
+            WHEN strpos(LOWER(username), LOWER($3)) > 0 THEN 1
+            ELSE 0
+        END DESC, rating DESC`
+
+// GetAll returns a page of summoners matching the filter, along with the paging metadata. If
+// byRelevance is true, results are ordered by how closely they match the filter's search term
+// rather than by the sort column.
+func (m SummonerModel) GetAll(filter SummonerFilter, byRelevance bool, filters Filters) ([]*Summoner, Metadata, error) {
+	totalRecords, err := m.Count(filter)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	summoners := []*Summoner{}
+
+	err = m.Stream(ctx, filter, byRelevance, filters, func(summoner *Summoner) error {
+		summoners = append(summoners, summoner)
+		return nil
+	})
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return summoners, calculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+// Count returns the number of summoners matching the filter.
+func (m SummonerModel) Count(filter SummonerFilter) (int, error) {
+	query := `SELECT count(*) FROM summoners` + summonerWhere
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	rows, err := m.DB.QueryContext(ctx, query, username, region, filters.limit(), filters.offset())
+	var count int
+	err := m.DB.QueryRowContext(ctx, query, filter.args()...).Scan(&count)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	defer rows.Close()
+	return count, nil
+}
 
-	summoners := []*Summoner{}
+// summonerListQuery returns the query run by GetAll and Stream, along with its arguments.
+func summonerListQuery(filter SummonerFilter, byRelevance bool, filters Filters) (string, []interface{}) {
+	order := fmt.Sprintf("%s %s", filters.sortColumn(), filters.sortDirection())
+	if byRelevance {
+		order = relevanceOrder
+	}
+
+	args := filter.args()
+
+	query := fmt.Sprintf(`
+        SELECT id, username, region, rating, count_of_played_games, win_rate, average_kda, last_match_at,
+            COALESCE(user_id, 0), owner_verified, is_private, is_pro
+        FROM summoners %s
+        ORDER BY %s, id ASC
+        LIMIT $%d OFFSET $%d`, summonerWhere, order, len(args)+1, len(args)+2)
+
+	return query, append(args, filters.limit(), filters.offset())
+}
+
+// Stream runs the same query as GetAll, but passes each summoner to fn as it is read from the
+// database instead of collecting them into a slice. Iteration stops at the first error returned
+// by fn.
+func (m SummonerModel) Stream(ctx context.Context, filter SummonerFilter, byRelevance bool, filters Filters, fn func(*Summoner) error) error {
+	query, args := summonerListQuery(filter, byRelevance, filters)
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
 
 	for rows.Next() {
 		var summoner Summoner
@@ -204,18 +461,412 @@ func (m SummonerModel) GetAll(username string, region string, filters Filters) (
 			&summoner.CountOfPlayedGames,
 			&summoner.WinRate,
 			&summoner.AverageKDA,
+			&summoner.LastMatchAt,
+			&summoner.UserID,
+			&summoner.OwnerVerified,
+			&summoner.IsPrivate,
+			&summoner.IsPro,
 		)
 		if err != nil {
+			return err
+		}
+
+		if err := fn(&summoner); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Streak describes a summoner's current run of consecutive wins or losses, along with their
+// longest ever runs.
+type Streak struct {
+	Type              string `json:"type"` // "win", "loss", or "" if the summoner has no matches
+	Length            int    `json:"length"`
+	LongestWinStreak  int    `json:"longestWinStreak"`
+	LongestLossStreak int    `json:"longestLossStreak"`
+}
+
+// GetCurrentStreak walks the summoner's matches in the order they were played and returns their
+// current and longest streaks. Remakes are skipped, so they neither extend nor break a streak.
+func (m SummonerModel) GetCurrentStreak(id int64) (*Streak, error) {
+	query := `
+        SELECT LOWER(m.result) = mp.team
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        WHERE mp.summoner_id = $1 AND LOWER(m.result) <> $2
+        ORDER BY m.played_date ASC, m.id ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, id, ResultRemake)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var wins []bool
+
+	for rows.Next() {
+		var won bool
+		if err := rows.Scan(&won); err != nil {
 			return nil, err
 		}
-		summoners = append(summoners, &summoner)
+		wins = append(wins, won)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return summoners, nil
+	return NewStreak(wins), nil
 }
 
+// NewStreak works out the current and longest streaks from the outcomes of a summoner's matches,
+// oldest first, where true is a win and false a loss.
+func NewStreak(wins []bool) *Streak {
+	streak := &Streak{}
+
+	for _, won := range wins {
+		outcome := "loss"
+		if won {
+			outcome = "win"
+		}
 
+		if outcome == streak.Type {
+			streak.Length++
+		} else {
+			streak.Type = outcome
+			streak.Length = 1
+		}
+
+		if won && streak.Length > streak.LongestWinStreak {
+			streak.LongestWinStreak = streak.Length
+		}
+		if !won && streak.Length > streak.LongestLossStreak {
+			streak.LongestLossStreak = streak.Length
+		}
+	}
+
+	return streak
+}
+
+// Transfer is a record of a summoner moving from one region to another.
+type Transfer struct {
+	ID            int64     `json:"id"`
+	SummonerID    int64     `json:"summonerId"`
+	FromRegion    string    `json:"fromRegion"`
+	ToRegion      string    `json:"toRegion"`
+	TransferredAt time.Time `json:"transferredAt"`
+}
+
+func ValidateTransfer(v *validator.Validator, summoner *Summoner, region string) {
+	v.Check(region != "", "region", "must be provided")
+	v.Check(validator.In(region, ValidRegions...), "region", "must be a valid region")
+	v.Check(region != summoner.Region, "region", "must be different from the summoner's current region")
+}
+
+// Transfer moves the summoner to a new region and records the move in summoner_transfers. If a
+// summoner with the same username already exists in the target region, ErrDuplicateSummoner is
+// returned and nothing is changed.
+func (m SummonerModel) Transfer(summoner *Summoner, region string) (*Transfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	transfer := &Transfer{
+		SummonerID: summoner.ID,
+		FromRegion: summoner.Region,
+		ToRegion:   region,
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `
+        SELECT EXISTS(SELECT 1 FROM summoners WHERE LOWER(username) = LOWER($1) AND region = $2 AND id <> $3)`,
+		summoner.Username, region, summoner.ID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrDuplicateSummoner
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE summoners SET region = $1 WHERE id = $2`, region, summoner.ID)
+	if err != nil {
+		// A concurrent insert or transfer could still take the name between our check and the
+		// update, in which case the unique index catches it.
+		if isDuplicateSummoner(err) {
+			return nil, ErrDuplicateSummoner
+		}
+		return nil, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO summoner_transfers (summoner_id, from_region, to_region)
+        VALUES ($1, $2, $3)
+        RETURNING id, transferred_at`, transfer.SummonerID, transfer.FromRegion, transfer.ToRegion).Scan(&transfer.ID, &transfer.TransferredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	summoner.Region = region
+
+	return transfer, nil
+}
+
+// ActivityGrid counts a summoner's matches by day of the week (0 is Sunday) and hour of the day.
+type ActivityGrid [7][24]int
+
+// GetActivity returns the summoner's matches bucketed by the day of the week and hour of the day
+// they were played, in the named IANA time zone (e.g. "Europe/Berlin").
+func (m SummonerModel) GetActivity(id int64, timezone string) (*ActivityGrid, error) {
+	query := `
+        SELECT EXTRACT(DOW FROM m.played_date AT TIME ZONE $2)::int,
+            EXTRACT(HOUR FROM m.played_date AT TIME ZONE $2)::int,
+            count(*)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        WHERE mp.summoner_id = $1
+        GROUP BY 1, 2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, id, timezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grid ActivityGrid
+
+	for rows.Next() {
+		var day, hour, count int
+		if err := rows.Scan(&day, &hour, &count); err != nil {
+			return nil, err
+		}
+		grid[day][hour] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &grid, nil
+}
+
+// GetChampionStats returns the summoner's record on each champion they've played, most played
+// first, including their total kills, deaths and assists on that champion.
+func (m SummonerModel) GetChampionStats(id int64) ([]*ChampionStats, error) {
+	query := `
+        SELECT c.id, c.name, c.main_role, scs.count_of_played_matches, scs.win_rate,
+            scs.kills, scs.deaths, scs.assists
+        FROM summoner_champion_stats scs
+        JOIN champions c ON c.id = scs.champion_id
+        WHERE scs.summoner_id = $1
+        ORDER BY scs.count_of_played_matches DESC, c.id ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []*ChampionStats{}
+
+	for rows.Next() {
+		var s ChampionStats
+		err := rows.Scan(
+			&s.Champion.ID,
+			&s.Champion.Name,
+			&s.Champion.MainRole,
+			&s.CountOfPlayedMatches,
+			&s.WinRate,
+			&s.KDA.Kills,
+			&s.KDA.Deaths,
+			&s.KDA.Assists,
+		)
+		if err != nil {
+			return nil, err
+		}
+		s.KDARatio = s.KDA.Ratio()
+
+		stats = append(stats, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// summonerAggregates holds the incrementally maintained statistics of a summoner.
+type summonerAggregates struct {
+	games      int
+	wins       int
+	averageKDA KDA
+}
+
+// merge combines the aggregates of two summoners, weighting their average KDAs by games played.
+func (a summonerAggregates) merge(b summonerAggregates) summonerAggregates {
+	merged := summonerAggregates{games: a.games + b.games, wins: a.wins + b.wins}
+	if merged.games == 0 {
+		return merged
+	}
+
+	merged.averageKDA = KDA{
+		Kills:   (a.averageKDA.Kills*a.games + b.averageKDA.Kills*b.games) / merged.games,
+		Deaths:  (a.averageKDA.Deaths*a.games + b.averageKDA.Deaths*b.games) / merged.games,
+		Assists: (a.averageKDA.Assists*a.games + b.averageKDA.Assists*b.games) / merged.games,
+	}
+	return merged
+}
+
+// winRate returns the share of games won, or 0 if no games were played.
+func (a summonerAggregates) winRate() float64 {
+	if a.games == 0 {
+		return 0
+	}
+	return float64(a.wins) / float64(a.games)
+}
+
+// Merge folds the source summoner into the target, for cleaning up duplicate accounts. The
+// source's match performances, per-role and per-champion stats and transfer history are moved to
+// the target, and the source is deleted. The target's game and win counts become the sum of both
+// summoners', with the win rate and average KDA weighted by games played; if the combined
+// summoner has a performance history, its aggregates are then recomputed from it. Everything
+// happens in one transaction, so a failure leaves both summoners untouched.
+func (m SummonerModel) Merge(targetID, sourceID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`UPDATE match_performance SET summoner_id = $1 WHERE summoner_id = $2`,
+		`UPDATE summoner_transfers SET summoner_id = $1 WHERE summoner_id = $2`,
+		// Combine per-role and per-champion stats with any the target already has for the same
+		// role or champion, weighting the win rates by games played.
+		`INSERT INTO role_stats (summoner_id, role, count_of_played_matches, win_rate)
+            SELECT $1, role, count_of_played_matches, win_rate
+            FROM role_stats
+            WHERE summoner_id = $2
+        ON CONFLICT (summoner_id, role) DO UPDATE
+        SET win_rate = COALESCE(
+                (role_stats.win_rate * role_stats.count_of_played_matches
+                    + EXCLUDED.win_rate * EXCLUDED.count_of_played_matches)
+                / NULLIF(role_stats.count_of_played_matches + EXCLUDED.count_of_played_matches, 0), 0),
+            count_of_played_matches = role_stats.count_of_played_matches + EXCLUDED.count_of_played_matches`,
+		`DELETE FROM role_stats WHERE summoner_id = $2`,
+		`INSERT INTO champion_stats (champion_id, summoner_id, count_of_played_matches, win_rate)
+            SELECT champion_id, $1, count_of_played_matches, win_rate
+            FROM champion_stats
+            WHERE summoner_id = $2
+        ON CONFLICT (champion_id, summoner_id) DO UPDATE
+        SET win_rate = COALESCE(
+                (champion_stats.win_rate * champion_stats.count_of_played_matches
+                    + EXCLUDED.win_rate * EXCLUDED.count_of_played_matches)
+                / NULLIF(champion_stats.count_of_played_matches + EXCLUDED.count_of_played_matches, 0), 0),
+            count_of_played_matches = champion_stats.count_of_played_matches + EXCLUDED.count_of_played_matches`,
+		`DELETE FROM champion_stats WHERE summoner_id = $2`,
+		`INSERT INTO summoner_champion_stats (summoner_id, champion_id, count_of_played_matches, win_rate, kills, deaths, assists)
+            SELECT $1, champion_id, count_of_played_matches, win_rate, kills, deaths, assists
+            FROM summoner_champion_stats
+            WHERE summoner_id = $2
+        ON CONFLICT (summoner_id, champion_id) DO UPDATE
+        SET win_rate = COALESCE(
+                (summoner_champion_stats.win_rate * summoner_champion_stats.count_of_played_matches
+                    + EXCLUDED.win_rate * EXCLUDED.count_of_played_matches)
+                / NULLIF(summoner_champion_stats.count_of_played_matches + EXCLUDED.count_of_played_matches, 0), 0),
+            count_of_played_matches = summoner_champion_stats.count_of_played_matches + EXCLUDED.count_of_played_matches,
+            kills = summoner_champion_stats.kills + EXCLUDED.kills,
+            deaths = summoner_champion_stats.deaths + EXCLUDED.deaths,
+            assists = summoner_champion_stats.assists + EXCLUDED.assists`,
+		`DELETE FROM summoner_champion_stats WHERE summoner_id = $2`,
+		`INSERT INTO champion_scoped_stats (champion_id, summoner_id, match_type, rank_tier, count_of_played_matches, wins)
+            SELECT champion_id, $1, match_type, rank_tier, count_of_played_matches, wins
+            FROM champion_scoped_stats
+            WHERE summoner_id = $2
+        ON CONFLICT (champion_id, summoner_id, match_type, rank_tier) DO UPDATE
+        SET count_of_played_matches = champion_scoped_stats.count_of_played_matches + EXCLUDED.count_of_played_matches,
+            wins = champion_scoped_stats.wins + EXCLUDED.wins`,
+		`DELETE FROM champion_scoped_stats WHERE summoner_id = $2`,
+		`UPDATE summoners t SET last_match_at = s.last_match_at
+            FROM summoners s
+            WHERE t.id = $1 AND s.id = $2 AND (t.last_match_at IS NULL OR t.last_match_at < s.last_match_at)`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, targetID, sourceID); err != nil {
+			return err
+		}
+	}
+
+	// Combine the aggregates, which is all that can be done for games which predate performance
+	// history. The recompute below replaces them if the summoner has any performances.
+	var target, source summonerAggregates
+	for _, agg := range []struct {
+		id  int64
+		dst *summonerAggregates
+	}{{targetID, &target}, {sourceID, &source}} {
+		err := tx.QueryRowContext(ctx, `
+            SELECT count_of_played_games, wins, average_kda FROM summoners WHERE id = $1 FOR UPDATE`,
+			agg.id).Scan(&agg.dst.games, &agg.dst.wins, &agg.dst.averageKDA)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrRecordNotFound
+			default:
+				return err
+			}
+		}
+	}
+
+	merged := target.merge(source)
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE summoners SET count_of_played_games = $1, wins = $2, win_rate = $3, average_kda = $4
+        WHERE id = $5`, merged.games, merged.wins, merged.winRate(), merged.averageKDA, targetID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, recomputeSummonerStatsQuery, ResultRemake, targetID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM summoners WHERE id = $1`, sourceID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return tx.Commit()
+}
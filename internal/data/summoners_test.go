@@ -0,0 +1,430 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestSummonerAggregatesMerge(t *testing.T) {
+	a := summonerAggregates{games: 30, wins: 18, averageKDA: KDA{Kills: 6, Deaths: 3, Assists: 9}}
+	b := summonerAggregates{games: 10, wins: 2, averageKDA: KDA{Kills: 2, Deaths: 7, Assists: 1}}
+
+	merged := a.merge(b)
+
+	if merged.games != 40 || merged.wins != 20 {
+		t.Errorf("got %d games and %d wins, want 40 and 20", merged.games, merged.wins)
+	}
+	if got := merged.winRate(); got != 0.5 {
+		t.Errorf("got win rate %v, want 0.5", got)
+	}
+
+	want := KDA{Kills: 5, Deaths: 4, Assists: 7}
+	if merged.averageKDA != want {
+		t.Errorf("got average KDA %+v, want %+v", merged.averageKDA, want)
+	}
+}
+
+func TestSummonerAggregatesMergeWithoutGames(t *testing.T) {
+	merged := summonerAggregates{averageKDA: KDA{Kills: 4}}.merge(summonerAggregates{})
+
+	if merged.games != 0 || merged.winRate() != 0 || merged.averageKDA != (KDA{}) {
+		t.Errorf("got %+v, want empty aggregates", merged)
+	}
+}
+
+func TestIsDuplicateSummoner(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unique violation", &pq.Error{Code: "23505", Constraint: "summoners_username_region_key"}, true},
+		{"wrapped", fmt.Errorf("update: %w", &pq.Error{Code: "23505", Constraint: "summoners_username_region_key"}), true},
+		{"other constraint", &pq.Error{Code: "23505", Constraint: "users_email_key"}, false},
+		{"other code", &pq.Error{Code: "23514", Constraint: "summoners_username_region_key"}, false},
+		{"not a pq error", errors.New("duplicate key"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := isDuplicateSummoner(tt.err); got != tt.want {
+			t.Errorf("%s: got %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+// insertRatedSummoner stores a summoner with the given username, region and rating. Insert always
+// starts summoners unrated, so the rating is set afterwards.
+func insertRatedSummoner(t *testing.T, models Models, username, region string, rating int) *Summoner {
+	t.Helper()
+
+	summoner := &Summoner{Username: username, Region: region}
+	if err := models.Summoners.Insert(summoner); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := models.Summoners.DB.Exec(`UPDATE summoners SET rating = $1 WHERE id = $2`, rating, summoner.ID); err != nil {
+		t.Fatal(err)
+	}
+	summoner.Rating = rating
+
+	return summoner
+}
+
+func TestGetAllByRelevance(t *testing.T) {
+	models := newTestModels(t)
+
+	// The better the match, the lower the rating, so rating alone would reverse the order.
+	insertRatedSummoner(t, models, "TheFaker", "NA", 3000)
+	insertRatedSummoner(t, models, "FakerFan", "EUW", 2000)
+	insertRatedSummoner(t, models, "Faker", "KR", 1000)
+	insertRatedSummoner(t, models, "Caps", "EUW", 3500)
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}}
+
+	tests := []struct {
+		byRelevance bool
+		want        []string
+	}{
+		// Exact match, then prefix match, then substring match.
+		{true, []string{"Faker", "FakerFan", "TheFaker"}},
+		// Without ranking, the sort column applies.
+		{false, []string{"TheFaker", "FakerFan", "Faker"}},
+	}
+
+	for _, tt := range tests {
+		summoners, _, err := models.Summoners.GetAll(SummonerFilter{Search: "faker"}, tt.byRelevance, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, summoner := range summoners {
+			got = append(got, summoner.Username)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("byRelevance=%t: got %v, want %v", tt.byRelevance, got, tt.want)
+		}
+	}
+}
+
+func TestNewStreak(t *testing.T) {
+	const W, L = true, false
+
+	tests := []struct {
+		name string
+		wins []bool
+		want Streak
+	}{
+		{"no matches", nil, Streak{}},
+		{"three wins", []bool{W, W, W}, Streak{Type: "win", Length: 3, LongestWinStreak: 3}},
+		{"three wins after a loss", []bool{L, W, W, W}, Streak{Type: "win", Length: 3, LongestWinStreak: 3, LongestLossStreak: 1}},
+		// The longest streaks needn't be the current one.
+		{"mixed", []bool{W, W, W, W, L, L, L, W, L, L}, Streak{Type: "loss", Length: 2, LongestWinStreak: 4, LongestLossStreak: 3}},
+		{"alternating", []bool{W, L, W, L}, Streak{Type: "loss", Length: 1, LongestWinStreak: 1, LongestLossStreak: 1}},
+	}
+
+	for _, tt := range tests {
+		if got := NewStreak(tt.wins); *got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, *got, tt.want)
+		}
+	}
+}
+
+func TestGetCurrentStreak(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	// Faker plays for the blue team: a loss, then three wins with a remake among them which
+	// doesn't break the streak. The played dates are out of insertion order.
+	results := []struct {
+		result string
+		day    int
+	}{
+		{ResultBlue, 4},
+		{ResultRed, 1},
+		{ResultBlue, 2},
+		{ResultRemake, 3},
+		{ResultBlue, 5},
+	}
+
+	for _, r := range results {
+		var matchID int64
+		err := models.Matches.DB.QueryRow(`
+            INSERT INTO matches (duration, result, match_type, played_date, blue_team, red_team)
+            VALUES (1800, $1, 'solo_queue', $2, '{}', '{}')
+            RETURNING id`, r.result, fmt.Sprintf("2024-03-%02d", r.day)).Scan(&matchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = models.Matches.DB.Exec(`
+            INSERT INTO match_performance (match_id, summoner_id, champion_id, team)
+            VALUES ($1, $2, $3, $4)`, matchID, summoner.ID, champion.ID, ResultBlue)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := models.Summoners.GetCurrentStreak(summoner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Streak{Type: "win", Length: 3, LongestWinStreak: 3, LongestLossStreak: 1}
+	if *got != want {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := insertTestSummoner(t, models, "Faker")
+
+	transfer, err := models.Summoners.Transfer(faker, "KR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transfer.FromRegion != "EUW" || transfer.ToRegion != "KR" || transfer.ID == 0 {
+		t.Errorf("got transfer %+v, want a stored transfer from EUW to KR", transfer)
+	}
+
+	got, err := models.Summoners.Get(faker.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Region != "KR" || faker.Region != "KR" {
+		t.Errorf("got region %q stored and %q in memory, want KR", got.Region, faker.Region)
+	}
+}
+
+func TestTransferNameCollision(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := insertTestSummoner(t, models, "Faker")
+	// The usernames differ only in case, which still collides.
+	taken := &Summoner{Username: "faker", Region: "KR"}
+	if err := models.Summoners.Insert(taken); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := models.Summoners.Transfer(faker, "KR")
+	if !errors.Is(err, ErrDuplicateSummoner) {
+		t.Fatalf("got error %v, want ErrDuplicateSummoner", err)
+	}
+
+	got, err := models.Summoners.Get(faker.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transfers int
+	if err := models.Summoners.DB.QueryRow(`SELECT count(*) FROM summoner_transfers`).Scan(&transfers); err != nil {
+		t.Fatal(err)
+	}
+	if got.Region != "EUW" || transfers != 0 {
+		t.Errorf("got region %q and %d transfers, want EUW and none", got.Region, transfers)
+	}
+}
+
+func TestUpdateRecordsRegionChange(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := insertTestSummoner(t, models, "Faker")
+
+	transfers := func() []string {
+		rows, err := models.Summoners.DB.Query(`SELECT from_region || '>' || to_region FROM summoner_transfers ORDER BY id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		var got []string
+		for rows.Next() {
+			var transfer string
+			if err := rows.Scan(&transfer); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, transfer)
+		}
+		return got
+	}
+
+	// Renaming alone isn't a transfer; moving region is.
+	faker.Username = "Hide on bush"
+	if err := models.Summoners.Update(faker); err != nil {
+		t.Fatal(err)
+	}
+	faker.Region = "KR"
+	if err := models.Summoners.Update(faker); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := transfers(); !reflect.DeepEqual(got, []string{"EUW>KR"}) {
+		t.Errorf("got transfers %q, want EUW>KR", got)
+	}
+}
+
+func TestCountMatchesGetAll(t *testing.T) {
+	models := newTestModels(t)
+
+	insertRatedSummoner(t, models, "Faker", "KR", 3000)
+	insertRatedSummoner(t, models, "Chovy", "KR", 2800)
+	insertRatedSummoner(t, models, "ShowMaker", "KR", 1500)
+	insertRatedSummoner(t, models, "Caps", "EUW", 2900)
+	insertRatedSummoner(t, models, "FakerFan", "EUW", 1000)
+
+	filters := Filters{Page: 1, PageSize: 100, Sort: "id", SortSafelist: []string{"id"}}
+
+	tests := []struct {
+		filter SummonerFilter
+		want   int
+	}{
+		{SummonerFilter{}, 5},
+		{SummonerFilter{Region: "kr"}, 3},
+		{SummonerFilter{Region: "KR", MinRating: 2500}, 2},
+		{SummonerFilter{Search: "faker"}, 2},
+		{SummonerFilter{Username: "caps"}, 1},
+		{SummonerFilter{Region: "NA"}, 0},
+	}
+
+	for _, tt := range tests {
+		count, err := models.Summoners.Count(tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		summoners, _, err := models.Summoners.GetAll(tt.filter, false, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != len(summoners) || count != tt.want {
+			t.Errorf("%+v: got a count of %d and %d summoners listed, want %d", tt.filter, count, len(summoners), tt.want)
+		}
+	}
+}
+
+func TestGetActivity(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := insertTestSummoner(t, models, "Faker")
+	caps := insertTestSummoner(t, models, "Caps")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	// 2024-03-04 was a Monday. Caps's match mustn't count towards Faker's grid.
+	games := []struct {
+		summonerID int64
+		playedDate string
+	}{
+		{faker.ID, "2024-03-04T20:30:00Z"},
+		{faker.ID, "2024-03-04T20:59:00Z"},
+		{faker.ID, "2024-03-03T23:15:00Z"},
+		{caps.ID, "2024-03-04T20:30:00Z"},
+	}
+
+	for _, g := range games {
+		var matchID int64
+		err := models.Matches.DB.QueryRow(`
+            INSERT INTO matches (duration, result, match_type, played_date, blue_team, red_team)
+            VALUES (1800, $1, 'solo_queue', $2, '{}', '{}')
+            RETURNING id`, ResultBlue, g.playedDate).Scan(&matchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = models.Matches.DB.Exec(`
+            INSERT INTO match_performance (match_id, summoner_id, champion_id, team)
+            VALUES ($1, $2, $3, $4)`, matchID, g.summonerID, champion.ID, ResultBlue)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		timezone string
+		want     map[[2]int]int // [day, hour] to count
+	}{
+		{"UTC", map[[2]int]int{{1, 20}: 2, {0, 23}: 1}},
+		// An hour ahead, which moves the Sunday night match into Monday.
+		{"Europe/Berlin", map[[2]int]int{{1, 21}: 2, {1, 0}: 1}},
+	}
+
+	for _, tt := range tests {
+		grid, err := models.Summoners.GetActivity(faker.ID, tt.timezone)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for day := range grid {
+			for hour, count := range grid[day] {
+				if want := tt.want[[2]int{day, hour}]; count != want {
+					t.Errorf("%s: got %d matches on day %d at hour %d, want %d", tt.timezone, count, day, hour, want)
+				}
+			}
+		}
+	}
+}
+
+func TestGetAllInactiveDays(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := insertTestSummoner(t, models, "Faker")
+	caps := insertTestSummoner(t, models, "Caps")
+	insertTestSummoner(t, models, "Chovy")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	// Faker last played 60 days ago and Caps 2 days ago; Chovy has never played.
+	for _, p := range []struct {
+		summonerID int64
+		daysAgo    int
+	}{{faker.ID, 90}, {faker.ID, 60}, {caps.ID, 2}} {
+		var matchID int64
+		err := models.Matches.DB.QueryRow(`
+            INSERT INTO matches (duration, result, match_type, played_date, blue_team, red_team)
+            VALUES (1800, 'blue', 'solo_queue', now() - make_interval(days => $1), '{}', '{}')
+            RETURNING id`, p.daysAgo).Scan(&matchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = models.Matches.DB.Exec(`
+            INSERT INTO match_performance (match_id, summoner_id, champion_id, team)
+            VALUES ($1, $2, $3, 'blue')`, matchID, p.summonerID, champion.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filters := Filters{Page: 1, PageSize: 20, Sort: "id", SortSafelist: []string{"id"}}
+
+	tests := []struct {
+		days int
+		want []string
+	}{
+		{0, []string{"Faker", "Caps", "Chovy"}},
+		{30, []string{"Faker", "Chovy"}},
+		{1, []string{"Faker", "Caps", "Chovy"}},
+		{365, []string{"Chovy"}},
+	}
+
+	for _, tt := range tests {
+		summoners, _, err := models.Summoners.GetAll(SummonerFilter{InactiveDays: tt.days}, false, filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := []string{}
+		for _, s := range summoners {
+			got = append(got, s.Username)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("inactive for %d days: got %v, want %v", tt.days, got, tt.want)
+		}
+	}
+}
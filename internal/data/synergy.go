@@ -0,0 +1,99 @@
+package data
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// SynergyEdge is the record of two champions when played on the same team. The pair is unordered:
+// ChampionID is always the lower of the two IDs, and the record is the same from either side.
+type SynergyEdge struct {
+	ChampionID int64   `json:"championId"`
+	PartnerID  int64   `json:"partnerId"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	WinRate    float64 `json:"winRate"`
+}
+
+// SynergyMatrix is the dense form of a list of synergy edges: WinRates[i][j] is the win rate of
+// Champions[i] alongside Champions[j], or nil if the pair wasn't played together often enough.
+// The matrix is symmetric and its diagonal is nil.
+type SynergyMatrix struct {
+	Champions []int64      `json:"champions"`
+	WinRates  [][]*float64 `json:"winRates"`
+}
+
+// NewSynergyMatrix builds the matrix of the champions which appear in at least one edge, in order
+// of ID.
+func NewSynergyMatrix(edges []*SynergyEdge) *SynergyMatrix {
+	index := make(map[int64]int)
+	for _, e := range edges {
+		index[e.ChampionID] = 0
+		index[e.PartnerID] = 0
+	}
+
+	matrix := &SynergyMatrix{Champions: make([]int64, 0, len(index))}
+	for id := range index {
+		matrix.Champions = append(matrix.Champions, id)
+	}
+	sort.Slice(matrix.Champions, func(i, j int) bool { return matrix.Champions[i] < matrix.Champions[j] })
+
+	matrix.WinRates = make([][]*float64, len(matrix.Champions))
+	for i, id := range matrix.Champions {
+		index[id] = i
+		matrix.WinRates[i] = make([]*float64, len(matrix.Champions))
+	}
+
+	for _, e := range edges {
+		winRate := e.WinRate
+		i, j := index[e.ChampionID], index[e.PartnerID]
+		matrix.WinRates[i][j] = &winRate
+		matrix.WinRates[j][i] = &winRate
+	}
+
+	return matrix
+}
+
+// GetSynergyEdges returns the same-team record of every pair of champions played together in at
+// least minGames matches (remakes excluded), in one pass over the match performances. If role is
+// set, only matches where one of the pair played the role are counted. The query joins every
+// performance with its teammates' and so is expensive; callers should cache the result.
+func (c ChampionModel) GetSynergyEdges(role string, minGames int) ([]*SynergyEdge, error) {
+	query := `
+        SELECT a.champion_id, b.champion_id, count(*), count(*) FILTER (WHERE LOWER(m.result) = a.team)
+        FROM match_performance a
+        JOIN match_performance b ON b.match_id = a.match_id AND b.team = a.team AND b.champion_id > a.champion_id
+        JOIN matches m ON m.id = a.match_id
+        WHERE LOWER(m.result) <> $1
+        AND ($2 = '' OR LOWER(a.role) = LOWER($2) OR LOWER(b.role) = LOWER($2))
+        GROUP BY a.champion_id, b.champion_id
+        HAVING count(*) >= $3
+        ORDER BY a.champion_id, b.champion_id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, ResultRemake, role, minGames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edges := []*SynergyEdge{}
+
+	for rows.Next() {
+		var e SynergyEdge
+		if err := rows.Scan(&e.ChampionID, &e.PartnerID, &e.Games, &e.Wins); err != nil {
+			return nil, err
+		}
+		e.WinRate = float64(e.Wins) / float64(e.Games)
+		edges = append(edges, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return edges, nil
+}
@@ -0,0 +1,50 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSynergyMatrixSymmetric(t *testing.T) {
+	edges := []*SynergyEdge{
+		{ChampionID: 3, PartnerID: 7, WinRate: 0.6},
+		{ChampionID: 1, PartnerID: 3, WinRate: 0.45},
+		{ChampionID: 1, PartnerID: 9, WinRate: 0.5},
+	}
+
+	matrix := NewSynergyMatrix(edges)
+
+	if want := []int64{1, 3, 7, 9}; !reflect.DeepEqual(matrix.Champions, want) {
+		t.Fatalf("got champions %v, want %v", matrix.Champions, want)
+	}
+
+	rates := matrix.WinRates
+	for i := range rates {
+		if rates[i][i] != nil {
+			t.Errorf("champion %d: got win rate %v with itself, want nil", matrix.Champions[i], *rates[i][i])
+		}
+
+		for j := range rates {
+			if (rates[i][j] == nil) != (rates[j][i] == nil) || rates[i][j] != nil && *rates[i][j] != *rates[j][i] {
+				t.Errorf("champions %d and %d: entries differ across the diagonal", matrix.Champions[i], matrix.Champions[j])
+			}
+		}
+	}
+
+	// Champions 3 and 7 are at indexes 1 and 2.
+	if got := rates[1][2]; got == nil || *got != 0.6 {
+		t.Errorf("got win rate %v for champions 3 and 7, want 0.6", got)
+	}
+	// 7 and 9 weren't played together often enough.
+	if got := rates[2][3]; got != nil {
+		t.Errorf("got win rate %v for champions 7 and 9, want nil", *got)
+	}
+}
+
+func TestNewSynergyMatrixEmpty(t *testing.T) {
+	matrix := NewSynergyMatrix(nil)
+
+	if len(matrix.Champions) != 0 || len(matrix.WinRates) != 0 {
+		t.Errorf("got %+v, want an empty matrix", matrix)
+	}
+}
@@ -0,0 +1,85 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// Announcement is an operator-supplied message shown to clients, such as a maintenance notice.
+type Announcement struct {
+	Message   string     `json:"message"`
+	Active    bool       `json:"active"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// IsLive reports whether the announcement should be shown to clients at the given time, i.e.
+// it's switched on and hasn't expired yet.
+func (a *Announcement) IsLive(now time.Time) bool {
+	return a.Active && (a.ExpiresAt == nil || now.Before(*a.ExpiresAt))
+}
+
+func ValidateAnnouncement(v *validator.Validator, announcement *Announcement) {
+	v.Check(len(announcement.Message) <= 500, "message", "must not be more than 500 bytes long")
+	v.Check(!announcement.Active || announcement.Message != "", "message", "must be provided when the announcement is active")
+	if announcement.ExpiresAt != nil {
+		v.Check(announcement.ExpiresAt.After(time.Now()), "expiresAt", "must be in the future")
+	}
+}
+
+// SystemModel wraps the single-row system_settings table.
+type SystemModel struct {
+	DB *DB
+}
+
+// GetAnnouncement returns the current announcement, whether or not it's live.
+func (m SystemModel) GetAnnouncement() (*Announcement, error) {
+	query := `
+        SELECT announcement_message, announcement_active, announcement_expires_at, updated_at
+        FROM system_settings
+        WHERE id = 1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var announcement Announcement
+	var expiresAt sql.NullTime
+
+	err := m.DB.QueryRowContext(ctx, query).Scan(
+		&announcement.Message,
+		&announcement.Active,
+		&expiresAt,
+		&announcement.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		announcement.ExpiresAt = &expiresAt.Time
+	}
+
+	return &announcement, nil
+}
+
+// UpdateAnnouncement replaces the current announcement, setting its UpdatedAt from the database.
+func (m SystemModel) UpdateAnnouncement(announcement *Announcement) error {
+	query := `
+        UPDATE system_settings
+        SET announcement_message = $1, announcement_active = $2, announcement_expires_at = $3, updated_at = NOW()
+        WHERE id = 1
+        RETURNING updated_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var expiresAt sql.NullTime
+	if announcement.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *announcement.ExpiresAt, Valid: true}
+	}
+
+	return m.DB.QueryRowContext(ctx, query, announcement.Message, announcement.Active, expiresAt).Scan(&announcement.UpdatedAt)
+}
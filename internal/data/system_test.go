@@ -0,0 +1,64 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnnouncementIsLive(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		announcement Announcement
+		want         bool
+	}{
+		{"active", Announcement{Message: "Maintenance", Active: true}, true},
+		{"inactive", Announcement{Message: "Maintenance"}, false},
+		{"active until later", Announcement{Message: "Maintenance", Active: true, ExpiresAt: &later}, true},
+		{"expired", Announcement{Message: "Maintenance", Active: true, ExpiresAt: &earlier}, false},
+		{"expiring now", Announcement{Message: "Maintenance", Active: true, ExpiresAt: &now}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.announcement.IsLive(now); got != tt.want {
+			t.Errorf("%s: IsLive = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateAnnouncement(t *testing.T) {
+	models := newTestModels(t)
+
+	expiresAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	announcement := &Announcement{Message: "Maintenance at midnight", Active: true, ExpiresAt: &expiresAt}
+
+	if err := models.System.UpdateAnnouncement(announcement); err != nil {
+		t.Fatal(err)
+	}
+	if announcement.UpdatedAt.IsZero() {
+		t.Error("got no updatedAt after the update")
+	}
+
+	got, err := models.System.GetAnnouncement()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Message != announcement.Message || !got.Active || got.ExpiresAt == nil || !got.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("got %+v, want %+v", got, announcement)
+	}
+
+	// Switching it off and clearing the expiry is stored too.
+	if err := models.System.UpdateAnnouncement(&Announcement{}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = models.System.GetAnnouncement()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Message != "" || got.Active || got.ExpiresAt != nil {
+		t.Errorf("got %+v after clearing, want an empty inactive announcement", got)
+	}
+}
@@ -0,0 +1,89 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// newTestModels connects to the Postgres database named by TEST_DATABASE_DSN, drops everything in
+// it and applies every migration, so each test starts from an empty schema. Tests which call it
+// are skipped when TEST_DATABASE_DSN isn't set. The database is wiped, so never point it at one
+// holding data you want to keep.
+func newTestModels(t *testing.T) Models {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://../../migrations", "postgres", driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop leaves the migrate instance unusable for Up, so migrate up with a fresh one.
+	if err := m.Drop(); err != nil {
+		t.Fatal(err)
+	}
+
+	driver, err = postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = migrate.NewWithDatabaseInstance("file://../../migrations", "postgres", driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatal(err)
+	}
+
+	models := NewModels(db, nil)
+	models.Matches.StatUpdates = NewSemaphore(4)
+
+	return models
+}
+
+// insertTestSummoner stores a summoner with the given username in EUW.
+func insertTestSummoner(t *testing.T, models Models, username string) *Summoner {
+	t.Helper()
+
+	summoner := &Summoner{Username: username, Region: "EUW"}
+	if err := models.Summoners.Insert(summoner); err != nil {
+		t.Fatal(err)
+	}
+
+	return summoner
+}
+
+// insertTestChampion stores a champion with the given name.
+func insertTestChampion(t *testing.T, models Models, name string) *Champion {
+	t.Helper()
+
+	champion := &Champion{Name: name, MainRole: "Mid", Classes: ChampionClasses{}}
+	if err := models.Champions.Insert(champion); err != nil {
+		t.Fatal(err)
+	}
+
+	return champion
+}
@@ -0,0 +1,170 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// WinRateTrend compares a champion's win rate over the most recent period with its win rate over
+// the period of the same length before it.
+type WinRateTrend struct {
+	Period        string  `json:"period"` // The period as given by the client, e.g. "7d"
+	Games         int     `json:"games"`
+	WinRate       float64 `json:"winRate"`
+	PreviousGames int     `json:"previousGames"`
+	PreviousRate  float64 `json:"previousWinRate"`
+	WinRateDelta  float64 `json:"winRateDelta"` // WinRate - PreviousRate
+	Significant   bool    `json:"significant"`  // Whether the change is significant at the 95% level
+}
+
+// twoProportionZ returns the z-score of the difference between two win rates, using the pooled
+// two-proportion z-test. It returns 0 when either sample is empty or the pooled rate is 0 or 1.
+func twoProportionZ(wins1, games1, wins2, games2 int) float64 {
+	if games1 == 0 || games2 == 0 {
+		return 0
+	}
+
+	pooled := float64(wins1+wins2) / float64(games1+games2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(games1) + 1/float64(games2)))
+	if se == 0 {
+		return 0
+	}
+
+	return (float64(wins1)/float64(games1) - float64(wins2)/float64(games2)) / se
+}
+
+// GetWinRateTrend returns the champion's win rate over the last period compared with the period
+// before it, counting only the matches and performances within scope. Remakes are excluded.
+func (c ChampionModel) GetWinRateTrend(id int64, period time.Duration, scope StatsScope) (*WinRateTrend, error) {
+	query := `
+        SELECT
+            count(*) FILTER (WHERE m.played_date >= $3),
+            count(*) FILTER (WHERE m.played_date >= $3 AND LOWER(m.result) = mp.team),
+            count(*) FILTER (WHERE m.played_date < $3),
+            count(*) FILTER (WHERE m.played_date < $3 AND LOWER(m.result) = mp.team)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        WHERE mp.champion_id = $1 AND LOWER(m.result) <> $2
+        AND m.played_date >= $4 AND m.played_date < $5
+        AND (m.match_type = $6 OR $6 = '')
+        AND (cardinality($7::text[]) = 0 OR mp.rank_tier = ANY($7))`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	start := now.Add(-period)
+	previousStart := start.Add(-period)
+
+	var trend WinRateTrend
+	var wins, previousWins int
+
+	err := c.DB.QueryRowContext(ctx, query, id, ResultRemake, start, previousStart, now, scope.MatchType, pq.Array(scope.Tiers)).Scan(
+		&trend.Games, &wins, &trend.PreviousGames, &previousWins)
+	if err != nil {
+		return nil, err
+	}
+
+	if trend.Games > 0 {
+		trend.WinRate = float64(wins) / float64(trend.Games)
+	}
+	if trend.PreviousGames > 0 {
+		trend.PreviousRate = float64(previousWins) / float64(trend.PreviousGames)
+	}
+
+	trend.WinRateDelta = trend.WinRate - trend.PreviousRate
+	trend.Significant = math.Abs(twoProportionZ(wins, trend.Games, previousWins, trend.PreviousGames)) >= wilsonZ
+
+	return &trend, nil
+}
+
+// Buckets for a win rate history. Weeks start on Monday; iso_week differs from week only in
+// labelling each point with its ISO 8601 week (e.g. "2024-W07") rather than its start date.
+// Season boundaries come from the seasons table, and matches outside every season are left out.
+const (
+	BucketDay     = "day"
+	BucketWeek    = "week"
+	BucketISOWeek = "iso_week"
+	BucketSeason  = "season"
+)
+
+var Buckets = []string{BucketDay, BucketWeek, BucketISOWeek, BucketSeason}
+
+// winRateBuckets holds the start and label expressions of each bucket, over matches m (and, for
+// seasons, the season s each match falls in). Days and weeks are taken in UTC.
+var winRateBuckets = map[string]struct{ start, label string }{
+	BucketDay:     {"date_trunc('day', m.played_date AT TIME ZONE 'UTC')", "to_char(date_trunc('day', m.played_date AT TIME ZONE 'UTC'), 'YYYY-MM-DD')"},
+	BucketWeek:    {"date_trunc('week', m.played_date AT TIME ZONE 'UTC')", "to_char(date_trunc('week', m.played_date AT TIME ZONE 'UTC'), 'YYYY-MM-DD')"},
+	BucketISOWeek: {"date_trunc('week', m.played_date AT TIME ZONE 'UTC')", `to_char(m.played_date AT TIME ZONE 'UTC', 'IYYY-"W"IW')`},
+	BucketSeason:  {"s.starts_at", "s.name"},
+}
+
+// WinRatePoint is a champion's record over one bucket of a win rate history.
+type WinRatePoint struct {
+	Label   string    `json:"label"` // Date, ISO week or season name, depending on the bucket
+	Start   time.Time `json:"start"`
+	Games   int       `json:"games"`
+	Wins    int       `json:"wins"`
+	WinRate float64   `json:"winRate"`
+}
+
+// GetWinRateHistory returns the champion's win rate in each bucket (one of Buckets) with any
+// games since the given time, oldest first. A zero since covers the whole history. Remakes are
+// excluded.
+func (c ChampionModel) GetWinRateHistory(id int64, bucket string, since time.Time, scope StatsScope) ([]*WinRatePoint, error) {
+	expr, ok := winRateBuckets[bucket]
+	if !ok {
+		return nil, fmt.Errorf("unknown bucket %q", bucket)
+	}
+
+	seasons := ""
+	if bucket == BucketSeason {
+		seasons = "JOIN seasons s ON m.played_date >= s.starts_at AND (s.ends_at IS NULL OR m.played_date < s.ends_at)"
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s AS label, %s AS start, count(*), count(*) FILTER (WHERE LOWER(m.result) = mp.team)
+        FROM match_performance mp
+        JOIN matches m ON m.id = mp.match_id
+        %s
+        WHERE mp.champion_id = $1 AND LOWER(m.result) <> $2
+        AND m.played_date >= $3
+        AND (m.match_type = $4 OR $4 = '')
+        AND (cardinality($5::text[]) = 0 OR mp.rank_tier = ANY($5))
+        GROUP BY 1, 2
+        ORDER BY 2`, expr.label, expr.start, seasons)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := c.DB.QueryContext(ctx, query, id, ResultRemake, since, scope.MatchType, pq.Array(scope.Tiers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []*WinRatePoint{}
+
+	for rows.Next() {
+		var p WinRatePoint
+		err := rows.Scan(&p.Label, &p.Start, &p.Games, &p.Wins)
+		if err != nil {
+			return nil, err
+		}
+		if p.Games > 0 {
+			p.WinRate = float64(p.Wins) / float64(p.Games)
+		}
+
+		points = append(points, &p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
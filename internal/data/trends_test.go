@@ -0,0 +1,86 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTwoProportionZ(t *testing.T) {
+	tests := []struct {
+		wins1, games1, wins2, games2 int
+		want                         float64
+	}{
+		{60, 100, 40, 100, 2.8284},
+		{40, 100, 60, 100, -2.8284},
+		{52, 100, 50, 100, 0.2829},
+		{50, 100, 50, 100, 0},
+		// Empty samples and pooled rates of 0 or 1 have no variance to test against.
+		{0, 0, 5, 10, 0},
+		{5, 10, 0, 0, 0},
+		{10, 10, 20, 20, 0},
+		{0, 10, 0, 20, 0},
+	}
+
+	for _, tt := range tests {
+		got := twoProportionZ(tt.wins1, tt.games1, tt.wins2, tt.games2)
+		if math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("twoProportionZ(%d, %d, %d, %d) = %.4f, want %.4f", tt.wins1, tt.games1, tt.wins2, tt.games2, got, tt.want)
+		}
+	}
+}
+
+func TestGetWinRateHistoryBuckets(t *testing.T) {
+	models := newTestModels(t)
+
+	summoner := insertTestSummoner(t, models, "Faker")
+	champion := insertTestChampion(t, models, "Ahri")
+
+	_, err := models.Champions.DB.Exec(`
+        INSERT INTO seasons (name, starts_at, ends_at) VALUES
+            ('2024', '2024-01-10T00:00:00Z', '2025-01-09T00:00:00Z'),
+            ('2025', '2025-01-09T00:00:00Z', NULL)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two games either side of the boundary between the 2024 and 2025 seasons, which falls
+	// mid-week, and one before the first season.
+	for _, playedDate := range []time.Time{
+		time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 7, 12, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 8, 23, 59, 0, 0, time.UTC),
+		time.Date(2025, time.January, 9, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 10, 12, 0, 0, 0, time.UTC),
+	} {
+		insertTestRolePerformance(t, models, summoner.ID, champion.ID, "Mid", playedDate)
+	}
+
+	tests := []struct {
+		bucket string
+		want   []string // label:games for each point
+	}{
+		// The game before the first season is in no season, so is left out.
+		{BucketSeason, []string{"2024:2", "2025:2"}},
+		{BucketISOWeek, []string{"2024-W01:1", "2025-W02:4"}},
+		{BucketWeek, []string{"2024-01-01:1", "2025-01-06:4"}},
+	}
+
+	for _, tt := range tests {
+		points, err := models.Champions.GetWinRateHistory(champion.ID, tt.bucket, time.Time{}, StatsScope{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, p := range points {
+			got = append(got, fmt.Sprintf("%s:%d", p.Label, p.Games))
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got points %v, want %v", tt.bucket, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrNestedTransaction is returned by DB.BeginTx inside Models.Transact when it's asked for
+// transaction options, such as a read-only transaction, which a savepoint in the caller's
+// transaction can't provide.
+var ErrNestedTransaction = errors.New("cannot begin a transaction with options inside Models.Transact")
+
+// Querier is the set of query methods shared by *sql.DB and *sql.Tx, so that statements can be
+// run without caring whether they're part of a transaction.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryRow(query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Transact runs fn with a copy of the models whose statements all run in a single transaction, so
+// that a handler making several writes has them commit or roll back together. The transaction is
+// committed if fn returns nil, and rolled back if it returns an error or panics. Model methods
+// which run their own transaction run it as a savepoint in this one, so their writes commit with
+// it, and a method which fails only undoes its own writes.
+//
+// Every row a method locks stays locked until the whole transaction commits. The match statistics
+// updates lock summoner and champion rows in an order of their own, so they're kept out of fn, or
+// two requests could deadlock.
+func (m Models) Transact(ctx context.Context, fn func(tx Models) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(m.withDB(&DB{DB: m.db.DB, tx: tx, metrics: m.db.metrics}))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Tx is a transaction begun by DB.BeginTx. Inside Models.Transact it's a savepoint in the
+// caller's transaction instead: committing it releases the savepoint, and rolling it back undoes
+// only the statements run since the savepoint. Either way the caller's transaction carries on.
+type Tx struct {
+	*sql.Tx
+	savepoint string // empty for a transaction of its own
+	done      bool
+}
+
+// Commit commits the transaction, or releases the savepoint.
+func (tx *Tx) Commit() error {
+	if tx.savepoint == "" {
+		return tx.Tx.Commit()
+	}
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+
+	_, err := tx.Tx.Exec("RELEASE SAVEPOINT " + tx.savepoint)
+	return err
+}
+
+// Rollback rolls back the transaction, or the statements run since the savepoint. Like
+// sql.Tx.Rollback it returns sql.ErrTxDone once the transaction has been committed, so it can be
+// deferred.
+func (tx *Tx) Rollback() error {
+	if tx.savepoint == "" {
+		return tx.Tx.Rollback()
+	}
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+
+	// Rolling back to a savepoint keeps it, so it's released too, leaving the caller's transaction
+	// as it was before the savepoint began.
+	_, err := tx.Tx.Exec("ROLLBACK TO SAVEPOINT " + tx.savepoint)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Tx.Exec("RELEASE SAVEPOINT " + tx.savepoint)
+	return err
+}
+
+// savepoint begins a savepoint in the transaction the DB is bound to. A savepoint runs with the
+// transaction's own options, so it can't be asked for any.
+func (db *DB) savepoint(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if opts != nil {
+		return nil, ErrNestedTransaction
+	}
+
+	db.savepoints++
+	name := fmt.Sprintf("nested_%d", db.savepoints)
+
+	_, err := db.tx.ExecContext(ctx, "SAVEPOINT "+name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Tx: db.tx.Tx, savepoint: name}, nil
+}
+
+// withDB returns a copy of the models which all run their statements through db.
+func (m Models) withDB(db *DB) Models {
+	m.Champions.DB = db
+	m.Matches.DB = db
+	m.Summoners.DB = db
+	m.Users.DB = db
+	m.Tokens.DB = db
+	m.Permissions.DB = db
+	m.System.DB = db
+	m.Webhooks.DB = db
+	m.Features.DB = db
+	m.APIKeys.DB = db
+	m.db = db
+
+	return m
+}
@@ -0,0 +1,123 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTransactRollsBack(t *testing.T) {
+	models := newTestModels(t)
+
+	first := &Summoner{Username: "Faker", Region: "KR"}
+
+	err := models.Transact(context.Background(), func(tx Models) error {
+		if err := tx.Summoners.Insert(first); err != nil {
+			return err
+		}
+		// Usernames are unique within a region, so the second write fails.
+		return tx.Summoners.Insert(&Summoner{Username: "faker", Region: "KR"})
+	})
+	if err == nil {
+		t.Fatal("got no error for the duplicate summoner")
+	}
+
+	if _, err := models.Summoners.Get(first.ID); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("got error %v reading the first summoner, want ErrRecordNotFound", err)
+	}
+}
+
+func TestTransactCommits(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := &Summoner{Username: "Faker", Region: "KR"}
+	caps := &Summoner{Username: "Caps", Region: "EUW"}
+
+	err := models.Transact(context.Background(), func(tx Models) error {
+		if err := tx.Summoners.Insert(faker); err != nil {
+			return err
+		}
+		return tx.Summoners.Insert(caps)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, summoner := range []*Summoner{faker, caps} {
+		if _, err := models.Summoners.Get(summoner.ID); err != nil {
+			t.Errorf("reading %s: %v", summoner.Username, err)
+		}
+	}
+}
+
+func TestTransactNested(t *testing.T) {
+	models := newTestModels(t)
+
+	faker := insertTestSummoner(t, models, "Faker")
+	caps := &Summoner{Username: "Caps", Region: "EUW"}
+
+	// Summoners.Update runs its own transaction, which becomes a savepoint in this one.
+	err := models.Transact(context.Background(), func(tx Models) error {
+		if err := tx.Summoners.Insert(caps); err != nil {
+			return err
+		}
+
+		// A failed update only undoes itself, so the transaction can carry on.
+		faker.Username = "Caps"
+		if err := tx.Summoners.Update(faker); !errors.Is(err, ErrDuplicateSummoner) {
+			return fmt.Errorf("got error %v renaming Faker to Caps, want ErrDuplicateSummoner", err)
+		}
+
+		faker.Username = "Faker"
+		faker.Region = "KR"
+		return tx.Summoners.Update(faker)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := models.Summoners.Get(faker.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Region != "KR" {
+		t.Errorf("got region %s after the transaction committed, want KR", got.Region)
+	}
+	if _, err := models.Summoners.Get(caps.ID); err != nil {
+		t.Errorf("reading Caps: %v", err)
+	}
+
+	// The update's savepoint is released into the transaction, so it's rolled back with it.
+	err = models.Transact(context.Background(), func(tx Models) error {
+		faker.Region = "NA"
+		if err := tx.Summoners.Update(faker); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	if err == nil || err.Error() != "abort" {
+		t.Fatalf("got error %v, want abort", err)
+	}
+
+	got, err = models.Summoners.Get(faker.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Region != "KR" {
+		t.Errorf("got region %s after the transaction rolled back, want KR", got.Region)
+	}
+}
+
+func TestTransactNestedOptions(t *testing.T) {
+	models := newTestModels(t)
+
+	err := models.Transact(context.Background(), func(tx Models) error {
+		_, err := tx.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+		return err
+	})
+	if !errors.Is(err, ErrNestedTransaction) {
+		t.Errorf("got error %v, want ErrNestedTransaction", err)
+	}
+}
@@ -0,0 +1,73 @@
+package data
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// UsernameRule describes the summoner names allowed in a region. Names must be between MinLength
+// and MaxLength characters long and may contain digits, spaces and letters from any of Scripts.
+type UsernameRule struct {
+	MinLength int
+	MaxLength int
+	Scripts   []*unicode.RangeTable
+}
+
+// defaultUsernameRule is used for regions without a rule of their own. It accepts letters from any
+// script, so we don't reject real names from servers we haven't described yet.
+var defaultUsernameRule = UsernameRule{MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Letter}}
+
+// katakanaProlongedSoundMark is the "ー" which lengthens a vowel in katakana. Unicode puts it in
+// the Common script rather than Katakana, so it needs a table of its own.
+var katakanaProlongedSoundMark = &unicode.RangeTable{R16: []unicode.Range16{{Lo: 0x30fc, Hi: 0x30fc, Stride: 1}}}
+
+// usernameRules holds the summoner name rules for each region code. Every region accepts Latin
+// letters; Asian, Cyrillic and Arabic servers also accept their local scripts.
+var usernameRules = map[string]UsernameRule{
+	"BR":   {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin}},
+	"EUNE": {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin, unicode.Greek}},
+	"EUW":  {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin}},
+	"JP":   {MinLength: 2, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin, unicode.Hiragana, unicode.Katakana, katakanaProlongedSoundMark, unicode.Han}},
+	"KR":   {MinLength: 2, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin, unicode.Hangul}},
+	"LAN":  {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin}},
+	"LAS":  {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin}},
+	"ME":   {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin, unicode.Arabic}},
+	"NA":   {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin}},
+	"OCE":  {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin}},
+	"PH":   {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin}},
+	"RU":   {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic}},
+	"SG":   {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin, unicode.Han}},
+	"TH":   {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin, unicode.Thai}},
+	"TR":   {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin}},
+	"TW":   {MinLength: 2, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin, unicode.Han}},
+	"VN":   {MinLength: 3, MaxLength: 16, Scripts: []*unicode.RangeTable{unicode.Latin}},
+}
+
+// UsernameRuleFor returns the summoner name rule for a region, or a permissive default if we
+// don't have a rule for it.
+func UsernameRuleFor(region string) UsernameRule {
+	if rule, ok := usernameRules[region]; ok {
+		return rule
+	}
+	return defaultUsernameRule
+}
+
+// ValidLength reports whether the username has an allowed number of characters.
+func (r UsernameRule) ValidLength(username string) bool {
+	n := utf8.RuneCountInString(username)
+	return n >= r.MinLength && n <= r.MaxLength
+}
+
+// ValidCharacters reports whether every character in the username is a digit, a space or a letter
+// from one of the rule's scripts.
+func (r UsernameRule) ValidCharacters(username string) bool {
+	for _, c := range username {
+		if c == ' ' || unicode.IsDigit(c) {
+			continue
+		}
+		if !unicode.IsLetter(c) || !unicode.IsOneOf(r.Scripts, c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package data
+
+import "testing"
+
+func TestUsernameRule(t *testing.T) {
+	tests := []struct {
+		region   string
+		username string
+		valid    bool
+	}{
+		{"EUW", "Caps", true},
+		{"EUW", "G2 Caps 1", true},
+		{"EUW", "Müller", true},
+		{"EUW", "Ca", false},
+		{"EUW", "ThisNameIsTooLong", false},
+		{"EUW", "Caps!", false},
+		{"EUW", "Фейкер", false},
+		{"RU", "Фейкер", true},
+		{"EUNE", "Αλέξης", true},
+		{"KR", "페이커", true},
+		{"KR", "페이", true},
+		{"KR", "フェイカー", false},
+		{"JP", "フェイカー", true},
+		{"JP", "漢字", true},
+		// Lengths count characters, not bytes.
+		{"KR", "페이커페이커페이커페이커페이커페", true},
+		// Unknown regions accept letters from any script.
+		{"XX", "Фейкер", true},
+		{"XX", "Fa", false},
+	}
+
+	for _, tt := range tests {
+		rule := UsernameRuleFor(tt.region)
+		got := rule.ValidLength(tt.username) && rule.ValidCharacters(tt.username)
+		if got != tt.valid {
+			t.Errorf("%s %q: got valid=%t, want %t", tt.region, tt.username, got, tt.valid)
+		}
+	}
+}
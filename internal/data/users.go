@@ -18,6 +18,14 @@ var (
 
 var AnonymousUser = &User{}
 
+// Bounds and default for the bcrypt cost used to hash passwords. Each step up doubles the time
+// taken to hash (and so to check) a password.
+const (
+	MinBcryptCost     = bcrypt.MinCost
+	MaxBcryptCost     = bcrypt.MaxCost
+	DefaultBcryptCost = 12
+)
+
 // User type whose fields describe a user. Note, that we use the json:"-" struct tag to prevent
 // the Password and Version fields from appearing in any output when we encode it to JSON.
 // Also, notice that the Password field uses the custom password type defined below.
@@ -38,7 +46,7 @@ func (u *User) IsAnonymous() bool {
 // UserModel struct wraps a sql.DB connection pool and allows us to work with the User struct type
 // and the users table in our database.
 type UserModel struct {
-	DB       *sql.DB
+	DB       *DB
 	InfoLog  *log.Logger
 	ErrorLog *log.Logger
 }
@@ -52,10 +60,10 @@ type password struct {
 	hash      []byte
 }
 
-// Set calculates the bcrypt hash of a plaintext password, and stores both the has and the
-// plaintext versions in the password struct.
-func (p *password) Set(plaintextPassword string) error {
-	hash, err := bcrypt.GenerateFromPassword([]byte(plaintextPassword), 12)
+// Set calculates the bcrypt hash of a plaintext password with the given cost, and stores both the
+// has and the plaintext versions in the password struct.
+func (p *password) Set(plaintextPassword string, cost int) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(plaintextPassword), cost)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,36 @@
+package data
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordSetCost(t *testing.T) {
+	for _, cost := range []int{MinBcryptCost, MinBcryptCost + 2} {
+		var p password
+		if err := p.Set("pa55word1234", cost); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := bcrypt.Cost(p.hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != cost {
+			t.Errorf("got a hash of cost %d, want %d", got, cost)
+		}
+
+		if ok, err := p.Matches("pa55word1234"); !ok || err != nil {
+			t.Errorf("cost %d: got match %t (%v) for the right password", cost, ok, err)
+		}
+		if ok, err := p.Matches("wrong password"); ok || err != nil {
+			t.Errorf("cost %d: got match %t (%v) for the wrong password", cost, ok, err)
+		}
+	}
+
+	var p password
+	if err := p.Set("pa55word1234", MaxBcryptCost+1); err == nil {
+		t.Error("got no error for a cost above the maximum")
+	}
+}
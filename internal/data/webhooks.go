@@ -0,0 +1,113 @@
+package data
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/validator"
+)
+
+// Webhook is an endpoint which is sent a signed event when champion stats change significantly.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // Only sent back when the webhook is created
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func ValidateWebhook(v *validator.Validator, webhook *Webhook) {
+	v.Check(webhook.URL != "", "url", "must be provided")
+	v.Check(len(webhook.URL) <= 2048, "url", "must not be more than 2048 bytes long")
+	v.Check(webhook.URL == "" || validator.IsURL(webhook.URL), "url", "must be an absolute http or https URL")
+}
+
+// GenerateWebhookSecret returns a random secret for signing a webhook's events.
+func GenerateWebhookSecret() (string, error) {
+	randomBytes := make([]byte, 32)
+
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(randomBytes), nil
+}
+
+type WebhookModel struct {
+	DB *DB
+}
+
+// Insert adds a webhook, setting its ID and CreatedAt from the database.
+func (m WebhookModel) Insert(webhook *Webhook) error {
+	query := `
+        INSERT INTO webhooks (url, secret)
+        VALUES ($1, $2)
+        RETURNING id, created_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, webhook.URL, webhook.Secret).Scan(&webhook.ID, &webhook.CreatedAt)
+}
+
+// GetAll returns every registered webhook, including its secret, in the order they were created.
+func (m WebhookModel) GetAll() ([]*Webhook, error) {
+	query := `
+        SELECT id, url, secret, created_at
+        FROM webhooks
+        ORDER BY id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*Webhook{}
+
+	for rows.Next() {
+		var webhook Webhook
+		err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &webhook.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Delete removes a webhook, returning ErrRecordNotFound if it doesn't exist.
+func (m WebhookModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
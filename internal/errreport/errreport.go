@@ -0,0 +1,115 @@
+package errreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+// Reporter sends unexpected errors (and recovered panics) to an external error-tracking service.
+// Implementations must be safe for concurrent use and must not block the caller for long.
+type Reporter interface {
+	Report(ctx context.Context, err error, stack []byte, tags map[string]string)
+}
+
+// Nop is a Reporter which discards everything. It's used when no error-tracking service has
+// been configured.
+type Nop struct{}
+
+// Report does nothing.
+func (Nop) Report(ctx context.Context, err error, stack []byte, tags map[string]string) {}
+
+// Sentry is a Reporter which posts events to a Sentry-compatible store endpoint. It talks to the
+// HTTP API directly so that we don't need to depend on the Sentry SDK.
+type Sentry struct {
+	endpoint  string
+	publicKey string
+	client    *http.Client
+	logger    *jsonlog.Logger
+}
+
+// NewSentry parses a Sentry DSN in the form "https://<key>@<host>/<project-id>" and returns a
+// Reporter which sends events to it. Failures to deliver an event are written to the logger.
+func NewSentry(dsn string, logger *jsonlog.Logger) (*Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sentry dsn: %w", err)
+	}
+
+	projectID := strings.Trim(u.Path, "/")
+	if u.User == nil || u.User.Username() == "" || projectID == "" {
+		return nil, errors.New("invalid sentry dsn: must include a public key and project id")
+	}
+
+	return &Sentry{
+		endpoint:  fmt.Sprintf("%s://%s/api/%s/store/", u.Scheme, u.Host, projectID),
+		publicKey: u.User.Username(),
+		client:    &http.Client{Timeout: 5 * time.Second},
+		logger:    logger,
+	}, nil
+}
+
+// Report sends the error to Sentry in a background goroutine, so the request which triggered
+// it isn't held up by a slow or unavailable error-tracking service.
+func (s *Sentry) Report(ctx context.Context, err error, stack []byte, tags map[string]string) {
+	eventID := make([]byte, 16)
+	if _, randErr := rand.Read(eventID); randErr != nil {
+		s.logger.PrintError(randErr, nil)
+		return
+	}
+
+	event := map[string]interface{}{
+		"event_id":  hex.EncodeToString(eventID),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"level":     "error",
+		"platform":  "go",
+		"message":   err.Error(),
+		"tags":      tags,
+		"extra":     map[string]string{"stack": string(stack)},
+	}
+
+	go func() {
+		sendErr := s.send(event)
+		if sendErr != nil {
+			s.logger.PrintError(sendErr, map[string]string{"reporter": "sentry"})
+		}
+	}()
+}
+
+// send posts a single event to the Sentry store endpoint.
+func (s *Sentry) send(event map[string]interface{}) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=league-of-graphs/1.0, sentry_key=%s", s.publicKey))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("sentry responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
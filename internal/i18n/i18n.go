@@ -0,0 +1,118 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is the language our messages are written in, and the one used when a client
+// doesn't ask for (or we don't support) anything else.
+const DefaultLanguage = "en"
+
+// catalog maps each supported language to its translations, keyed by the English message. A
+// message without a translation is sent in English.
+var catalog = map[string]map[string]string{
+	DefaultLanguage: {},
+	"ko": {
+		// Validation messages.
+		"must be provided":                    "필수 항목입니다",
+		"must be greater than zero":           "0보다 커야 합니다",
+		"must not be negative":                "음수일 수 없습니다",
+		"must be an integer value":            "정수여야 합니다",
+		"must be a boolean value":             "불리언 값이어야 합니다",
+		"must be valid email address":         "유효한 이메일 주소여야 합니다",
+		"must be at least 8 bytes long":       "8바이트 이상이어야 합니다",
+		"must not be more than 72 bytes long": "72바이트를 넘을 수 없습니다",
+		"must not be in the future":           "미래 날짜일 수 없습니다",
+		"must be in the future":               "미래 날짜여야 합니다",
+		"must be a maximum of 50":             "최대 50까지 가능합니다",
+		"must be a maximum of 100":            "최대 100까지 가능합니다",
+		"must be an existing summoner":        "존재하는 소환사여야 합니다",
+		"must not be set for both teams":      "양 팀 모두에 설정할 수 없습니다",
+		"cannot be cleared":                   "비울 수 없습니다",
+		"invalid sort value":                  "잘못된 정렬 값입니다",
+
+		// Error responses.
+		"the server encountered a problem and could not process your request":              "서버에 문제가 발생하여 요청을 처리할 수 없습니다",
+		"the response is too large to send, please request fewer results":                  "응답이 너무 커서 보낼 수 없습니다. 더 적은 결과를 요청해 주세요",
+		"the requested resource could not be found":                                        "요청한 리소스를 찾을 수 없습니다",
+		"the requested champion could not be found":                                        "요청한 챔피언을 찾을 수 없습니다",
+		"the requested summoner could not be found":                                        "요청한 소환사를 찾을 수 없습니다",
+		"the requested match could not be found":                                           "요청한 매치를 찾을 수 없습니다",
+		"the match has no gold or XP timeline data":                                        "매치에 골드 또는 경험치 타임라인 데이터가 없습니다",
+		"unable to update the record due to an edit conflict, please try again":            "편집 충돌로 레코드를 업데이트할 수 없습니다. 다시 시도해 주세요",
+		"invalid authentication credentials":                                               "잘못된 인증 정보입니다",
+		"invalid or missing authentication token":                                          "인증 토큰이 없거나 잘못되었습니다",
+		"invalid, expired or revoked API key":                                              "API 키가 잘못되었거나 만료 또는 폐기되었습니다",
+		"you must be authenticated to access this resource":                                "이 리소스에 접근하려면 인증이 필요합니다",
+		"your user account must be activated to access this resource":                      "이 리소스에 접근하려면 계정을 활성화해야 합니다",
+		"your user account doesn't have the necessary permissions to access this resource": "이 리소스에 접근할 권한이 없습니다",
+		"rate limited exceeded":                                                            "요청 한도를 초과했습니다",
+	},
+}
+
+// Translate returns message in the given language, or unchanged if the language isn't supported
+// or has no translation for it.
+func Translate(language, message string) string {
+	if translated, ok := catalog[language][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// TranslateAll returns a copy of messages with every value translated into the given language.
+func TranslateAll(language string, messages map[string]string) map[string]string {
+	translated := make(map[string]string, len(messages))
+	for key, message := range messages {
+		translated[key] = Translate(language, message)
+	}
+	return translated
+}
+
+// Negotiate picks the supported language the client prefers most from the value of an
+// Accept-Language header (e.g. "ko-KR,ko;q=0.9,en;q=0.8"). Region subtags are ignored, so
+// "ko-KR" matches "ko". If nothing acceptable is supported, DefaultLanguage is returned.
+func Negotiate(header string) string {
+	type preference struct {
+		language string
+		quality  float64
+	}
+
+	var preferences []preference
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		language, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		preferences = append(preferences, preference{language, quality})
+	}
+
+	// Keep the header's order between languages of equal quality.
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, p := range preferences {
+		if p.quality <= 0 {
+			break
+		}
+		if _, ok := catalog[p.language]; ok {
+			return p.language
+		}
+	}
+
+	return DefaultLanguage
+}
@@ -0,0 +1,48 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"ko", "ko"},
+		{"ko-KR,ko;q=0.9,en;q=0.8", "ko"},
+		{"KO-kr", "ko"},
+		{"en-US,ko;q=0.5", "en"},
+		{"fr,ko;q=0.5", "ko"},
+		{"en;q=0.5, ko;q=0.8", "ko"},
+		// Languages of equal quality keep the header's order.
+		{"ko;q=0.7,en;q=0.7", "ko"},
+		// A quality of zero means the language isn't acceptable.
+		{"ko;q=0", "en"},
+		{"ko;q=abc", "en"},
+		{"fr, de", "en"},
+		{"*", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := Translate("ko", "must be provided"); got != "필수 항목입니다" {
+		t.Errorf("got %q for a translated message", got)
+	}
+	if got := Translate("ko", "no translation for this"); got != "no translation for this" {
+		t.Errorf("got %q for a message without a translation", got)
+	}
+	if got := Translate("fr", "must be provided"); got != "must be provided" {
+		t.Errorf("got %q for an unsupported language", got)
+	}
+
+	got := TranslateAll("ko", map[string]string{"name": "must be provided", "other": "unknown"})
+	if got["name"] != "필수 항목입니다" || got["other"] != "unknown" {
+		t.Errorf("TranslateAll: got %v", got)
+	}
+}
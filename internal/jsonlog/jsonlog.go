@@ -2,9 +2,12 @@ package jsonlog
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"runtime/debug"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -35,21 +38,65 @@ func (l Level) String() string {
 	}
 }
 
+// ParseLevel converts a level name ("info", "error", "fatal" or "off", in any case) into a Level.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "info":
+		return LevelInfo, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	case "off":
+		return LevelOff, nil
+	default:
+		return LevelInfo, fmt.Errorf("invalid log level %q", s)
+	}
+}
+
+// Format is the encoding used for log entries.
+type Format int8
+
+const (
+	FormatJSON Format = iota // One JSON object per line, for ingestion into log aggregators.
+	FormatText               // Human-readable lines, for local development.
+)
+
+// ParseFormat converts a format name ("json" or "text") into a Format.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return FormatJSON, nil
+	case "text":
+		return FormatText, nil
+	default:
+		return FormatJSON, fmt.Errorf("invalid log format %q", s)
+	}
+}
+
 // Logger is the custom logger. It holds the output destination that the log entries will be
 // written to, the minimum severity level that log entries will be written for, and a mutex
 // for coordination the writes.
 type Logger struct {
 	out      io.Writer
 	minLevel Level
+	format   Format
 	mu       sync.Mutex
 }
 
-// NewLogger returns a new Logger instance which writes log entries at or above a minimum severity
-// level to a specific output destination.
+// NewLogger returns a new Logger instance which writes JSON log entries at or above a minimum
+// severity level to a specific output destination.
 func NewLogger(out io.Writer, minLevel Level) *Logger {
+	return New(out, minLevel, FormatJSON)
+}
+
+// New returns a new Logger instance which writes log entries at or above a minimum severity level
+// to a specific output destination, in the given format.
+func New(out io.Writer, minLevel Level, format Format) *Logger {
 	return &Logger{
 		out:      out,
 		minLevel: minLevel,
+		format:   format,
 	}
 }
 
@@ -99,12 +146,17 @@ func (l *Logger) print(level Level, message string, properties map[string]string
 	// Declare a line variable for holding the actual log entry text.
 	var line []byte
 
-	// Marshal the anonymous struct to JSON and store it in the line variable. If there was a
-	// problem creating the JSON then set the contents of the log entry to be that
-	// plan-text error message instead.
-	line, err := json.Marshal(aux)
-	if err != nil {
-		line = []byte(LevelError.String() + ": unable to marshal log message:" + err.Error())
+	if l.format == FormatText {
+		line = formatText(aux.Time, aux.Level, aux.Message, aux.Properties, aux.Trace)
+	} else {
+		// Marshal the anonymous struct to JSON and store it in the line variable. If there was a
+		// problem creating the JSON then set the contents of the log entry to be that
+		// plan-text error message instead.
+		var err error
+		line, err = json.Marshal(aux)
+		if err != nil {
+			line = []byte(LevelError.String() + ": unable to marshal log message:" + err.Error())
+		}
 	}
 
 	// Lock the mutex so that no two writes to the output destination cannot happen concurrently.
@@ -117,6 +169,32 @@ func (l *Logger) print(level Level, message string, properties map[string]string
 	return l.out.Write(append(line, '\n'))
 }
 
+// formatText renders a log entry as a single human-readable line, in the form
+// "<time> <LEVEL> <message> key=value ...", with the properties sorted by key. Any stack trace
+// follows on the next lines.
+func formatText(time, level, message string, properties map[string]string, trace string) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s %s", time, level, message)
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%q", key, properties[key])
+	}
+
+	if trace != "" {
+		b.WriteString("\n")
+		b.WriteString(strings.TrimRight(trace, "\n"))
+	}
+
+	return []byte(b.String())
+}
+
 // Write satisfies the io.Writer interface. It writes a log entry at the ERROR level with
 // no additional properties
 func (l *Logger) Write(message []byte) (n int, err error) {
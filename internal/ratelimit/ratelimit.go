@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+// Limiter decides whether a client, identified by key (e.g. its IP address), may make another
+// request. Implementations must be safe for concurrent use.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// Memory is a token-bucket Limiter which keeps its buckets in process memory. The limits it
+// enforces are per API instance, so behind a load balancer the effective limit is multiplied by
+// the number of instances.
+type Memory struct {
+	rps   float64
+	burst int
+
+	mu      sync.Mutex
+	clients map[string]*memoryClient
+}
+
+type memoryClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewMemory returns a Memory limiter allowing rps requests per second per key, with bursts of up
+// to burst requests. It starts a background goroutine which forgets keys that haven't been seen
+// for three minutes.
+func NewMemory(rps float64, burst int) *Memory {
+	m := &Memory{rps: rps, burst: burst, clients: make(map[string]*memoryClient)}
+
+	go func() {
+		for {
+			time.Sleep(time.Minute)
+
+			m.mu.Lock()
+			for key, client := range m.clients {
+				if time.Since(client.lastSeen) > 3*time.Minute {
+					delete(m.clients, key)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}()
+
+	return m
+}
+
+// Allow takes a token from the key's bucket, creating the bucket if this is the key's first
+// request. It never returns an error.
+func (m *Memory) Allow(ctx context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	client, found := m.clients[key]
+	if !found {
+		client = &memoryClient{limiter: rate.NewLimiter(rate.Limit(m.rps), m.burst)}
+		m.clients[key] = client
+	}
+
+	client.lastSeen = time.Now()
+
+	return client.limiter.Allow(), nil
+}
+
+// Fallback is a Limiter which uses Primary, but switches to Secondary whenever Primary returns an
+// error, for example because Redis is unreachable. After a failure Secondary is used for
+// RetryAfter before Primary is tried again, so that an outage costs one log entry and one slow
+// request per RetryAfter rather than one per request.
+type Fallback struct {
+	Primary    Limiter
+	Secondary  Limiter
+	RetryAfter time.Duration
+	Logger     *jsonlog.Logger
+
+	mu       sync.Mutex
+	failedAt time.Time
+	degraded bool
+}
+
+// Allow checks the key against Primary, or against Secondary while Primary is unavailable.
+func (f *Fallback) Allow(ctx context.Context, key string) (bool, error) {
+	f.mu.Lock()
+	usePrimary := !f.degraded || time.Since(f.failedAt) >= f.RetryAfter
+	f.mu.Unlock()
+
+	if usePrimary {
+		allowed, err := f.Primary.Allow(ctx, key)
+		if err == nil {
+			f.recover()
+			return allowed, nil
+		}
+
+		f.fail(err)
+	}
+
+	return f.Secondary.Allow(ctx, key)
+}
+
+func (f *Fallback) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.degraded {
+		f.Logger.PrintInfo("warning: rate limiter unavailable, falling back to in-memory limits", map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	f.degraded = true
+	f.failedAt = time.Now()
+}
+
+func (f *Fallback) recover() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.degraded {
+		f.Logger.PrintInfo("rate limiter recovered", nil)
+	}
+
+	f.degraded = false
+}
@@ -0,0 +1,226 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// tokenBucketScript implements a token bucket in Redis. The bucket for each key is a hash holding
+// the number of tokens left and the time (in milliseconds) they were last topped up. The time is
+// read from the Redis server rather than passed in, so API instances with skewed clocks still
+// share a consistent view of each bucket. Idle buckets expire once they would have refilled.
+const tokenBucketScript = `
+local rps = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rps)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rps * 1000) + 1000)
+
+return allowed
+`
+
+// Redis is a token-bucket Limiter which keeps its buckets in Redis, so that every API instance
+// sharing the Redis server enforces the same global limit. It speaks the Redis protocol (RESP)
+// directly over a small pool of connections, which is all we need for a single script call.
+type Redis struct {
+	addr    string
+	rps     float64
+	burst   int
+	timeout time.Duration
+	conns   chan *redisConn
+}
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// NewRedis returns a Redis limiter allowing rps requests per second per key, with bursts of up
+// to burst requests. It checks that the server at addr is reachable before returning.
+func NewRedis(addr string, rps float64, burst int) (*Redis, error) {
+	if rps <= 0 {
+		return nil, errors.New("ratelimit: rps must be greater than zero")
+	}
+
+	l := &Redis{
+		addr:    addr,
+		rps:     rps,
+		burst:   burst,
+		timeout: 500 * time.Millisecond,
+		conns:   make(chan *redisConn, 16),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reply, err := l.do(ctx, "PING")
+	if err != nil {
+		return nil, err
+	}
+	if reply != "PONG" {
+		return nil, fmt.Errorf("ratelimit: unexpected reply to PING: %v", reply)
+	}
+
+	return l, nil
+}
+
+// Allow takes a token from the key's bucket in Redis.
+func (l *Redis) Allow(ctx context.Context, key string) (bool, error) {
+	reply, err := l.do(ctx, "EVAL", tokenBucketScript, "1", "ratelimit:"+key,
+		strconv.FormatFloat(l.rps, 'f', -1, 64), strconv.Itoa(l.burst))
+	if err != nil {
+		return false, err
+	}
+
+	allowed, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("ratelimit: unexpected reply to EVAL: %v", reply)
+	}
+
+	return allowed == 1, nil
+}
+
+// do sends a single command and returns its reply. Connections are returned to the pool only
+// after a complete reply has been read, so a connection is never reused mid-reply.
+func (l *Redis) do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := l.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(l.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	reply, err := conn.command(args...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	select {
+	case l.conns <- conn:
+	default:
+		conn.Close()
+	}
+
+	if replyErr, ok := reply.(redisError); ok {
+		return nil, replyErr
+	}
+
+	return reply, nil
+}
+
+// get takes an idle connection from the pool, or dials a new one if none are idle.
+func (l *Redis) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: l.timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", l.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redisConn{Conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+// redisError is an error reply sent by the Redis server.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// command writes args as a RESP array of bulk strings and reads the reply.
+func (c *redisConn) command(args ...string) (interface{}, error) {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+
+	if _, err := c.Write(buf); err != nil {
+		return nil, err
+	}
+
+	return c.readReply()
+}
+
+// readReply reads a single RESP reply. Simple strings are returned as strings, integers as
+// int64, bulk strings as strings (or nil), arrays as []interface{} and errors as redisError.
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("ratelimit: malformed reply %q", line)
+	}
+
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return redisError(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("ratelimit: unknown reply type %q", kind)
+	}
+}
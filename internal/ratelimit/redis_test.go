@@ -0,0 +1,196 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"league_of_graphs.satellite.net/internal/jsonlog"
+)
+
+// newTestRedis starts a miniredis server with its clock frozen at a fixed time, and returns it
+// with a Redis limiter connected to it.
+func newTestRedis(t *testing.T, rps float64, burst int) (*miniredis.Miniredis, *Redis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	server.SetTime(time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC))
+
+	limiter, err := NewRedis(server.Addr(), rps, burst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server, limiter
+}
+
+// take calls Allow n times and returns how many calls were allowed.
+func take(t *testing.T, limiter Limiter, key string, n int) int {
+	t.Helper()
+
+	allowed := 0
+	for i := 0; i < n; i++ {
+		ok, err := limiter.Allow(context.Background(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			allowed++
+		}
+	}
+
+	return allowed
+}
+
+func TestRedisBurst(t *testing.T) {
+	_, limiter := newTestRedis(t, 1, 3)
+
+	if got := take(t, limiter, "192.0.2.1", 5); got != 3 {
+		t.Errorf("got %d requests allowed, want the burst of 3", got)
+	}
+
+	// Each key has a bucket of its own.
+	if got := take(t, limiter, "192.0.2.2", 1); got != 1 {
+		t.Errorf("got %d requests allowed for a second key, want 1", got)
+	}
+}
+
+func TestRedisRefill(t *testing.T) {
+	server, limiter := newTestRedis(t, 2, 2)
+	start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := take(t, limiter, "192.0.2.1", 3); got != 2 {
+		t.Fatalf("got %d requests allowed, want 2", got)
+	}
+
+	// Half a second at 2 rps tops the bucket up with one token.
+	server.SetTime(start.Add(500 * time.Millisecond))
+	if got := take(t, limiter, "192.0.2.1", 2); got != 1 {
+		t.Errorf("got %d requests allowed after half a second, want 1", got)
+	}
+
+	// The bucket never holds more than the burst, however long it's left.
+	server.SetTime(start.Add(time.Hour))
+	if got := take(t, limiter, "192.0.2.1", 5); got != 2 {
+		t.Errorf("got %d requests allowed after an hour, want 2", got)
+	}
+}
+
+func TestRedisBucketExpires(t *testing.T) {
+	server, limiter := newTestRedis(t, 1, 2)
+
+	take(t, limiter, "192.0.2.1", 1)
+
+	key := "ratelimit:192.0.2.1"
+	if !server.Exists(key) {
+		t.Fatalf("bucket %q was not stored", key)
+	}
+
+	// An idle bucket is dropped once it would have refilled, plus a second's grace.
+	if ttl := server.TTL(key); ttl <= 0 || ttl > 3*time.Second {
+		t.Errorf("got TTL %v, want at most 3s", ttl)
+	}
+}
+
+func TestNewRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	if _, err := NewRedis(server.Addr(), 0, 1); err == nil {
+		t.Error("got no error for an rps of zero")
+	}
+
+	addr := server.Addr()
+	server.Close()
+
+	if _, err := NewRedis(addr, 1, 1); err == nil {
+		t.Error("got no error for an unreachable server")
+	}
+}
+
+func TestRedisErrors(t *testing.T) {
+	server, limiter := newTestRedis(t, 1, 1)
+
+	_, err := limiter.do(context.Background(), "NOSUCHCOMMAND")
+	var replyErr redisError
+	if !errors.As(err, &replyErr) {
+		t.Errorf("got error %v, want an error reply", err)
+	}
+
+	// The connection is still usable after an error reply.
+	if _, err := limiter.Allow(context.Background(), "192.0.2.1"); err != nil {
+		t.Errorf("got error %v after an error reply", err)
+	}
+
+	server.Close()
+
+	if _, err := limiter.Allow(context.Background(), "192.0.2.1"); err == nil {
+		t.Error("got no error with the server gone")
+	}
+}
+
+func TestRedisFallback(t *testing.T) {
+	server, primary := newTestRedis(t, 1, 1)
+
+	fallback := &Fallback{
+		Primary:    primary,
+		Secondary:  NewMemory(0.001, 5),
+		RetryAfter: time.Hour,
+		Logger:     jsonlog.NewLogger(io.Discard, jsonlog.LevelInfo),
+	}
+
+	if got := take(t, fallback, "192.0.2.1", 3); got != 1 {
+		t.Fatalf("got %d requests allowed by redis, want 1", got)
+	}
+
+	server.Close()
+
+	// With redis gone the in-memory limiter takes over, with its own buckets.
+	if got := take(t, fallback, "192.0.2.1", 10); got != 5 {
+		t.Errorf("got %d requests allowed after the fallback, want 5", got)
+	}
+}
+
+func TestReadReply(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  interface{}
+	}{
+		{"+PONG\r\n", "PONG"},
+		{"-ERR unknown command\r\n", redisError("ERR unknown command")},
+		{":42\r\n", int64(42)},
+		{"$5\r\nhello\r\n", "hello"},
+		{"$0\r\n\r\n", ""},
+		{"$-1\r\n", nil},
+		{"*2\r\n:1\r\n$2\r\nok\r\n", []interface{}{int64(1), "ok"}},
+		{"*0\r\n", []interface{}{}},
+	}
+
+	for _, tt := range tests {
+		conn := &redisConn{r: bufio.NewReader(strings.NewReader(tt.reply))}
+
+		got, err := conn.readReply()
+		if err != nil {
+			t.Errorf("%q: %v", tt.reply, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %#v, want %#v", tt.reply, got, tt.want)
+		}
+	}
+}
+
+func TestReadReplyMalformed(t *testing.T) {
+	for _, reply := range []string{"", "+OK\n", "?what\r\n", ":NaN\r\n", "$5\r\nhi\r\n"} {
+		conn := &redisConn{r: bufio.NewReader(strings.NewReader(reply))}
+
+		if got, err := conn.readReply(); err == nil {
+			t.Errorf("%q: got %#v, want an error", reply, got)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+// Package leaguev1 holds the generated protobuf messages and gRPC service of the read API defined
+// in proto/league/v1/read.proto. Regenerate it with go generate after changing the definition.
+package leaguev1
+
+//go:generate protoc -I ../../../proto --go_out=../../.. --go_opt=module=league_of_graphs.satellite.net --go-grpc_out=../../.. --go-grpc_opt=module=league_of_graphs.satellite.net league/v1/read.proto
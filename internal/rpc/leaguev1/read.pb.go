@@ -0,0 +1,1668 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: league/v1/read.proto
+
+package leaguev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Filters mirrors data.Filters. Unset fields take the same defaults as the HTTP query string.
+type Filters struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Page     int32  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Sort     string `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+}
+
+func (x *Filters) Reset() {
+	*x = Filters{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Filters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Filters) ProtoMessage() {}
+
+func (x *Filters) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Filters.ProtoReflect.Descriptor instead.
+func (*Filters) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Filters) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Filters) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *Filters) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+// Metadata mirrors data.Metadata.
+type Metadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CurrentPage  int32 `protobuf:"varint,1,opt,name=current_page,json=currentPage,proto3" json:"current_page,omitempty"`
+	PageSize     int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	FirstPage    int32 `protobuf:"varint,3,opt,name=first_page,json=firstPage,proto3" json:"first_page,omitempty"`
+	LastPage     int32 `protobuf:"varint,4,opt,name=last_page,json=lastPage,proto3" json:"last_page,omitempty"`
+	TotalRecords int32 `protobuf:"varint,5,opt,name=total_records,json=totalRecords,proto3" json:"total_records,omitempty"`
+	OutOfRange   bool  `protobuf:"varint,6,opt,name=out_of_range,json=outOfRange,proto3" json:"out_of_range,omitempty"`
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Metadata) GetCurrentPage() int32 {
+	if x != nil {
+		return x.CurrentPage
+	}
+	return 0
+}
+
+func (x *Metadata) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *Metadata) GetFirstPage() int32 {
+	if x != nil {
+		return x.FirstPage
+	}
+	return 0
+}
+
+func (x *Metadata) GetLastPage() int32 {
+	if x != nil {
+		return x.LastPage
+	}
+	return 0
+}
+
+func (x *Metadata) GetTotalRecords() int32 {
+	if x != nil {
+		return x.TotalRecords
+	}
+	return 0
+}
+
+func (x *Metadata) GetOutOfRange() bool {
+	if x != nil {
+		return x.OutOfRange
+	}
+	return false
+}
+
+type KDA struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kills   int32 `protobuf:"varint,1,opt,name=kills,proto3" json:"kills,omitempty"`
+	Deaths  int32 `protobuf:"varint,2,opt,name=deaths,proto3" json:"deaths,omitempty"`
+	Assists int32 `protobuf:"varint,3,opt,name=assists,proto3" json:"assists,omitempty"`
+}
+
+func (x *KDA) Reset() {
+	*x = KDA{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KDA) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KDA) ProtoMessage() {}
+
+func (x *KDA) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KDA.ProtoReflect.Descriptor instead.
+func (*KDA) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *KDA) GetKills() int32 {
+	if x != nil {
+		return x.Kills
+	}
+	return 0
+}
+
+func (x *KDA) GetDeaths() int32 {
+	if x != nil {
+		return x.Deaths
+	}
+	return 0
+}
+
+func (x *KDA) GetAssists() int32 {
+	if x != nil {
+		return x.Assists
+	}
+	return 0
+}
+
+type Champion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         int64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name       string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MainRole   string  `protobuf:"bytes,3,opt,name=main_role,json=mainRole,proto3" json:"main_role,omitempty"`
+	ImageUrl   string  `protobuf:"bytes,4,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	Popularity float64 `protobuf:"fixed64,5,opt,name=popularity,proto3" json:"popularity,omitempty"`
+	WinRate    float64 `protobuf:"fixed64,6,opt,name=win_rate,json=winRate,proto3" json:"win_rate,omitempty"`
+	BanRate    float64 `protobuf:"fixed64,7,opt,name=ban_rate,json=banRate,proto3" json:"ban_rate,omitempty"`
+	IsMeta     bool    `protobuf:"varint,8,opt,name=is_meta,json=isMeta,proto3" json:"is_meta,omitempty"`
+	Version    int32   `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *Champion) Reset() {
+	*x = Champion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Champion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Champion) ProtoMessage() {}
+
+func (x *Champion) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Champion.ProtoReflect.Descriptor instead.
+func (*Champion) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Champion) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Champion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Champion) GetMainRole() string {
+	if x != nil {
+		return x.MainRole
+	}
+	return ""
+}
+
+func (x *Champion) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Champion) GetPopularity() float64 {
+	if x != nil {
+		return x.Popularity
+	}
+	return 0
+}
+
+func (x *Champion) GetWinRate() float64 {
+	if x != nil {
+		return x.WinRate
+	}
+	return 0
+}
+
+func (x *Champion) GetBanRate() float64 {
+	if x != nil {
+		return x.BanRate
+	}
+	return 0
+}
+
+func (x *Champion) GetIsMeta() bool {
+	if x != nil {
+		return x.IsMeta
+	}
+	return false
+}
+
+func (x *Champion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type GetChampionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetChampionRequest) Reset() {
+	*x = GetChampionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetChampionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChampionRequest) ProtoMessage() {}
+
+func (x *GetChampionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChampionRequest.ProtoReflect.Descriptor instead.
+func (*GetChampionRequest) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetChampionRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListChampionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MainRole string   `protobuf:"bytes,2,opt,name=main_role,json=mainRole,proto3" json:"main_role,omitempty"`
+	MetaOnly bool     `protobuf:"varint,3,opt,name=meta_only,json=metaOnly,proto3" json:"meta_only,omitempty"`
+	Filters  *Filters `protobuf:"bytes,4,opt,name=filters,proto3" json:"filters,omitempty"`
+}
+
+func (x *ListChampionsRequest) Reset() {
+	*x = ListChampionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListChampionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChampionsRequest) ProtoMessage() {}
+
+func (x *ListChampionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChampionsRequest.ProtoReflect.Descriptor instead.
+func (*ListChampionsRequest) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListChampionsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListChampionsRequest) GetMainRole() string {
+	if x != nil {
+		return x.MainRole
+	}
+	return ""
+}
+
+func (x *ListChampionsRequest) GetMetaOnly() bool {
+	if x != nil {
+		return x.MetaOnly
+	}
+	return false
+}
+
+func (x *ListChampionsRequest) GetFilters() *Filters {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type ListChampionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Champions []*Champion `protobuf:"bytes,1,rep,name=champions,proto3" json:"champions,omitempty"`
+	Metadata  *Metadata   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+}
+
+func (x *ListChampionsResponse) Reset() {
+	*x = ListChampionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListChampionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChampionsResponse) ProtoMessage() {}
+
+func (x *ListChampionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChampionsResponse.ProtoReflect.Descriptor instead.
+func (*ListChampionsResponse) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListChampionsResponse) GetChampions() []*Champion {
+	if x != nil {
+		return x.Champions
+	}
+	return nil
+}
+
+func (x *ListChampionsResponse) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Summoner mirrors data.Summoner. As over HTTP to an anonymous user, a private summoner only has
+// its id, username, region and is_private set.
+type Summoner struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                 int64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username           string  `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Region             string  `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	Rating             int32   `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	CountOfPlayedGames int32   `protobuf:"varint,5,opt,name=count_of_played_games,json=countOfPlayedGames,proto3" json:"count_of_played_games,omitempty"`
+	WinRate            float64 `protobuf:"fixed64,6,opt,name=win_rate,json=winRate,proto3" json:"win_rate,omitempty"`
+	AverageKda         *KDA    `protobuf:"bytes,7,opt,name=average_kda,json=averageKda,proto3" json:"average_kda,omitempty"`
+	IsPrivate          bool    `protobuf:"varint,8,opt,name=is_private,json=isPrivate,proto3" json:"is_private,omitempty"`
+}
+
+func (x *Summoner) Reset() {
+	*x = Summoner{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Summoner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summoner) ProtoMessage() {}
+
+func (x *Summoner) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summoner.ProtoReflect.Descriptor instead.
+func (*Summoner) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Summoner) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Summoner) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Summoner) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Summoner) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Summoner) GetCountOfPlayedGames() int32 {
+	if x != nil {
+		return x.CountOfPlayedGames
+	}
+	return 0
+}
+
+func (x *Summoner) GetWinRate() float64 {
+	if x != nil {
+		return x.WinRate
+	}
+	return 0
+}
+
+func (x *Summoner) GetAverageKda() *KDA {
+	if x != nil {
+		return x.AverageKda
+	}
+	return nil
+}
+
+func (x *Summoner) GetIsPrivate() bool {
+	if x != nil {
+		return x.IsPrivate
+	}
+	return false
+}
+
+type GetSummonerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetSummonerRequest) Reset() {
+	*x = GetSummonerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSummonerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSummonerRequest) ProtoMessage() {}
+
+func (x *GetSummonerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSummonerRequest.ProtoReflect.Descriptor instead.
+func (*GetSummonerRequest) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetSummonerRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListSummonersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Username  string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Region    string   `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Search    string   `protobuf:"bytes,3,opt,name=search,proto3" json:"search,omitempty"`
+	MinRating int32    `protobuf:"varint,4,opt,name=min_rating,json=minRating,proto3" json:"min_rating,omitempty"`
+	Filters   *Filters `protobuf:"bytes,5,opt,name=filters,proto3" json:"filters,omitempty"`
+}
+
+func (x *ListSummonersRequest) Reset() {
+	*x = ListSummonersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSummonersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSummonersRequest) ProtoMessage() {}
+
+func (x *ListSummonersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSummonersRequest.ProtoReflect.Descriptor instead.
+func (*ListSummonersRequest) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListSummonersRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ListSummonersRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ListSummonersRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListSummonersRequest) GetMinRating() int32 {
+	if x != nil {
+		return x.MinRating
+	}
+	return 0
+}
+
+func (x *ListSummonersRequest) GetFilters() *Filters {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type ListSummonersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Summoners []*Summoner `protobuf:"bytes,1,rep,name=summoners,proto3" json:"summoners,omitempty"`
+	Metadata  *Metadata   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+}
+
+func (x *ListSummonersResponse) Reset() {
+	*x = ListSummonersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSummonersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSummonersResponse) ProtoMessage() {}
+
+func (x *ListSummonersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSummonersResponse.ProtoReflect.Descriptor instead.
+func (*ListSummonersResponse) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListSummonersResponse) GetSummoners() []*Summoner {
+	if x != nil {
+		return x.Summoners
+	}
+	return nil
+}
+
+func (x *ListSummonersResponse) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type Team struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TeamKda             *KDA  `protobuf:"bytes,1,opt,name=team_kda,json=teamKda,proto3" json:"team_kda,omitempty"`
+	TurretsDestroyed    int32 `protobuf:"varint,2,opt,name=turrets_destroyed,json=turretsDestroyed,proto3" json:"turrets_destroyed,omitempty"`
+	InhibitorsDestroyed int32 `protobuf:"varint,3,opt,name=inhibitors_destroyed,json=inhibitorsDestroyed,proto3" json:"inhibitors_destroyed,omitempty"`
+	RiftHeraldsKilled   int32 `protobuf:"varint,4,opt,name=rift_heralds_killed,json=riftHeraldsKilled,proto3" json:"rift_heralds_killed,omitempty"`
+	DragonsKilled       int32 `protobuf:"varint,5,opt,name=dragons_killed,json=dragonsKilled,proto3" json:"dragons_killed,omitempty"`
+	BaronNashorsKilled  int32 `protobuf:"varint,6,opt,name=baron_nashors_killed,json=baronNashorsKilled,proto3" json:"baron_nashors_killed,omitempty"`
+	TotalNetWorth       int32 `protobuf:"varint,7,opt,name=total_net_worth,json=totalNetWorth,proto3" json:"total_net_worth,omitempty"`
+	TotalGold           int32 `protobuf:"varint,8,opt,name=total_gold,json=totalGold,proto3" json:"total_gold,omitempty"`
+	FirstBlood          bool  `protobuf:"varint,9,opt,name=first_blood,json=firstBlood,proto3" json:"first_blood,omitempty"`
+	FirstTower          bool  `protobuf:"varint,10,opt,name=first_tower,json=firstTower,proto3" json:"first_tower,omitempty"`
+	FirstDragon         bool  `protobuf:"varint,11,opt,name=first_dragon,json=firstDragon,proto3" json:"first_dragon,omitempty"`
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Team) GetTeamKda() *KDA {
+	if x != nil {
+		return x.TeamKda
+	}
+	return nil
+}
+
+func (x *Team) GetTurretsDestroyed() int32 {
+	if x != nil {
+		return x.TurretsDestroyed
+	}
+	return 0
+}
+
+func (x *Team) GetInhibitorsDestroyed() int32 {
+	if x != nil {
+		return x.InhibitorsDestroyed
+	}
+	return 0
+}
+
+func (x *Team) GetRiftHeraldsKilled() int32 {
+	if x != nil {
+		return x.RiftHeraldsKilled
+	}
+	return 0
+}
+
+func (x *Team) GetDragonsKilled() int32 {
+	if x != nil {
+		return x.DragonsKilled
+	}
+	return 0
+}
+
+func (x *Team) GetBaronNashorsKilled() int32 {
+	if x != nil {
+		return x.BaronNashorsKilled
+	}
+	return 0
+}
+
+func (x *Team) GetTotalNetWorth() int32 {
+	if x != nil {
+		return x.TotalNetWorth
+	}
+	return 0
+}
+
+func (x *Team) GetTotalGold() int32 {
+	if x != nil {
+		return x.TotalGold
+	}
+	return 0
+}
+
+func (x *Team) GetFirstBlood() bool {
+	if x != nil {
+		return x.FirstBlood
+	}
+	return false
+}
+
+func (x *Team) GetFirstTower() bool {
+	if x != nil {
+		return x.FirstTower
+	}
+	return false
+}
+
+func (x *Team) GetFirstDragon() bool {
+	if x != nil {
+		return x.FirstDragon
+	}
+	return false
+}
+
+type Match struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlayedDate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=played_date,json=playedDate,proto3" json:"played_date,omitempty"`
+	Duration   int32                  `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Result     string                 `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	MatchType  string                 `protobuf:"bytes,5,opt,name=match_type,json=matchType,proto3" json:"match_type,omitempty"`
+	BlueTeam   *Team                  `protobuf:"bytes,6,opt,name=blue_team,json=blueTeam,proto3" json:"blue_team,omitempty"`
+	RedTeam    *Team                  `protobuf:"bytes,7,opt,name=red_team,json=redTeam,proto3" json:"red_team,omitempty"`
+}
+
+func (x *Match) Reset() {
+	*x = Match{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Match) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Match) ProtoMessage() {}
+
+func (x *Match) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Match.ProtoReflect.Descriptor instead.
+func (*Match) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Match) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Match) GetPlayedDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PlayedDate
+	}
+	return nil
+}
+
+func (x *Match) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *Match) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *Match) GetMatchType() string {
+	if x != nil {
+		return x.MatchType
+	}
+	return ""
+}
+
+func (x *Match) GetBlueTeam() *Team {
+	if x != nil {
+		return x.BlueTeam
+	}
+	return nil
+}
+
+func (x *Match) GetRedTeam() *Team {
+	if x != nil {
+		return x.RedTeam
+	}
+	return nil
+}
+
+type GetMatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetMatchRequest) Reset() {
+	*x = GetMatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchRequest) ProtoMessage() {}
+
+func (x *GetMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchRequest.ProtoReflect.Descriptor instead.
+func (*GetMatchRequest) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetMatchRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListMatchesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tag     string   `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Filters *Filters `protobuf:"bytes,2,opt,name=filters,proto3" json:"filters,omitempty"`
+}
+
+func (x *ListMatchesRequest) Reset() {
+	*x = ListMatchesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListMatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchesRequest) ProtoMessage() {}
+
+func (x *ListMatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchesRequest.ProtoReflect.Descriptor instead.
+func (*ListMatchesRequest) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListMatchesRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListMatchesRequest) GetFilters() *Filters {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type ListMatchesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Matches  []*Match  `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	Metadata *Metadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+}
+
+func (x *ListMatchesResponse) Reset() {
+	*x = ListMatchesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_league_v1_read_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListMatchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchesResponse) ProtoMessage() {}
+
+func (x *ListMatchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_league_v1_read_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchesResponse.ProtoReflect.Descriptor instead.
+func (*ListMatchesResponse) Descriptor() ([]byte, []int) {
+	return file_league_v1_read_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListMatchesResponse) GetMatches() []*Match {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *ListMatchesResponse) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+var File_league_v1_read_proto protoreflect.FileDescriptor
+
+var file_league_v1_read_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x61, 0x64,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76,
+	0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x4e, 0x0a, 0x07, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x61, 0x67,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f,
+	0x72, 0x74, 0x22, 0xcd, 0x01, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x21, 0x0a, 0x0c, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x61,
+	0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x50, 0x61, 0x67, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x50, 0x61, 0x67, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73,
+	0x12, 0x20, 0x0a, 0x0c, 0x6f, 0x75, 0x74, 0x5f, 0x6f, 0x66, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x4f, 0x66, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x22, 0x4d, 0x0a, 0x03, 0x4b, 0x44, 0x41, 0x12, 0x14, 0x0a, 0x05, 0x6b, 0x69, 0x6c,
+	0x6c, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x64, 0x65, 0x61, 0x74, 0x68, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x06, 0x64, 0x65, 0x61, 0x74, 0x68, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x73, 0x73, 0x69, 0x73,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x73, 0x73, 0x69, 0x73, 0x74,
+	0x73, 0x22, 0xf1, 0x01, 0x0a, 0x08, 0x43, 0x68, 0x61, 0x6d, 0x70, 0x69, 0x6f, 0x6e, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x72, 0x6f, 0x6c, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6c, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x55, 0x72, 0x6c, 0x12, 0x1e, 0x0a, 0x0a,
+	0x70, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x70, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x12, 0x19, 0x0a, 0x08,
+	0x77, 0x69, 0x6e, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07,
+	0x77, 0x69, 0x6e, 0x52, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x61, 0x6e, 0x5f, 0x72,
+	0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x62, 0x61, 0x6e, 0x52, 0x61,
+	0x74, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x73, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x24, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6d,
+	0x70, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x92, 0x01, 0x0a, 0x14,
+	0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6d, 0x70, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x69, 0x6e,
+	0x5f, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x69,
+	0x6e, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x74, 0x61, 0x5f, 0x6f, 0x6e,
+	0x6c, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x4f, 0x6e,
+	0x6c, 0x79, 0x12, 0x2c, 0x0a, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73,
+	0x22, 0x7b, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6d, 0x70, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x63, 0x68, 0x61,
+	0x6d, 0x70, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c,
+	0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x6d, 0x70, 0x69, 0x6f,
+	0x6e, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6d, 0x70, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2f, 0x0a, 0x08,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x84, 0x02,
+	0x0a, 0x08, 0x53, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x31, 0x0a, 0x15, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f,
+	0x6f, 0x66, 0x5f, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x5f, 0x67, 0x61, 0x6d, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x12, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4f, 0x66, 0x50, 0x6c,
+	0x61, 0x79, 0x65, 0x64, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x77, 0x69, 0x6e,
+	0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x77, 0x69, 0x6e,
+	0x52, 0x61, 0x74, 0x65, 0x12, 0x2f, 0x0a, 0x0b, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x5f,
+	0x6b, 0x64, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x65, 0x61, 0x67,
+	0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x44, 0x41, 0x52, 0x0a, 0x61, 0x76, 0x65, 0x72, 0x61,
+	0x67, 0x65, 0x4b, 0x64, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x70, 0x72, 0x69, 0x76,
+	0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x50, 0x72, 0x69,
+	0x76, 0x61, 0x74, 0x65, 0x22, 0x24, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x6f,
+	0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0xaf, 0x01, 0x0a, 0x14, 0x4c,
+	0x69, 0x73, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6d, 0x69, 0x6e, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x69, 0x6e, 0x52, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x2c,
+	0x0a, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x73, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x22, 0x7b, 0x0a, 0x15,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x09, 0x73, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65,
+	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x52, 0x09, 0x73,
+	0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x65, 0x61,
+	0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0xc6, 0x03, 0x0a, 0x04, 0x54, 0x65,
+	0x61, 0x6d, 0x12, 0x29, 0x0a, 0x08, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x6b, 0x64, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x4b, 0x44, 0x41, 0x52, 0x07, 0x74, 0x65, 0x61, 0x6d, 0x4b, 0x64, 0x61, 0x12, 0x2b, 0x0a,
+	0x11, 0x74, 0x75, 0x72, 0x72, 0x65, 0x74, 0x73, 0x5f, 0x64, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x74, 0x75, 0x72, 0x72, 0x65, 0x74,
+	0x73, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x65, 0x64, 0x12, 0x31, 0x0a, 0x14, 0x69, 0x6e,
+	0x68, 0x69, 0x62, 0x69, 0x74, 0x6f, 0x72, 0x73, 0x5f, 0x64, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x69, 0x6e, 0x68, 0x69, 0x62, 0x69,
+	0x74, 0x6f, 0x72, 0x73, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x65, 0x64, 0x12, 0x2e, 0x0a,
+	0x13, 0x72, 0x69, 0x66, 0x74, 0x5f, 0x68, 0x65, 0x72, 0x61, 0x6c, 0x64, 0x73, 0x5f, 0x6b, 0x69,
+	0x6c, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x72, 0x69, 0x66, 0x74,
+	0x48, 0x65, 0x72, 0x61, 0x6c, 0x64, 0x73, 0x4b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x25, 0x0a,
+	0x0e, 0x64, 0x72, 0x61, 0x67, 0x6f, 0x6e, 0x73, 0x5f, 0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x64, 0x72, 0x61, 0x67, 0x6f, 0x6e, 0x73, 0x4b, 0x69,
+	0x6c, 0x6c, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x62, 0x61, 0x72, 0x6f, 0x6e, 0x5f, 0x6e, 0x61,
+	0x73, 0x68, 0x6f, 0x72, 0x73, 0x5f, 0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x12, 0x62, 0x61, 0x72, 0x6f, 0x6e, 0x4e, 0x61, 0x73, 0x68, 0x6f, 0x72, 0x73,
+	0x4b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x6e, 0x65, 0x74, 0x5f, 0x77, 0x6f, 0x72, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4e, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x74, 0x68, 0x12, 0x1d,
+	0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x67, 0x6f, 0x6c, 0x64, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x47, 0x6f, 0x6c, 0x64, 0x12, 0x1f, 0x0a,
+	0x0b, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x6f, 0x64, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x6f, 0x64, 0x12, 0x1f,
+	0x0a, 0x0b, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x74, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x54, 0x6f, 0x77, 0x65, 0x72, 0x12,
+	0x21, 0x0a, 0x0c, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x64, 0x72, 0x61, 0x67, 0x6f, 0x6e, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x66, 0x69, 0x72, 0x73, 0x74, 0x44, 0x72, 0x61, 0x67,
+	0x6f, 0x6e, 0x22, 0x81, 0x02, 0x0a, 0x05, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x3b, 0x0a, 0x0b,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2c, 0x0a, 0x09,
+	0x62, 0x6c, 0x75, 0x65, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x61, 0x6d,
+	0x52, 0x08, 0x62, 0x6c, 0x75, 0x65, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x2a, 0x0a, 0x08, 0x72, 0x65,
+	0x64, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6c,
+	0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x07, 0x72,
+	0x65, 0x64, 0x54, 0x65, 0x61, 0x6d, 0x22, 0x21, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x54, 0x0a, 0x12, 0x4c, 0x69, 0x73,
+	0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61,
+	0x67, 0x12, 0x2c, 0x0a, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x52, 0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x22,
+	0x72, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x65, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x32, 0xc3, 0x03, 0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6d, 0x70, 0x69,
+	0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x43, 0x68, 0x61, 0x6d, 0x70, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x13, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68,
+	0x61, 0x6d, 0x70, 0x69, 0x6f, 0x6e, 0x12, 0x52, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68,
+	0x61, 0x6d, 0x70, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6d, 0x70, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6d, 0x70, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0b, 0x47, 0x65,
+	0x74, 0x53, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x12, 0x1d, 0x2e, 0x6c, 0x65, 0x61, 0x67,
+	0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x12, 0x52, 0x0a,
+	0x0d, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x1f,
+	0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x20, 0x2e, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x53, 0x75, 0x6d, 0x6d, 0x6f, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x38, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1a, 0x2e,
+	0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x6c, 0x65, 0x61, 0x67,
+	0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x4c, 0x0a, 0x0b, 0x4c,
+	0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x6c, 0x65, 0x61,
+	0x67, 0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6c, 0x65, 0x61, 0x67,
+	0x75, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x36, 0x5a, 0x34, 0x6c, 0x65, 0x61,
+	0x67, 0x75, 0x65, 0x5f, 0x6f, 0x66, 0x5f, 0x67, 0x72, 0x61, 0x70, 0x68, 0x73, 0x2e, 0x73, 0x61,
+	0x74, 0x65, 0x6c, 0x6c, 0x69, 0x74, 0x65, 0x2e, 0x6e, 0x65, 0x74, 0x2f, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x76,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_league_v1_read_proto_rawDescOnce sync.Once
+	file_league_v1_read_proto_rawDescData = file_league_v1_read_proto_rawDesc
+)
+
+func file_league_v1_read_proto_rawDescGZIP() []byte {
+	file_league_v1_read_proto_rawDescOnce.Do(func() {
+		file_league_v1_read_proto_rawDescData = protoimpl.X.CompressGZIP(file_league_v1_read_proto_rawDescData)
+	})
+	return file_league_v1_read_proto_rawDescData
+}
+
+var file_league_v1_read_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_league_v1_read_proto_goTypes = []any{
+	(*Filters)(nil),               // 0: league.v1.Filters
+	(*Metadata)(nil),              // 1: league.v1.Metadata
+	(*KDA)(nil),                   // 2: league.v1.KDA
+	(*Champion)(nil),              // 3: league.v1.Champion
+	(*GetChampionRequest)(nil),    // 4: league.v1.GetChampionRequest
+	(*ListChampionsRequest)(nil),  // 5: league.v1.ListChampionsRequest
+	(*ListChampionsResponse)(nil), // 6: league.v1.ListChampionsResponse
+	(*Summoner)(nil),              // 7: league.v1.Summoner
+	(*GetSummonerRequest)(nil),    // 8: league.v1.GetSummonerRequest
+	(*ListSummonersRequest)(nil),  // 9: league.v1.ListSummonersRequest
+	(*ListSummonersResponse)(nil), // 10: league.v1.ListSummonersResponse
+	(*Team)(nil),                  // 11: league.v1.Team
+	(*Match)(nil),                 // 12: league.v1.Match
+	(*GetMatchRequest)(nil),       // 13: league.v1.GetMatchRequest
+	(*ListMatchesRequest)(nil),    // 14: league.v1.ListMatchesRequest
+	(*ListMatchesResponse)(nil),   // 15: league.v1.ListMatchesResponse
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+}
+var file_league_v1_read_proto_depIdxs = []int32{
+	0,  // 0: league.v1.ListChampionsRequest.filters:type_name -> league.v1.Filters
+	3,  // 1: league.v1.ListChampionsResponse.champions:type_name -> league.v1.Champion
+	1,  // 2: league.v1.ListChampionsResponse.metadata:type_name -> league.v1.Metadata
+	2,  // 3: league.v1.Summoner.average_kda:type_name -> league.v1.KDA
+	0,  // 4: league.v1.ListSummonersRequest.filters:type_name -> league.v1.Filters
+	7,  // 5: league.v1.ListSummonersResponse.summoners:type_name -> league.v1.Summoner
+	1,  // 6: league.v1.ListSummonersResponse.metadata:type_name -> league.v1.Metadata
+	2,  // 7: league.v1.Team.team_kda:type_name -> league.v1.KDA
+	16, // 8: league.v1.Match.played_date:type_name -> google.protobuf.Timestamp
+	11, // 9: league.v1.Match.blue_team:type_name -> league.v1.Team
+	11, // 10: league.v1.Match.red_team:type_name -> league.v1.Team
+	0,  // 11: league.v1.ListMatchesRequest.filters:type_name -> league.v1.Filters
+	12, // 12: league.v1.ListMatchesResponse.matches:type_name -> league.v1.Match
+	1,  // 13: league.v1.ListMatchesResponse.metadata:type_name -> league.v1.Metadata
+	4,  // 14: league.v1.ReadService.GetChampion:input_type -> league.v1.GetChampionRequest
+	5,  // 15: league.v1.ReadService.ListChampions:input_type -> league.v1.ListChampionsRequest
+	8,  // 16: league.v1.ReadService.GetSummoner:input_type -> league.v1.GetSummonerRequest
+	9,  // 17: league.v1.ReadService.ListSummoners:input_type -> league.v1.ListSummonersRequest
+	13, // 18: league.v1.ReadService.GetMatch:input_type -> league.v1.GetMatchRequest
+	14, // 19: league.v1.ReadService.ListMatches:input_type -> league.v1.ListMatchesRequest
+	3,  // 20: league.v1.ReadService.GetChampion:output_type -> league.v1.Champion
+	6,  // 21: league.v1.ReadService.ListChampions:output_type -> league.v1.ListChampionsResponse
+	7,  // 22: league.v1.ReadService.GetSummoner:output_type -> league.v1.Summoner
+	10, // 23: league.v1.ReadService.ListSummoners:output_type -> league.v1.ListSummonersResponse
+	12, // 24: league.v1.ReadService.GetMatch:output_type -> league.v1.Match
+	15, // 25: league.v1.ReadService.ListMatches:output_type -> league.v1.ListMatchesResponse
+	20, // [20:26] is the sub-list for method output_type
+	14, // [14:20] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_league_v1_read_proto_init() }
+func file_league_v1_read_proto_init() {
+	if File_league_v1_read_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_league_v1_read_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Filters); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Metadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*KDA); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*Champion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GetChampionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ListChampionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*ListChampionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*Summoner); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*GetSummonerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*ListSummonersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*ListSummonersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*Team); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*Match); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*GetMatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*ListMatchesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_league_v1_read_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*ListMatchesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_league_v1_read_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_league_v1_read_proto_goTypes,
+		DependencyIndexes: file_league_v1_read_proto_depIdxs,
+		MessageInfos:      file_league_v1_read_proto_msgTypes,
+	}.Build()
+	File_league_v1_read_proto = out.File
+	file_league_v1_read_proto_rawDesc = nil
+	file_league_v1_read_proto_goTypes = nil
+	file_league_v1_read_proto_depIdxs = nil
+}
@@ -0,0 +1,319 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: league/v1/read.proto
+
+package leaguev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ReadService_GetChampion_FullMethodName   = "/league.v1.ReadService/GetChampion"
+	ReadService_ListChampions_FullMethodName = "/league.v1.ReadService/ListChampions"
+	ReadService_GetSummoner_FullMethodName   = "/league.v1.ReadService/GetSummoner"
+	ReadService_ListSummoners_FullMethodName = "/league.v1.ReadService/ListSummoners"
+	ReadService_GetMatch_FullMethodName      = "/league.v1.ReadService/GetMatch"
+	ReadService_ListMatches_FullMethodName   = "/league.v1.ReadService/ListMatches"
+)
+
+// ReadServiceClient is the client API for ReadService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ReadService exposes the read side of the champion, summoner and match models to internal
+// services. Each method maps onto the model method used by the matching HTTP handler, so the
+// two APIs return the same data.
+type ReadServiceClient interface {
+	GetChampion(ctx context.Context, in *GetChampionRequest, opts ...grpc.CallOption) (*Champion, error)
+	ListChampions(ctx context.Context, in *ListChampionsRequest, opts ...grpc.CallOption) (*ListChampionsResponse, error)
+	GetSummoner(ctx context.Context, in *GetSummonerRequest, opts ...grpc.CallOption) (*Summoner, error)
+	ListSummoners(ctx context.Context, in *ListSummonersRequest, opts ...grpc.CallOption) (*ListSummonersResponse, error)
+	GetMatch(ctx context.Context, in *GetMatchRequest, opts ...grpc.CallOption) (*Match, error)
+	ListMatches(ctx context.Context, in *ListMatchesRequest, opts ...grpc.CallOption) (*ListMatchesResponse, error)
+}
+
+type readServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReadServiceClient(cc grpc.ClientConnInterface) ReadServiceClient {
+	return &readServiceClient{cc}
+}
+
+func (c *readServiceClient) GetChampion(ctx context.Context, in *GetChampionRequest, opts ...grpc.CallOption) (*Champion, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Champion)
+	err := c.cc.Invoke(ctx, ReadService_GetChampion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readServiceClient) ListChampions(ctx context.Context, in *ListChampionsRequest, opts ...grpc.CallOption) (*ListChampionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChampionsResponse)
+	err := c.cc.Invoke(ctx, ReadService_ListChampions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readServiceClient) GetSummoner(ctx context.Context, in *GetSummonerRequest, opts ...grpc.CallOption) (*Summoner, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Summoner)
+	err := c.cc.Invoke(ctx, ReadService_GetSummoner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readServiceClient) ListSummoners(ctx context.Context, in *ListSummonersRequest, opts ...grpc.CallOption) (*ListSummonersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSummonersResponse)
+	err := c.cc.Invoke(ctx, ReadService_ListSummoners_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readServiceClient) GetMatch(ctx context.Context, in *GetMatchRequest, opts ...grpc.CallOption) (*Match, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Match)
+	err := c.cc.Invoke(ctx, ReadService_GetMatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readServiceClient) ListMatches(ctx context.Context, in *ListMatchesRequest, opts ...grpc.CallOption) (*ListMatchesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMatchesResponse)
+	err := c.cc.Invoke(ctx, ReadService_ListMatches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReadServiceServer is the server API for ReadService service.
+// All implementations must embed UnimplementedReadServiceServer
+// for forward compatibility.
+//
+// ReadService exposes the read side of the champion, summoner and match models to internal
+// services. Each method maps onto the model method used by the matching HTTP handler, so the
+// two APIs return the same data.
+type ReadServiceServer interface {
+	GetChampion(context.Context, *GetChampionRequest) (*Champion, error)
+	ListChampions(context.Context, *ListChampionsRequest) (*ListChampionsResponse, error)
+	GetSummoner(context.Context, *GetSummonerRequest) (*Summoner, error)
+	ListSummoners(context.Context, *ListSummonersRequest) (*ListSummonersResponse, error)
+	GetMatch(context.Context, *GetMatchRequest) (*Match, error)
+	ListMatches(context.Context, *ListMatchesRequest) (*ListMatchesResponse, error)
+	mustEmbedUnimplementedReadServiceServer()
+}
+
+// UnimplementedReadServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReadServiceServer struct{}
+
+func (UnimplementedReadServiceServer) GetChampion(context.Context, *GetChampionRequest) (*Champion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChampion not implemented")
+}
+func (UnimplementedReadServiceServer) ListChampions(context.Context, *ListChampionsRequest) (*ListChampionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChampions not implemented")
+}
+func (UnimplementedReadServiceServer) GetSummoner(context.Context, *GetSummonerRequest) (*Summoner, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSummoner not implemented")
+}
+func (UnimplementedReadServiceServer) ListSummoners(context.Context, *ListSummonersRequest) (*ListSummonersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSummoners not implemented")
+}
+func (UnimplementedReadServiceServer) GetMatch(context.Context, *GetMatchRequest) (*Match, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMatch not implemented")
+}
+func (UnimplementedReadServiceServer) ListMatches(context.Context, *ListMatchesRequest) (*ListMatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMatches not implemented")
+}
+func (UnimplementedReadServiceServer) mustEmbedUnimplementedReadServiceServer() {}
+func (UnimplementedReadServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeReadServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReadServiceServer will
+// result in compilation errors.
+type UnsafeReadServiceServer interface {
+	mustEmbedUnimplementedReadServiceServer()
+}
+
+func RegisterReadServiceServer(s grpc.ServiceRegistrar, srv ReadServiceServer) {
+	// If the following call pancis, it indicates UnimplementedReadServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ReadService_ServiceDesc, srv)
+}
+
+func _ReadService_GetChampion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChampionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadServiceServer).GetChampion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadService_GetChampion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadServiceServer).GetChampion(ctx, req.(*GetChampionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadService_ListChampions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChampionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadServiceServer).ListChampions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadService_ListChampions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadServiceServer).ListChampions(ctx, req.(*ListChampionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadService_GetSummoner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSummonerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadServiceServer).GetSummoner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadService_GetSummoner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadServiceServer).GetSummoner(ctx, req.(*GetSummonerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadService_ListSummoners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSummonersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadServiceServer).ListSummoners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadService_ListSummoners_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadServiceServer).ListSummoners(ctx, req.(*ListSummonersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadService_GetMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadServiceServer).GetMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadService_GetMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadServiceServer).GetMatch(ctx, req.(*GetMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadService_ListMatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadServiceServer).ListMatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadService_ListMatches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadServiceServer).ListMatches(ctx, req.(*ListMatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ReadService_ServiceDesc is the grpc.ServiceDesc for ReadService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReadService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "league.v1.ReadService",
+	HandlerType: (*ReadServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetChampion",
+			Handler:    _ReadService_GetChampion_Handler,
+		},
+		{
+			MethodName: "ListChampions",
+			Handler:    _ReadService_ListChampions_Handler,
+		},
+		{
+			MethodName: "GetSummoner",
+			Handler:    _ReadService_GetSummoner_Handler,
+		},
+		{
+			MethodName: "ListSummoners",
+			Handler:    _ReadService_ListSummoners_Handler,
+		},
+		{
+			MethodName: "GetMatch",
+			Handler:    _ReadService_GetMatch_Handler,
+		},
+		{
+			MethodName: "ListMatches",
+			Handler:    _ReadService_ListMatches_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "league/v1/read.proto",
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"net/url"
 	"regexp"
 )
 
@@ -13,14 +14,16 @@ var (
 	EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 )
 
-// Define a new Validator type which contains a map of validation errors.
+// Define a new Validator type which contains a map of validation errors, and a map of warnings
+// for values which look suspicious but shouldn't cause the request to be rejected.
 type Validator struct {
-	Errors map[string]string
+	Errors   map[string]string
+	Warnings map[string]string
 }
 
-// New is a helper which creates a new Validator instance with an empty errors map.
+// New is a helper which creates a new Validator instance with empty errors and warnings maps.
 func New() *Validator {
-	return &Validator{Errors: make(map[string]string)}
+	return &Validator{Errors: make(map[string]string), Warnings: make(map[string]string)}
 }
 
 // Valid returns true if the errors map doesn't contain any entries.
@@ -43,6 +46,27 @@ func (v *Validator) Check(ok bool, key, message string) {
 	}
 }
 
+// HasWarnings returns true if the warnings map contains any entries.
+func (v *Validator) HasWarnings() bool {
+	return len(v.Warnings) != 0
+}
+
+// AddWarning adds a warning message to the map (so long as no entry already exists for
+// the given key).
+func (v *Validator) AddWarning(key, message string) {
+	if _, exists := v.Warnings[key]; !exists {
+		v.Warnings[key] = message
+	}
+}
+
+// Warn adds a warning message to the map only if a check is not 'ok'. Unlike Check, this
+// doesn't affect the result of Valid().
+func (v *Validator) Warn(ok bool, key, message string) {
+	if !ok {
+		v.AddWarning(key, message)
+	}
+}
+
 // In returns true if a specific value is in a list of strings.
 func In(value string, list ...string) bool {
 	for i := range list {
@@ -66,3 +90,9 @@ func Unique(values []string) bool {
 	}
 	return len(values) == len(uniqueValues)
 }
+
+// IsURL returns true if a string value is an absolute http or https URL.
+func IsURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -1,2 +1 @@
-DROP TABLE IF EXISTS summoner_champion_stats;
 DROP TABLE IF EXISTS champions;
\ No newline at end of file
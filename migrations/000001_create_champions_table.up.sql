@@ -6,11 +6,3 @@ CREATE TABLE IF NOT EXISTS champions (
     win_rate float8 DEFAULT 0,
     ban_rate float8 DEFAULT 0
 );
-
-CREATE TABLE IF NOT EXISTS summoner_champion_stats (
-    id bigserial PRIMARY KEY,
-    summoner_id bigserial NOT NULL REFERENCES summoners(id),
-    champion_id bigserial NOT NULL REFERENCES champions(id),
-    win_rate float8 NOT NULL,
-    count_of_played_matches integer NOT NULL
-);
@@ -1,3 +1,4 @@
+DROP TABLE IF EXISTS summoner_champion_stats;
 DROP TABLE IF EXISTS role_stats;
 DROP TABLE IF EXISTS champion_stats;
 DROP TABLE IF EXISTS summoners;
\ No newline at end of file
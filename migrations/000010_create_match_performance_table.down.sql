@@ -0,0 +1 @@
+DROP TABLE IF EXISTS match_performance;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS match_performance (
+    id bigserial PRIMARY KEY,
+    match_id bigint NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    summoner_id bigint NOT NULL REFERENCES summoners(id),
+    champion_id bigint NOT NULL REFERENCES champions(id),
+    team text NOT NULL,
+    role text NOT NULL DEFAULT '',
+    net_worth integer NOT NULL DEFAULT 0,
+    kills integer NOT NULL DEFAULT 0,
+    deaths integer NOT NULL DEFAULT 0,
+    assists integer NOT NULL DEFAULT 0,
+    bought_items jsonb NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX IF NOT EXISTS match_performance_match_id_idx ON match_performance (match_id);
+CREATE INDEX IF NOT EXISTS match_performance_summoner_id_idx ON match_performance (summoner_id);
+CREATE INDEX IF NOT EXISTS match_performance_champion_id_idx ON match_performance (champion_id);
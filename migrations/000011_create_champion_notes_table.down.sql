@@ -0,0 +1 @@
+DROP TABLE IF EXISTS champion_notes;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS champion_notes (
+    id bigserial PRIMARY KEY,
+    champion_id bigint NOT NULL REFERENCES champions(id) ON DELETE CASCADE,
+    user_id bigint REFERENCES users(id) ON DELETE SET NULL,
+    body text NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS champion_notes_champion_id_idx ON champion_notes (champion_id, created_at DESC);
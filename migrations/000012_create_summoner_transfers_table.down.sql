@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS summoner_transfers;
+DROP INDEX IF EXISTS summoners_username_region_key;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- Usernames are unique within a region regardless of case. Summoners which only differ in the
+-- case of their username have to be merged (POST /v1/summoners/:id/merge) before this can run.
+CREATE UNIQUE INDEX IF NOT EXISTS summoners_username_region_key ON summoners (LOWER(username), region);
+
+CREATE TABLE IF NOT EXISTS summoner_transfers (
+    id bigserial PRIMARY KEY,
+    summoner_id bigint NOT NULL REFERENCES summoners(id) ON DELETE CASCADE,
+    from_region text NOT NULL,
+    to_region text NOT NULL,
+    transferred_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DELETE FROM permissions WHERE code = 'system:write';
+DROP TABLE IF EXISTS system_settings;
\ No newline at end of file
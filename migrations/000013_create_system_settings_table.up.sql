@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS system_settings (
+    id integer PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    announcement_message text NOT NULL DEFAULT '',
+    announcement_active boolean NOT NULL DEFAULT false,
+    announcement_expires_at timestamp(0) with time zone,
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO system_settings (id) VALUES (1) ON CONFLICT DO NOTHING;
+
+INSERT INTO permissions (code) VALUES ('system:write');
@@ -0,0 +1,6 @@
+ALTER TABLE champions
+    DROP COLUMN IF EXISTS image_url_version,
+    DROP COLUMN IF EXISTS main_role_version,
+    DROP COLUMN IF EXISTS name_version,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS image_url;
@@ -0,0 +1,6 @@
+ALTER TABLE champions
+    ADD COLUMN IF NOT EXISTS image_url text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS name_version integer NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS main_role_version integer NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS image_url_version integer NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+DELETE FROM permissions WHERE code = 'ratelimit:exempt';
\ No newline at end of file
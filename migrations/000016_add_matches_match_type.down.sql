@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS matches_match_type_idx;
+
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_match_type_check;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS match_type;
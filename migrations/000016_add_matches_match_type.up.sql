@@ -0,0 +1,5 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS match_type text NOT NULL DEFAULT 'solo_queue';
+
+ALTER TABLE matches ADD CONSTRAINT matches_match_type_check CHECK (match_type IN ('solo_queue', 'pro', 'tournament'));
+
+CREATE INDEX IF NOT EXISTS matches_match_type_idx ON matches (match_type);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS match_tags;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS match_tags (
+    match_id bigint NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    tag text NOT NULL,
+    PRIMARY KEY (match_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS match_tags_tag_idx ON match_tags (tag);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS summoner_champion_stats_summoner_champion_key;
+
+ALTER TABLE summoner_champion_stats
+    DROP COLUMN IF EXISTS assists,
+    DROP COLUMN IF EXISTS deaths,
+    DROP COLUMN IF EXISTS kills;
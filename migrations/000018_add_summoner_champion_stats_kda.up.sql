@@ -0,0 +1,6 @@
+ALTER TABLE summoner_champion_stats
+    ADD COLUMN IF NOT EXISTS kills integer NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS deaths integer NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS assists integer NOT NULL DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS summoner_champion_stats_summoner_champion_key ON summoner_champion_stats (summoner_id, champion_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS match_performance_rank_tier_idx;
+DROP TRIGGER IF EXISTS match_performance_set_rank_tier ON match_performance;
+DROP FUNCTION IF EXISTS match_performance_set_rank_tier();
+ALTER TABLE match_performance DROP COLUMN IF EXISTS rank_tier;
+DROP FUNCTION IF EXISTS rank_tier(integer);
@@ -0,0 +1,41 @@
+-- rank_tier maps a summoner rating to its rank tier. Keep the thresholds in step with RankTiers
+-- in internal/data/ranks.go.
+CREATE OR REPLACE FUNCTION rank_tier(rating integer) RETURNS text AS $$
+    SELECT CASE
+        WHEN rating >= 3600 THEN 'Challenger'
+        WHEN rating >= 3200 THEN 'Grandmaster'
+        WHEN rating >= 2800 THEN 'Master'
+        WHEN rating >= 2400 THEN 'Diamond'
+        WHEN rating >= 2000 THEN 'Emerald'
+        WHEN rating >= 1600 THEN 'Platinum'
+        WHEN rating >= 1200 THEN 'Gold'
+        WHEN rating >= 800 THEN 'Silver'
+        WHEN rating >= 400 THEN 'Bronze'
+        ELSE 'Iron'
+    END
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE match_performance ADD COLUMN IF NOT EXISTS rank_tier text NOT NULL DEFAULT '';
+
+-- Existing performances can only be given the summoner's current tier.
+UPDATE match_performance mp
+SET rank_tier = rank_tier(COALESCE(s.rating, 0))
+FROM summoners s
+WHERE s.id = mp.summoner_id AND mp.rank_tier = '';
+
+-- New performances record the summoner's tier at the time they're stored, so later rating
+-- changes don't move old games between tiers.
+CREATE OR REPLACE FUNCTION match_performance_set_rank_tier() RETURNS trigger AS $$
+BEGIN
+    IF NEW.rank_tier = '' THEN
+        SELECT rank_tier(COALESCE(rating, 0)) INTO NEW.rank_tier FROM summoners WHERE id = NEW.summoner_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER match_performance_set_rank_tier
+    BEFORE INSERT ON match_performance
+    FOR EACH ROW EXECUTE FUNCTION match_performance_set_rank_tier();
+
+CREATE INDEX IF NOT EXISTS match_performance_rank_tier_idx ON match_performance (rank_tier);
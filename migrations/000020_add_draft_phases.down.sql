@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS match_bans;
+ALTER TABLE match_performance DROP COLUMN IF EXISTS pick_phase;
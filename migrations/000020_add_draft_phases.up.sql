@@ -0,0 +1,15 @@
+-- pick_phase is the position (1-10) in the draft's pick sequence at which the champion was
+-- picked, counting both teams' picks. It's NULL for performances recorded without a draft.
+ALTER TABLE match_performance ADD COLUMN IF NOT EXISTS pick_phase smallint
+    CHECK (pick_phase BETWEEN 1 AND 10);
+
+-- match_bans records each ban along with its position (1-10) in the draft's ban sequence.
+CREATE TABLE IF NOT EXISTS match_bans (
+    match_id bigint NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    team text NOT NULL,
+    champion_id bigint NOT NULL REFERENCES champions(id) ON DELETE CASCADE,
+    ban_phase smallint NOT NULL CHECK (ban_phase BETWEEN 1 AND 10),
+    PRIMARY KEY (match_id, ban_phase)
+);
+
+CREATE INDEX IF NOT EXISTS match_bans_champion_id_idx ON match_bans (champion_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS summoners_last_match_at_idx;
+DROP TRIGGER IF EXISTS match_performance_set_last_match_at ON match_performance;
+DROP FUNCTION IF EXISTS match_performance_set_last_match_at();
+ALTER TABLE summoners DROP COLUMN IF EXISTS last_match_at;
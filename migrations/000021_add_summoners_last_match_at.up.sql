@@ -0,0 +1,30 @@
+-- last_match_at is the played date of the summoner's most recent match. It's denormalized from
+-- match_performance so that listing dormant summoners doesn't need to aggregate every match.
+ALTER TABLE summoners ADD COLUMN IF NOT EXISTS last_match_at timestamp(0) with time zone;
+
+UPDATE summoners s
+SET last_match_at = latest.played_date
+FROM (
+    SELECT mp.summoner_id, max(m.played_date) AS played_date
+    FROM match_performance mp
+    JOIN matches m ON m.id = mp.match_id
+    GROUP BY mp.summoner_id
+) latest
+WHERE latest.summoner_id = s.id;
+
+CREATE OR REPLACE FUNCTION match_performance_set_last_match_at() RETURNS trigger AS $$
+BEGIN
+    UPDATE summoners s
+    SET last_match_at = m.played_date
+    FROM matches m
+    WHERE s.id = NEW.summoner_id AND m.id = NEW.match_id
+    AND (s.last_match_at IS NULL OR s.last_match_at < m.played_date);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER match_performance_set_last_match_at
+    AFTER INSERT ON match_performance
+    FOR EACH ROW EXECUTE FUNCTION match_performance_set_last_match_at();
+
+CREATE INDEX IF NOT EXISTS summoners_last_match_at_idx ON summoners (last_match_at);
@@ -0,0 +1,8 @@
+-- Aliases are stored normalized (lowercase, single-spaced) and belong to a single champion, so an
+-- alias search always resolves to one champion.
+CREATE TABLE IF NOT EXISTS champion_aliases (
+    alias text PRIMARY KEY,
+    champion_id bigint NOT NULL REFERENCES champions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS champion_aliases_champion_id_idx ON champion_aliases (champion_id);
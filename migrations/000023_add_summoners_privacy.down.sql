@@ -0,0 +1,2 @@
+ALTER TABLE summoners DROP COLUMN IF EXISTS is_private;
+ALTER TABLE summoners DROP COLUMN IF EXISTS user_id;
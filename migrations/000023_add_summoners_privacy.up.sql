@@ -0,0 +1,4 @@
+-- user_id is the user who owns the summoner profile, if any. Only the owner (and admins) can see
+-- the detailed stats of a private profile.
+ALTER TABLE summoners ADD COLUMN IF NOT EXISTS user_id bigint REFERENCES users ON DELETE SET NULL;
+ALTER TABLE summoners ADD COLUMN IF NOT EXISTS is_private boolean NOT NULL DEFAULT false;
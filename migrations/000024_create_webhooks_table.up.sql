@@ -0,0 +1,8 @@
+-- Webhooks are the endpoints integrators have registered to be told about significant changes in
+-- champion stats. Each event is signed with the webhook's secret.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id bigserial PRIMARY KEY,
+    url text NOT NULL,
+    secret text NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
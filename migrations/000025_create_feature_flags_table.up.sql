@@ -0,0 +1,7 @@
+-- feature_flags records features switched on or off at runtime through the admin API. A feature
+-- without a row keeps the state it was given on the command line.
+CREATE TABLE IF NOT EXISTS feature_flags (
+    name text PRIMARY KEY,
+    enabled boolean NOT NULL,
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS summoners_is_pro_idx;
+ALTER TABLE summoners DROP COLUMN IF EXISTS is_pro;
@@ -0,0 +1,4 @@
+-- is_pro marks summoner accounts known to belong to professional players. It's set by admins.
+ALTER TABLE summoners ADD COLUMN IF NOT EXISTS is_pro boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS summoners_is_pro_idx ON summoners (id) WHERE is_pro;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS matches_has_vod_idx;
+ALTER TABLE matches DROP COLUMN IF EXISTS vod_url;
+ALTER TABLE matches DROP COLUMN IF EXISTS replay_url;
@@ -0,0 +1,5 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS replay_url text NOT NULL DEFAULT '';
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS vod_url text NOT NULL DEFAULT '';
+
+-- Only a small share of matches have a VOD, so index just those for the ?has_vod= filter.
+CREATE INDEX IF NOT EXISTS matches_has_vod_idx ON matches (id) WHERE vod_url <> '';
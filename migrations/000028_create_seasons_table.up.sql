@@ -0,0 +1,9 @@
+-- seasons holds the start and end of each competitive season (or split), used to group stats the
+-- way Riot does. The current season has no end yet. Rows are maintained by operators as Riot
+-- announces the dates.
+CREATE TABLE IF NOT EXISTS seasons (
+    name text PRIMARY KEY,
+    starts_at timestamp(0) with time zone NOT NULL,
+    ends_at timestamp(0) with time zone,
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE champions DROP CONSTRAINT IF EXISTS champions_damage_profile_check;
+ALTER TABLE champions DROP COLUMN IF EXISTS damage_profile;
@@ -0,0 +1,3 @@
+-- An empty damage_profile means the champion hasn't been classified yet.
+ALTER TABLE champions ADD COLUMN IF NOT EXISTS damage_profile text NOT NULL DEFAULT '';
+ALTER TABLE champions ADD CONSTRAINT champions_damage_profile_check CHECK (damage_profile IN ('', 'AD', 'AP', 'mixed'));
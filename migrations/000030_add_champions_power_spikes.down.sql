@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS champions_power_spikes_idx;
+ALTER TABLE champions DROP COLUMN IF EXISTS power_spikes;
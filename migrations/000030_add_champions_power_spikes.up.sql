@@ -0,0 +1,4 @@
+ALTER TABLE champions ADD COLUMN IF NOT EXISTS power_spikes text[] NOT NULL DEFAULT '{}';
+
+-- Speeds up ?power_spike= filtering, which tests for overlap with a set of tags.
+CREATE INDEX IF NOT EXISTS champions_power_spikes_idx ON champions USING GIN (power_spikes);
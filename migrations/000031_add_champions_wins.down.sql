@@ -0,0 +1,3 @@
+ALTER TABLE champions DROP CONSTRAINT IF EXISTS champions_wins_check;
+ALTER TABLE champions DROP COLUMN IF EXISTS wins;
+ALTER TABLE champions DROP COLUMN IF EXISTS count_of_played_matches;
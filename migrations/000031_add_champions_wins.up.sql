@@ -0,0 +1,20 @@
+-- Win rates were accumulated incrementally from the stored rate, which loses precision and read
+-- the rate back as a win count. Keep explicit counts instead and derive win_rate from them.
+ALTER TABLE champions ADD COLUMN IF NOT EXISTS count_of_played_matches integer NOT NULL DEFAULT 0;
+ALTER TABLE champions ADD COLUMN IF NOT EXISTS wins integer NOT NULL DEFAULT 0;
+
+-- Backfill the counts from match history, leaving out remakes.
+UPDATE champions c
+SET count_of_played_matches = s.played, wins = s.wins,
+    win_rate = s.wins::float8 / s.played
+FROM (
+    SELECT mp.champion_id, count(*) AS played,
+        count(*) FILTER (WHERE LOWER(m.result) = mp.team) AS wins
+    FROM match_performance mp
+    JOIN matches m ON m.id = mp.match_id
+    WHERE LOWER(m.result) <> 'remake'
+    GROUP BY mp.champion_id
+) s
+WHERE s.champion_id = c.id;
+
+ALTER TABLE champions ADD CONSTRAINT champions_wins_check CHECK (wins BETWEEN 0 AND count_of_played_matches);
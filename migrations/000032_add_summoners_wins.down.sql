@@ -0,0 +1,2 @@
+ALTER TABLE summoners DROP CONSTRAINT IF EXISTS summoners_wins_check;
+ALTER TABLE summoners DROP COLUMN IF EXISTS wins;
@@ -0,0 +1,29 @@
+-- Like champions, keep an explicit win count for summoners and derive win_rate from it.
+ALTER TABLE summoners ADD COLUMN IF NOT EXISTS wins integer NOT NULL DEFAULT 0;
+
+-- Recompute the counts and win rate of every summoner with a match history, leaving out remakes.
+UPDATE summoners su
+SET count_of_played_games = s.played, wins = s.wins,
+    win_rate = s.wins::float8 / s.played
+FROM (
+    SELECT mp.summoner_id, count(*) AS played,
+        count(*) FILTER (WHERE LOWER(m.result) = mp.team) AS wins
+    FROM match_performance mp
+    JOIN matches m ON m.id = mp.match_id
+    WHERE LOWER(m.result) <> 'remake'
+    GROUP BY mp.summoner_id
+) s
+WHERE s.summoner_id = su.id;
+
+-- Summoners without a match history (other than remakes) only have the stored rate to go on, so estimate their wins
+-- from it, clamped in case the rate had drifted out of range.
+UPDATE summoners su
+SET wins = LEAST(GREATEST(round(su.win_rate * su.count_of_played_games)::integer, 0), su.count_of_played_games)
+WHERE su.count_of_played_games > 0
+AND NOT EXISTS (
+    SELECT 1 FROM match_performance mp
+    JOIN matches m ON m.id = mp.match_id
+    WHERE mp.summoner_id = su.id AND LOWER(m.result) <> 'remake'
+);
+
+ALTER TABLE summoners ADD CONSTRAINT summoners_wins_check CHECK (wins BETWEEN 0 AND count_of_played_games);
@@ -0,0 +1,18 @@
+-- rating_decay_rules holds how an inactive summoner's rating decays in each rank tier. After
+-- grace_days without a match the rating drops by decay_points, and again every interval_days,
+-- never falling below min_rating. Tiers without a row don't decay.
+CREATE TABLE IF NOT EXISTS rating_decay_rules (
+    rank_tier text PRIMARY KEY,
+    grace_days integer NOT NULL CHECK (grace_days >= 0),
+    interval_days integer NOT NULL CHECK (interval_days > 0),
+    decay_points integer NOT NULL CHECK (decay_points > 0),
+    min_rating integer NOT NULL DEFAULT 0
+);
+
+INSERT INTO rating_decay_rules (rank_tier, grace_days, interval_days, decay_points, min_rating)
+VALUES
+    ('Diamond', 28, 7, 50, 2400),
+    ('Master', 14, 7, 75, 2400),
+    ('Grandmaster', 14, 7, 75, 2400),
+    ('Challenger', 14, 7, 75, 2400)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS champions_classes_idx;
+ALTER TABLE champions DROP COLUMN IF EXISTS classes;
@@ -0,0 +1,4 @@
+ALTER TABLE champions ADD COLUMN IF NOT EXISTS classes text[] NOT NULL DEFAULT '{}';
+
+-- Speeds up ?class= filtering, which tests for array containment.
+CREATE INDEX IF NOT EXISTS champions_classes_idx ON champions USING GIN (classes);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS match_events (
+    id bigserial PRIMARY KEY,
+    match_id bigint NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    game_time integer NOT NULL CHECK (game_time >= 0),
+    type text NOT NULL,
+    team text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS match_events_match_id_game_time_idx ON match_events (match_id, game_time);
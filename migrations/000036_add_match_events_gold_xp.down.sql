@@ -0,0 +1,2 @@
+ALTER TABLE match_events DROP COLUMN IF EXISTS xp;
+ALTER TABLE match_events DROP COLUMN IF EXISTS gold;
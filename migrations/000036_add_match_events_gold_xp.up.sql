@@ -0,0 +1,2 @@
+ALTER TABLE match_events ADD COLUMN IF NOT EXISTS gold integer NOT NULL DEFAULT 0 CHECK (gold >= 0);
+ALTER TABLE match_events ADD COLUMN IF NOT EXISTS xp integer NOT NULL DEFAULT 0 CHECK (xp >= 0);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS champions_release_date_idx;
+ALTER TABLE champions DROP COLUMN IF EXISTS release_date;
@@ -0,0 +1,4 @@
+ALTER TABLE champions ADD COLUMN IF NOT EXISTS release_date date;
+
+-- Speeds up ?released_after= filtering and sorting by release date.
+CREATE INDEX IF NOT EXISTS champions_release_date_idx ON champions (release_date);
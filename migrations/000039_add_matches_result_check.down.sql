@@ -0,0 +1 @@
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_result_check;
@@ -0,0 +1,16 @@
+-- Win/loss queries compare LOWER(result) with the team of each performance, so any result other
+-- than a team or a remake would silently count as a loss for both teams.
+UPDATE matches SET result = LOWER(TRIM(result)) WHERE result <> LOWER(TRIM(result));
+
+ALTER TABLE matches ADD CONSTRAINT matches_result_check CHECK (LOWER(result) IN ('blue', 'red', 'remake')) NOT VALID;
+
+-- Only check the existing rows if they all pass, so that a database holding bad results can still
+-- be migrated. New and updated rows are always checked; the rest need correcting by hand, after
+-- which the constraint can be validated.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM matches WHERE LOWER(result) NOT IN ('blue', 'red', 'remake')) THEN
+        ALTER TABLE matches VALIDATE CONSTRAINT matches_result_check;
+    END IF;
+END
+$$;
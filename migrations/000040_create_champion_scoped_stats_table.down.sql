@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS match_performance_count_scoped_stats ON match_performance;
+DROP FUNCTION IF EXISTS match_performance_count_scoped_stats();
+DROP TABLE IF EXISTS champion_scoped_stats;
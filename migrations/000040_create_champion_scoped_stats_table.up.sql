@@ -0,0 +1,45 @@
+-- champion_scoped_stats splits each champion's match and win counts by match type and by the rank
+-- tier the summoner was in, so champion stats can be scoped with ?match_type= and ?rank= without
+-- aggregating every performance. Rows are kept per summoner as well, which lets the number of
+-- distinct summoners (the champion's popularity) be counted within a scope.
+CREATE TABLE IF NOT EXISTS champion_scoped_stats (
+    champion_id bigint NOT NULL REFERENCES champions(id) ON DELETE CASCADE,
+    summoner_id bigint NOT NULL REFERENCES summoners(id) ON DELETE CASCADE,
+    match_type text NOT NULL,
+    rank_tier text NOT NULL,
+    count_of_played_matches integer NOT NULL DEFAULT 0,
+    wins integer NOT NULL DEFAULT 0,
+    PRIMARY KEY (champion_id, summoner_id, match_type, rank_tier),
+    CONSTRAINT champion_scoped_stats_wins_check CHECK (wins BETWEEN 0 AND count_of_played_matches)
+);
+
+CREATE INDEX IF NOT EXISTS champion_scoped_stats_summoner_id_idx ON champion_scoped_stats (summoner_id);
+
+-- Backfill from match history, leaving out remakes.
+INSERT INTO champion_scoped_stats (champion_id, summoner_id, match_type, rank_tier, count_of_played_matches, wins)
+SELECT mp.champion_id, mp.summoner_id, m.match_type, mp.rank_tier, count(*),
+    count(*) FILTER (WHERE LOWER(m.result) = mp.team)
+FROM match_performance mp
+JOIN matches m ON m.id = mp.match_id
+WHERE LOWER(m.result) <> 'remake'
+GROUP BY mp.champion_id, mp.summoner_id, m.match_type, mp.rank_tier;
+
+-- New performances are counted as they're stored. This runs after match_performance_set_rank_tier,
+-- so the tier is already filled in.
+CREATE OR REPLACE FUNCTION match_performance_count_scoped_stats() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO champion_scoped_stats (champion_id, summoner_id, match_type, rank_tier, count_of_played_matches, wins)
+    SELECT NEW.champion_id, NEW.summoner_id, m.match_type, NEW.rank_tier, 1,
+        CASE WHEN LOWER(m.result) = NEW.team THEN 1 ELSE 0 END
+    FROM matches m
+    WHERE m.id = NEW.match_id AND LOWER(m.result) <> 'remake'
+    ON CONFLICT (champion_id, summoner_id, match_type, rank_tier) DO UPDATE
+    SET count_of_played_matches = champion_scoped_stats.count_of_played_matches + 1,
+        wins = champion_scoped_stats.wins + EXCLUDED.wins;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER match_performance_count_scoped_stats
+    AFTER INSERT ON match_performance
+    FOR EACH ROW EXECUTE FUNCTION match_performance_count_scoped_stats();
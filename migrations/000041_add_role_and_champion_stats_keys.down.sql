@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS champion_stats_champion_summoner_key;
+DROP INDEX IF EXISTS role_stats_summoner_role_key;
@@ -0,0 +1,6 @@
+-- Match results are upserted into role_stats per summoner and role, and into champion_stats per
+-- champion and summoner, which needs a unique key on each. Both tables were only ever written by
+-- those upserts, which failed without the keys, so they hold no duplicates.
+CREATE UNIQUE INDEX IF NOT EXISTS role_stats_summoner_role_key ON role_stats (summoner_id, role);
+
+CREATE UNIQUE INDEX IF NOT EXISTS champion_stats_champion_summoner_key ON champion_stats (champion_id, summoner_id);
@@ -0,0 +1 @@
+ALTER TABLE summoners DROP COLUMN IF EXISTS owner_verified;
@@ -0,0 +1,3 @@
+-- owner_verified records that an admin has confirmed the owning user really plays the summoner.
+-- Only a verified owner can make their profile private.
+ALTER TABLE summoners ADD COLUMN IF NOT EXISTS owner_verified boolean NOT NULL DEFAULT false;